@@ -135,6 +135,7 @@ func InitSchema() {
 	);
 
 	ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_level INT DEFAULT 0;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS full_name VARCHAR(255);
 
 	CREATE TABLE IF NOT EXISTS game_sessions (
 		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -15,6 +15,7 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 	Mobile   string `json:"mobile" binding:"required"`
+	FullName string `json:"full_name"` // Legal name, as on KYC documents
 }
 
 type LoginRequest struct {
@@ -45,6 +46,7 @@ func Register(c *gin.Context) {
 		Username: req.Username,
 		Email:    req.Email,
 		Mobile:   req.Mobile,
+		FullName: req.FullName,
 	}
 	if err := user.SetPassword(req.Password); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
@@ -53,8 +55,8 @@ func Register(c *gin.Context) {
 
 	// Insert User
 	err = db.DB.QueryRow(
-		"INSERT INTO users (username, email, password_hash, mobile) VALUES ($1, $2, $3, $4) RETURNING id",
-		user.Username, user.Email, user.PasswordHash, user.Mobile,
+		"INSERT INTO users (username, email, password_hash, mobile, full_name) VALUES ($1, $2, $3, $4, NULLIF($5, '')) RETURNING id",
+		user.Username, user.Email, user.PasswordHash, user.Mobile, user.FullName,
 	).Scan(&user.ID)
 
 	if err != nil {
@@ -12,6 +12,7 @@ type User struct {
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
 	Mobile       string    `json:"mobile"`
+	FullName     string    `json:"full_name,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
@@ -0,0 +1,41 @@
+-- Migration 019: Crash Rounds
+-- Persists every Crash round with its provably fair seeds and all player bets
+
+-- 1. Create crash_rounds table
+CREATE TABLE IF NOT EXISTS crash_rounds (
+    round_id VARCHAR(255) PRIMARY KEY,
+    session_id VARCHAR(255) NOT NULL,
+    nonce INT NOT NULL,
+    server_seed VARCHAR(128) NOT NULL,
+    server_seed_hash VARCHAR(128) NOT NULL,
+    client_seed VARCHAR(128) NOT NULL,
+    chain_commitment VARCHAR(128) NOT NULL, -- Published hash of the first seed in the chain
+    crash_point DECIMAL(12,2) NOT NULL,
+    total_wagered DECIMAL(14,2) NOT NULL DEFAULT 0,
+    total_payout DECIMAL(14,2) NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL,
+    crashed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_crash_rounds_session ON crash_rounds(session_id, crashed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_crash_rounds_seed_hash ON crash_rounds(server_seed_hash);
+
+-- 2. Create crash_bets table
+CREATE TABLE IF NOT EXISTS crash_bets (
+    id SERIAL PRIMARY KEY,
+    round_id VARCHAR(255) NOT NULL REFERENCES crash_rounds(round_id),
+    user_id VARCHAR(255) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    auto_cashout DECIMAL(12,2) DEFAULT 0,
+    cashout_at DECIMAL(12,2) DEFAULT 0, -- 0 = lost
+    payout DECIMAL(14,2) DEFAULT 0,
+    capped BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_crash_bets_round ON crash_bets(round_id);
+CREATE INDEX IF NOT EXISTS idx_crash_bets_user ON crash_bets(user_id);
+
+COMMENT ON TABLE crash_rounds IS 'Completed Crash rounds with revealed hash-chain seeds';
+COMMENT ON TABLE crash_bets IS 'Player bets and cashouts per Crash round';
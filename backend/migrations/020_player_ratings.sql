@@ -0,0 +1,36 @@
+-- Migration 020: Player Ratings
+-- Glicko-2 skill ratings per player per game, with the change from every rated session
+
+-- 1. Create player_ratings table
+CREATE TABLE IF NOT EXISTS player_ratings (
+    user_id VARCHAR(255) NOT NULL,
+    game_id VARCHAR(100) NOT NULL,
+    rating DECIMAL(8,2) NOT NULL DEFAULT 1500,
+    rd DECIMAL(8,2) NOT NULL DEFAULT 350, -- Rating deviation
+    volatility DECIMAL(10,8) NOT NULL DEFAULT 0.06,
+    games_played INT NOT NULL DEFAULT 0,
+    wins INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, game_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_ratings_ladder ON player_ratings(game_id, rating DESC);
+
+-- 2. Create rating_history table
+CREATE TABLE IF NOT EXISTS rating_history (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    game_id VARCHAR(100) NOT NULL,
+    session_id VARCHAR(255) NOT NULL,
+    outcome VARCHAR(10) NOT NULL, -- WIN, LOSS
+    rating_before DECIMAL(8,2) NOT NULL,
+    rating_after DECIMAL(8,2) NOT NULL,
+    rd DECIMAL(8,2) NOT NULL,
+    volatility DECIMAL(10,8) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_history_player ON rating_history(user_id, game_id, created_at DESC);
+
+COMMENT ON TABLE player_ratings IS 'Current Glicko-2 rating per player per skill game';
+COMMENT ON TABLE rating_history IS 'Rating change from each rated session';
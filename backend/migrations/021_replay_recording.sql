@@ -0,0 +1,12 @@
+-- Migration 021: Replay Recording
+-- Every session's move log with the random inputs the game drew, so a replay can be checked for determinism
+
+-- 1. Columns needed to re-run a recorded session
+ALTER TABLE game_replays ADD COLUMN IF NOT EXISTS entry_fee DECIMAL(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE game_replays ADD COLUMN IF NOT EXISTS start_rng JSONB NOT NULL DEFAULT '[]'; -- Random inputs drawn as the game started
+
+-- 2. Latest replays first
+CREATE INDEX IF NOT EXISTS idx_replays_completed ON game_replays(completed_at DESC NULLS LAST);
+
+COMMENT ON COLUMN game_replays.moves IS 'Every accepted move with its timestamp and the random inputs it drew';
+COMMENT ON COLUMN game_replays.states IS 'Game state snapshot at the start and after each move';
@@ -0,0 +1,51 @@
+-- Migration 022: Anticheat Review
+-- Collusion alerts, the alert review queue, session history for the rules and account suspensions
+
+-- 1. Collusion alert types
+ALTER TABLE anticheat_alerts DROP CONSTRAINT IF EXISTS anticheat_alerts_alert_type_check;
+ALTER TABLE anticheat_alerts ADD CONSTRAINT anticheat_alerts_alert_type_check
+    CHECK (alert_type IN ('TIMING', 'WIN_RATE', 'INVALID_MOVE', 'STATISTICAL', 'COLLUSION', 'CHIP_DUMPING', 'SHARED_DEVICE'));
+
+-- 2. Review queue
+ALTER TABLE anticheat_alerts ADD COLUMN IF NOT EXISTS game_id VARCHAR(50);
+ALTER TABLE anticheat_alerts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'OPEN'
+    CHECK (status IN ('OPEN', 'CONFIRMED', 'DISMISSED'));
+ALTER TABLE anticheat_alerts ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(255);
+ALTER TABLE anticheat_alerts ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_status ON anticheat_alerts(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_alerts_user_severity ON anticheat_alerts(user_id, severity, created_at DESC);
+
+-- 3. Finished sessions as the rules see them (win rate, co-play, chip dumping)
+CREATE TABLE IF NOT EXISTS anticheat_sessions (
+    session_id VARCHAR(255) PRIMARY KEY,
+    game_id VARCHAR(50) NOT NULL,
+    players TEXT[] NOT NULL,
+    winners TEXT[] NOT NULL DEFAULT '{}',
+    moves JSONB NOT NULL DEFAULT '{}', -- userID -> moves made
+    completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_anticheat_sessions_players ON anticheat_sessions USING GIN(players);
+CREATE INDEX IF NOT EXISTS idx_anticheat_sessions_completed ON anticheat_sessions(completed_at DESC);
+
+-- 4. Shared address lookups
+CREATE INDEX IF NOT EXISTS idx_device_fingerprints_ip ON device_fingerprints(ip_address);
+
+-- 5. Accounts suspended by AutoBan, pending review
+CREATE TABLE IF NOT EXISTS account_suspensions (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'UPHELD', 'LIFTED')),
+    reviewed_by VARCHAR(255),
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_suspensions_user ON account_suspensions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_suspensions_pending ON account_suspensions(created_at DESC) WHERE status = 'ACTIVE';
+
+COMMENT ON TABLE anticheat_sessions IS 'Finished sessions kept for anticheat rules that look at play history';
+COMMENT ON TABLE account_suspensions IS 'Accounts suspended with wallets frozen by anticheat AutoBan, and their review';
@@ -0,0 +1,15 @@
+-- Migration 023: Tournament Lifecycle
+-- Registration windows and the game session each bracket match is played in
+
+-- 1. Registration opens at registration_opens and closes at start_time
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS registration_opens TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tournaments_due ON tournaments(start_time) WHERE status = 'REGISTRATION';
+
+-- 2. Winners are advanced when the match's session ends
+ALTER TABLE tournament_matches ADD COLUMN IF NOT EXISTS session_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_session ON tournament_matches(session_id) WHERE session_id IS NOT NULL;
+
+-- 3. Participants of cancelled tournaments are refunded
+COMMENT ON COLUMN tournament_participants.status IS 'REGISTERED, ELIMINATED, WINNER or REFUNDED';
@@ -0,0 +1,15 @@
+-- Migration 024: Tournament Formats
+-- Double elimination, round-robin and Swiss tournaments
+
+-- 1. Double elimination: which bracket a match is in and where its loser drops to
+ALTER TABLE tournament_matches ADD COLUMN IF NOT EXISTS bracket VARCHAR(20); -- WINNERS, LOSERS, GRAND_FINAL; NULL for single-bracket formats
+ALTER TABLE tournament_matches ADD COLUMN IF NOT EXISTS loser_next_match_id UUID REFERENCES tournament_matches(id);
+
+-- 2. Game scores, for the score difference tie-breaker
+ALTER TABLE tournament_matches ADD COLUMN IF NOT EXISTS player_1_score INT NOT NULL DEFAULT 0;
+ALTER TABLE tournament_matches ADD COLUMN IF NOT EXISTS player_2_score INT NOT NULL DEFAULT 0;
+
+-- 3. Seeds, for round-robin scheduling and the last tie-breaker
+ALTER TABLE tournament_participants ADD COLUMN IF NOT EXISTS seed INT;
+
+COMMENT ON COLUMN tournaments.config IS 'Bracket type (SINGLE_ELIMINATION, DOUBLE_ELIMINATION, ROUND_ROBIN, SWISS), Swiss rounds, tie-breakers and prize rules';
@@ -0,0 +1,9 @@
+-- Migration 025: Tournament Payouts
+-- Payout tables, entry-funded pools with rake, and house overlay
+
+-- 1. What the house kept from entry fees and what it added to meet the pool
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS rake_amount DECIMAL(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS overlay_amount DECIMAL(12,2) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN tournaments.prize_pool IS 'Fixed prize pool, or the guaranteed minimum when config.pool_from_entries is set';
+COMMENT ON COLUMN tournaments.config IS 'Bracket type, Swiss rounds, tie-breakers and prize rules (prize_strategy, payout_table such as "1:25%, 2:15%, 3-5:5%", pool_from_entries, rake_percent, tied_ranks)';
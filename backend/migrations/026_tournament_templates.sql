@@ -0,0 +1,34 @@
+-- Migration 026: Tournament Templates
+-- Sit-and-go and scheduled tournaments created over and over from a template
+
+-- 1. Templates
+CREATE TABLE IF NOT EXISTS tournament_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- SIT_AND_GO, SCHEDULED
+    game_type VARCHAR(50) NOT NULL,
+    entry_fee DECIMAL(10,2) NOT NULL,
+    prize_pool DECIMAL(12,2) NOT NULL,
+    max_players INT NOT NULL,
+    config JSONB NOT NULL,
+    schedule VARCHAR(100), -- Cron expression, scheduled templates only
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    registration_minutes INT NOT NULL, -- Scheduled: opens before the start; sit-and-go: time to fill
+    late_registration_minutes INT NOT NULL DEFAULT 0,
+    max_entries INT NOT NULL DEFAULT 0, -- Per user per day; 0 = no limit
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_templates_active ON tournament_templates(created_at) WHERE active;
+
+-- 2. Tournaments remember their template, and may take late entries after the start
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES tournament_templates(id);
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS registration_closes TIMESTAMP;
+
+UPDATE tournaments SET registration_closes = start_time WHERE registration_closes IS NULL;
+ALTER TABLE tournaments ALTER COLUMN registration_closes SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tournaments_template ON tournaments(template_id, status) WHERE template_id IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Migration 027: Fantasy Contests
+-- Contest rules, lineup lock at match start, refunds and payouts
+
+-- 1. Contest rules
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS guaranteed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS min_teams INT; -- Cancelled and refunded below this unless guaranteed
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS max_entries_per_user INT NOT NULL DEFAULT 1;
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS payout_table VARCHAR(500) NOT NULL DEFAULT '1:100%';
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS start_time TIMESTAMP; -- Match start, when lineups lock
+
+UPDATE fantasy_contests SET min_teams = max_teams WHERE min_teams IS NULL;
+UPDATE fantasy_contests SET start_time = created_at WHERE start_time IS NULL;
+ALTER TABLE fantasy_contests ALTER COLUMN min_teams SET NOT NULL;
+ALTER TABLE fantasy_contests ALTER COLUMN start_time SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_fantasy_contests_lock ON fantasy_contests(start_time) WHERE status = 'OPEN';
+
+-- 2. Entries: refunded when a contest is cancelled, paid when it completes
+ALTER TABLE fantasy_teams ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'JOINED'; -- JOINED, REFUNDED
+ALTER TABLE fantasy_teams ADD COLUMN IF NOT EXISTS prize_amount DECIMAL(12,2) NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_fantasy_teams_contest_user ON fantasy_teams(contest_id, user_id);
+
+COMMENT ON COLUMN fantasy_contests.status IS 'OPEN, LIVE (lineups locked), COMPLETED or CANCELLED';
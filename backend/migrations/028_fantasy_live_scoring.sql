@@ -0,0 +1,12 @@
+-- Migration 028: Fantasy Live Scoring
+-- Final player stats of settled matches, kept so scoring corrections can rerank and adjust payouts
+
+CREATE TABLE IF NOT EXISTS fantasy_match_stats (
+    match_id VARCHAR(255) NOT NULL,
+    player_id VARCHAR(255) NOT NULL,
+    stats JSONB NOT NULL, -- PlayerStats: runs, balls_faced, wickets, ...
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (match_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fantasy_contests_live ON fantasy_contests(match_id) WHERE status = 'LIVE';
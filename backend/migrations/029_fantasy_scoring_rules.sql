@@ -0,0 +1,15 @@
+-- Migration 029: Fantasy Scoring Rules
+-- Versioned points systems per format; a contest keeps the version current when it opened
+
+CREATE TABLE IF NOT EXISTS fantasy_scoring_rules (
+    format VARCHAR(20) NOT NULL, -- T20, ODI, TEST, T10, ...
+    version INT NOT NULL,
+    sport VARCHAR(20) NOT NULL, -- CRICKET, FOOTBALL, KABADDI
+    rules JSONB NOT NULL, -- ScoringRules: points, milestones, strike-rate and economy bands
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (format, version)
+);
+
+-- Version 1 of each built-in format is its default in code
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS format VARCHAR(20) NOT NULL DEFAULT 'T20';
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS rules_version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,10 @@
+-- Migration 030: Private Fantasy Leagues
+-- User-created contests shared by invite code, paying their entry fees less the platform's commission
+
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS private BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS creator_id VARCHAR(255);
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS invite_code VARCHAR(16);
+ALTER TABLE fantasy_contests ADD COLUMN IF NOT EXISTS commission_percent DECIMAL(5,2) NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fantasy_contests_invite ON fantasy_contests(invite_code) WHERE invite_code IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_fantasy_contests_creator ON fantasy_contests(creator_id) WHERE private;
@@ -0,0 +1,14 @@
+-- Migration 031: Fantasy Match Players
+-- Each match's player pool with its credits and, once announced, who is in the playing XI
+
+CREATE TABLE IF NOT EXISTS fantasy_match_players (
+    match_id VARCHAR(255) NOT NULL,
+    player_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    team VARCHAR(50) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    credits DECIMAL(5,2) NOT NULL,
+    lineup VARCHAR(20), -- PLAYING or NOT_PLAYING once the XI is announced
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (match_id, player_id)
+);
@@ -0,0 +1,24 @@
+-- Migration 032: KYC Document Storage
+-- Uploaded ID images and PDFs, encrypted in a blob store with retention-based deletion
+
+-- Files now live in kyc_documents; image_url is kept for earlier requests
+ALTER TABLE kyc_requests ALTER COLUMN image_url DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS kyc_documents (
+    id UUID PRIMARY KEY,
+    request_id UUID NOT NULL REFERENCES kyc_requests(id),
+    user_id VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL, -- image/jpeg, image/png, application/pdf
+    size INT NOT NULL,
+    sha256 VARCHAR(64) NOT NULL, -- Of the plaintext file
+    blob_key VARCHAR(255) NOT NULL, -- Encrypted file in the blob store
+    delete_after TIMESTAMP, -- Set when the request is reviewed
+    deleted_at TIMESTAMP, -- File removed; the row stays as a record
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_request ON kyc_documents(request_id);
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_retention ON kyc_documents(delete_after) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE kyc_documents IS 'KYC document files (encrypted blobs) and their retention';
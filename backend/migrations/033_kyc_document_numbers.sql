@@ -0,0 +1,15 @@
+-- Migration 033: KYC Document Numbers
+-- Keyed hashes for deduplication, masked numbers for display, and profile names for matching
+
+ALTER TABLE kyc_requests ADD COLUMN IF NOT EXISTS document_hash VARCHAR(64); -- HMAC of the normalized number
+ALTER TABLE kyc_requests ADD COLUMN IF NOT EXISTS document_masked VARCHAR(20); -- e.g. XXXXXX234F
+ALTER TABLE kyc_requests ADD COLUMN IF NOT EXISTS name_match_score DECIMAL(4,3);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_document_hash ON kyc_requests(document_hash);
+
+-- One document can verify only one account
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_document_verified ON kyc_requests(document_hash)
+    WHERE status = 'VERIFIED';
+
+-- Legal name, matched against the name on KYC documents
+ALTER TABLE users ADD COLUMN IF NOT EXISTS full_name VARCHAR(255);
@@ -0,0 +1,43 @@
+-- Migration 034: AML Transaction Monitoring
+-- Versioned rule sets, cases for the compliance queue, and a record of every ledger scan
+
+CREATE TABLE IF NOT EXISTS aml_rule_sets (
+    version INT PRIMARY KEY, -- Version 1 is built in until stored
+    rules JSONB NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS aml_cases (
+    id UUID PRIMARY KEY,
+    rule_id VARCHAR(50) NOT NULL, -- DEPOSIT_WITHDRAWAL_MINIMAL_PLAY, STRUCTURING, P2P_ROUND_TRIP, DORMANCY_SPIKE
+    rule_version INT NOT NULL, -- The rule set version that fired
+    user_id VARCHAR(255) NOT NULL,
+    counterparty_id VARCHAR(255), -- The other user of a round trip
+    dedupe_key VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN', -- OPEN, ESCALATED, DISMISSED, REPORTED
+    evidence JSONB NOT NULL, -- Summary, ledger transaction IDs and figures
+    triggered_at TIMESTAMP NOT NULL,
+    reviewed_by VARCHAR(255),
+    review_notes TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (rule_id, dedupe_key) -- Rescans and replays don't raise a match twice
+);
+
+CREATE INDEX IF NOT EXISTS idx_aml_cases_status ON aml_cases(status, triggered_at);
+CREATE INDEX IF NOT EXISTS idx_aml_cases_user ON aml_cases(user_id);
+
+CREATE TABLE IF NOT EXISTS aml_scans (
+    id SERIAL PRIMARY KEY,
+    scan_from TIMESTAMP NOT NULL,
+    scan_to TIMESTAMP NOT NULL,
+    rule_version INT NOT NULL,
+    matches INT NOT NULL,
+    created INT NOT NULL,
+    scanned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_created_at ON ledger(created_at);
+
+COMMENT ON TABLE aml_cases IS 'AML monitoring matches awaiting compliance review';
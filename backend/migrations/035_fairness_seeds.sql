@@ -0,0 +1,26 @@
+-- Migration 035: Fairness Seeds
+-- Active provably fair seed pairs and every revealed server seed, so nonces survive
+-- restarts and any past round can be verified by its server seed hash
+
+-- 1. Active seed pairs, one per owner (a user or a shared table)
+CREATE TABLE IF NOT EXISTS fairness_seed_pairs (
+    owner_id VARCHAR(255) PRIMARY KEY,
+    server_seed VARCHAR(128) NOT NULL, -- Secret until the pair is rotated
+    server_seed_hash VARCHAR(128) NOT NULL,
+    client_seed VARCHAR(128) NOT NULL,
+    nonce INT NOT NULL DEFAULT 0, -- Nonce of the last round played
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 2. Revealed seeds: rotated pairs and Crash hash-chain rounds
+CREATE TABLE IF NOT EXISTS fairness_revealed_seeds (
+    server_seed_hash VARCHAR(128) PRIMARY KEY,
+    server_seed VARCHAR(128) NOT NULL,
+    client_seed VARCHAR(128) NOT NULL,
+    last_nonce INT NOT NULL,
+    revealed_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE fairness_seed_pairs IS 'Active provably fair seed pairs with their current nonce';
+COMMENT ON TABLE fairness_revealed_seeds IS 'Server seeds revealed by rotation or a finished Crash round, by hash';
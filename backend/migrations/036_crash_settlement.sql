@@ -0,0 +1,29 @@
+-- Migration 036: Crash Chains and Unsettled Credits
+-- Stores each Crash table's hash chain so it can resume without replaying a seed,
+-- and the credits (winnings, refunds) the wallet had not accepted at shutdown
+
+-- 1. Hash chains; the terminal seed regenerates the chain and stays secret
+CREATE TABLE IF NOT EXISTS fairness_hash_chains (
+    commitment VARCHAR(128) PRIMARY KEY, -- Published hash of the first seed
+    owner_id VARCHAR(255) NOT NULL,
+    terminal_seed VARCHAR(128) NOT NULL,
+    length INT NOT NULL,
+    salt VARCHAR(128) NOT NULL,
+    position INT NOT NULL DEFAULT 0, -- Rounds handed out so far
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fairness_hash_chains_owner ON fairness_hash_chains(owner_id, created_at DESC);
+
+-- 2. Credits still owed when the game engine stopped, paid on the next start
+CREATE TABLE IF NOT EXISTS unsettled_credits (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    amount DECIMAL(14,2) NOT NULL,
+    reference_id VARCHAR(255) NOT NULL,
+    reference_type VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE fairness_hash_chains IS 'Crash hash chains with how far each has been played';
+COMMENT ON TABLE unsettled_credits IS 'Winnings and refunds the wallet had not accepted at shutdown';
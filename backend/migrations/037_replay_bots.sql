@@ -0,0 +1,6 @@
+-- Migration 037: Replay and Anticheat Bots
+-- Records which players of a session were server-side bots, so replays and the
+-- anticheat history no longer infer it from the user ID
+
+ALTER TABLE game_replays ADD COLUMN IF NOT EXISTS bots TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE anticheat_sessions ADD COLUMN IF NOT EXISTS bots TEXT[] NOT NULL DEFAULT '{}';
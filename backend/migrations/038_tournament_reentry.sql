@@ -0,0 +1,12 @@
+-- Migration 038: Tournament Re-entry
+-- Entry limits are per tournament: a player in a running Swiss tournament may
+-- buy another entry while late registration is open, up to max_entries
+
+-- 1. Tournaments carry their template's limit; template max_entries now means per tournament
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS max_entries INT NOT NULL DEFAULT 1;
+
+UPDATE tournament_templates SET max_entries = 0 WHERE max_entries > 1 AND late_registration_minutes = 0;
+
+-- 2. Entries each participant paid for, and the round their latest one counts from
+ALTER TABLE tournament_participants ADD COLUMN IF NOT EXISTS entries INT NOT NULL DEFAULT 1;
+ALTER TABLE tournament_participants ADD COLUMN IF NOT EXISTS entered_round INT;
@@ -0,0 +1,8 @@
+-- Migration 039: KYC Document Hash Backfill
+-- Requests made before migration 033 have no document hash, so duplicate
+-- detection can't see them. Their numbers are encrypted with the payment
+-- service's key, which the database doesn't have: the service hashes them on
+-- startup (KYCService.BackfillDocumentHashes). This index finds what is left.
+
+CREATE INDEX IF NOT EXISTS idx_kyc_unhashed ON kyc_requests(created_at)
+    WHERE document_hash IS NULL AND status IN ('PENDING', 'VERIFIED');
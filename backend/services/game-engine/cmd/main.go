@@ -3,18 +3,40 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/playkaro/game-engine/games/andarbahar"
 	"github.com/playkaro/game-engine/games/crash"
 	"github.com/playkaro/game-engine/games/dice"
+	"github.com/playkaro/game-engine/games/fantasy"
 	"github.com/playkaro/game-engine/games/ludo"
+	"github.com/playkaro/game-engine/games/mines"
+	"github.com/playkaro/game-engine/games/plinko"
+	"github.com/playkaro/game-engine/games/roulette"
+	"github.com/playkaro/game-engine/games/rummy"
+	"github.com/playkaro/game-engine/games/teenpatti"
+	"github.com/playkaro/game-engine/internal/anticheat"
+	"github.com/playkaro/game-engine/internal/bots"
+	"github.com/playkaro/game-engine/internal/db"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
 	grpc_client "github.com/playkaro/game-engine/internal/grpc"
 	"github.com/playkaro/game-engine/internal/handlers"
+	"github.com/playkaro/game-engine/internal/matchmaking"
+	"github.com/playkaro/game-engine/internal/rating"
 	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/replay"
 	"github.com/playkaro/game-engine/internal/session"
+	"github.com/playkaro/game-engine/internal/settlement"
 	"github.com/playkaro/game-engine/internal/telemetry"
+	"github.com/playkaro/game-engine/internal/wallet"
+	"github.com/playkaro/game-engine/tournament"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
@@ -24,17 +46,125 @@ func main() {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	// Connect to Database (optional: games fall back to in-memory only)
+	var crashStore crash.RoundStore
+	var rouletteStore roulette.RoundStore
+	var ratingStore rating.Store = rating.NewMemoryStore()
+	var replayStore replay.Store = replay.NewMemoryStore()
+	var antiCheatStore anticheat.Store = anticheat.NewMemoryStore()
+	var tournamentStore tournament.Store = tournament.NewMemoryStore()
+	var contestStore fantasy.Store = fantasy.NewMemoryStore()
+	var seedStore fairness.SeedStore = fairness.NewMemoryStore()
+	var settlementStore settlement.Store
+	if err := db.Connect(); err != nil {
+		log.Printf("Failed to connect to database, round persistence disabled: %v", err)
+	} else {
+		defer db.DB.Close()
+		crashStore = crash.NewPostgresRoundStore(db.DB)
+		rouletteStore = roulette.NewPostgresRoundStore(db.DB)
+		ratingStore = rating.NewPostgresStore(db.DB)
+		replayStore = replay.NewPostgresStore(db.DB)
+		antiCheatStore = anticheat.NewPostgresStore(db.DB)
+		tournamentStore = tournament.NewPostgresStore(db.DB)
+		contestStore = fantasy.NewPostgresStore(db.DB)
+		seedStore = fairness.NewPostgresStore(db.DB)
+		settlementStore = settlement.NewPostgresStore(db.DB)
+	}
+
 	// Initialize Registry
 	reg := registry.GetRegistry()
 
+	// Provably fair seed pairs (per user and per shared table) and every revealed seed
+	seedManager := fairness.NewSeedManager(seedStore)
+
+	// Winnings, prizes and refunds are paid through one settler, which retries
+	// failed credits and saves the ones still unpaid at shutdown
+	settler := settlement.NewSettler(settlement.DefaultConfig(), wallet.NewWalletClient(), settlementStore)
+	settleCtx, stopSettler := context.WithCancel(context.Background())
+	settled := make(chan struct{})
+	go func() {
+		settler.Run(settleCtx)
+		close(settled)
+	}()
+
 	// Register built-in games
 	reg.RegisterGame(ludo.NewLudoGame())
-	reg.RegisterGame(crash.NewCrashGame())
-	reg.RegisterGame(dice.NewDiceGame())
+	reg.RegisterGame(crash.NewCrashGame(crashStore, seedManager, settler))
+	reg.RegisterGame(dice.NewDiceGame(seedManager))
+	reg.RegisterGame(mines.NewMinesGame(seedManager, settler))
+
+	reg.RegisterGame(plinko.NewPlinkoGame(seedManager, settler))
+	reg.RegisterGame(roulette.NewRouletteGame(seedManager, settler, rouletteStore))
+	reg.RegisterGame(andarbahar.NewAndarBaharGame(seedManager, settler))
+	reg.RegisterGame(rummy.NewRummyGame(rummy.PointsRummy(), seedManager))
+	reg.RegisterGame(rummy.NewRummyGame(rummy.Pool101Rummy(), seedManager))
+	reg.RegisterGame(rummy.NewRummyGame(rummy.Pool201Rummy(), seedManager))
+	reg.RegisterGame(rummy.NewRummyGame(rummy.DealsRummy(), seedManager))
+	reg.RegisterGame(teenpatti.NewTableGame(100, 5))
 	log.Println("Registered games:", reg.ListGames())
 
 	// Initialize Session Manager
 	sessionManager := session.NewSessionManager()
+	// Every session's moves are recorded for replays
+	sessionManager.SetReplayStore(replayStore)
+
+	// Skill ratings are updated whenever a session ends
+	ratingService := rating.NewService(ratingStore)
+	sessionManager.OnGameEnd(ratingService.HandleGameEnd)
+
+	// Anticheat watches every move and finished session; collusion is only
+	// possible in the multiplayer card games. Move timing is only checked in the
+	// turn-based games, where a person can't keep up a bot's pace (casino games
+	// allow auto-bets), and only flags for review: it never bans on its own.
+	detector := anticheat.NewDetector(anticheat.DefaultConfig(), antiCheatStore, wallet.NewWalletClient())
+	detector.RegisterDefault(anticheat.NewWinRateRule())
+	detector.Register("ludo_classic", anticheat.NewTimingRule(10, 400*time.Millisecond, anticheat.SeverityMedium))
+	for _, variant := range []rummy.Variant{rummy.PointsRummy(), rummy.Pool101Rummy(), rummy.Pool201Rummy(), rummy.DealsRummy()} {
+		detector.Register(variant.GameID, anticheat.CollusionRules()...)
+		detector.Register(variant.GameID, anticheat.NewTimingRule(20, time.Second, anticheat.SeverityMedium))
+	}
+	detector.Register("teen_patti", anticheat.CollusionRules()...)
+	detector.Register("teen_patti", anticheat.NewTimingRule(10, 500*time.Millisecond, anticheat.SeverityMedium))
+	sessionManager.OnMove(detector.HandleMove)
+	sessionManager.OnGameEnd(detector.HandleGameEnd)
+
+	// Matchmaking for skill games: Redis shares queues between instances
+	var matchBackend matchmaking.Backend = matchmaking.NewMemoryBackend()
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		redisBackend, err := matchmaking.NewRedisBackend(redisURL)
+		if err != nil {
+			log.Printf("Invalid REDIS_URL, matchmaking queues kept in memory: %v", err)
+		} else {
+			matchBackend = redisBackend
+		}
+	}
+	matchmaker := matchmaking.NewMatchmaker(matchmaking.DefaultConfig(), matchBackend, sessionManager, wallet.NewWalletClient(), ratingService, nil)
+	// Bots fill Ludo, Rummy and Teen Patti tables that wait too long for players
+	matchmaker.SetBots(bots.NewManager(bots.DefaultConfig(), sessionManager))
+	matchCtx, stopMatchmaker := context.WithCancel(context.Background())
+	defer stopMatchmaker()
+	go matchmaker.Run(matchCtx)
+
+	// Tournaments: brackets seeded by rating, winners advanced as match sessions end,
+	// sit-and-go and scheduled tournaments created from templates
+	tournamentManager := tournament.NewTournamentManager(tournamentStore, sessionManager, wallet.NewWalletClient(), settler)
+	tournamentManager.BracketGen = tournament.NewSeededBracketGenerator(ratingService)
+	sessionManager.OnGameEnd(tournamentManager.HandleGameEnd)
+	tournamentScheduler := tournament.NewScheduler(tournamentManager)
+	go tournamentScheduler.Run(matchCtx, 10*time.Second)
+
+	// Fantasy contests: lineups lock at match start, squads and stats from the cricket feed
+	cricketAPI := fantasy.NewCricketAPIClient(os.Getenv("CRICKET_API_KEY"))
+	contestManager := fantasy.NewContestManager(contestStore, cricketAPI, cricketAPI, wallet.NewWalletClient(), settler)
+	if replayDir := os.Getenv("FANTASY_REPLAY_DIR"); replayDir != "" {
+		// Live scoring from recorded ball-by-ball feeds, a ball every 2s like match-service's
+		// simulator, with playing XI announcements from the same directory
+		replayFeed := fantasy.NewFileReplayFeed(replayDir, 2*time.Second)
+		contestManager.Lineups = replayFeed
+		liveScorer := fantasy.NewLiveScorer(contestManager, replayFeed)
+		go liveScorer.Run(matchCtx, 10*time.Second)
+	}
+	go contestManager.Run(matchCtx, 10*time.Second)
 
 	// Initialize gRPC Clients
 	walletAddr := os.Getenv("WALLET_SERVICE_ADDR")
@@ -56,6 +186,13 @@ func main() {
 	// Initialize Handlers
 	gameHandler := handlers.NewGameHandler(sessionManager, grpcClients)
 	wsHandler := handlers.NewWebSocketHandler(sessionManager)
+	fairnessHandler := handlers.NewFairnessHandler(seedManager)
+	matchmakingHandler := handlers.NewMatchmakingHandler(matchmaker)
+	ratingHandler := handlers.NewRatingHandler(ratingService)
+	replayHandler := handlers.NewReplayHandler(replayStore)
+	antiCheatHandler := handlers.NewAntiCheatHandler(detector)
+	tournamentHandler := handlers.NewTournamentHandler(tournamentManager, tournamentScheduler)
+	fantasyHandler := handlers.NewFantasyHandler(contestManager)
 
 	// Initialize OpenTelemetry
 	shutdown, err := telemetry.InitTracer("game-engine", "otel-collector:4317")
@@ -92,21 +229,101 @@ func main() {
 		// Game Catalog
 		v1.GET("/games", gameHandler.ListGames)
 
+		// Provably Fair verification is public so anyone can audit a round
+		v1.POST("/fairness/verify", fairnessHandler.Verify)
+
+		// Skill ratings
+		v1.GET("/ratings/:game_id/ladder", ratingHandler.GetLadder)
+		v1.GET("/ratings/:game_id/players/:user_id", ratingHandler.GetPlayerRating)
+
+		// Tournaments
+		v1.GET("/tournaments", tournamentHandler.ListTournaments)
+		v1.GET("/tournaments/:tournament_id", tournamentHandler.GetTournament)
+		v1.GET("/tournaments/:tournament_id/bracket", tournamentHandler.GetBracket)
+		v1.GET("/tournaments/:tournament_id/standings", tournamentHandler.GetStandings)
+
+		// Fantasy contests
+		v1.GET("/fantasy/matches/:match_id/contests", fantasyHandler.ListContests)
+		v1.GET("/fantasy/matches/:match_id/players", fantasyHandler.GetSquad)
+		v1.GET("/fantasy/contests/:contest_id", fantasyHandler.GetContest)
+		v1.GET("/fantasy/contests/:contest_id/selections", fantasyHandler.GetSelections)
+		v1.GET("/fantasy/scoring-rules/:format", fantasyHandler.GetScoringRules)
+
 		// Session Management
 		// In production, use middleware to extract userID from JWT
 		// For demo, we'll simulate auth via header
 		authorized := v1.Group("")
-		authorized.Use(MockAuthMiddleware())
+		authorized.Use(MockAuthMiddleware(), antiCheatHandler.Guard())
 		{
 			authorized.POST("/games/sessions", gameHandler.CreateSession)
 			authorized.POST("/sessions/:session_id/join", gameHandler.JoinSession)
 			authorized.POST("/sessions/:session_id/move", gameHandler.MakeMove)
 			authorized.GET("/sessions/:session_id", gameHandler.GetSessionState)
+			authorized.GET("/sessions/:session_id/view", gameHandler.GetPlayerView)
+
+			// Provably Fair seed management
+			authorized.GET("/fairness/seed", fairnessHandler.GetActiveSeed)
+			authorized.PUT("/fairness/client-seed", fairnessHandler.SetClientSeed)
+			authorized.POST("/fairness/rotate", fairnessHandler.RotateSeed)
+
+			// Matchmaking
+			authorized.POST("/matchmaking/queue", matchmakingHandler.Enqueue)
+			authorized.GET("/matchmaking/tickets/:ticket_id", matchmakingHandler.GetTicket)
+			authorized.DELETE("/matchmaking/tickets/:ticket_id", matchmakingHandler.CancelTicket)
+
+			// Tournament registration
+			authorized.POST("/tournaments/:tournament_id/register", tournamentHandler.Register)
+
+			// Fantasy entries and lineups
+			authorized.POST("/fantasy/contests/:contest_id/join", fantasyHandler.JoinContest)
+			authorized.PUT("/fantasy/teams/:team_id", fantasyHandler.EditLineup)
+
+			// Private leagues
+			authorized.POST("/fantasy/leagues", fantasyHandler.CreateLeague)
+			authorized.GET("/fantasy/leagues/:invite_code", fantasyHandler.GetLeague)
+			authorized.POST("/fantasy/leagues/:invite_code/join", fantasyHandler.JoinLeague)
+			authorized.POST("/fantasy/contests/:contest_id/lock", fantasyHandler.LockLeague)
+			authorized.POST("/fantasy/contests/:contest_id/cancel", fantasyHandler.CancelLeague)
+
+			// Replays of finished sessions
+			authorized.GET("/replays", replayHandler.ListReplays)
+			authorized.GET("/replays/:session_id", replayHandler.GetReplay)
+			authorized.GET("/replays/:session_id/frame", replayHandler.GetFrame)
+			authorized.POST("/replays/:session_id/verify", replayHandler.VerifyReplay)
+		}
+
+		// Anticheat review queue
+		admin := v1.Group("/admin")
+		admin.Use(MockAdminMiddleware())
+		{
+			admin.GET("/anticheat/alerts", antiCheatHandler.ListAlerts)
+			admin.POST("/anticheat/alerts/:alert_id/review", antiCheatHandler.ReviewAlert)
+			admin.GET("/anticheat/suspensions", antiCheatHandler.ListSuspensions)
+			admin.POST("/anticheat/suspensions/:user_id/review", antiCheatHandler.ReviewSuspension)
+
+			// Tournament lifecycle
+			admin.POST("/tournaments", tournamentHandler.CreateTournament)
+			admin.POST("/tournaments/:tournament_id/start", tournamentHandler.StartTournament)
+			admin.POST("/tournaments/:tournament_id/cancel", tournamentHandler.CancelTournament)
+			admin.POST("/tournaments/matches/:match_id/result", tournamentHandler.ReportResult)
+			admin.GET("/tournaments/templates", tournamentHandler.ListTemplates)
+			admin.POST("/tournaments/templates", tournamentHandler.CreateTemplate)
+			admin.POST("/tournaments/templates/:template_id/active", tournamentHandler.SetTemplateActive)
+
+			// Fantasy contests
+			admin.POST("/fantasy/contests", fantasyHandler.CreateContest)
+			admin.POST("/fantasy/contests/:contest_id/cancel", fantasyHandler.CancelContest)
+			admin.POST("/fantasy/matches/:match_id/complete", fantasyHandler.CompleteMatch)
+			admin.POST("/fantasy/matches/:match_id/corrections", fantasyHandler.CorrectStats)
+			admin.POST("/fantasy/scoring-rules", fantasyHandler.PublishScoringRules)
+			admin.PUT("/fantasy/matches/:match_id/players", fantasyHandler.IngestSquad)
+			admin.POST("/fantasy/matches/:match_id/lineup", fantasyHandler.AnnounceLineup)
 		}
 	}
 
 	// WebSocket
 	r.GET("/ws/sessions/:session_id", wsHandler.HandleWebSocket)
+	r.GET("/ws/fantasy/contests/:contest_id", fantasyHandler.StreamRanks)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -114,10 +331,29 @@ func main() {
 		port = "8083"
 	}
 
-	log.Printf("Game Engine Service starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := &http.Server{Addr: ":" + port, Handler: r}
+	go func() {
+		log.Printf("Game Engine Service starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// On shutdown, stop taking requests, close the live tables so open bets are
+	// refunded, then let the settler pay or save what is owed
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	<-stop.Done()
+	log.Println("Shutting down game engine")
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP shutdown: %v", err)
 	}
+	sessionManager.CloseSharedTables()
+	stopSettler()
+	<-settled
 }
 
 func MockAuthMiddleware() gin.HandlerFunc {
@@ -126,7 +362,30 @@ func MockAuthMiddleware() gin.HandlerFunc {
 		if userID == "" {
 			userID = "demo_user" // Fallback for easy testing
 		}
+		if engine.ReservedUserID(userID) {
+			// Bot IDs are issued by the server; a client must never pass as one
+			c.JSON(401, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
 		c.Set("userID", userID)
 		c.Next()
 	}
 }
+
+func MockAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// TODO: Implement proper admin JWT validation
+		if c.GetHeader("X-Admin-Key") == "" {
+			c.JSON(401, gin.H{"error": "Unauthorized - Admin access required"})
+			c.Abort()
+			return
+		}
+		adminID := c.GetHeader("X-Admin-ID")
+		if adminID == "" {
+			adminID = "admin"
+		}
+		c.Set("adminID", adminID)
+		c.Next()
+	}
+}
@@ -0,0 +1,212 @@
+// Command simulate certifies the RTP of the engine's games of chance.
+//
+//	simulate -game all -rounds 1000000 -json rtp.json -csv rtp.csv
+//	simulate -game dice_classic -strategy martingale -bet '{"amount":100,"target":50,"condition":"UNDER"}'
+//
+// Every round is drawn from the provably fair RNG with a fixed seed pair and the
+// round number as nonce, so the same flags always give the same report. The
+// command exits non-zero if any run FAILs or a game declares a theoretical RTP
+// above 1; high-variance bets may need more rounds than the default to move from
+// INCONCLUSIVE to PASS.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/playkaro/game-engine/games/andarbahar"
+	"github.com/playkaro/game-engine/games/crash"
+	"github.com/playkaro/game-engine/games/dice"
+	"github.com/playkaro/game-engine/games/mines"
+	"github.com/playkaro/game-engine/games/plinko"
+	"github.com/playkaro/game-engine/games/roulette"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/simulation"
+)
+
+// certificationBets is the default suite per game: the main bet types and the
+// extremes of each game's configuration. Stakes are large enough that rounding
+// payouts down to the cent does not show up in the RTP.
+var certificationBets = map[string][]string{
+	"dice_classic": {
+		`{"amount":100,"target":50,"condition":"UNDER"}`,
+		`{"amount":100,"target":2,"condition":"UNDER"}`,
+		`{"amount":100,"target":95,"condition":"OVER"}`,
+	},
+	"crash_aviator": {
+		`{"amount":100,"auto_cashout":1.5}`,
+		`{"amount":100,"auto_cashout":2}`,
+		`{"amount":100,"auto_cashout":10}`,
+	},
+	"mines_classic": {
+		`{"amount":100,"mines":1,"reveals":5}`,
+		`{"amount":100,"mines":3,"reveals":3}`,
+		`{"amount":100,"mines":10,"reveals":2}`,
+	},
+	"plinko_classic": {
+		`{"amount":100,"rows":8,"risk":"LOW"}`,
+		`{"amount":100,"rows":12,"risk":"MEDIUM"}`,
+		`{"amount":100,"rows":16,"risk":"HIGH"}`,
+	},
+	"roulette_european": {
+		`{"bets":[{"type":"STRAIGHT","numbers":[17],"amount":100}]}`,
+		`{"bets":[{"type":"RED","amount":100}]}`,
+		`{"bets":[{"type":"DOZEN","target":2,"amount":100},{"type":"SPLIT","numbers":[0,1],"amount":50}]}`,
+	},
+	"andar_bahar": {
+		`{"bet_type":"ANDAR","amount":100}`,
+		`{"bet_type":"BAHAR","amount":100}`,
+		`{"bet_type":"SIDE_1_5","amount":100}`,
+		`{"bet_type":"SIDE_41_PLUS","amount":100}`,
+	},
+}
+
+func main() {
+	gameID := flag.String("game", "all", "game ID to certify, or all")
+	rounds := flag.Int("rounds", 1000000, "rounds per run")
+	strategy := flag.String("strategy", "flat", fmt.Sprintf("betting strategy %v", simulation.StrategyNames()))
+	betJSON := flag.String("bet", "", "bet payload as JSON (default: the game's certification suite)")
+	serverSeed := flag.String("server-seed", "playkaro-rtp-certification", "server seed for the run")
+	clientSeed := flag.String("client-seed", "simulation", "client seed for the run")
+	workers := flag.Int("workers", 0, "parallel workers (0 = one per CPU)")
+	tolerance := flag.Float64("tolerance", 0.005, "allowed |simulated - theoretical| RTP")
+	confidence := flag.Float64("confidence", 0.99, "confidence level of the interval")
+	jsonPath := flag.String("json", "", "write the JSON report to this file")
+	csvPath := flag.String("csv", "", "write the CSV report to this file")
+	flag.Parse()
+
+	reg := registry.GetRegistry()
+	registerGames(reg)
+
+	games, err := selectGames(reg, *gameID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reports := []*simulation.Report{}
+	for _, game := range games {
+		bets := certificationBets[game.GetGameID()]
+		if *betJSON != "" {
+			bets = []string{*betJSON}
+		}
+		if len(bets) == 0 {
+			log.Fatalf("%s: no certification bets; pass -bet", game.GetGameID())
+		}
+
+		for _, raw := range bets {
+			var bet map[string]interface{}
+			if err := json.Unmarshal([]byte(raw), &bet); err != nil {
+				log.Fatalf("%s: invalid bet %s: %v", game.GetGameID(), raw, err)
+			}
+
+			report, err := simulation.Run(game, simulation.Config{
+				Rounds:     *rounds,
+				Workers:    *workers,
+				ServerSeed: *serverSeed,
+				ClientSeed: *clientSeed,
+				Strategy:   *strategy,
+				Bet:        bet,
+				Tolerance:  *tolerance,
+				Confidence: *confidence,
+			})
+			if err != nil {
+				log.Fatalf("%s: %v", game.GetGameID(), err)
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	printSummary(reports)
+
+	if *jsonPath != "" {
+		if err := writeFile(*jsonPath, func(f *os.File) error { return simulation.WriteJSON(f, reports) }); err != nil {
+			log.Fatal("Failed to write JSON report:", err)
+		}
+	}
+	if *csvPath != "" {
+		if err := writeFile(*csvPath, func(f *os.File) error { return simulation.WriteCSV(f, reports) }); err != nil {
+			log.Fatal("Failed to write CSV report:", err)
+		}
+	}
+
+	for _, r := range reports {
+		if r.Status == simulation.StatusInconclusive {
+			log.Printf("%s %v: inconclusive after %d rounds; rerun with more -rounds", r.GameID, r.Bet, r.Rounds)
+		}
+	}
+	if simulation.Failed(reports) {
+		os.Exit(1)
+	}
+}
+
+// registerGames registers the certifiable games as cmd/main does, without persistence
+func registerGames(reg *registry.GameRegistry) {
+	seedManager := fairness.NewSeedManager(nil)
+
+	reg.RegisterGame(crash.NewCrashGame(nil, seedManager, nil))
+	reg.RegisterGame(dice.NewDiceGame(seedManager))
+	reg.RegisterGame(mines.NewMinesGame(seedManager, nil))
+
+	reg.RegisterGame(plinko.NewPlinkoGame(seedManager, nil))
+	reg.RegisterGame(roulette.NewRouletteGame(seedManager, nil, nil))
+	reg.RegisterGame(andarbahar.NewAndarBaharGame(seedManager, nil))
+}
+
+func selectGames(reg *registry.GameRegistry, gameID string) ([]engine.CertifiableGame, error) {
+	ids := []string{gameID}
+	if gameID == "all" {
+		ids = nil
+		for _, meta := range reg.ListGames() {
+			ids = append(ids, meta["game_id"].(string))
+		}
+		sort.Strings(ids)
+	}
+
+	games := []engine.CertifiableGame{}
+	for _, id := range ids {
+		game, err := reg.GetGame(id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", id, err)
+		}
+		certifiable, ok := game.(engine.CertifiableGame)
+		if !ok {
+			if gameID == "all" {
+				continue
+			}
+			return nil, fmt.Errorf("%s does not support RTP simulation", id)
+		}
+		games = append(games, certifiable)
+	}
+	return games, nil
+}
+
+func printSummary(reports []*simulation.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GAME\tBET\tROUNDS\tRTP\tTHEORETICAL\tCI\tHIT FREQ\tMAX WIN\tSTATUS")
+	for _, r := range reports {
+		bet, _ := json.Marshal(r.Bet)
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.4f%%\t%.4f%%\t[%.4f%%, %.4f%%]\t%.2f%%\t%.2fx\t%s\n",
+			r.GameID, bet, r.Rounds, r.RTP*100, r.TheoreticalRTP*100, r.CILow*100, r.CIHigh*100,
+			r.HitFrequency*100, r.MaxMultiplier, r.Status)
+	}
+	w.Flush()
+}
+
+func writeFile(path string, write func(*os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/simulation"
+)
+
+// TestCertificationSuite runs every certification bet for a short, fixed-seed
+// run and fails if a game's simulated RTP is significantly out of tolerance of
+// what it declares, or if it declares more than 100%. The full certification
+// uses the command's million-round default.
+func TestCertificationSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("simulates every certification bet")
+	}
+
+	reg := registry.GetRegistry()
+	registerGames(reg)
+	games, err := selectGames(reg, "all")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, game := range games {
+		bets := certificationBets[game.GetGameID()]
+		if len(bets) == 0 {
+			t.Errorf("%s has no certification bets", game.GetGameID())
+		}
+		for _, raw := range bets {
+			var bet map[string]interface{}
+			if err := json.Unmarshal([]byte(raw), &bet); err != nil {
+				t.Fatalf("%s: invalid bet %s: %v", game.GetGameID(), raw, err)
+			}
+
+			report, err := simulation.Run(game, simulation.Config{
+				Rounds:     20000,
+				ServerSeed: "playkaro-rtp-certification",
+				ClientSeed: "simulation",
+				Strategy:   "flat",
+				Bet:        bet,
+				Tolerance:  0.005,
+				Confidence: 0.99,
+			})
+			if err != nil {
+				t.Errorf("%s %s: %v", game.GetGameID(), raw, err)
+				continue
+			}
+			if report.Status == simulation.StatusFail {
+				t.Errorf("%s %s: RTP %.4f, theoretical %.4f, CI [%.4f, %.4f]",
+					game.GetGameID(), raw, report.RTP, report.TheoreticalRTP, report.CILow, report.CIHigh)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package andarbahar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// Wallet takes stakes (implemented by wallet.WalletClient). Winnings and
+// refunds are paid through the settler.
+type Wallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+}
+
+type AndarBaharGame struct {
+	gameID   string
+	entryFee float64
+	config   Config
+	wallet   Wallet
+	seeds    *fairness.SeedManager
+	settler  *settlement.Settler
+
+	tables map[string]*runningTable // sessionID -> table
+	mu     sync.Mutex
+}
+
+type runningTable struct {
+	*Table
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// NewAndarBaharGame creates the game. settler pays winnings and refunds and must
+// be running while tables are.
+func NewAndarBaharGame(seeds *fairness.SeedManager, settler *settlement.Settler) *AndarBaharGame {
+	return &AndarBaharGame{
+		gameID:   "andar_bahar",
+		entryFee: 10.0, // Min bet
+		config:   DefaultConfig(),
+		wallet:   wallet.NewWalletClient(),
+		seeds:    seeds,
+		settler:  settler,
+		tables:   make(map[string]*runningTable),
+	}
+}
+
+func (g *AndarBaharGame) GetGameID() string { return g.gameID }
+func (g *AndarBaharGame) GetGameName() string { return "Andar Bahar" }
+func (g *AndarBaharGame) GetGameType() engine.GameType { return engine.GameTypeCasino }
+func (g *AndarBaharGame) GetMinPlayers() int { return 1 }
+func (g *AndarBaharGame) GetMaxPlayers() int { return 500 }
+func (g *AndarBaharGame) GetEntryFee() float64 { return g.entryFee }
+
+// IsSharedRound seats everyone at one live table dealing the same cards
+func (g *AndarBaharGame) IsSharedRound() bool { return true }
+
+func (g *AndarBaharGame) Initialize() error {
+	return nil
+}
+
+func (g *AndarBaharGame) Start(session *engine.GameSession) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	table := &runningTable{
+		Table:   NewTable(session.SessionID, g.config, g.settler, g.seeds),
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+
+	g.mu.Lock()
+	g.tables[session.SessionID] = table
+	g.mu.Unlock()
+
+	// The table serializes its own state, so the session can be read concurrently
+	session.State = table.Table
+
+	go func() {
+		table.Run(ctx)
+		close(table.stopped)
+	}()
+
+	return nil
+}
+
+func (g *AndarBaharGame) tableFor(session *engine.GameSession) (*Table, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	table, ok := g.tables[session.SessionID]
+	if !ok {
+		return nil, errors.New("andar bahar table not running")
+	}
+	return table.Table, nil
+}
+
+func (g *AndarBaharGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
+	if move.Type != "BET" {
+		return nil, errors.New("invalid move type")
+	}
+
+	table, err := g.tableFor(session)
+	if err != nil {
+		return nil, err
+	}
+
+	betType, _ := move.Data["bet_type"].(string)
+	if !ValidBet(betType) {
+		return nil, ErrInvalidBet
+	}
+	amount, _ := move.Data["amount"].(float64)
+	if amount < g.entryFee {
+		return nil, errors.New("bet amount too low")
+	}
+
+	// Cheap early rejection before touching the wallet
+	state := table.State()
+	if state.Status != StatusBetting {
+		return nil, ErrBettingClosed
+	}
+	roundID := state.RoundID
+
+	// Deduct bet from wallet
+	if err := g.wallet.Debit(move.PlayerID, amount, roundID, "GAME_ANDAR_BAHAR"); err != nil {
+		return nil, fmt.Errorf("bet failed: %v", err)
+	}
+
+	if err := table.PlaceBet(move.PlayerID, roundID, betType, amount); err != nil {
+		// The window closed while we were debiting; give the stake back
+		g.settler.Pay(settlement.Credit{UserID: move.PlayerID, Amount: amount, RefID: roundID, RefType: "GAME_ANDAR_BAHAR_REFUND"})
+		return nil, err
+	}
+
+	return &engine.MoveResult{
+		Success: true,
+		StateUpdate: map[string]interface{}{
+			"round_id": roundID,
+			"bet_type": betType,
+			"amount":   amount,
+		},
+	}, nil
+}
+
+// End stops the table's dealing loop, refunding bets on a hand not yet matched
+func (g *AndarBaharGame) End(session *engine.GameSession) (*engine.GameResult, error) {
+	g.mu.Lock()
+	table, ok := g.tables[session.SessionID]
+	delete(g.tables, session.SessionID)
+	g.mu.Unlock()
+
+	if ok {
+		table.cancel()
+		<-table.stopped
+	}
+	return nil, nil
+}
+
+func (g *AndarBaharGame) GetState(session *engine.GameSession) interface{} {
+	table, err := g.tableFor(session)
+	if err != nil {
+		return session.State
+	}
+	return table.State()
+}
@@ -0,0 +1,87 @@
+package andarbahar
+
+// Bet types
+const (
+	BetAndar = "ANDAR"
+	BetBahar = "BAHAR"
+
+	// Side bets on how many cards are dealt (joker excluded) before the match
+	BetSide1To5     = "SIDE_1_5"
+	BetSide6To10    = "SIDE_6_10"
+	BetSide11To15   = "SIDE_11_15"
+	BetSide16To25   = "SIDE_16_25"
+	BetSide26To30   = "SIDE_26_30"
+	BetSide31To35   = "SIDE_31_35"
+	BetSide36To40   = "SIDE_36_40"
+	BetSide41OrMore = "SIDE_41_PLUS"
+)
+
+// Card suits, in deck index order
+var suits = []string{"H", "D", "C", "S"}
+
+// Card represents a playing card
+type Card struct {
+	Suit  string `json:"suit"`  // "H", "D", "C", "S"
+	Value int    `json:"value"` // 2-14 (14 = Ace)
+}
+
+// cardAt maps a deck index (0-51) from fairness.Shuffle to a card
+func cardAt(idx int) Card {
+	return Card{Suit: suits[idx/13], Value: idx%13 + 2}
+}
+
+// sideBetRange is an inclusive range of cards dealt and its gross payout
+type sideBetRange struct {
+	min, max   int
+	multiplier float64 // Total returned per unit staked, stake included
+}
+
+// Each multiplier is priced from the exact chance of the first match landing in
+// its range (see TheoreticalRTP), for a return of 96-98%
+var sideBets = map[string]sideBetRange{
+	BetSide1To5:     {1, 5, 3.55},  // 2.55:1, p = 0.2711
+	BetSide6To10:    {6, 10, 4.45}, // 3.45:1, p = 0.2171
+	BetSide11To15:   {11, 15, 5.7}, // 4.7:1, p = 0.1690
+	BetSide16To25:   {16, 25, 4.5}, // 3.5:1
+	BetSide26To30:   {26, 30, 16},  // 15:1
+	BetSide31To35:   {31, 35, 26},  // 25:1
+	BetSide36To40:   {36, 40, 51},  // 50:1
+	BetSide41OrMore: {41, 51, 121}, // 120:1
+}
+
+// Main bet payouts. Andar is dealt first, so it wins slightly more often and pays 0.9:1.
+const (
+	AndarMultiplier = 1.9
+	BaharMultiplier = 2.0
+)
+
+// ValidBet reports whether betType is on the table
+func ValidBet(betType string) bool {
+	if betType == BetAndar || betType == BetBahar {
+		return true
+	}
+	_, ok := sideBets[betType]
+	return ok
+}
+
+// Multiplier returns the gross payout of a bet given the round outcome (0 = lost)
+func Multiplier(betType, winner string, cardsDealt int) float64 {
+	switch betType {
+	case BetAndar:
+		if winner == BetAndar {
+			return AndarMultiplier
+		}
+		return 0
+	case BetBahar:
+		if winner == BetBahar {
+			return BaharMultiplier
+		}
+		return 0
+	}
+
+	side, ok := sideBets[betType]
+	if ok && cardsDealt >= side.min && cardsDealt <= side.max {
+		return side.multiplier
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package andarbahar
+
+import (
+	"math"
+	"testing"
+)
+
+func allBets() []string {
+	bets := []string{BetAndar, BetBahar}
+	for betType := range sideBets {
+		bets = append(bets, betType)
+	}
+	return bets
+}
+
+// Every bet must return less than it takes, within the house range
+func TestTheoreticalRTPBelowOne(t *testing.T) {
+	g := &AndarBaharGame{}
+	for _, betType := range allBets() {
+		rtp, err := g.TheoreticalRTP(map[string]interface{}{"bet_type": betType, "amount": 1.0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rtp >= 1 || rtp < 0.95 {
+			t.Errorf("%s: theoretical RTP %.4f, want in [0.95, 1)", betType, rtp)
+		}
+	}
+}
+
+// TheoreticalRTP against every way the three joker matches can sit among the 51 cards
+func TestTheoreticalRTPMatchesEnumeration(t *testing.T) {
+	const remaining = 51
+	payout := make(map[string]float64)
+	sets := 0
+	for a := 1; a <= remaining; a++ {
+		for b := a + 1; b <= remaining; b++ {
+			for c := b + 1; c <= remaining; c++ {
+				sets++
+				// a is the first match; odd positions go to Andar
+				winner := BetBahar
+				if a%2 == 1 {
+					winner = BetAndar
+				}
+				for _, betType := range allBets() {
+					payout[betType] += Multiplier(betType, winner, a)
+				}
+			}
+		}
+	}
+
+	g := &AndarBaharGame{}
+	for _, betType := range allBets() {
+		rtp, _ := g.TheoreticalRTP(map[string]interface{}{"bet_type": betType, "amount": 1.0})
+		if want := payout[betType] / float64(sets); math.Abs(rtp-want) > 1e-9 {
+			t.Errorf("%s: theoretical RTP %.6f, enumeration %.6f", betType, rtp, want)
+		}
+	}
+}
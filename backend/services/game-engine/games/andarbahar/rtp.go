@@ -0,0 +1,69 @@
+package andarbahar
+
+import (
+	"errors"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+func parseSimulatedBet(bet map[string]interface{}) (string, float64, error) {
+	betType, _ := bet["bet_type"].(string)
+	amount, _ := bet["amount"].(float64)
+	if !ValidBet(betType) {
+		return "", 0, ErrInvalidBet
+	}
+	if amount <= 0 {
+		return "", 0, errors.New("invalid bet amount")
+	}
+	return betType, amount, nil
+}
+
+// dealOutcome plays out a shuffled deck the way the table deals it: deck[0] is the
+// joker, then cards alternate Andar, Bahar until one matches the joker's value
+func dealOutcome(deck []int) (winner string, cardsDealt int) {
+	joker := cardAt(deck[0])
+	for dealt := 0; 1+dealt < len(deck); dealt++ {
+		if cardAt(deck[1+dealt]).Value == joker.Value {
+			if dealt%2 == 0 {
+				return BetAndar, dealt + 1
+			}
+			return BetBahar, dealt + 1
+		}
+	}
+	return "", 0 // Unreachable with a full deck: three cards match the joker
+}
+
+// TheoreticalRTP sums the bet's payout over the exact distribution of the first match.
+// Three of the 51 cards left match the joker, so the first one is dealt k-th with
+// probability C(51-k, 2) / C(51, 3).
+func (g *AndarBaharGame) TheoreticalRTP(bet map[string]interface{}) (float64, error) {
+	betType, _, err := parseSimulatedBet(bet)
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := fairness.DeckSize - 1
+	total := float64(remaining*(remaining-1)*(remaining-2)) / 6
+	rtp := 0.0
+	for k := 1; k <= remaining-2; k++ {
+		after := remaining - k
+		p := float64(after*(after-1)) / 2 / total
+		winner := BetBahar
+		if k%2 == 1 {
+			winner = BetAndar
+		}
+		rtp += p * Multiplier(betType, winner, k)
+	}
+	return rtp, nil
+}
+
+func (g *AndarBaharGame) SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (float64, float64, error) {
+	betType, amount, err := parseSimulatedBet(bet)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deck := fairness.Shuffle(serverSeed, clientSeed, nonce, fairness.DeckSize)
+	winner, cardsDealt := dealOutcome(deck)
+	return amount, amount * Multiplier(betType, winner, cardsDealt), nil
+}
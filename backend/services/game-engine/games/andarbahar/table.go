@@ -0,0 +1,349 @@
+package andarbahar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/actor"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+)
+
+// Round phases
+const (
+	StatusBetting = "BETTING"
+	StatusDealing = "DEALING"
+	StatusResult  = "RESULT"
+	StatusPaused  = "PAUSED" // No seed for the next hand yet; betting stays closed
+)
+
+var (
+	ErrBettingClosed = errors.New("betting window closed")
+	ErrRoundChanged  = errors.New("round already started")
+	ErrInvalidBet    = errors.New("invalid bet type")
+	ErrTableStopped  = errors.New("table stopped")
+)
+
+// Config controls table timing
+type Config struct {
+	BettingWindow  time.Duration
+	DealInterval   time.Duration
+	ResultDuration time.Duration
+	RetryInterval  time.Duration // Wait before retrying a seed the store failed to save
+	TickInterval   time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BettingWindow:  15 * time.Second,
+		DealInterval:   500 * time.Millisecond,
+		ResultDuration: 5 * time.Second,
+		RetryInterval:  5 * time.Second,
+		TickInterval:   100 * time.Millisecond,
+	}
+}
+
+// TableState is what players see of the table: the joker, both piles and every bet
+type TableState struct {
+	Status         string                        `json:"status"` // BETTING, DEALING, RESULT, PAUSED
+	RoundID        string                        `json:"round_id"`
+	ClosesIn       int                           `json:"closes_in"` // Seconds left to bet
+	Joker          Card                          `json:"joker"`     // Turned face up before betting opens
+	Andar          []Card                        `json:"andar"`
+	Bahar          []Card                        `json:"bahar"`
+	Winner         string                        `json:"winner,omitempty"`
+	CardsDealt     int                           `json:"cards_dealt"`
+	Bets           map[string]map[string]float64 `json:"bets"`               // userID -> bet type -> amount
+	Payouts        map[string]float64            `json:"payouts,omitempty"`  // userID -> total returned
+	Refunded       bool                          `json:"refunded,omitempty"` // Table stopped before the match; stakes returned
+	History        []string                      `json:"history"`
+	ServerSeedHash string                        `json:"server_seed_hash"`
+	ClientSeed     string                        `json:"client_seed"`
+	Nonce          int                           `json:"nonce"`
+	LastServerSeed string                        `json:"last_server_seed"` // Revealed seed of the previous round
+}
+
+type betCommand struct {
+	userID  string
+	roundID string
+	betType string
+	amount  float64
+	reply   chan error
+}
+
+// deal is one hand from joker to match, including the bets on it
+type deal struct {
+	id           string
+	seed         fairness.RoundSeed
+	deck         []int
+	joker        Card
+	andar        []Card
+	bahar        []Card
+	winner       string
+	status       string
+	bets         map[string]map[string]float64
+	payouts      map[string]float64
+	refunded     bool
+	bettingUntil time.Time
+	nextDealAt   time.Time
+	resultUntil  time.Time
+	retryAt      time.Time
+}
+
+// cardsDealt counts the cards dealt to both piles, the joker excluded
+func (d *deal) cardsDealt() int {
+	return len(d.andar) + len(d.bahar)
+}
+
+// Table deals Andar Bahar hands on a fixed clock: a betting window after the
+// joker is shown, one card every DealInterval until the match, then the result.
+// The hand in play belongs to the loop goroutine; bets reach it through the loop.
+type Table struct {
+	sessionID string
+	cfg       Config
+	settler   *settlement.Settler
+	seeds     *fairness.SeedManager
+
+	loop     *actor.Loop[betCommand]
+	snapshot actor.Snapshot[TableState]
+
+	// Owned by the loop goroutine
+	hand           *deal
+	history        []string
+	lastServerSeed string
+}
+
+// NewTable creates a table that shuffles from the session's seed pair and pays
+// winnings and refunds through settler
+func NewTable(sessionID string, cfg Config, settler *settlement.Settler, seeds *fairness.SeedManager) *Table {
+	t := &Table{
+		sessionID: sessionID,
+		cfg:       cfg,
+		settler:   settler,
+		seeds:     seeds,
+		loop:      actor.NewLoop[betCommand](),
+		history:   []string{},
+	}
+	t.snapshot.Store(&TableState{Status: StatusBetting, Bets: map[string]map[string]float64{}, History: []string{}})
+	return t
+}
+
+// Run deals hands until ctx is cancelled. Stakes on a hand without a match yet
+// are refunded when it stops.
+func (t *Table) Run(ctx context.Context) {
+	t.startHand(time.Now())
+	t.loop.Run(ctx, t.cfg.TickInterval, t.handleBet, t.tick)
+	t.refundUnmatched(time.Now())
+}
+
+// State returns what players currently see
+func (t *Table) State() *TableState {
+	return t.snapshot.Load()
+}
+
+// MarshalJSON serializes the current state for GameSession.State
+func (t *Table) MarshalJSON() ([]byte, error) {
+	return t.snapshot.MarshalJSON()
+}
+
+// PlaceBet adds to the user's stake on betType for roundID. The stake must already be debited.
+func (t *Table) PlaceBet(userID, roundID, betType string, amount float64) error {
+	cmd := betCommand{userID: userID, roundID: roundID, betType: betType, amount: amount, reply: make(chan error, 1)}
+	if !t.loop.Send(cmd) {
+		return ErrTableStopped
+	}
+	return <-cmd.reply
+}
+
+func (t *Table) handleBet(cmd betCommand, now time.Time) {
+	cmd.reply <- t.placeBet(cmd)
+	t.publish(now)
+}
+
+func (t *Table) placeBet(cmd betCommand) error {
+	d := t.hand
+	switch {
+	case d.status != StatusBetting:
+		return ErrBettingClosed
+	case cmd.roundID != d.id:
+		return ErrRoundChanged
+	case !ValidBet(cmd.betType):
+		return ErrInvalidBet
+	}
+
+	if d.bets[cmd.userID] == nil {
+		d.bets[cmd.userID] = make(map[string]float64)
+	}
+	d.bets[cmd.userID][cmd.betType] += cmd.amount
+	return nil
+}
+
+// startHand shuffles a new deck and turns up the joker. If the round's seed
+// can't be stored the table pauses with betting closed and tries again after
+// RetryInterval: a nonce that isn't stored could repeat after a restart.
+func (t *Table) startHand(now time.Time) {
+	seed, err := t.seeds.NextRound(t.sessionID)
+	if err != nil {
+		log.Printf("andar bahar %s: failed to store seed nonce, pausing table: %v", t.sessionID, err)
+		t.hand = &deal{
+			andar:   []Card{},
+			bahar:   []Card{},
+			status:  StatusPaused,
+			bets:    make(map[string]map[string]float64),
+			retryAt: now.Add(t.cfg.RetryInterval),
+		}
+		t.publish(now)
+		return
+	}
+	deck := fairness.Shuffle(seed.ServerSeed, seed.ClientSeed, seed.Nonce, fairness.DeckSize)
+
+	t.hand = &deal{
+		id:           fmt.Sprintf("ab_%s_%d", t.sessionID, now.UnixNano()),
+		seed:         seed,
+		deck:         deck,
+		joker:        cardAt(deck[0]),
+		andar:        []Card{},
+		bahar:        []Card{},
+		status:       StatusBetting,
+		bets:         make(map[string]map[string]float64),
+		bettingUntil: now.Add(t.cfg.BettingWindow),
+	}
+	t.publish(now)
+}
+
+func (t *Table) tick(now time.Time) {
+	d := t.hand
+	switch d.status {
+	case StatusBetting:
+		if !now.Before(d.bettingUntil) {
+			d.status = StatusDealing
+			d.nextDealAt = now
+		}
+	case StatusDealing:
+		// Catch up on every card due since the last tick
+		for d.status == StatusDealing && !now.Before(d.nextDealAt) {
+			t.dealCard(now)
+			d.nextDealAt = d.nextDealAt.Add(t.cfg.DealInterval)
+		}
+	case StatusResult:
+		if !now.Before(d.resultUntil) {
+			t.startHand(now)
+			return
+		}
+	case StatusPaused:
+		if !now.Before(d.retryAt) {
+			t.startHand(now)
+			return
+		}
+	}
+	t.publish(now)
+}
+
+// dealCard deals the next card, Andar first, and ends the hand on a match
+func (t *Table) dealCard(now time.Time) {
+	d := t.hand
+	dealt := d.cardsDealt()
+	card := cardAt(d.deck[1+dealt])
+
+	side := BetAndar
+	if dealt%2 == 0 {
+		d.andar = append(d.andar, card)
+	} else {
+		side = BetBahar
+		d.bahar = append(d.bahar, card)
+	}
+
+	if card.Value == d.joker.Value {
+		d.winner = side
+		t.payWinners(now)
+	}
+}
+
+// payWinners pays every winning main and side bet, then reveals the seed pair
+// the deck was shuffled from and commits to a new one
+func (t *Table) payWinners(now time.Time) {
+	d := t.hand
+	d.status = StatusResult
+	d.resultUntil = now.Add(t.cfg.ResultDuration)
+
+	d.payouts = make(map[string]float64)
+	for userID, bets := range d.bets {
+		total := 0.0
+		for betType, amount := range bets {
+			total += amount * Multiplier(betType, d.winner, d.cardsDealt())
+		}
+		total = math.Floor(total*100) / 100
+		if total > 0 {
+			d.payouts[userID] = total
+			t.settler.Pay(settlement.Credit{UserID: userID, Amount: total, RefID: d.id, RefType: "GAME_ANDAR_BAHAR"})
+		}
+	}
+
+	t.history = append(t.history, d.winner)
+	if len(t.history) > 20 {
+		t.history = t.history[1:]
+	}
+
+	if revealed, _, err := t.seeds.Rotate(t.sessionID, ""); err == nil {
+		t.lastServerSeed = revealed.ServerSeed
+	}
+}
+
+// refundUnmatched returns every stake on a hand the table stopped before its match
+func (t *Table) refundUnmatched(now time.Time) {
+	d := t.hand
+	if d == nil || d.status == StatusResult || d.status == StatusPaused {
+		return
+	}
+	for userID, bets := range d.bets {
+		staked := 0.0
+		for _, amount := range bets {
+			staked += amount
+		}
+		t.settler.Pay(settlement.Credit{UserID: userID, Amount: staked, RefID: d.id, RefType: "GAME_ANDAR_BAHAR_REFUND"})
+	}
+	d.refunded = true
+	t.publish(now)
+}
+
+// publish copies the hand into a new TableState for players to read
+func (t *Table) publish(now time.Time) {
+	d := t.hand
+	state := &TableState{
+		Status:         d.status,
+		RoundID:        d.id,
+		Joker:          d.joker,
+		Andar:          append([]Card(nil), d.andar...),
+		Bahar:          append([]Card(nil), d.bahar...),
+		Winner:         d.winner,
+		CardsDealt:     d.cardsDealt(),
+		Bets:           make(map[string]map[string]float64, len(d.bets)),
+		Refunded:       d.refunded,
+		History:        append([]string(nil), t.history...),
+		ServerSeedHash: d.seed.ServerSeedHash,
+		ClientSeed:     d.seed.ClientSeed,
+		Nonce:          d.seed.Nonce,
+		LastServerSeed: t.lastServerSeed,
+	}
+	if d.status == StatusBetting {
+		state.ClosesIn = int(math.Ceil(d.bettingUntil.Sub(now).Seconds()))
+	}
+	for userID, bets := range d.bets {
+		copied := make(map[string]float64, len(bets))
+		for betType, amount := range bets {
+			copied[betType] = amount
+		}
+		state.Bets[userID] = copied
+	}
+	if d.payouts != nil {
+		state.Payouts = make(map[string]float64, len(d.payouts))
+		for userID, amount := range d.payouts {
+			state.Payouts[userID] = amount
+		}
+	}
+	t.snapshot.Store(state)
+}
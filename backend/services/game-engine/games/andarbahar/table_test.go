@@ -0,0 +1,126 @@
+package andarbahar
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+)
+
+// ledger records every credit the settler pays
+type ledger struct {
+	credits []settlement.Credit
+	mu      sync.Mutex
+}
+
+func (l *ledger) Credit(userID string, amount float64, refID, refType string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.credits = append(l.credits, settlement.Credit{UserID: userID, Amount: amount, RefID: refID, RefType: refType})
+	return nil
+}
+
+func TestTableRefundsUnmatchedHandOnStop(t *testing.T) {
+	w := &ledger{}
+	settler := settlement.NewSettler(settlement.Config{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}, w, nil)
+	cfg := DefaultConfig()
+	cfg.BettingWindow = time.Hour
+	table := NewTable("refund", cfg, settler, fairness.NewSeedManager(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		table.Run(ctx)
+		close(stopped)
+	}()
+
+	// Run opens betting on the first hand right away
+	for table.State().RoundID == "" {
+		time.Sleep(time.Millisecond)
+	}
+	roundID := table.State().RoundID
+	if err := table.PlaceBet("u1", roundID, BetAndar, 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.PlaceBet("u1", roundID, BetSide1To5, 20); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-stopped
+
+	if err := table.PlaceBet("u1", roundID, BetBahar, 10); err != ErrTableStopped {
+		t.Errorf("bet after stop: err = %v", err)
+	}
+	if !table.State().Refunded {
+		t.Error("final state not marked refunded")
+	}
+
+	settleCtx, stopSettler := context.WithCancel(context.Background())
+	stopSettler() // Run makes one final attempt at everything queued
+	settler.Run(settleCtx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.credits) != 1 || w.credits[0].Amount != 50 || w.credits[0].RefType != "GAME_ANDAR_BAHAR_REFUND" {
+		t.Errorf("credits = %+v, want one refund of 50", w.credits)
+	}
+}
+
+// flakyStore fails to save seed nonces while down is set
+type flakyStore struct {
+	*fairness.MemoryStore
+	down atomic.Bool
+}
+
+func (s *flakyStore) SaveActive(ownerID string, pair *fairness.SeedPair) error {
+	if s.down.Load() {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryStore.SaveActive(ownerID, pair)
+}
+
+func TestTablePausesWhenSeedIsNotStored(t *testing.T) {
+	store := &flakyStore{MemoryStore: fairness.NewMemoryStore()}
+	store.down.Store(true)
+	cfg := DefaultConfig()
+	cfg.BettingWindow = time.Hour
+	cfg.RetryInterval = 5 * time.Millisecond
+	cfg.TickInterval = time.Millisecond
+	table := NewTable("paused", cfg, nil, fairness.NewSeedManager(store))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go table.Run(ctx)
+
+	for table.State().Status != StatusPaused {
+		time.Sleep(time.Millisecond)
+	}
+	if state := table.State(); state.RoundID != "" || state.ServerSeedHash != "" {
+		t.Fatalf("paused table shows round %q with seed hash %q", state.RoundID, state.ServerSeedHash)
+	}
+	if err := table.PlaceBet("u1", "", BetAndar, 10); err != ErrBettingClosed {
+		t.Fatalf("bet on paused table: err = %v, want ErrBettingClosed", err)
+	}
+
+	// Betting opens once the store is back
+	store.down.Store(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for table.State().Status != StatusBetting {
+		if time.Now().After(deadline) {
+			t.Fatal("table still paused after the store recovered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	state := table.State()
+	if state.ServerSeedHash == "" || state.Nonce == 0 {
+		t.Fatalf("hand opened without a stored seed: %+v", state)
+	}
+	if err := table.PlaceBet("u1", state.RoundID, BetAndar, 10); err != nil {
+		t.Fatal(err)
+	}
+}
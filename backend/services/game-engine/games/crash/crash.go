@@ -1,46 +1,54 @@
 package crash
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"math"
-	"time"
+	"sync"
 
 	"github.com/playkaro/game-engine/internal/engine"
 	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
 	"github.com/playkaro/game-engine/internal/wallet"
 )
 
-type CrashGame struct {
-	gameID       string
-	entryFee     float64
-	walletClient *wallet.WalletClient
+// Wallet moves money for bets and winnings (implemented by wallet.WalletClient)
+type Wallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+	Credit(userID string, amount float64, refID, refType string) error
 }
 
-type CrashState struct {
-	Status          string             `json:"status"` // WAITING, FLYING, CRASHED
-	Multiplier      float64            `json:"multiplier"`
-	StartTime       time.Time          `json:"start_time"`
-	NextRoundIn     int                `json:"next_round_in"` // Seconds
-	Bets            map[string]*Bet    `json:"bets"`
-	History         []float64          `json:"history"`
-	ServerSeedHash  string             `json:"server_seed_hash"`
-	CurrentRoundID  string             `json:"current_round_id"`
+type CrashGame struct {
+	gameID   string
+	entryFee float64
+	config   Config
+	wallet   Wallet
+	store    RoundStore
+	seeds    *fairness.SeedManager
+	settler  *settlement.Settler
+
+	engines map[string]*tableEngine // sessionID -> running table
+	mu      sync.Mutex
 }
 
-type Bet struct {
-	UserID      string  `json:"user_id"`
-	Amount      float64 `json:"amount"`
-	AutoCashout float64 `json:"auto_cashout"`
-	CashoutAt   float64 `json:"cashout_at"` // 0 if not cashed out
-	Profit      float64 `json:"profit"`
+type tableEngine struct {
+	*RoundEngine
+	cancel  context.CancelFunc
+	stopped chan struct{}
 }
 
-func NewCrashGame() *CrashGame {
+// NewCrashGame creates the Crash game. store may be nil to skip round persistence.
+// settler pays winnings and refunds and must be running while tables are.
+func NewCrashGame(store RoundStore, seeds *fairness.SeedManager, settler *settlement.Settler) *CrashGame {
 	return &CrashGame{
-		gameID:       "crash_aviator",
-		entryFee:     10.0, // Min bet
-		walletClient: wallet.NewWalletClient(),
+		gameID:   "crash_aviator",
+		entryFee: 10.0, // Min bet
+		config:   DefaultConfig(),
+		wallet:   wallet.NewWalletClient(),
+		store:    store,
+		seeds:    seeds,
+		settler:  settler,
+		engines:  make(map[string]*tableEngine),
 	}
 }
 
@@ -51,142 +59,98 @@ func (g *CrashGame) GetMinPlayers() int { return 1 }
 func (g *CrashGame) GetMaxPlayers() int { return 1000 } // Unlimited
 func (g *CrashGame) GetEntryFee() float64 { return g.entryFee }
 
+// IsSharedRound puts every player on the same table, watching the same multiplier
+func (g *CrashGame) IsSharedRound() bool { return true }
+
 func (g *CrashGame) Initialize() error {
 	return nil
 }
 
 func (g *CrashGame) Start(session *engine.GameSession) error {
-	session.State = &CrashState{
-		Status:         "WAITING",
-		Multiplier:     1.00,
-		Bets:           make(map[string]*Bet),
-		History:        []float64{},
-		ServerSeedHash: fairness.HashServerSeed("secret_seed"),
-		CurrentRoundID: fmt.Sprintf("round_%d", time.Now().Unix()),
+	ctx, cancel := context.WithCancel(context.Background())
+	table := &tableEngine{
+		RoundEngine: NewRoundEngine(session.SessionID, g.config, g.settler, g.store, g.seeds),
+		cancel:      cancel,
+		stopped:     make(chan struct{}),
 	}
 
-	go g.RunGameLoop(session)
-
-	return nil
-}
-
-func (g *CrashGame) RunGameLoop(session *engine.GameSession) {
-	state := session.State.(*CrashState)
-
-	for {
-		// 1. WAITING PHASE
-		state.Status = "WAITING"
-		state.Multiplier = 1.00
-		state.Bets = make(map[string]*Bet)
-		state.CurrentRoundID = fmt.Sprintf("round_%d", time.Now().Unix())
-
-		for i := 5; i > 0; i-- {
-			state.NextRoundIn = i
-			time.Sleep(1 * time.Second)
-		}
-
-		// 2. CALCULATE CRASH POINT
-		crashPoint := fairness.CalculateCrashPoint("secret_seed", "public_seed", int(time.Now().Unix()))
+	g.mu.Lock()
+	g.engines[session.SessionID] = table
+	g.mu.Unlock()
 
-		// 3. FLYING PHASE
-		state.Status = "FLYING"
-		startTime := time.Now()
+	// The engine serializes its own state, so the session can be read concurrently
+	session.State = table.RoundEngine
 
-		for {
-			elapsed := time.Since(startTime).Seconds()
-			currentMult := math.Exp(0.06 * elapsed)
+	go func() {
+		table.Run(ctx)
+		close(table.stopped)
+	}()
 
-			if currentMult >= crashPoint {
-				state.Multiplier = crashPoint
-				break
-			}
-
-			state.Multiplier = currentMult
-			g.processAutoCashouts(state)
-			time.Sleep(100 * time.Millisecond)
-		}
-
-		// 4. CRASH PHASE
-		state.Status = "CRASHED"
-		state.History = append(state.History, state.Multiplier)
-		if len(state.History) > 10 {
-			state.History = state.History[1:]
-		}
-
-		time.Sleep(3 * time.Second)
-	}
-}
-
-func (g *CrashGame) processAutoCashouts(state *CrashState) {
-	for _, bet := range state.Bets {
-		if bet.CashoutAt == 0 && bet.AutoCashout > 0 && state.Multiplier >= bet.AutoCashout {
-			g.cashoutUser(bet, bet.AutoCashout, state.CurrentRoundID)
-		}
-	}
+	return nil
 }
 
-func (g *CrashGame) cashoutUser(bet *Bet, multiplier float64, roundID string) {
-	bet.CashoutAt = multiplier
-	bet.Profit = bet.Amount * multiplier
+func (g *CrashGame) engineFor(session *engine.GameSession) (*RoundEngine, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	// Credit winnings to wallet
-	// Note: We credit the FULL amount (Stake + Profit) because we debited the stake earlier
-	g.walletClient.Credit(bet.UserID, bet.Profit, roundID, "GAME_CRASH")
+	table, ok := g.engines[session.SessionID]
+	if !ok {
+		return nil, errors.New("crash table not running")
+	}
+	return table.RoundEngine, nil
 }
 
 func (g *CrashGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
-	state := session.State.(*CrashState)
+	e, err := g.engineFor(session)
+	if err != nil {
+		return nil, err
+	}
 
 	if move.Type == "BET" {
-		if state.Status != "WAITING" {
-			return nil, errors.New("can only bet during waiting phase")
-		}
-
-		amount := move.Data["amount"].(float64)
+		amount, _ := move.Data["amount"].(float64)
 		if amount < g.entryFee {
 			return nil, errors.New("bet amount too low")
 		}
 
-		// Deduct bet from wallet
-		err := g.walletClient.Debit(move.PlayerID, amount, state.CurrentRoundID, "GAME_CRASH")
-		if err != nil {
-			return nil, fmt.Errorf("bet failed: %v", err)
+		autoCashout, _ := move.Data["auto_cashout"].(float64)
+		if autoCashout != 0 && autoCashout < 1.01 {
+			return nil, errors.New("auto cashout must be at least 1.01x")
 		}
 
-		autoCashout := 0.0
-		if val, ok := move.Data["auto_cashout"]; ok {
-			autoCashout = val.(float64)
+		// Cheap early rejection before touching the wallet
+		state := e.State()
+		if state.Status != StatusWaiting {
+			return nil, ErrNotWaiting
 		}
+		roundID := state.CurrentRoundID
 
-		state.Bets[move.PlayerID] = &Bet{
-			UserID:      move.PlayerID,
-			Amount:      amount,
-			AutoCashout: autoCashout,
+		// Deduct bet from wallet
+		if err := g.wallet.Debit(move.PlayerID, amount, roundID, "GAME_CRASH"); err != nil {
+			return nil, fmt.Errorf("bet failed: %v", err)
 		}
 
-		return &engine.MoveResult{Success: true}, nil
-	}
-
-	if move.Type == "CASHOUT" {
-		if state.Status != "FLYING" {
-			return nil, errors.New("can only cashout while flying")
+		bet, err := e.PlaceBet(move.PlayerID, roundID, amount, autoCashout)
+		if err != nil {
+			// The round moved on while we were debiting; give the stake back
+			g.settler.Pay(settlement.Credit{UserID: move.PlayerID, Amount: amount, RefID: roundID, RefType: "GAME_CRASH_REFUND"})
+			return nil, err
 		}
 
-		bet, exists := state.Bets[move.PlayerID]
-		if !exists {
-			return nil, errors.New("no active bet")
-		}
+		return &engine.MoveResult{Success: true, StateUpdate: bet}, nil
+	}
 
-		if bet.CashoutAt > 0 {
-			return nil, errors.New("already cashed out")
+	if move.Type == "CASHOUT" {
+		bet, err := e.Cashout(move.PlayerID)
+		if err != nil {
+			return nil, err
 		}
 
-		g.cashoutUser(bet, state.Multiplier, state.CurrentRoundID)
-
 		return &engine.MoveResult{
 			Success: true,
 			StateUpdate: map[string]interface{}{
-				"profit": bet.Profit,
+				"cashout_at": bet.CashoutAt,
+				"payout":     bet.Payout,
+				"profit":     bet.Profit,
 			},
 		}, nil
 	}
@@ -194,10 +158,25 @@ func (g *CrashGame) HandleMove(session *engine.GameSession, move engine.Move) (*
 	return nil, errors.New("invalid move type")
 }
 
+// End stops the table's round loop, refunding bets still in play
 func (g *CrashGame) End(session *engine.GameSession) (*engine.GameResult, error) {
+	g.mu.Lock()
+	table, ok := g.engines[session.SessionID]
+	delete(g.engines, session.SessionID)
+	g.mu.Unlock()
+
+	if ok {
+		table.cancel()
+		// Open bets are refunded by the time End returns
+		<-table.stopped
+	}
 	return nil, nil
 }
 
 func (g *CrashGame) GetState(session *engine.GameSession) interface{} {
-	return session.State
+	e, err := g.engineFor(session)
+	if err != nil {
+		return session.State
+	}
+	return e.State()
 }
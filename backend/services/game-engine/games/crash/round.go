@@ -0,0 +1,528 @@
+package crash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/actor"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+)
+
+// Round phases
+const (
+	StatusWaiting = "WAITING"
+	StatusFlying  = "FLYING"
+	StatusCrashed = "CRASHED"
+)
+
+var (
+	ErrNotWaiting       = errors.New("can only bet during waiting phase")
+	ErrNotFlying        = errors.New("can only cashout while flying")
+	ErrRoundChanged     = errors.New("round already started")
+	ErrDuplicateBet     = errors.New("already placed a bet this round")
+	ErrNoActiveBet      = errors.New("no active bet")
+	ErrAlreadyCashedOut = errors.New("already cashed out")
+	ErrEngineStopped    = errors.New("crash engine stopped")
+)
+
+// Config controls round timing and risk limits
+type Config struct {
+	WaitingDuration   time.Duration
+	CrashedDuration   time.Duration
+	TickInterval      time.Duration
+	GrowthRate        float64 // multiplier = e^(GrowthRate * seconds)
+	MaxPayoutPerBet   float64 // 0 = unlimited
+	MaxPayoutPerRound float64 // 0 = unlimited
+	ChainLength       int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		WaitingDuration:   5 * time.Second,
+		CrashedDuration:   3 * time.Second,
+		TickInterval:      100 * time.Millisecond,
+		GrowthRate:        0.06,
+		MaxPayoutPerBet:   100000,
+		MaxPayoutPerRound: 500000,
+		ChainLength:       10000,
+	}
+}
+
+// Bet is a single player's stake in a round
+type Bet struct {
+	UserID      string  `json:"user_id"`
+	Amount      float64 `json:"amount"`
+	AutoCashout float64 `json:"auto_cashout"`
+	CashoutAt   float64 `json:"cashout_at"` // 0 if not cashed out
+	Payout      float64 `json:"payout"`
+	Profit      float64 `json:"profit"`
+	Capped      bool    `json:"capped,omitempty"`   // Cashed out early by a max-win cap
+	Refunded    bool    `json:"refunded,omitempty"` // Stake returned when the table stopped mid-round
+}
+
+// CrashState is an immutable snapshot of the table, safe to read from any goroutine
+type CrashState struct {
+	Status          string         `json:"status"` // WAITING, FLYING, CRASHED
+	Multiplier      float64        `json:"multiplier"`
+	StartTime       time.Time      `json:"start_time"`
+	NextRoundIn     int            `json:"next_round_in"` // Seconds
+	Bets            map[string]Bet `json:"bets"`
+	History         []float64      `json:"history"`
+	ServerSeedHash  string         `json:"server_seed_hash"`
+	ClientSeed      string         `json:"client_seed"`
+	Nonce           int            `json:"nonce"`
+	ChainCommitment string         `json:"chain_commitment"`
+	LastServerSeed  string         `json:"last_server_seed"` // Revealed seed of the previous round
+	CurrentRoundID  string         `json:"current_round_id"`
+}
+
+// RoundRecord is a finished round, handed to the RoundStore for persistence
+type RoundRecord struct {
+	RoundID         string    `json:"round_id"`
+	SessionID       string    `json:"session_id"`
+	Nonce           int       `json:"nonce"`
+	ServerSeed      string    `json:"server_seed"`
+	ServerSeedHash  string    `json:"server_seed_hash"`
+	ClientSeed      string    `json:"client_seed"`
+	ChainCommitment string    `json:"chain_commitment"`
+	CrashPoint      float64   `json:"crash_point"`
+	TotalWagered    float64   `json:"total_wagered"`
+	TotalPayout     float64   `json:"total_payout"`
+	Bets            []Bet     `json:"bets"`
+	StartedAt       time.Time `json:"started_at"`
+	CrashedAt       time.Time `json:"crashed_at"`
+}
+
+type commandKind int
+
+const (
+	cmdBet commandKind = iota
+	cmdCashout
+)
+
+type command struct {
+	kind        commandKind
+	userID      string
+	roundID     string
+	amount      float64
+	autoCashout float64
+	reply       chan commandResult
+}
+
+type commandResult struct {
+	bet Bet
+	err error
+}
+
+// round is the mutable state of the current round. Only the actor goroutine touches it.
+type round struct {
+	id             string
+	nonce          int
+	serverSeed     string
+	serverSeedHash string
+	crashPoint     float64
+	status         string
+	multiplier     float64
+	waitUntil      time.Time
+	startedAt      time.Time
+	crashedAt      time.Time
+	bets           map[string]*Bet
+	wagered        float64
+	paidOut        float64
+}
+
+// RoundEngine runs one Crash table. All round state is owned by the Run goroutine;
+// everything else talks to it over channels and reads published snapshots.
+type RoundEngine struct {
+	sessionID string
+	cfg       Config
+	settler   *settlement.Settler
+	store     RoundStore
+	seeds     *fairness.SeedManager
+
+	loop     *actor.Loop[command]
+	io       chan func() // Persistence, run in order off the actor goroutine
+	snapshot actor.Snapshot[CrashState]
+
+	// Actor-owned
+	chain          *fairness.HashChain
+	current        *round
+	history        []float64
+	lastServerSeed string
+}
+
+// NewRoundEngine creates a table. Winnings and refunds are paid through settler.
+// The hash chain is kept in seeds, which also reveals each crashed round's seed
+// so it can be verified like any other game's; seeds may be nil in tests.
+func NewRoundEngine(sessionID string, cfg Config, settler *settlement.Settler, store RoundStore, seeds *fairness.SeedManager) *RoundEngine {
+	e := &RoundEngine{
+		sessionID: sessionID,
+		cfg:       cfg,
+		settler:   settler,
+		store:     store,
+		seeds:     seeds,
+		loop:      actor.NewLoop[command](),
+		io:        make(chan func(), 64),
+		history:   []float64{},
+	}
+	e.chain = e.newChain()
+	e.snapshot.Store(&CrashState{Status: StatusWaiting, Multiplier: 1.00, Bets: map[string]Bet{}, History: []float64{}})
+	return e
+}
+
+// chainOwner keys the table's hash chains in the seed store
+func (e *RoundEngine) chainOwner() string {
+	return "crash:" + e.sessionID
+}
+
+// newChain resumes the table's stored hash chain or starts a new one. If the
+// store is down the table still plays, on a chain that is not persisted.
+func (e *RoundEngine) newChain() *fairness.HashChain {
+	if e.seeds == nil {
+		return fairness.NewHashChain(e.cfg.ChainLength, "")
+	}
+	chain, err := e.seeds.HashChain(e.chainOwner(), e.cfg.ChainLength)
+	if err != nil {
+		log.Printf("crash %s: failed to load hash chain, using an unsaved one: %v", e.sessionID, err)
+		return fairness.NewHashChain(e.cfg.ChainLength, "")
+	}
+	return chain
+}
+
+// Run drives rounds until ctx is cancelled. Bets still open when it stops are refunded.
+func (e *RoundEngine) Run(ctx context.Context) {
+	persisted := make(chan struct{})
+	go e.persist(persisted)
+
+	e.startRound(time.Now())
+	e.loop.Run(ctx, e.cfg.TickInterval, e.handle, e.tick)
+	e.refundOpenBets(time.Now())
+
+	close(e.io)
+	<-persisted
+}
+
+// State returns the latest published snapshot
+func (e *RoundEngine) State() *CrashState {
+	return e.snapshot.Load()
+}
+
+// MarshalJSON lets the engine sit in GameSession.State and serialize safely
+func (e *RoundEngine) MarshalJSON() ([]byte, error) {
+	return e.snapshot.MarshalJSON()
+}
+
+// PlaceBet registers a bet for roundID. The stake must already be debited.
+func (e *RoundEngine) PlaceBet(userID, roundID string, amount, autoCashout float64) (Bet, error) {
+	return e.send(command{kind: cmdBet, userID: userID, roundID: roundID, amount: amount, autoCashout: autoCashout})
+}
+
+// Cashout settles the user's bet at the current multiplier
+func (e *RoundEngine) Cashout(userID string) (Bet, error) {
+	return e.send(command{kind: cmdCashout, userID: userID})
+}
+
+func (e *RoundEngine) send(cmd command) (Bet, error) {
+	cmd.reply = make(chan commandResult, 1)
+	if !e.loop.Send(cmd) {
+		return Bet{}, ErrEngineStopped
+	}
+	res := <-cmd.reply
+	return res.bet, res.err
+}
+
+func (e *RoundEngine) startRound(now time.Time) {
+	seed, nonce, err := e.chain.Next()
+	if err == fairness.ErrHashChainExhausted {
+		e.chain = e.newChain()
+		log.Printf("crash %s: hash chain exhausted, new commitment %s", e.sessionID, e.chain.Commitment)
+		seed, nonce, _ = e.chain.Next()
+	}
+	// Record the round as handed out before anyone sees it fly, so a restart
+	// never replays a seed whose multiplier was partly shown
+	if e.seeds != nil {
+		chain := e.chain
+		e.io <- func() {
+			if err := e.seeds.SaveChain(e.chainOwner(), chain); err != nil {
+				log.Printf("crash %s: failed to save hash chain position: %v", e.sessionID, err)
+			}
+		}
+	}
+
+	e.current = &round{
+		id:             fmt.Sprintf("round_%s_%d", e.sessionID, now.UnixNano()),
+		nonce:          nonce,
+		serverSeed:     seed,
+		serverSeedHash: fairness.HashServerSeed(seed),
+		crashPoint:     fairness.CalculateCrashPoint(seed, e.chain.Salt, nonce),
+		status:         StatusWaiting,
+		multiplier:     1.00,
+		waitUntil:      now.Add(e.cfg.WaitingDuration),
+		bets:           make(map[string]*Bet),
+	}
+	e.publish(now)
+}
+
+func (e *RoundEngine) tick(now time.Time) {
+	r := e.current
+	switch r.status {
+	case StatusWaiting:
+		if !now.Before(r.waitUntil) {
+			r.status = StatusFlying
+			r.startedAt = now
+		}
+	case StatusFlying:
+		e.advance(now)
+	case StatusCrashed:
+		if now.Sub(r.crashedAt) >= e.cfg.CrashedDuration {
+			e.startRound(now)
+			return
+		}
+	}
+	e.publish(now)
+}
+
+// advance moves the multiplier to now, settling auto-cashouts and caps, and crashes if due
+func (e *RoundEngine) advance(now time.Time) {
+	r := e.current
+	mult := e.multiplierAt(now.Sub(r.startedAt))
+	if mult > r.crashPoint {
+		mult = r.crashPoint
+	}
+
+	// Auto-cashouts settle at exactly their target, even if the tick overshot it
+	for _, bet := range r.bets {
+		if bet.CashoutAt == 0 && bet.AutoCashout > 0 && bet.AutoCashout <= mult {
+			e.cashout(bet, bet.AutoCashout, false)
+		}
+	}
+
+	e.applyCaps(mult)
+	r.multiplier = mult
+
+	if mult >= r.crashPoint {
+		e.crash(now)
+	}
+}
+
+// applyCaps force-cashes bets whose payout would exceed the per-bet or per-round limit
+func (e *RoundEngine) applyCaps(mult float64) {
+	r := e.current
+
+	if e.cfg.MaxPayoutPerBet > 0 {
+		for _, bet := range r.bets {
+			if bet.CashoutAt == 0 && bet.Amount*mult >= e.cfg.MaxPayoutPerBet {
+				e.cashout(bet, mult, true)
+			}
+		}
+	}
+
+	if e.cfg.MaxPayoutPerRound > 0 {
+		liability := r.paidOut
+		for _, bet := range r.bets {
+			if bet.CashoutAt == 0 {
+				liability += bet.Amount * mult
+			}
+		}
+		if liability >= e.cfg.MaxPayoutPerRound {
+			for _, bet := range r.bets {
+				if bet.CashoutAt == 0 {
+					e.cashout(bet, mult, true)
+				}
+			}
+		}
+	}
+}
+
+func (e *RoundEngine) crash(now time.Time) {
+	r := e.current
+	r.status = StatusCrashed
+	r.multiplier = r.crashPoint
+	r.crashedAt = now
+
+	e.history = append(e.history, r.crashPoint)
+	if len(e.history) > 10 {
+		e.history = e.history[1:]
+	}
+	e.lastServerSeed = r.serverSeed
+
+	record := &RoundRecord{
+		RoundID:         r.id,
+		SessionID:       e.sessionID,
+		Nonce:           r.nonce,
+		ServerSeed:      r.serverSeed,
+		ServerSeedHash:  r.serverSeedHash,
+		ClientSeed:      e.chain.Salt,
+		ChainCommitment: e.chain.Commitment,
+		CrashPoint:      r.crashPoint,
+		TotalWagered:    r.wagered,
+		TotalPayout:     r.paidOut,
+		Bets:            make([]Bet, 0, len(r.bets)),
+		StartedAt:       r.startedAt,
+		CrashedAt:       r.crashedAt,
+	}
+	for _, bet := range r.bets {
+		record.Bets = append(record.Bets, *bet)
+	}
+	e.io <- func() { e.saveRound(record) }
+}
+
+// refundOpenBets returns the stake of every bet still riding when the table
+// stops: all of them while waiting, those not cashed out while flying
+func (e *RoundEngine) refundOpenBets(now time.Time) {
+	r := e.current
+	if r == nil || r.status == StatusCrashed {
+		return
+	}
+	if r.status == StatusFlying {
+		// Settle auto-cashouts the multiplier has already reached
+		e.advance(now)
+		if r.status == StatusCrashed {
+			e.publish(now)
+			return
+		}
+	}
+
+	for _, bet := range r.bets {
+		if bet.CashoutAt > 0 {
+			continue
+		}
+		bet.Refunded = true
+		e.settler.Pay(settlement.Credit{UserID: bet.UserID, Amount: bet.Amount, RefID: r.id, RefType: "GAME_CRASH_REFUND"})
+	}
+	e.publish(now)
+}
+
+func (e *RoundEngine) cashout(bet *Bet, multiplier float64, capped bool) {
+	if e.cfg.MaxPayoutPerBet > 0 && bet.Amount*multiplier > e.cfg.MaxPayoutPerBet {
+		multiplier = capMultiplier(e.cfg.MaxPayoutPerBet, bet.Amount)
+		capped = true
+	}
+
+	bet.CashoutAt = multiplier
+	bet.Payout = math.Floor(bet.Amount*multiplier*100) / 100
+	bet.Profit = bet.Payout - bet.Amount
+	bet.Capped = capped
+	e.current.paidOut += bet.Payout
+
+	// We credit the FULL amount (Stake + Profit) because we debited the stake earlier
+	e.settler.Pay(settlement.Credit{UserID: bet.UserID, Amount: bet.Payout, RefID: e.current.id, RefType: "GAME_CRASH"})
+}
+
+func (e *RoundEngine) handle(cmd command, now time.Time) {
+	r := e.current
+
+	// Bring a flying round up to date first so a late cashout can't beat the crash
+	if r.status == StatusFlying {
+		e.advance(now)
+	}
+
+	switch cmd.kind {
+	case cmdBet:
+		switch {
+		case r.status != StatusWaiting:
+			cmd.reply <- commandResult{err: ErrNotWaiting}
+		case cmd.roundID != r.id:
+			cmd.reply <- commandResult{err: ErrRoundChanged}
+		case r.bets[cmd.userID] != nil:
+			cmd.reply <- commandResult{err: ErrDuplicateBet}
+		default:
+			bet := &Bet{UserID: cmd.userID, Amount: cmd.amount, AutoCashout: cmd.autoCashout}
+			r.bets[cmd.userID] = bet
+			r.wagered += cmd.amount
+			cmd.reply <- commandResult{bet: *bet}
+		}
+
+	case cmdCashout:
+		bet := r.bets[cmd.userID]
+		switch {
+		case r.status != StatusFlying:
+			cmd.reply <- commandResult{err: ErrNotFlying}
+		case bet == nil:
+			cmd.reply <- commandResult{err: ErrNoActiveBet}
+		case bet.CashoutAt > 0:
+			cmd.reply <- commandResult{err: ErrAlreadyCashedOut}
+		default:
+			e.cashout(bet, r.multiplier, false)
+			cmd.reply <- commandResult{bet: *bet}
+		}
+	}
+
+	e.publish(now)
+}
+
+// publish stores a fresh immutable snapshot of the actor state
+func (e *RoundEngine) publish(now time.Time) {
+	r := e.current
+	state := &CrashState{
+		Status:          r.status,
+		Multiplier:      r.multiplier,
+		StartTime:       r.startedAt,
+		Bets:            make(map[string]Bet, len(r.bets)),
+		History:         append([]float64(nil), e.history...),
+		ServerSeedHash:  r.serverSeedHash,
+		ClientSeed:      e.chain.Salt,
+		Nonce:           r.nonce,
+		ChainCommitment: e.chain.Commitment,
+		LastServerSeed:  e.lastServerSeed,
+		CurrentRoundID:  r.id,
+	}
+	if r.status == StatusWaiting {
+		state.NextRoundIn = int(math.Ceil(r.waitUntil.Sub(now).Seconds()))
+	}
+	for userID, bet := range r.bets {
+		state.Bets[userID] = *bet
+	}
+	e.snapshot.Store(state)
+}
+
+// persist runs storage work in order without blocking the actor on I/O
+func (e *RoundEngine) persist(done chan struct{}) {
+	defer close(done)
+	for job := range e.io {
+		job()
+	}
+}
+
+// saveRound reveals a crashed round's seed and stores the round
+func (e *RoundEngine) saveRound(rec *RoundRecord) {
+	e.reveal(rec)
+	if e.store == nil {
+		return
+	}
+	if err := e.store.SaveRound(rec); err != nil {
+		log.Printf("crash %s: failed to persist round: %v", rec.RoundID, err)
+	}
+}
+
+// reveal archives a crashed round's seed with every other revealed seed, so
+// /v1/fairness/verify can find it by hash
+func (e *RoundEngine) reveal(rec *RoundRecord) {
+	if e.seeds == nil {
+		return
+	}
+	err := e.seeds.Reveal(&fairness.RevealedSeed{
+		ServerSeed:     rec.ServerSeed,
+		ServerSeedHash: rec.ServerSeedHash,
+		ClientSeed:     rec.ClientSeed,
+		LastNonce:      rec.Nonce,
+		RevealedAt:     rec.CrashedAt,
+	})
+	if err != nil {
+		log.Printf("crash %s: failed to reveal seed: %v", rec.RoundID, err)
+	}
+}
+
+func (e *RoundEngine) multiplierAt(elapsed time.Duration) float64 {
+	return math.Floor(math.Exp(e.cfg.GrowthRate*elapsed.Seconds())*100) / 100
+}
+
+// capMultiplier is the highest 2-decimal multiplier whose payout stays within limit
+func capMultiplier(limit, amount float64) float64 {
+	return math.Floor(limit/amount*100) / 100
+}
@@ -0,0 +1,228 @@
+package crash
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+)
+
+// ledger records every credit the settler pays
+type ledger struct {
+	credits []settlement.Credit
+	mu      sync.Mutex
+}
+
+func (l *ledger) Credit(userID string, amount float64, refID, refType string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.credits = append(l.credits, settlement.Credit{UserID: userID, Amount: amount, RefID: refID, RefType: refType})
+	return nil
+}
+
+func (l *ledger) total(refType string) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sum := 0.0
+	for _, c := range l.credits {
+		if c.RefType == refType {
+			sum += c.Amount
+		}
+	}
+	return sum
+}
+
+// rounds collects finished rounds
+type rounds struct {
+	records []*RoundRecord
+	mu      sync.Mutex
+}
+
+func (r *rounds) SaveRound(record *RoundRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+	return nil
+}
+
+func fastConfig() Config {
+	return Config{
+		WaitingDuration:   5 * time.Millisecond,
+		CrashedDuration:   2 * time.Millisecond,
+		TickInterval:      time.Millisecond,
+		GrowthRate:        20, // Reaches 2x in ~35ms
+		MaxPayoutPerBet:   1000,
+		MaxPayoutPerRound: 5000,
+		ChainLength:       1000,
+	}
+}
+
+func startSettler(w settlement.Wallet) (*settlement.Settler, func()) {
+	s := settlement.NewSettler(settlement.Config{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}, w, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(stopped)
+	}()
+	return s, func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for s.Pending() > 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		<-stopped
+	}
+}
+
+// Run with -race: players bet and cash out from many goroutines while rounds
+// tick, then the table stops mid-round. Every credit paid must be accounted
+// for by a cashout in a stored round or a refund in the final state.
+func TestRoundEngineConcurrentPlay(t *testing.T) {
+	w := &ledger{}
+	settler, drain := startSettler(w)
+	store := &rounds{}
+	e := NewRoundEngine("race", fastConfig(), settler, store, fairness.NewSeedManager(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(stopped)
+	}()
+
+	var wg sync.WaitGroup
+	playing := time.Now().Add(300 * time.Millisecond)
+	for p := 0; p < 20; p++ {
+		userID := string(rune('a' + p))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(playing) {
+				state := e.State()
+				switch state.Status {
+				case StatusWaiting:
+					e.PlaceBet(userID, state.CurrentRoundID, 10, 0)
+				case StatusFlying:
+					if state.Multiplier > 1.5 {
+						e.Cashout(userID)
+					}
+				}
+				_ = state.Bets[userID] // Snapshots are read concurrently with the actor
+				time.Sleep(200 * time.Microsecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cancel()
+	<-stopped
+	if _, err := e.PlaceBet("a", "any", 10, 0); err != ErrEngineStopped {
+		t.Errorf("bet after stop: err = %v", err)
+	}
+
+	// The round in play when the table stopped is never stored
+	final := e.State()
+	refunds := 0.0
+	for _, bet := range final.Bets {
+		if bet.Refunded {
+			refunds += bet.Amount
+			if bet.CashoutAt > 0 {
+				t.Errorf("%s refunded after cashing out", bet.UserID)
+			}
+		}
+	}
+
+	drain()
+	// Stored rounds are saved asynchronously; the engine has stopped, so all are in
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.records) == 0 {
+		t.Fatal("no rounds finished")
+	}
+	paid := 0.0
+	for _, rec := range store.records {
+		paid += rec.TotalPayout
+	}
+	if final.Status != StatusCrashed {
+		for _, bet := range final.Bets {
+			paid += bet.Payout
+		}
+	}
+
+	if paid == 0 {
+		t.Error("no bet was ever cashed out")
+	}
+	if got := w.total("GAME_CRASH"); math.Abs(got-paid) > 0.001 {
+		t.Errorf("credited %.2f in winnings, rounds paid %.2f", got, paid)
+	}
+	if got := w.total("GAME_CRASH_REFUND"); math.Abs(got-refunds) > 0.001 {
+		t.Errorf("credited %.2f in refunds, state shows %.2f", got, refunds)
+	}
+}
+
+func TestRoundEngineRefundsOnStop(t *testing.T) {
+	w := &ledger{}
+	settler, drain := startSettler(w)
+	cfg := fastConfig()
+	cfg.WaitingDuration = time.Hour
+	e := NewRoundEngine("refund", cfg, settler, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(stopped)
+	}()
+
+	// Run publishes the first round right away
+	for e.State().CurrentRoundID == "" {
+		time.Sleep(time.Millisecond)
+	}
+	roundID := e.State().CurrentRoundID
+	for _, userID := range []string{"u1", "u2"} {
+		if _, err := e.PlaceBet(userID, roundID, 40, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancel()
+	<-stopped
+	drain()
+
+	if got := w.total("GAME_CRASH_REFUND"); got != 80 {
+		t.Errorf("refunded %.2f, want 80", got)
+	}
+	for _, bet := range e.State().Bets {
+		if !bet.Refunded {
+			t.Errorf("%s not marked refunded", bet.UserID)
+		}
+	}
+}
+
+func TestRoundEngineResumesHashChain(t *testing.T) {
+	seeds := fairness.NewSeedManager(nil)
+	settler := settlement.NewSettler(settlement.DefaultConfig(), &ledger{}, nil)
+
+	first := NewRoundEngine("resume", fastConfig(), settler, nil, seeds)
+	first.startRound(time.Now())
+	first.startRound(time.Now())
+	close(first.io)
+	first.persist(make(chan struct{}))
+
+	// A restarted table continues the same chain after the rounds handed out
+	second := NewRoundEngine("resume", fastConfig(), settler, nil, seeds)
+	if second.chain.Commitment != first.chain.Commitment {
+		t.Fatal("restarted table started a new chain")
+	}
+	second.startRound(time.Now())
+	if second.current.nonce != 3 {
+		t.Errorf("resumed at nonce %d, want 3", second.current.nonce)
+	}
+	if !fairness.VerifyChainLink(second.current.serverSeed, first.current.serverSeed) {
+		t.Error("resumed round does not link to the last one played")
+	}
+}
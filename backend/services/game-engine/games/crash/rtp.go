@@ -0,0 +1,55 @@
+package crash
+
+import (
+	"errors"
+	"math"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// parseSimulatedBet reads a BET payload. Simulated players always use auto cashout,
+// since there is no one to press the button.
+func parseSimulatedBet(bet map[string]interface{}) (amount, autoCashout float64, err error) {
+	amount, _ = bet["amount"].(float64)
+	autoCashout, _ = bet["auto_cashout"].(float64)
+	if amount <= 0 {
+		return 0, 0, errors.New("invalid bet amount")
+	}
+	if autoCashout < 1.01 {
+		return 0, 0, errors.New("auto cashout must be at least 1.01x")
+	}
+	return amount, autoCashout, nil
+}
+
+// settledMultiplier applies the per-bet payout cap to a cashout, as cashout() does
+func (g *CrashGame) settledMultiplier(amount, multiplier float64) float64 {
+	if g.config.MaxPayoutPerBet > 0 && amount*multiplier > g.config.MaxPayoutPerBet {
+		return capMultiplier(g.config.MaxPayoutPerBet, amount)
+	}
+	return multiplier
+}
+
+// TheoreticalRTP: the crash point reaches m with probability 0.99/m, so cashing out
+// at m returns 0.99 of the stake unless the per-bet cap cuts the multiplier
+func (g *CrashGame) TheoreticalRTP(bet map[string]interface{}) (float64, error) {
+	amount, autoCashout, err := parseSimulatedBet(bet)
+	if err != nil {
+		return 0, err
+	}
+	return 0.99 / autoCashout * g.settledMultiplier(amount, autoCashout), nil
+}
+
+// SimulateRound draws the crash point from the seed the way each hash chain link does.
+// The per-round liability cap depends on the other players and is not simulated.
+func (g *CrashGame) SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (float64, float64, error) {
+	amount, autoCashout, err := parseSimulatedBet(bet)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if fairness.CalculateCrashPoint(serverSeed, clientSeed, nonce) < autoCashout {
+		return amount, 0, nil
+	}
+	multiplier := g.settledMultiplier(amount, autoCashout)
+	return amount, math.Floor(amount*multiplier*100) / 100, nil
+}
@@ -0,0 +1,50 @@
+package crash
+
+import (
+	"database/sql"
+)
+
+// RoundStore persists finished Crash rounds
+type RoundStore interface {
+	SaveRound(record *RoundRecord) error
+}
+
+// PostgresRoundStore writes rounds to crash_rounds / crash_bets
+type PostgresRoundStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresRoundStore(db *sql.DB) *PostgresRoundStore {
+	return &PostgresRoundStore{DB: db}
+}
+
+// SaveRound stores the round and all of its bets in one transaction
+func (s *PostgresRoundStore) SaveRound(record *RoundRecord) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO crash_rounds (round_id, session_id, nonce, server_seed, server_seed_hash, client_seed,
+			chain_commitment, crash_point, total_wagered, total_payout, started_at, crashed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, record.RoundID, record.SessionID, record.Nonce, record.ServerSeed, record.ServerSeedHash, record.ClientSeed,
+		record.ChainCommitment, record.CrashPoint, record.TotalWagered, record.TotalPayout, record.StartedAt, record.CrashedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, bet := range record.Bets {
+		_, err = tx.Exec(`
+			INSERT INTO crash_bets (round_id, user_id, amount, auto_cashout, cashout_at, payout, capped)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, record.RoundID, bet.UserID, bet.Amount, bet.AutoCashout, bet.CashoutAt, bet.Payout, bet.Capped)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -14,21 +14,26 @@ type DiceGame struct {
 	gameID       string
 	entryFee     float64
 	walletClient *wallet.WalletClient
+	seeds        *fairness.SeedManager
 }
 
 type DiceState struct {
-	LastRoll  float64 `json:"last_roll"`
-	Target    float64 `json:"target"`
-	Condition string  `json:"condition"` // OVER, UNDER
-	Win       bool    `json:"win"`
-	Profit    float64 `json:"profit"`
+	LastRoll       float64 `json:"last_roll"`
+	Target         float64 `json:"target"`
+	Condition      string  `json:"condition"` // OVER, UNDER
+	Win            bool    `json:"win"`
+	Profit         float64 `json:"profit"`
+	ServerSeedHash string  `json:"server_seed_hash"`
+	ClientSeed     string  `json:"client_seed"`
+	Nonce          int     `json:"nonce"`
 }
 
-func NewDiceGame() *DiceGame {
+func NewDiceGame(seeds *fairness.SeedManager) *DiceGame {
 	return &DiceGame{
 		gameID:       "dice_classic",
 		entryFee:     1.0,
 		walletClient: wallet.NewWalletClient(),
+		seeds:        seeds,
 	}
 }
 
@@ -50,36 +55,33 @@ func (g *DiceGame) Start(session *engine.GameSession) error {
 
 func (g *DiceGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
 	if move.Type == "ROLL" {
-		amount := move.Data["amount"].(float64)
-		target := move.Data["target"].(float64)
-		condition := move.Data["condition"].(string)
+		amount, target, condition, err := parseRoll(move.Data)
+		if err != nil {
+			return nil, err
+		}
+		if amount < g.entryFee {
+			return nil, errors.New("bet amount too low")
+		}
 
 		roundID := fmt.Sprintf("dice_%s_%d", session.SessionID, time.Now().UnixNano())
 
-		// 1. Deduct Bet
-		err := g.walletClient.Debit(move.PlayerID, amount, roundID, "GAME_DICE")
+		// The round's seeds are committed before any money moves
+		seed, err := g.seeds.NextRound(move.PlayerID)
 		if err != nil {
-			return nil, fmt.Errorf("bet failed: %v", err)
+			return nil, fmt.Errorf("seed unavailable: %v", err)
 		}
 
-		// 2. Generate Result
-		roll := fairness.GenerateFloat("server_seed", "client_seed", 1) * 100
-
-		win := false
-		multiplier := 0.0
-
-		if condition == "UNDER" {
-			if roll < target {
-				win = true
-				multiplier = 99.0 / target // Standard Dice Multiplier Formula
-			}
-		} else if condition == "OVER" {
-			if roll > target {
-				win = true
-				multiplier = 99.0 / (100.0 - target)
-			}
+		// 1. Deduct Bet
+		if err := g.walletClient.Debit(move.PlayerID, amount, roundID, "GAME_DICE"); err != nil {
+			return nil, fmt.Errorf("bet failed: %v", err)
 		}
 
+		// 2. Generate Result from the player's own seed pair
+		roll := fairness.DiceRoll(seed.ServerSeed, seed.ClientSeed, seed.Nonce)
+
+		multiplier := payoutMultiplier(roll, target, condition)
+		win := multiplier > 0
+
 		profit := 0.0
 		if win {
 			profit = amount * multiplier
@@ -93,6 +95,10 @@ func (g *DiceGame) HandleMove(session *engine.GameSession, move engine.Move) (*e
 			Condition: condition,
 			Win:       win,
 			Profit:    profit,
+
+			ServerSeedHash: seed.ServerSeedHash,
+			ClientSeed:     seed.ClientSeed,
+			Nonce:          seed.Nonce,
 		}
 		session.State = state
 
@@ -0,0 +1,61 @@
+package dice
+
+import (
+	"errors"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// parseRoll validates the ROLL move payload
+func parseRoll(data map[string]interface{}) (amount, target float64, condition string, err error) {
+	amount, _ = data["amount"].(float64)
+	target, _ = data["target"].(float64)
+	condition, _ = data["condition"].(string)
+
+	if amount <= 0 {
+		return 0, 0, "", errors.New("invalid bet amount")
+	}
+	if target <= 0 || target >= 100 {
+		return 0, 0, "", errors.New("target must be between 0 and 100")
+	}
+	if condition != "UNDER" && condition != "OVER" {
+		return 0, 0, "", errors.New("condition must be UNDER or OVER")
+	}
+	return amount, target, condition, nil
+}
+
+// payoutMultiplier is the gross payout per unit staked for a roll (0 = lost)
+func payoutMultiplier(roll, target float64, condition string) float64 {
+	if condition == "UNDER" && roll < target {
+		return 99.0 / target // Standard Dice Multiplier Formula
+	}
+	if condition == "OVER" && roll > target {
+		return 99.0 / (100.0 - target)
+	}
+	return 0
+}
+
+// TheoreticalRTP counts the winning rolls exactly: rolls are the 10000 values 0.00 to 99.99
+func (g *DiceGame) TheoreticalRTP(bet map[string]interface{}) (float64, error) {
+	_, target, condition, err := parseRoll(bet)
+	if err != nil {
+		return 0, err
+	}
+
+	rtp := 0.0
+	for i := 0; i < 10000; i++ {
+		roll := float64(i) / 100
+		rtp += payoutMultiplier(roll, target, condition) / 10000
+	}
+	return rtp, nil
+}
+
+func (g *DiceGame) SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (float64, float64, error) {
+	amount, target, condition, err := parseRoll(bet)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	roll := fairness.DiceRoll(serverSeed, clientSeed, nonce)
+	return amount, amount * payoutMultiplier(roll, target, condition), nil
+}
@@ -0,0 +1,739 @@
+package fantasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/tournament"
+)
+
+// Contest Statuses
+const (
+	ContestOpen      = "OPEN"
+	ContestLive      = "LIVE" // The match has started and lineups are locked
+	ContestCompleted = "COMPLETED"
+	ContestCancelled = "CANCELLED"
+)
+
+// Team Statuses
+const (
+	TeamJoined   = "JOINED"
+	TeamRefunded = "REFUNDED" // Contest cancelled, entry fee returned
+)
+
+var (
+	ErrContestNotFound = errors.New("contest not found")
+	ErrTeamNotFound    = errors.New("team not found")
+	ErrContestFull     = errors.New("contest is full")
+	ErrEntryLimit      = errors.New("entry limit reached for this contest")
+	ErrLineupLocked    = errors.New("lineups are locked")
+	ErrInvalidStatus   = errors.New("contest is not in the right state")
+	ErrNotTeamOwner    = errors.New("team belongs to another user")
+	ErrInviteRequired  = errors.New("private leagues are joined with their invite code")
+	ErrNotCreator      = errors.New("only the league's creator can do this")
+)
+
+// Contest is a paid contest on one match. A guaranteed contest runs and pays
+// its full prize pool however few teams join; any other is cancelled and
+// refunded if it has fewer than MinTeams when the match starts, and pays the
+// prize pool in proportion to how full it is.
+//
+// A private league is a contest a user creates and shares by invite code. Its
+// prize pool is its entry fees less the platform's commission.
+type Contest struct {
+	ID                string    `json:"id"`
+	MatchID           string    `json:"match_id"`
+	Name              string    `json:"name"`
+	Format            string    `json:"format"`        // Scoring format, e.g. T20; defaults to T20
+	RulesVersion      int       `json:"rules_version"` // Scoring rules version current when the contest opened
+	EntryFee          float64   `json:"entry_fee"`
+	PrizePool         float64   `json:"prize_pool"` // Paid when full, or always if guaranteed
+	Guaranteed        bool      `json:"guaranteed"`
+	MaxTeams          int       `json:"max_teams"`            // Contest size
+	MinTeams          int       `json:"min_teams"`            // Fewest teams a contest that isn't guaranteed runs with; defaults to MaxTeams
+	MaxEntriesPerUser int       `json:"max_entries_per_user"` // Teams one user may enter
+	CurrentTeams      int       `json:"current_teams"`
+	PayoutTable       string    `json:"payout_table"` // Prize split, e.g. "1:50%, 2:30%, 3:20%"; see tournament.ParsePayoutTable
+	Status            string    `json:"status"`
+	StartTime         time.Time `json:"start_time"` // Match start, when lineups lock
+	Private           bool      `json:"private"`
+	CreatorID         string    `json:"creator_id,omitempty"`
+	InviteCode        string    `json:"invite_code,omitempty"`
+	CommissionPercent float64   `json:"commission_percent"` // Platform's cut of a private league's entry fees
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// takesLineups reports whether the contest takes entries and lineup changes at now
+func (c *Contest) takesLineups(now time.Time) bool {
+	return c.Status == ContestOpen && now.Before(c.StartTime)
+}
+
+// Pool is what the contest pays out with its current fill
+func (c *Contest) Pool() float64 {
+	if c.Guaranteed || c.MaxTeams == 0 {
+		return c.PrizePool
+	}
+	return c.PrizePool * float64(c.CurrentTeams) / float64(c.MaxTeams)
+}
+
+// Wallet collects entry fees and claws back prizes cut by a stats correction
+// (implemented by wallet.WalletClient). Prizes and refunds are paid through a
+// settlement.Settler.
+type Wallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+}
+
+// SquadSource supplies a match's player pool (implemented by CricketAPIClient)
+type SquadSource interface {
+	GetSquads(matchID string) ([]FantasyPlayer, error)
+}
+
+// StatsSource supplies a match's final player stats (implemented by CricketAPIClient)
+type StatsSource interface {
+	GetMatchStats(matchID string) (map[string]PlayerStats, error)
+}
+
+// Clock is the contest manager's time source, swapped for a fake one in tests
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// ContestManager runs contests from entry through lineup lock to payout
+type ContestManager struct {
+	Store Store
+	Teams map[string]TeamRules // Team constraints by sport, over the defaults
+	Clock Clock
+	Ranks *RankHub // Leaderboards pushed as points change
+
+	Notifier Notifier     // Tells users about their teams, e.g. benched players
+	Lineups  LineupSource // Playing XI announcements; optional
+
+	LeagueCommission float64 // Percent of private leagues' entry fees the platform keeps
+	LeagueMinFill    float64 // Share of its places a league must fill to run
+	MaxLeagueSize    int
+
+	squads  SquadSource
+	stats   StatsSource
+	wallet  Wallet
+	settler *settlement.Settler // Pays prizes and refunds, retrying failed credits
+	mu      sync.Mutex          // Serialises lifecycle changes
+
+	live   map[string]map[string]PlayerStats // MatchID -> stats so far, while live
+	liveMu sync.RWMutex
+}
+
+// NewContestManager creates a manager that collects entry fees from wallet and
+// pays prizes and refunds through settler
+func NewContestManager(store Store, squads SquadSource, stats StatsSource, wallet Wallet, settler *settlement.Settler) *ContestManager {
+	return &ContestManager{
+		Store: store,
+		Teams: make(map[string]TeamRules),
+		Clock: realClock{},
+		Ranks: NewRankHub(),
+
+		Notifier: LogNotifier{},
+
+		LeagueCommission: 10,
+		LeagueMinFill:    1,
+		MaxLeagueSize:    100,
+
+		squads:  squads,
+		stats:   stats,
+		wallet:  wallet,
+		settler: settler,
+		live:    make(map[string]map[string]PlayerStats),
+	}
+}
+
+// CreateContest opens a contest on a match that starts at startTime
+func (m *ContestManager) CreateContest(c *Contest) error {
+	if c.MatchID == "" || c.Name == "" {
+		return errors.New("match and name are required")
+	}
+	if c.MaxTeams < 2 {
+		return errors.New("a contest needs room for at least 2 teams")
+	}
+	if c.MinTeams == 0 {
+		c.MinTeams = c.MaxTeams
+	}
+	if c.MinTeams < 2 || c.MinTeams > c.MaxTeams {
+		return errors.New("min teams must be between 2 and the contest size")
+	}
+	if c.MaxEntriesPerUser < 1 {
+		c.MaxEntriesPerUser = 1
+	}
+	if c.EntryFee < 0 || c.PrizePool < 0 {
+		return errors.New("entry fee and prize pool can't be negative")
+	}
+	if c.PayoutTable == "" {
+		c.PayoutTable = "1:100%"
+	}
+	if c.Format == "" {
+		c.Format = FormatT20
+	}
+	rules, err := m.CurrentRules(c.Format)
+	if err != nil {
+		return err
+	}
+	c.RulesVersion = rules.Version
+	if _, err := tournament.ParsePayoutTable(c.PayoutTable); err != nil {
+		return err
+	}
+	now := m.Clock.Now()
+	if !c.StartTime.After(now) {
+		return errors.New("start time must be in the future")
+	}
+
+	c.ID = uuid.New().String()
+	c.Status = ContestOpen
+	c.CurrentTeams = 0
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	return m.Store.CreateContest(c)
+}
+
+// GetContest returns a contest, failing with ErrContestNotFound
+func (m *ContestManager) GetContest(contestID string) (*Contest, error) {
+	c, err := m.Store.GetContest(contestID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrContestNotFound
+	}
+	return c, nil
+}
+
+// Squad returns the match's player pool: the squad ingested for it with its
+// credits, or the squad source's
+func (m *ContestManager) Squad(matchID string) ([]FantasyPlayer, error) {
+	players, err := m.Store.MatchPlayers(matchID)
+	if err != nil || players != nil {
+		return players, err
+	}
+	return m.squads.GetSquads(matchID)
+}
+
+// Join enters a team picked from the match's player pool and collects the entry fee
+func (m *ContestManager) Join(contestID, userID string, playerIDs []string, captainID, viceCaptainID string) (*FantasyTeam, error) {
+	c, err := m.openContest(contestID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Private {
+		return nil, ErrInviteRequired
+	}
+	return m.enter(c, userID, playerIDs, captainID, viceCaptainID)
+}
+
+// enter adds a team to an open contest and collects the entry fee
+func (m *ContestManager) enter(c *Contest, userID string, playerIDs []string, captainID, viceCaptainID string) (*FantasyTeam, error) {
+	contestID := c.ID
+	players, err := m.pick(c.MatchID, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := m.builder(c)
+	if err != nil {
+		return nil, err
+	}
+	team, err := builder.CreateTeam(userID, contestID, c.MatchID, players, captainID, viceCaptainID)
+	if err != nil {
+		return nil, err
+	}
+	team.CreatedAt = m.Clock.Now()
+	team.UpdatedAt = team.CreatedAt
+
+	// Take the place first so a full contest never charges anyone, nor enters
+	// a contest that locked after openContest looked
+	if err := m.Store.AddTeam(team, c.MaxTeams, c.MaxEntriesPerUser); err != nil {
+		return nil, err
+	}
+	if c.EntryFee > 0 {
+		if err := m.wallet.Debit(userID, c.EntryFee, contestID, "FANTASY_ENTRY"); err != nil {
+			if removeErr := m.Store.RemoveTeam(team.ID); removeErr != nil {
+				log.Printf("contest %s: failed to free the place of team %s: %v", contestID, team.ID, removeErr)
+			}
+			return nil, fmt.Errorf("failed to collect entry fee: %v", err)
+		}
+	}
+	return team, nil
+}
+
+// EditLineup replaces a team's players and captains until the match starts
+func (m *ContestManager) EditLineup(teamID, userID string, playerIDs []string, captainID, viceCaptainID string) (*FantasyTeam, error) {
+	team, err := m.Store.GetTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+	if team.UserID != userID {
+		return nil, ErrNotTeamOwner
+	}
+	c, err := m.openContest(team.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	players, err := m.pick(c.MatchID, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := m.builder(c)
+	if err != nil {
+		return nil, err
+	}
+	lineup, err := builder.CreateTeam(userID, team.ContestID, c.MatchID, players, captainID, viceCaptainID)
+	if err != nil {
+		return nil, err
+	}
+
+	team.Players = lineup.Players
+	team.CaptainID = lineup.CaptainID
+	team.ViceCaptainID = lineup.ViceCaptainID
+	team.UpdatedAt = m.Clock.Now()
+	// Saved only if the contest is still open: it may have locked since openContest
+	if err := m.Store.UpdateLineup(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// openContest returns a contest that still takes entries and lineup changes.
+// The store checks again as it saves them.
+func (m *ContestManager) openContest(contestID string) (*Contest, error) {
+	c, err := m.GetContest(contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.takesLineups(m.Clock.Now()) {
+		return nil, ErrLineupLocked
+	}
+	return c, nil
+}
+
+// pick looks players up in the match's pool, so costs and roles come from us
+// rather than the client
+func (m *ContestManager) pick(matchID string, playerIDs []string) ([]FantasyPlayer, error) {
+	squad, err := m.Squad(matchID)
+	if err != nil {
+		return nil, err
+	}
+	pool := make(map[string]FantasyPlayer, len(squad))
+	for _, p := range squad {
+		pool[p.PlayerID] = p
+	}
+	players := make([]FantasyPlayer, 0, len(playerIDs))
+	for _, id := range playerIDs {
+		p, ok := pool[id]
+		if !ok {
+			return nil, fmt.Errorf("player %s is not in this match", id)
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// LockDue locks the lineups of every open contest whose match has started.
+// Contests short of their minimum that aren't guaranteed are cancelled instead.
+func (m *ContestManager) LockDue(now time.Time) {
+	open, err := m.Store.ListContests("", ContestOpen)
+	if err != nil {
+		log.Printf("fantasy: failed to list open contests: %v", err)
+		return
+	}
+	for _, c := range open {
+		if c.StartTime.After(now) {
+			break // Listed by start time
+		}
+		if err := m.lock(c.ID); err != nil {
+			log.Printf("contest %s: failed to lock: %v", c.ID, err)
+		}
+	}
+}
+
+func (m *ContestManager) lock(contestID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.GetContest(contestID)
+	if err != nil {
+		return err
+	}
+	if c.Status != ContestOpen {
+		return nil
+	}
+	if !c.Guaranteed && c.CurrentTeams < c.MinTeams {
+		log.Printf("contest %s: %d of %d teams, cancelling", c.ID, c.CurrentTeams, c.MinTeams)
+		return m.cancel(c)
+	}
+	c.Status = ContestLive
+	c.UpdatedAt = m.Clock.Now()
+	return m.Store.UpdateContest(c)
+}
+
+// CancelContest cancels a contest that hasn't been settled and refunds every
+// entry. Cancelling a cancelled contest again refunds any entry a failure left
+// unrefunded.
+func (m *ContestManager) CancelContest(contestID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.GetContest(contestID)
+	if err != nil {
+		return err
+	}
+	if c.Status != ContestOpen && c.Status != ContestLive && c.Status != ContestCancelled {
+		return ErrInvalidStatus
+	}
+	return m.cancel(c)
+}
+
+// cancel closes the contest to entries, then refunds every team still JOINED
+func (m *ContestManager) cancel(c *Contest) error {
+	if c.Status != ContestCancelled {
+		c.Status = ContestCancelled
+		c.UpdatedAt = m.Clock.Now()
+		if err := m.Store.UpdateContest(c); err != nil {
+			return err
+		}
+	}
+
+	teams, err := m.Store.Teams(c.ID)
+	if err != nil {
+		return err
+	}
+	refunded := 0
+	for _, team := range teams {
+		if team.Status != TeamJoined {
+			continue
+		}
+		// Marked first, so cancelling again never refunds a team twice
+		team.Status = TeamRefunded
+		team.UpdatedAt = m.Clock.Now()
+		if err := m.Store.UpdateTeam(team); err != nil {
+			return fmt.Errorf("failed to mark team %s refunded: %v", team.ID, err)
+		}
+		m.settler.Pay(settlement.Credit{UserID: team.UserID, Amount: c.EntryFee, RefID: c.ID, RefType: "FANTASY_REFUND"})
+		refunded++
+	}
+	log.Printf("contest %s cancelled, %d entries refunded", c.ID, refunded)
+	return nil
+}
+
+// CompleteMatch settles a finished match from its final stats as the stats
+// source reports them
+func (m *ContestManager) CompleteMatch(matchID string) error {
+	stats, err := m.stats.GetMatchStats(matchID)
+	if err != nil {
+		return err
+	}
+	return m.SettleMatch(matchID, stats)
+}
+
+// SettleMatch records a finished match's final stats and completes and pays
+// out every live contest on it
+func (m *ContestManager) SettleMatch(matchID string, stats map[string]PlayerStats) error {
+	stats = m.withPlayingXI(matchID, stats)
+	if err := m.Store.SaveMatchStats(matchID, stats); err != nil {
+		return err
+	}
+	m.liveMu.Lock()
+	delete(m.live, matchID)
+	m.liveMu.Unlock()
+
+	live, err := m.Store.ListContests(matchID, ContestLive)
+	if err != nil {
+		return err
+	}
+	for _, c := range live {
+		if err := m.settle(c.ID, stats); err != nil {
+			return fmt.Errorf("contest %s: %v", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// Rescore updates the points and ranks of the teams in a match's live
+// contests from the stats so far, and pushes the new leaderboards
+func (m *ContestManager) Rescore(matchID string, stats map[string]PlayerStats) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats = m.withPlayingXI(matchID, stats)
+	m.liveMu.Lock()
+	m.live[matchID] = stats
+	m.liveMu.Unlock()
+
+	live, err := m.Store.ListContests(matchID, ContestLive)
+	if err != nil {
+		return err
+	}
+	now := m.Clock.Now()
+	for _, c := range live {
+		teams, err := m.Store.Teams(c.ID)
+		if err != nil {
+			return err
+		}
+		board, err := m.rank(c, teams, stats)
+		if err != nil {
+			return err
+		}
+		for _, team := range teams {
+			team.UpdatedAt = now
+			if err := m.Store.UpdateTeam(team); err != nil {
+				return err
+			}
+		}
+		m.Ranks.Publish(c.ID, board.Entries)
+	}
+	return nil
+}
+
+// CorrectStats replaces players' stats in a settled match, e.g. after an
+// official scoring correction, and reranks and pays out its completed contests
+// again. Only the difference from what each team was paid moves.
+func (m *ContestManager) CorrectStats(matchID string, corrections map[string]PlayerStats) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, err := m.Store.MatchStats(matchID)
+	if err != nil {
+		return err
+	}
+	if stats == nil {
+		return ErrInvalidStatus // Not settled yet
+	}
+	for playerID, corrected := range corrections {
+		stats[playerID] = corrected
+	}
+	if err := m.Store.SaveMatchStats(matchID, stats); err != nil {
+		return err
+	}
+
+	completed, err := m.Store.ListContests(matchID, ContestCompleted)
+	if err != nil {
+		return err
+	}
+	for _, c := range completed {
+		if err := m.award(c, stats, "FANTASY_PRIZE_ADJUSTMENT"); err != nil {
+			return fmt.Errorf("contest %s: %v", c.ID, err)
+		}
+		log.Printf("contest %s rescored after a stats correction", c.ID)
+	}
+	return nil
+}
+
+// settle pays out a live contest, then completes it. A contest whose award
+// fails stays live, and settling it again pays only what is still owed.
+func (m *ContestManager) settle(contestID string, stats map[string]PlayerStats) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.GetContest(contestID)
+	if err != nil {
+		return err
+	}
+	if c.Status != ContestLive {
+		return ErrInvalidStatus
+	}
+	if err := m.award(c, stats, "FANTASY_PRIZE"); err != nil {
+		return err
+	}
+	c.Status = ContestCompleted
+	c.UpdatedAt = m.Clock.Now()
+	if err := m.Store.UpdateContest(c); err != nil {
+		return err
+	}
+	log.Printf("contest %s completed", c.ID)
+	return nil
+}
+
+// award scores and ranks a contest's teams, teams on equal points sharing a
+// rank, and splits the pool by the payout table, tied teams sharing the prizes
+// of the places they cover. Each team is paid the difference between its
+// prize and what it has already been paid, so awarding again after a
+// correction, or after a failure, credits or claws back only the change.
+//
+// A team's prize is saved before its money moves, so a failure can't pay it
+// twice. Credits go to the settler; a claw back the wallet refuses leaves the
+// team at what it was paid and fails the award, to be retried.
+func (m *ContestManager) award(c *Contest, stats map[string]PlayerStats, refType string) error {
+	table, err := tournament.ParsePayoutTable(c.PayoutTable)
+	if err != nil {
+		return err
+	}
+	teams, err := m.Store.Teams(c.ID)
+	if err != nil {
+		return err
+	}
+
+	board, err := m.rank(c, teams, stats)
+	if err != nil {
+		return err
+	}
+	placings := make([]tournament.Placing, len(board.Entries))
+	for i, entry := range board.Entries {
+		placings[i] = tournament.Placing{ID: entry.TeamID, Rank: entry.Rank}
+	}
+	prizes, _ := table.Split(c.Pool(), placings)
+
+	now := m.Clock.Now()
+	failed := 0
+	for _, team := range teams {
+		team.UpdatedAt = now
+		paid := team.PrizeAmount
+		paise := int64(math.Round(prizes[team.ID]*100)) - int64(math.Round(paid*100))
+		change := float64(paise) / 100
+		team.PrizeAmount = prizes[team.ID]
+		if err := m.Store.UpdateTeam(team); err != nil {
+			return fmt.Errorf("failed to save result of team %s: %v", team.ID, err)
+		}
+
+		switch {
+		case change > 0:
+			m.settler.Pay(settlement.Credit{UserID: team.UserID, Amount: change, RefID: c.ID, RefType: refType})
+		case change < 0:
+			if err := m.wallet.Debit(team.UserID, -change, c.ID, refType); err != nil {
+				log.Printf("contest %s: failed to claw back %.2f from team %s: %v", c.ID, -change, team.ID, err)
+				failed++
+				team.PrizeAmount = paid
+				if err := m.Store.UpdateTeam(team); err != nil {
+					return fmt.Errorf("failed to save result of team %s: %v", team.ID, err)
+				}
+			}
+		}
+	}
+	m.Ranks.Publish(c.ID, board.Entries)
+	if failed > 0 {
+		return fmt.Errorf("failed to claw back prizes from %d teams", failed)
+	}
+	return nil
+}
+
+// rank scores teams from the stats and sets their ranks
+func (m *ContestManager) rank(c *Contest, teams []*FantasyTeam, stats map[string]PlayerStats) (*Leaderboard, error) {
+	rules, err := m.Rules(c.Format, c.RulesVersion)
+	if err != nil {
+		return nil, err
+	}
+	scorer := NewFantasyScorerFor(rules)
+	for _, team := range teams {
+		scorer.CalculateTeamPoints(team, stats)
+	}
+	board := NewLeaderboard(c.ID)
+	board.UpdateRankings(teams)
+	ranks := make(map[string]int, len(board.Entries))
+	for _, entry := range board.Entries {
+		ranks[entry.TeamID] = entry.Rank
+	}
+	for _, team := range teams {
+		team.Rank = ranks[team.ID]
+	}
+	return board, nil
+}
+
+// CurrentRules returns the latest scoring rules of a format
+func (m *ContestManager) CurrentRules(format string) (*ScoringRules, error) {
+	rules, err := m.Store.LatestScoringRules(format)
+	if err != nil || rules != nil {
+		return rules, err
+	}
+	return DefaultScoringRules(format)
+}
+
+// Rules returns a version of a format's scoring rules. Version 1 of a built-in
+// format is its default.
+func (m *ContestManager) Rules(format string, version int) (*ScoringRules, error) {
+	rules, err := m.Store.GetScoringRules(format, version)
+	if err != nil || rules != nil {
+		return rules, err
+	}
+	if version == 1 {
+		return DefaultScoringRules(format)
+	}
+	return nil, fmt.Errorf("no version %d of the %s scoring rules", version, format)
+}
+
+// PublishRules saves a new version of a format's scoring rules. Contests
+// opened from now on use it; those already open keep theirs.
+func (m *ContestManager) PublishRules(rules *ScoringRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	latest, err := m.Store.LatestScoringRules(rules.Format)
+	if err != nil {
+		return err
+	}
+	rules.Version = 1 // A new format
+	if latest != nil {
+		rules.Version = latest.Version + 1
+	} else if _, err := DefaultScoringRules(rules.Format); err == nil {
+		rules.Version = 2 // After the built-in default
+	}
+	rules.CreatedAt = m.Clock.Now()
+	return m.Store.CreateScoringRules(rules)
+}
+
+// builder returns a team builder for the sport of a contest's rules
+func (m *ContestManager) builder(c *Contest) (*TeamBuilder, error) {
+	rules, err := m.Rules(c.Format, c.RulesVersion)
+	if err != nil {
+		return nil, err
+	}
+	if teamRules, ok := m.Teams[rules.Sport]; ok {
+		return NewTeamBuilderFor(teamRules), nil
+	}
+	teamRules, err := DefaultTeamRules(rules.Sport)
+	if err != nil {
+		return nil, err
+	}
+	return NewTeamBuilderFor(teamRules), nil
+}
+
+// Leaderboard ranks a contest's teams by their points so far
+func (m *ContestManager) Leaderboard(contestID string) (*Leaderboard, error) {
+	c, err := m.GetContest(contestID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := m.Store.Teams(c.ID)
+	if err != nil {
+		return nil, err
+	}
+	board := NewLeaderboard(c.ID)
+	board.UpdateRankings(teams)
+	return board, nil
+}
+
+// Run locks contests as their matches start, and picks up playing XI
+// announcements before they do, until ctx is cancelled
+func (m *ContestManager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := m.Clock.Now()
+			if m.Lineups != nil {
+				m.pollLineups(now)
+			}
+			m.LockDue(now)
+		}
+	}
+}
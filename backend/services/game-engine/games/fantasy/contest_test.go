@@ -0,0 +1,119 @@
+package fantasy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/settlement"
+)
+
+// flakyStore fails to save teams while down is set
+type flakyStore struct {
+	*MemoryStore
+	down bool
+}
+
+func (s *flakyStore) UpdateTeam(t *FantasyTeam) error {
+	if s.down {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryStore.UpdateTeam(t)
+}
+
+// okWallet accepts every debit and credit
+type okWallet struct{}
+
+func (okWallet) Debit(userID string, amount float64, refID, refType string) error  { return nil }
+func (okWallet) Credit(userID string, amount float64, refID, refType string) error { return nil }
+
+func TestSettleCompletesOnlyOnceAwarded(t *testing.T) {
+	store := &flakyStore{MemoryStore: NewMemoryStore()}
+	settler := settlement.NewSettler(settlement.DefaultConfig(), okWallet{}, nil)
+	m := NewContestManager(store, nil, nil, okWallet{}, settler)
+
+	contest := &Contest{
+		ID: "c1", MatchID: "m1", Format: FormatT20, RulesVersion: 1, PrizePool: 100, Guaranteed: true,
+		MaxTeams: 2, PayoutTable: "1:100%", Status: ContestOpen, StartTime: time.Now().Add(time.Hour),
+	}
+	if err := store.CreateContest(contest); err != nil {
+		t.Fatal(err)
+	}
+	for _, team := range []*FantasyTeam{
+		{ID: "t1", UserID: "u1", ContestID: "c1", Players: []FantasyPlayer{{PlayerID: "a", Role: RoleBatsman}}},
+		{ID: "t2", UserID: "u2", ContestID: "c1", Players: []FantasyPlayer{{PlayerID: "b", Role: RoleBatsman}}},
+	} {
+		team.Status, team.CreatedAt = TeamJoined, time.Now()
+		if err := store.AddTeam(team, 2, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	contest.Status = ContestLive
+	if err := store.UpdateContest(contest); err != nil {
+		t.Fatal(err)
+	}
+	stats := map[string]PlayerStats{"a": {Runs: 50, BallsFaced: 30}, "b": {Runs: 5, BallsFaced: 10}}
+
+	store.down = true
+	if err := m.SettleMatch("m1", stats); err == nil {
+		t.Fatal("settled without saving any prize")
+	}
+	if c, _ := m.GetContest("c1"); c.Status != ContestLive {
+		t.Fatalf("contest %s after a failed award, want LIVE", c.Status)
+	}
+	if n := settler.Pending(); n != 0 {
+		t.Fatalf("%d credits queued for prizes that weren't saved", n)
+	}
+
+	// Settling again pays the prize once and completes the contest
+	store.down = false
+	for i := 0; i < 2; i++ {
+		if err := m.SettleMatch("m1", stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c, _ := m.GetContest("c1"); c.Status != ContestCompleted {
+		t.Fatalf("contest %s, want COMPLETED", c.Status)
+	}
+	if n := settler.Pending(); n != 1 {
+		t.Fatalf("%d credits queued, want the one prize", n)
+	}
+	if winner, _ := store.GetTeam("t1"); winner.PrizeAmount != 100 {
+		t.Errorf("winner's prize %.2f, want 100", winner.PrizeAmount)
+	}
+}
+
+func TestCancelAgainRefundsOnlyWhatIsLeft(t *testing.T) {
+	store := &flakyStore{MemoryStore: NewMemoryStore()}
+	settler := settlement.NewSettler(settlement.DefaultConfig(), okWallet{}, nil)
+	m := NewContestManager(store, nil, nil, okWallet{}, settler)
+
+	contest := &Contest{ID: "c1", MatchID: "m1", EntryFee: 25, MaxTeams: 3, Status: ContestOpen, StartTime: time.Now().Add(time.Hour)}
+	if err := store.CreateContest(contest); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"t1", "t2", "t3"} {
+		team := &FantasyTeam{ID: id, UserID: "u_" + id, ContestID: "c1", Status: TeamJoined, CreatedAt: time.Now()}
+		if err := store.AddTeam(team, 3, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store.down = true
+	if err := m.CancelContest("c1"); err == nil {
+		t.Fatal("cancel reported success without refunding anyone")
+	}
+	if c, _ := m.GetContest("c1"); c.Status != ContestCancelled {
+		t.Fatalf("contest %s, want CANCELLED so no one else can enter", c.Status)
+	}
+
+	store.down = false
+	for i := 0; i < 2; i++ {
+		if err := m.CancelContest("c1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := settler.Pending(); n != 3 {
+		t.Fatalf("%d refunds queued, want one per team", n)
+	}
+}
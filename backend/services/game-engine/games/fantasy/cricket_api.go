@@ -54,13 +54,30 @@ func (c *CricketAPIClient) getMockStats(matchID string) map[string]PlayerStats {
 
 // GetSquads fetches players for a match
 func (c *CricketAPIClient) GetSquads(matchID string) ([]FantasyPlayer, error) {
-	// Mock squad data
+	// Mock squad data: two full squads, enough to pick a valid eleven
 	players := []FantasyPlayer{
 		{PlayerID: "player_1", Name: "Virat Kohli", Team: "IND", Role: RoleBatsman, Cost: 10.5},
 		{PlayerID: "player_2", Name: "Jasprit Bumrah", Team: "IND", Role: RoleBowler, Cost: 9.5},
 		{PlayerID: "player_3", Name: "Steve Smith", Team: "AUS", Role: RoleBatsman, Cost: 10.0},
 		{PlayerID: "player_4", Name: "Pat Cummins", Team: "AUS", Role: RoleAllRounder, Cost: 9.0},
-		// Add more mock players...
+		{PlayerID: "player_5", Name: "Rohit Sharma", Team: "IND", Role: RoleBatsman, Cost: 10.0},
+		{PlayerID: "player_6", Name: "Shubman Gill", Team: "IND", Role: RoleBatsman, Cost: 9.0},
+		{PlayerID: "player_7", Name: "Rishabh Pant", Team: "IND", Role: RoleWicketKeeper, Cost: 9.0},
+		{PlayerID: "player_8", Name: "Hardik Pandya", Team: "IND", Role: RoleAllRounder, Cost: 9.0},
+		{PlayerID: "player_9", Name: "Ravindra Jadeja", Team: "IND", Role: RoleAllRounder, Cost: 8.5},
+		{PlayerID: "player_10", Name: "Kuldeep Yadav", Team: "IND", Role: RoleBowler, Cost: 8.0},
+		{PlayerID: "player_11", Name: "Mohammed Siraj", Team: "IND", Role: RoleBowler, Cost: 8.0},
+		{PlayerID: "player_12", Name: "Arshdeep Singh", Team: "IND", Role: RoleBowler, Cost: 7.5},
+		{PlayerID: "player_13", Name: "Suryakumar Yadav", Team: "IND", Role: RoleBatsman, Cost: 9.0},
+		{PlayerID: "player_14", Name: "David Warner", Team: "AUS", Role: RoleBatsman, Cost: 9.5},
+		{PlayerID: "player_15", Name: "Travis Head", Team: "AUS", Role: RoleBatsman, Cost: 9.0},
+		{PlayerID: "player_16", Name: "Marnus Labuschagne", Team: "AUS", Role: RoleBatsman, Cost: 8.5},
+		{PlayerID: "player_17", Name: "Alex Carey", Team: "AUS", Role: RoleWicketKeeper, Cost: 8.0},
+		{PlayerID: "player_18", Name: "Glenn Maxwell", Team: "AUS", Role: RoleAllRounder, Cost: 9.0},
+		{PlayerID: "player_19", Name: "Mitchell Marsh", Team: "AUS", Role: RoleAllRounder, Cost: 8.5},
+		{PlayerID: "player_20", Name: "Mitchell Starc", Team: "AUS", Role: RoleBowler, Cost: 9.0},
+		{PlayerID: "player_21", Name: "Josh Hazlewood", Team: "AUS", Role: RoleBowler, Cost: 8.5},
+		{PlayerID: "player_22", Name: "Adam Zampa", Team: "AUS", Role: RoleBowler, Cost: 8.0},
 	}
 
 	return players, nil
@@ -2,6 +2,7 @@ package fantasy
 
 import (
 	"sort"
+	"sync"
 )
 
 // LeaderboardEntry represents a single entry in the leaderboard
@@ -73,3 +74,49 @@ func (l *Leaderboard) GetUserRank(userID string) *LeaderboardEntry {
 	}
 	return nil
 }
+
+// RankHub pushes contest leaderboards to subscribers as they change. A slow
+// subscriber only misses boards that have since been replaced.
+type RankHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan []LeaderboardEntry]struct{} // ContestID -> subscribers
+}
+
+// NewRankHub creates an empty hub
+func NewRankHub() *RankHub {
+	return &RankHub{subs: make(map[string]map[chan []LeaderboardEntry]struct{})}
+}
+
+// Subscribe returns a contest's board updates and a func to stop them
+func (h *RankHub) Subscribe(contestID string) (<-chan []LeaderboardEntry, func()) {
+	ch := make(chan []LeaderboardEntry, 1)
+	h.mu.Lock()
+	if h.subs[contestID] == nil {
+		h.subs[contestID] = make(map[chan []LeaderboardEntry]struct{})
+	}
+	h.subs[contestID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[contestID], ch)
+		if len(h.subs[contestID]) == 0 {
+			delete(h.subs, contestID)
+		}
+	}
+}
+
+// Publish sends a contest's new board to its subscribers, replacing any board
+// they haven't read yet
+func (h *RankHub) Publish(contestID string, entries []LeaderboardEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[contestID] {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- entries
+	}
+}
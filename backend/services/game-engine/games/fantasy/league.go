@@ -0,0 +1,176 @@
+package fantasy
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Invite codes avoid letters and digits that are easy to mix up
+const (
+	inviteAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	inviteLength   = 8
+)
+
+// CreateLeague opens a private league on a match for a user. The creator picks
+// the entry fee, size, minimum and prize split; the prize pool is the entry
+// fees less LeagueCommission, and the league locks when the match's public
+// contests do. A league short of its minimum at the start is cancelled and
+// refunded like any contest.
+func (m *ContestManager) CreateLeague(creatorID string, c *Contest) error {
+	if creatorID == "" {
+		return errors.New("creator is required")
+	}
+	if c.MaxTeams < 2 || c.MaxTeams > m.MaxLeagueSize {
+		return fmt.Errorf("a league has between 2 and %d teams", m.MaxLeagueSize)
+	}
+	minFill := max(2, int(math.Ceil(float64(c.MaxTeams)*m.LeagueMinFill)))
+	if c.MinTeams == 0 {
+		c.MinTeams = c.MaxTeams
+	}
+	if c.MinTeams < minFill {
+		return fmt.Errorf("a league of %d needs at least %d teams to run", c.MaxTeams, minFill)
+	}
+	start, err := m.matchStart(c.MatchID)
+	if err != nil {
+		return err
+	}
+
+	c.Private = true
+	c.CreatorID = creatorID
+	c.Guaranteed = false
+	c.StartTime = start
+	c.CommissionPercent = m.LeagueCommission
+	// Paid in proportion to the fill, so the pool is always the fees less commission
+	c.PrizePool = math.Round(c.EntryFee*float64(c.MaxTeams)*(100-c.CommissionPercent)) / 100
+
+	for attempt := 0; ; attempt++ {
+		code, err := newInviteCode()
+		if err != nil {
+			return err
+		}
+		existing, err := m.Store.GetContestByInvite(code)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			c.InviteCode = code
+			break
+		}
+		if attempt == 4 {
+			return errors.New("failed to pick an invite code")
+		}
+	}
+	return m.CreateContest(c)
+}
+
+// matchStart is when a match starts, as its public contests have it. Leagues
+// can only be made on matches with a public contest open.
+func (m *ContestManager) matchStart(matchID string) (start time.Time, err error) {
+	open, err := m.Store.ListContests(matchID, ContestOpen)
+	if err != nil {
+		return start, err
+	}
+	for _, c := range open {
+		if !c.Private {
+			return c.StartTime, nil
+		}
+	}
+	return start, fmt.Errorf("no contests are open on match %s", matchID)
+}
+
+// League finds a league by its invite code
+func (m *ContestManager) League(inviteCode string) (*Contest, error) {
+	c, err := m.Store.GetContestByInvite(strings.ToUpper(strings.TrimSpace(inviteCode)))
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrContestNotFound
+	}
+	return c, nil
+}
+
+// JoinLeague enters a team in the league with an invite code
+func (m *ContestManager) JoinLeague(inviteCode, userID string, playerIDs []string, captainID, viceCaptainID string) (*FantasyTeam, error) {
+	league, err := m.League(inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	c, err := m.openContest(league.ID)
+	if err != nil {
+		return nil, err
+	}
+	return m.enter(c, userID, playerIDs, captainID, viceCaptainID)
+}
+
+// LockLeague closes a league to entries and lineup changes ahead of the match.
+// Only its creator can, and only once it has its minimum.
+func (m *ContestManager) LockLeague(contestID, userID string) (*Contest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.creatorsLeague(contestID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if c.CurrentTeams < c.MinTeams {
+		return nil, fmt.Errorf("league has %d of the %d teams it needs", c.CurrentTeams, c.MinTeams)
+	}
+	c.Status = ContestLive
+	c.UpdatedAt = m.Clock.Now()
+	if err := m.Store.UpdateContest(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CancelLeague cancels a league and refunds its entries. Only its creator can,
+// and only before the match starts.
+func (m *ContestManager) CancelLeague(contestID, userID string) (*Contest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.creatorsLeague(contestID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.cancel(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// creatorsLeague returns an open league belonging to userID
+func (m *ContestManager) creatorsLeague(contestID, userID string) (*Contest, error) {
+	c, err := m.GetContest(contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Private {
+		return nil, ErrContestNotFound
+	}
+	if c.CreatorID != userID {
+		return nil, ErrNotCreator
+	}
+	if c.Status != ContestOpen || !m.Clock.Now().Before(c.StartTime) {
+		return nil, ErrLineupLocked
+	}
+	return c, nil
+}
+
+func newInviteCode() (string, error) {
+	code := make([]byte, inviteLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(inviteAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = inviteAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
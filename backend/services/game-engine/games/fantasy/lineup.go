@@ -0,0 +1,332 @@
+package fantasy
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Lineup Statuses, set on a match's player pool when the playing XI is announced
+const (
+	LineupPlaying    = "PLAYING"
+	LineupNotPlaying = "NOT_PLAYING"
+)
+
+// LineupWindow is how long before the start a match's playing XI is looked for
+const LineupWindow = 90 * time.Minute
+
+// Notification tells a user something about one of their teams
+type Notification struct {
+	Type      string   `json:"type"`
+	UserID    string   `json:"user_id"`
+	MatchID   string   `json:"match_id"`
+	ContestID string   `json:"contest_id"`
+	TeamID    string   `json:"team_id"`
+	PlayerIDs []string `json:"player_ids"`
+	Message   string   `json:"message"`
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// LogNotifier only logs notifications, until a push service is wired in
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(n Notification) error {
+	log.Printf("fantasy notification for %s: %s", n.UserID, n.Message)
+	return nil
+}
+
+// LineupSource reports a match's announced playing XI, or nil before it is
+// announced (implemented by FileReplayFeed)
+type LineupSource interface {
+	GetPlayingXI(matchID string) ([]string, error)
+}
+
+// IngestSquad replaces a match's player pool, e.g. with the announced squad
+// and this match's credits. Teams already entered keep the credits they were
+// picked at; players keep any playing XI status already announced.
+func (m *ContestManager) IngestSquad(matchID string, players []FantasyPlayer) error {
+	if len(players) == 0 {
+		return errors.New("squad is empty")
+	}
+	previous, err := m.Store.MatchPlayers(matchID)
+	if err != nil {
+		return err
+	}
+	lineups := make(map[string]string, len(previous))
+	for _, p := range previous {
+		lineups[p.PlayerID] = p.Lineup
+	}
+
+	seen := make(map[string]bool, len(players))
+	for i := range players {
+		p := &players[i]
+		if p.PlayerID == "" || p.Name == "" || p.Team == "" || p.Role == "" {
+			return fmt.Errorf("player %d: id, name, team and role are required", i+1)
+		}
+		if p.Cost <= 0 {
+			return fmt.Errorf("player %s: credits must be positive", p.PlayerID)
+		}
+		if seen[p.PlayerID] {
+			return fmt.Errorf("player %s is listed twice", p.PlayerID)
+		}
+		seen[p.PlayerID] = true
+		p.Role = strings.ToUpper(p.Role)
+		if p.Lineup == "" {
+			p.Lineup = lineups[p.PlayerID]
+		}
+	}
+	return m.Store.SaveMatchPlayers(matchID, players)
+}
+
+// AnnounceLineup records a match's playing XI, flagging every other player in
+// the pool as not playing, and notifies users whose teams in open contests
+// picked any of them. It returns how many teams were affected.
+func (m *ContestManager) AnnounceLineup(matchID string, playing []string) (int, error) {
+	if len(playing) == 0 {
+		return 0, errors.New("no players announced")
+	}
+	pool, err := m.Squad(matchID)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[string]bool, len(pool))
+	for _, p := range pool {
+		byID[p.PlayerID] = true
+	}
+	inXI := make(map[string]bool, len(playing))
+	for _, id := range playing {
+		if !byID[id] {
+			return 0, fmt.Errorf("player %s is not in this match", id)
+		}
+		inXI[id] = true
+	}
+
+	names := make(map[string]string, len(pool))
+	for i := range pool {
+		pool[i].Lineup = LineupNotPlaying
+		if inXI[pool[i].PlayerID] {
+			pool[i].Lineup = LineupPlaying
+		}
+		names[pool[i].PlayerID] = pool[i].Name
+	}
+	if err := m.Store.SaveMatchPlayers(matchID, pool); err != nil {
+		return 0, err
+	}
+
+	open, err := m.Store.ListContests(matchID, ContestOpen)
+	if err != nil {
+		return 0, err
+	}
+	affected := 0
+	for _, c := range open {
+		teams, err := m.Store.Teams(c.ID)
+		if err != nil {
+			return affected, err
+		}
+		for _, team := range teams {
+			benched := []string{}
+			for _, p := range team.Players {
+				if !inXI[p.PlayerID] {
+					benched = append(benched, p.PlayerID)
+				}
+			}
+			if len(benched) == 0 {
+				continue
+			}
+			affected++
+			benchedNames := make([]string, len(benched))
+			for i, id := range benched {
+				benchedNames[i] = names[id]
+			}
+			n := Notification{
+				Type:      "LINEUP_ANNOUNCED",
+				UserID:    team.UserID,
+				MatchID:   matchID,
+				ContestID: c.ID,
+				TeamID:    team.ID,
+				PlayerIDs: benched,
+				Message:   fmt.Sprintf("%s: not in the playing XI for %s. Edit your team before the match starts.", strings.Join(benchedNames, ", "), c.Name),
+			}
+			if err := m.Notifier.Notify(n); err != nil {
+				log.Printf("contest %s: failed to notify %s about team %s: %v", c.ID, team.UserID, team.ID, err)
+			}
+		}
+	}
+	log.Printf("match %s: playing XI announced, %d teams affected", matchID, affected)
+	return affected, nil
+}
+
+// pollLineups asks the lineup source for the playing XI of matches starting
+// within LineupWindow that haven't had one announced yet
+func (m *ContestManager) pollLineups(now time.Time) {
+	open, err := m.Store.ListContests("", ContestOpen)
+	if err != nil {
+		log.Printf("fantasy: failed to list open contests: %v", err)
+		return
+	}
+	polled := make(map[string]bool)
+	for _, c := range open {
+		if c.StartTime.Sub(now) > LineupWindow {
+			break // Listed by start time
+		}
+		if polled[c.MatchID] {
+			continue
+		}
+		polled[c.MatchID] = true
+
+		announced, err := m.lineupAnnounced(c.MatchID)
+		if err != nil || announced {
+			continue
+		}
+		playing, err := m.Lineups.GetPlayingXI(c.MatchID)
+		if err != nil {
+			log.Printf("match %s: failed to get the playing XI: %v", c.MatchID, err)
+			continue
+		}
+		if playing == nil {
+			continue
+		}
+		if _, err := m.AnnounceLineup(c.MatchID, playing); err != nil {
+			log.Printf("match %s: failed to announce the playing XI: %v", c.MatchID, err)
+		}
+	}
+}
+
+func (m *ContestManager) lineupAnnounced(matchID string) (bool, error) {
+	pool, err := m.Store.MatchPlayers(matchID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range pool {
+		if p.Lineup != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// withPlayingXI marks the announced playing XI in a match's stats, so they
+// earn the playing XI bonus whether or not they have batted or bowled yet
+func (m *ContestManager) withPlayingXI(matchID string, stats map[string]PlayerStats) map[string]PlayerStats {
+	pool, err := m.Store.MatchPlayers(matchID)
+	if err != nil {
+		log.Printf("match %s: failed to load the player pool: %v", matchID, err)
+		return stats
+	}
+	marked := make(map[string]PlayerStats, len(stats))
+	for id, st := range stats {
+		marked[id] = st
+	}
+	for _, p := range pool {
+		if p.Lineup == LineupPlaying {
+			st := marked[p.PlayerID]
+			st.InPlayingXI = true
+			marked[p.PlayerID] = st
+		}
+	}
+	return marked
+}
+
+// PlayerSelection is how a contest's teams picked a player, shown once
+// lineups lock
+type PlayerSelection struct {
+	PlayerID      string  `json:"player_id"`
+	Name          string  `json:"name"`
+	Team          string  `json:"team"`
+	Role          string  `json:"role"`
+	SelectedBy    float64 `json:"selected_by"` // Percent of teams
+	Captain       float64 `json:"captain"`
+	ViceCaptain   float64 `json:"vice_captain"`
+	Points        float64 `json:"points"`         // The player's own points so far
+	AveragePoints float64 `json:"average_points"` // Points per team that picked them, with captaincy
+}
+
+// SelectionStats returns each picked player's selection, captaincy and points
+// across a contest's teams, most selected first. They are hidden until lineups
+// lock so they can't be copied.
+func (m *ContestManager) SelectionStats(contestID string) ([]PlayerSelection, error) {
+	c, err := m.GetContest(contestID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != ContestLive && c.Status != ContestCompleted {
+		return nil, ErrInvalidStatus
+	}
+	rules, err := m.Rules(c.Format, c.RulesVersion)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := m.Store.Teams(c.ID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := m.matchStats(c.MatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	scorer := NewFantasyScorerFor(rules)
+	byID := make(map[string]*PlayerSelection)
+	earned := make(map[string]float64)
+	for _, team := range teams {
+		for _, p := range team.Players {
+			sel, ok := byID[p.PlayerID]
+			if !ok {
+				sel = &PlayerSelection{PlayerID: p.PlayerID, Name: p.Name, Team: p.Team, Role: p.Role}
+				sel.Points = scorer.CalculatePoints(stats[p.PlayerID], p.Role)
+				byID[p.PlayerID] = sel
+			}
+			sel.SelectedBy++
+			points := sel.Points
+			switch p.PlayerID {
+			case team.CaptainID:
+				sel.Captain++
+				points *= rules.Captain
+			case team.ViceCaptainID:
+				sel.ViceCaptain++
+				points *= rules.ViceCaptain
+			}
+			earned[p.PlayerID] += points
+		}
+	}
+
+	selections := make([]PlayerSelection, 0, len(byID))
+	for id, sel := range byID {
+		picks := sel.SelectedBy
+		sel.AveragePoints = earned[id] / picks
+		sel.SelectedBy = picks * 100 / float64(len(teams))
+		sel.Captain = sel.Captain * 100 / float64(len(teams))
+		sel.ViceCaptain = sel.ViceCaptain * 100 / float64(len(teams))
+		selections = append(selections, *sel)
+	}
+	sort.Slice(selections, func(i, j int) bool {
+		if selections[i].SelectedBy != selections[j].SelectedBy {
+			return selections[i].SelectedBy > selections[j].SelectedBy
+		}
+		return selections[i].PlayerID < selections[j].PlayerID
+	})
+	return selections, nil
+}
+
+// matchStats returns a match's stats so far: live while it is being scored,
+// then as settled
+func (m *ContestManager) matchStats(matchID string) (map[string]PlayerStats, error) {
+	m.liveMu.RLock()
+	stats, ok := m.live[matchID]
+	m.liveMu.RUnlock()
+	if ok {
+		return stats, nil
+	}
+	stats, err := m.Store.MatchStats(matchID)
+	if err != nil || stats != nil {
+		return stats, err
+	}
+	return m.withPlayingXI(matchID, map[string]PlayerStats{}), nil
+}
@@ -0,0 +1,321 @@
+package fantasy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Dismissals, for a "W" ball
+const (
+	DismissalBowled  = "BOWLED"
+	DismissalCaught  = "CAUGHT"
+	DismissalLBW     = "LBW"
+	DismissalStumped = "STUMPED"
+	DismissalRunOut  = "RUN_OUT"
+)
+
+// BallEvent is one delivery. Event uses match-service's MatchSimulator
+// notation: the runs scored ("0", "1", "2", "3", "4" or "6") or "W" for a
+// wicket.
+type BallEvent struct {
+	MatchID   string `json:"match_id"`
+	Innings   int    `json:"innings"`
+	Over      int    `json:"over"` // From 0, as in 0.1
+	Ball      int    `json:"ball"` // 1-6
+	BatsmanID string `json:"batsman_id"`
+	BowlerID  string `json:"bowler_id"`
+	Event     string `json:"event"`
+	Dismissal string `json:"dismissal,omitempty"`  // With "W"; BOWLED if not given
+	FielderID string `json:"fielder_id,omitempty"` // Catcher, keeper or run-out fielder
+	Timestamp int64  `json:"timestamp"`
+}
+
+// after reports whether e comes later in the match than o
+func (e BallEvent) after(o BallEvent) bool {
+	if e.Innings != o.Innings {
+		return e.Innings > o.Innings
+	}
+	if e.Over != o.Over {
+		return e.Over > o.Over
+	}
+	return e.Ball > o.Ball
+}
+
+// MatchScore builds a match's player stats up one ball at a time
+type MatchScore struct {
+	MatchID string
+	Balls   int
+	stats   map[string]*PlayerStats
+	bowled  map[string]int    // Bowler -> legal balls
+	overs   map[string][2]int // "innings/over/bowler" -> balls, runs conceded
+	last    BallEvent
+}
+
+func NewMatchScore(matchID string) *MatchScore {
+	return &MatchScore{
+		MatchID: matchID,
+		stats:   make(map[string]*PlayerStats),
+		bowled:  make(map[string]int),
+		overs:   make(map[string][2]int),
+	}
+}
+
+// Apply adds a ball to the stats. Balls at or before the last one applied are
+// ignored, so a feed can be replayed from the start after a reconnect.
+func (s *MatchScore) Apply(e BallEvent) error {
+	if e.BatsmanID == "" || e.BowlerID == "" || e.Ball < 1 || e.Ball > 6 || e.Over < 0 {
+		return fmt.Errorf("ball %d.%d: incomplete event", e.Over, e.Ball)
+	}
+	if s.Balls > 0 && !e.after(s.last) {
+		return nil
+	}
+
+	runs, wicket := 0, e.Event == "W"
+	if !wicket {
+		var err error
+		runs, err = strconv.Atoi(e.Event)
+		if err != nil || runs < 0 || runs > 6 || runs == 5 {
+			return fmt.Errorf("ball %d.%d: unknown event %q", e.Over, e.Ball, e.Event)
+		}
+	}
+
+	batsman, bowler := s.player(e.BatsmanID), s.player(e.BowlerID)
+	batsman.BallsFaced++
+	batsman.Runs += runs
+	switch runs {
+	case 4:
+		batsman.Fours++
+	case 6:
+		batsman.Sixes++
+	}
+
+	bowler.RunsConceded += runs
+	s.bowled[e.BowlerID]++
+	bowler.OversBowled = float64(s.bowled[e.BowlerID]) / 6
+	key := fmt.Sprintf("%d/%d/%s", e.Innings, e.Over, e.BowlerID)
+	over := s.overs[key]
+	over[0]++
+	over[1] += runs
+	s.overs[key] = over
+	if e.Ball == 6 && over[0] == 6 && over[1] == 0 {
+		bowler.Maidens++
+	}
+
+	if wicket {
+		switch e.Dismissal {
+		case DismissalRunOut:
+			if e.FielderID != "" {
+				s.player(e.FielderID).RunOuts++
+			}
+		case DismissalCaught, DismissalStumped:
+			if e.FielderID == "" {
+				return fmt.Errorf("ball %d.%d: %s needs a fielder", e.Over, e.Ball, e.Dismissal)
+			}
+			bowler.Wickets++
+			if e.Dismissal == DismissalCaught {
+				s.player(e.FielderID).Catches++
+			} else {
+				s.player(e.FielderID).Stumpings++
+			}
+		case "", DismissalBowled, DismissalLBW:
+			bowler.Wickets++
+		default:
+			return fmt.Errorf("ball %d.%d: unknown dismissal %q", e.Over, e.Ball, e.Dismissal)
+		}
+		batsman.Duck = batsman.Runs == 0
+	}
+
+	s.Balls++
+	s.last = e
+	return nil
+}
+
+// player returns a player's stats, who by taking part is in the playing XI
+func (s *MatchScore) player(id string) *PlayerStats {
+	p, ok := s.stats[id]
+	if !ok {
+		p = &PlayerStats{InPlayingXI: true}
+		s.stats[id] = p
+	}
+	return p
+}
+
+// Stats returns a copy of the stats so far
+func (s *MatchScore) Stats() map[string]PlayerStats {
+	stats := make(map[string]PlayerStats, len(s.stats))
+	for id, p := range s.stats {
+		stats[id] = *p
+	}
+	return stats
+}
+
+// BallFeed streams a match's balls in order, closing the channel at the end of
+// the match
+type BallFeed interface {
+	Events(ctx context.Context, matchID string) (<-chan BallEvent, error)
+}
+
+// FileReplayFeed replays recorded matches from <Dir>/<match_id>.jsonl, one
+// BallEvent per line, a ball every Interval
+type FileReplayFeed struct {
+	Dir      string
+	Interval time.Duration
+}
+
+func NewFileReplayFeed(dir string, interval time.Duration) *FileReplayFeed {
+	return &FileReplayFeed{Dir: dir, Interval: interval}
+}
+
+func (f *FileReplayFeed) Events(ctx context.Context, matchID string) (<-chan BallEvent, error) {
+	if matchID == "" || filepath.Base(matchID) != matchID {
+		return nil, fmt.Errorf("bad match id %q", matchID)
+	}
+	file, err := os.Open(filepath.Join(f.Dir, matchID+".jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Read the whole match up front so a bad file fails before any ball is sent
+	balls := []BallEvent{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e BallEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", file.Name(), line, err)
+		}
+		e.MatchID = matchID
+		balls = append(balls, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	events := make(chan BallEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(f.Interval)
+		defer ticker.Stop()
+		for _, e := range balls {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			e.Timestamp = time.Now().Unix()
+			select {
+			case <-ctx.Done():
+				return
+			case events <- e:
+			}
+		}
+	}()
+	return events, nil
+}
+
+// GetPlayingXI reads a match's playing XI from <Dir>/<match_id>.xi.json, a
+// JSON array of player IDs, once the file is there
+func (f *FileReplayFeed) GetPlayingXI(matchID string) ([]string, error) {
+	if matchID == "" || filepath.Base(matchID) != matchID {
+		return nil, fmt.Errorf("bad match id %q", matchID)
+	}
+	data, err := os.ReadFile(filepath.Join(f.Dir, matchID+".xi.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var playing []string
+	if err := json.Unmarshal(data, &playing); err != nil {
+		return nil, fmt.Errorf("%s.xi.json: %v", matchID, err)
+	}
+	return playing, nil
+}
+
+// LiveScorer follows the feed of every match with live contests, rescoring and
+// reranking their teams after each ball, and settles the contests when the
+// match ends
+type LiveScorer struct {
+	Contests  *ContestManager
+	Feed      BallFeed
+	mu        sync.Mutex
+	following map[string]bool // Match IDs being followed or settled
+}
+
+func NewLiveScorer(contests *ContestManager, feed BallFeed) *LiveScorer {
+	return &LiveScorer{Contests: contests, Feed: feed, following: make(map[string]bool)}
+}
+
+// Follow scores a match ball by ball until its feed ends, then settles it
+func (l *LiveScorer) Follow(ctx context.Context, matchID string) error {
+	events, err := l.Feed.Events(ctx, matchID)
+	if err != nil {
+		return err
+	}
+	score := NewMatchScore(matchID)
+	for e := range events {
+		if err := score.Apply(e); err != nil {
+			log.Printf("match %s: skipped a ball: %v", matchID, err)
+			continue
+		}
+		if err := l.Contests.Rescore(matchID, score.Stats()); err != nil {
+			log.Printf("match %s: failed to rescore: %v", matchID, err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return l.Contests.SettleMatch(matchID, score.Stats())
+}
+
+// Run starts following matches as their contests go live until ctx is
+// cancelled. A match whose feed fails is tried again on the next tick.
+func (l *LiveScorer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.followLive(ctx)
+		}
+	}
+}
+
+func (l *LiveScorer) followLive(ctx context.Context) {
+	live, err := l.Contests.Store.ListContests("", ContestLive)
+	if err != nil {
+		log.Printf("fantasy: failed to list live contests: %v", err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, c := range live {
+		if l.following[c.MatchID] {
+			continue
+		}
+		l.following[c.MatchID] = true
+		go func(matchID string) {
+			if err := l.Follow(ctx, matchID); err != nil && ctx.Err() == nil {
+				log.Printf("match %s: live scoring stopped: %v", matchID, err)
+				l.mu.Lock()
+				delete(l.following, matchID)
+				l.mu.Unlock()
+			}
+		}(c.MatchID)
+	}
+}
@@ -0,0 +1,240 @@
+package fantasy
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Sports
+const (
+	SportCricket  = "CRICKET"
+	SportFootball = "FOOTBALL"
+	SportKabaddi  = "KABADDI"
+)
+
+// Cricket Formats
+const (
+	FormatT20  = "T20"
+	FormatODI  = "ODI"
+	FormatTest = "TEST"
+	FormatT10  = "T10"
+)
+
+// Football Roles
+const (
+	RoleGoalkeeper = "GOALKEEPER"
+	RoleDefender   = "DEFENDER"
+	RoleMidfielder = "MIDFIELDER"
+	RoleForward    = "FORWARD"
+)
+
+// Kabaddi Roles
+const (
+	RoleRaider = "RAIDER"
+	// Kabaddi defenders use RoleDefender and all-rounders RoleAllRounder
+)
+
+var ErrRulesVersionExists = errors.New("scoring rules version already exists")
+
+// Milestone is a bonus for reaching a number of runs or wickets. Only the
+// highest milestone reached pays.
+type Milestone struct {
+	At     int     `json:"at"`
+	Points float64 `json:"points"`
+}
+
+// Band pays Points for a rate from Min up to, but not including, Max. A zero
+// Max has no upper limit.
+type Band struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Points float64 `json:"points"`
+}
+
+func (b Band) matches(v float64) bool {
+	return v >= b.Min && (b.Max == 0 || v < b.Max)
+}
+
+// ScoringRules is one version of a format's points system. Versions are never
+// changed once published; a contest keeps the version that was current when it
+// opened.
+type ScoringRules struct {
+	Sport   string `json:"sport"`
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+
+	PlayingXI float64 `json:"playing_xi"` // For being named in the starting lineup
+
+	// Batting
+	Run             float64     `json:"run"`
+	Four            float64     `json:"four"` // Boundary bonus on top of the runs
+	Six             float64     `json:"six"`
+	Duck            float64     `json:"duck"`       // Usually negative
+	DuckRoles       []string    `json:"duck_roles"` // Roles the duck penalty applies to
+	RunMilestones   []Milestone `json:"run_milestones"`
+	StrikeRateBalls int         `json:"strike_rate_balls"` // Fewest balls faced for a strike-rate band
+	StrikeRate      []Band      `json:"strike_rate"`       // Runs per 100 balls
+
+	// Bowling
+	Wicket           float64     `json:"wicket"`
+	Maiden           float64     `json:"maiden"`
+	WicketMilestones []Milestone `json:"wicket_milestones"`
+	EconomyOvers     float64     `json:"economy_overs"` // Fewest overs bowled for an economy band
+	Economy          []Band      `json:"economy"`       // Runs conceded per over
+
+	// Fielding
+	Catch    float64 `json:"catch"`
+	Stumping float64 `json:"stumping"`
+	RunOut   float64 `json:"run_out"`
+
+	Captain     float64 `json:"captain"` // Multipliers
+	ViceCaptain float64 `json:"vice_captain"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks a rule set can be scored with
+func (r *ScoringRules) Validate() error {
+	if r.Sport != SportCricket {
+		return fmt.Errorf("no scoring for sport %q yet", r.Sport)
+	}
+	if r.Format == "" {
+		return errors.New("format is required")
+	}
+	if r.Captain <= 0 || r.ViceCaptain <= 0 {
+		return errors.New("captain and vice-captain multipliers must be positive")
+	}
+	for _, bands := range [][]Band{r.StrikeRate, r.Economy} {
+		for _, b := range bands {
+			if b.Min < 0 || (b.Max != 0 && b.Max <= b.Min) {
+				return fmt.Errorf("bad band %g-%g", b.Min, b.Max)
+			}
+		}
+	}
+	for _, milestones := range [][]Milestone{r.RunMilestones, r.WicketMilestones} {
+		for _, m := range milestones {
+			if m.At < 1 {
+				return fmt.Errorf("bad milestone at %d", m.At)
+			}
+		}
+	}
+	return nil
+}
+
+// cricketRules are the points every cricket format shares
+func cricketRules(format string) *ScoringRules {
+	return &ScoringRules{
+		Sport:       SportCricket,
+		Format:      format,
+		Version:     1,
+		PlayingXI:   4,
+		Run:         1,
+		Four:        1,
+		Six:         2,
+		Duck:        -2,
+		DuckRoles:   []string{RoleBatsman, RoleWicketKeeper, RoleAllRounder},
+		Wicket:      25,
+		Maiden:      12,
+		Catch:       8,
+		Stumping:    12,
+		RunOut:      6,
+		Captain:     2,
+		ViceCaptain: 1.5,
+	}
+}
+
+// DefaultScoringRules returns version 1 of a format's rules, used until a
+// newer version is published
+func DefaultScoringRules(format string) (*ScoringRules, error) {
+	r := cricketRules(format)
+	switch format {
+	case FormatT20:
+		r.RunMilestones = []Milestone{{30, 4}, {50, 8}, {100, 16}}
+		r.StrikeRateBalls = 10
+		r.StrikeRate = []Band{{170, 0, 6}, {150, 170, 4}, {50, 60, -4}, {0, 50, -6}}
+		r.WicketMilestones = []Milestone{{3, 4}, {4, 8}, {5, 16}}
+		r.EconomyOvers = 2
+		r.Economy = []Band{{0, 5, 6}, {5, 6, 4}, {10, 12, -4}, {12, 0, -6}}
+	case FormatT10:
+		r.Six = 3
+		r.RunMilestones = []Milestone{{30, 8}, {50, 16}}
+		r.StrikeRateBalls = 5
+		r.StrikeRate = []Band{{190, 0, 6}, {170, 190, 4}, {60, 70, -4}, {0, 60, -6}}
+		r.WicketMilestones = []Milestone{{2, 8}, {3, 16}}
+		r.Maiden = 16
+		r.EconomyOvers = 1
+		r.Economy = []Band{{0, 7, 6}, {7, 8, 4}, {14, 16, -4}, {16, 0, -6}}
+	case FormatODI:
+		r.Duck = -3
+		r.RunMilestones = []Milestone{{50, 4}, {100, 8}, {150, 12}}
+		r.StrikeRateBalls = 20
+		r.StrikeRate = []Band{{140, 0, 6}, {120, 140, 4}, {40, 50, -4}, {0, 40, -6}}
+		r.WicketMilestones = []Milestone{{4, 4}, {5, 8}}
+		r.Maiden = 4
+		r.EconomyOvers = 5
+		r.Economy = []Band{{0, 2.5, 6}, {2.5, 3.5, 4}, {7, 8, -4}, {8, 0, -6}}
+	case FormatTest:
+		// No strike-rate or economy bands over five days
+		r.Duck = -4
+		r.RunMilestones = []Milestone{{50, 4}, {100, 8}, {200, 16}}
+		r.Wicket = 16
+		r.Maiden = 0
+		r.WicketMilestones = []Milestone{{4, 4}, {5, 8}}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	return r, nil
+}
+
+// RoleLimit is how many players of a role a team may pick
+type RoleLimit struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// TeamRules are a sport's team building constraints
+type TeamRules struct {
+	Sport          string               `json:"sport"`
+	Players        int                  `json:"players"`
+	Budget         float64              `json:"budget"`
+	MaxFromOneTeam int                  `json:"max_from_one_team"`
+	Roles          map[string]RoleLimit `json:"roles"` // Every pickable role
+}
+
+// DefaultTeamRules returns a sport's standard team constraints
+func DefaultTeamRules(sport string) (TeamRules, error) {
+	switch sport {
+	case SportCricket:
+		return TeamRules{
+			Sport: SportCricket, Players: MaxPlayers, Budget: Budget, MaxFromOneTeam: MaxFromOneTeam,
+			Roles: map[string]RoleLimit{
+				RoleWicketKeeper: {MinWicketKeepers, 4},
+				RoleBatsman:      {MinBatsmen, 6},
+				RoleAllRounder:   {MinAllRounders, 4},
+				RoleBowler:       {MinBowlers, 6},
+			},
+		}, nil
+	case SportFootball:
+		return TeamRules{
+			Sport: SportFootball, Players: 11, Budget: 100, MaxFromOneTeam: 7,
+			Roles: map[string]RoleLimit{
+				RoleGoalkeeper: {1, 1},
+				RoleDefender:   {3, 5},
+				RoleMidfielder: {3, 5},
+				RoleForward:    {1, 3},
+			},
+		}, nil
+	case SportKabaddi:
+		return TeamRules{
+			Sport: SportKabaddi, Players: 7, Budget: 100, MaxFromOneTeam: 5,
+			Roles: map[string]RoleLimit{
+				RoleDefender:   {2, 4},
+				RoleAllRounder: {1, 2},
+				RoleRaider:     {1, 3},
+			},
+		}, nil
+	default:
+		return TeamRules{}, fmt.Errorf("unknown sport %q", sport)
+	}
+}
@@ -2,107 +2,106 @@ package fantasy
 
 // PlayerStats represents real-world performance
 type PlayerStats struct {
-	Runs        int
-	BallsFaced  int
-	Fours       int
-	Sixes       int
-	Wickets     int
-	Maidens     int
-	OversBowled float64
-	RunsConceded int
-	Catches     int
-	Stumpings   int
-	RunOuts     int
-	Duck        bool
+	Runs         int     `json:"runs"`
+	BallsFaced   int     `json:"balls_faced"`
+	Fours        int     `json:"fours"`
+	Sixes        int     `json:"sixes"`
+	Wickets      int     `json:"wickets"`
+	Maidens      int     `json:"maidens"`
+	OversBowled  float64 `json:"overs_bowled"`
+	RunsConceded int     `json:"runs_conceded"`
+	Catches      int     `json:"catches"`
+	Stumpings    int     `json:"stumpings"`
+	RunOuts      int     `json:"run_outs"`
+	Duck         bool    `json:"duck"`
+	InPlayingXI  bool    `json:"in_playing_xi"`
 }
 
-// FantasyScorer calculates points
-type FantasyScorer struct{}
+// FantasyScorer calculates points by a version of a format's scoring rules
+type FantasyScorer struct {
+	Rules *ScoringRules
+}
 
-// NewFantasyScorer creates a new scorer
+// NewFantasyScorer creates a scorer with the default T20 rules
 func NewFantasyScorer() *FantasyScorer {
-	return &FantasyScorer{}
+	rules, _ := DefaultScoringRules(FormatT20)
+	return &FantasyScorer{Rules: rules}
+}
+
+// NewFantasyScorerFor creates a scorer for a rule set
+func NewFantasyScorerFor(rules *ScoringRules) *FantasyScorer {
+	return &FantasyScorer{Rules: rules}
 }
 
 // CalculatePoints computes points for a player based on stats
 func (s *FantasyScorer) CalculatePoints(stats PlayerStats, role string) float64 {
+	r := s.Rules
 	points := 0.0
+	if stats.InPlayingXI {
+		points += r.PlayingXI
+	}
 
 	// 1. Batting Points
-	points += float64(stats.Runs) // 1 point per run
-	points += float64(stats.Fours) * 1.0 // Bonus for 4
-	points += float64(stats.Sixes) * 2.0 // Bonus for 6
-
-	// Milestones
-	if stats.Runs >= 100 {
-		points += 16.0
-	} else if stats.Runs >= 50 {
-		points += 8.0
-	} else if stats.Runs >= 30 {
-		points += 4.0 // T20 specific usually
+	points += float64(stats.Runs) * r.Run
+	points += float64(stats.Fours) * r.Four
+	points += float64(stats.Sixes) * r.Six
+	points += milestoneBonus(r.RunMilestones, stats.Runs)
+	if stats.Duck {
+		for _, duckRole := range r.DuckRoles {
+			if role == duckRole {
+				points += r.Duck
+				break
+			}
+		}
 	}
-
-	// Duck penalty (only for batsmen, wicket-keepers, and all-rounders)
-	if stats.Duck && (role == RoleBatsman || role == RoleWicketKeeper || role == RoleAllRounder) {
-		points -= 2.0
+	if stats.BallsFaced > 0 && stats.BallsFaced >= r.StrikeRateBalls {
+		strikeRate := float64(stats.Runs) / float64(stats.BallsFaced) * 100
+		points += bandPoints(r.StrikeRate, strikeRate)
 	}
 
 	// 2. Bowling Points
-	points += float64(stats.Wickets) * 25.0
-	points += float64(stats.Maidens) * 12.0 // Bonus for maiden over
-
-	// LBW/Bowled bonus (not tracked in simple stats, assume included in Wickets)
-
-	// Milestones
-	if stats.Wickets >= 5 {
-		points += 16.0
-	} else if stats.Wickets >= 4 {
-		points += 8.0
-	} else if stats.Wickets >= 3 {
-		points += 4.0
+	points += float64(stats.Wickets) * r.Wicket
+	points += float64(stats.Maidens) * r.Maiden
+	points += milestoneBonus(r.WicketMilestones, stats.Wickets)
+	if stats.OversBowled > 0 && stats.OversBowled >= r.EconomyOvers {
+		economy := float64(stats.RunsConceded) / stats.OversBowled
+		points += bandPoints(r.Economy, economy)
 	}
 
-	// Economy Rate Bonus (T20)
-	if stats.OversBowled >= 2.0 {
-		economy := float64(stats.RunsConceded) / stats.OversBowled
-		if economy < 5.0 {
-			points += 6.0
-		} else if economy < 6.0 {
-			points += 4.0
-		} else if economy >= 12.0 {
-			points -= 6.0
-		} else if economy >= 10.0 {
-			points -= 4.0
+	// 3. Fielding Points
+	points += float64(stats.Catches) * r.Catch
+	points += float64(stats.Stumpings) * r.Stumping
+	points += float64(stats.RunOuts) * r.RunOut
+
+	return points
+}
+
+// milestoneBonus is the bonus of the highest milestone reached
+func milestoneBonus(milestones []Milestone, value int) float64 {
+	best, bonus := 0, 0.0
+	for _, m := range milestones {
+		if value >= m.At && m.At > best {
+			best, bonus = m.At, m.Points
 		}
 	}
+	return bonus
+}
 
-	// Strike Rate Bonus (Batting)
-	if stats.BallsFaced >= 10 {
-		strikeRate := (float64(stats.Runs) / float64(stats.BallsFaced)) * 100.0
-		if strikeRate > 170.0 {
-			points += 6.0
-		} else if strikeRate > 150.0 {
-			points += 4.0
-		} else if strikeRate < 50.0 {
-			points -= 6.0
-		} else if strikeRate < 60.0 {
-			points -= 4.0
+// bandPoints is the points of the first band the value falls in
+func bandPoints(bands []Band, value float64) float64 {
+	for _, b := range bands {
+		if b.matches(value) {
+			return b.Points
 		}
 	}
-
-	// 3. Fielding Points
-	points += float64(stats.Catches) * 8.0
-	points += float64(stats.Stumpings) * 12.0
-	points += float64(stats.RunOuts) * 6.0
-
-	return points
+	return 0
 }
 
 // CalculateTeamPoints computes total points for a user team
 func (s *FantasyScorer) CalculateTeamPoints(team *FantasyTeam, matchStats map[string]PlayerStats) float64 {
 	totalPoints := 0.0
 
-	for i, player := range team.Players {
+	for _, player := range team.Players {
 		stats, ok := matchStats[player.PlayerID]
 		if !ok {
 			continue
@@ -112,19 +111,11 @@ func (s *FantasyScorer) CalculateTeamPoints(team *FantasyTeam, matchStats map[st
 
 		// Apply multipliers
 		if player.PlayerID == team.CaptainID {
-			points *= 2.0
+			points *= s.Rules.Captain
 		} else if player.PlayerID == team.ViceCaptainID {
-			points *= 1.5
+			points *= s.Rules.ViceCaptain
 		}
-
-		// Update individual player points in team struct (if we wanted to persist it)
-		// For now just summing up
 		totalPoints += points
-
-		// Hack to update the struct in place (not ideal but works for now)
-		// In real app, we'd return a new struct or map
-		// team.Players[i].Points = points (FantasyPlayer struct doesn't have Points field yet, let's assume it does or ignore)
-		_ = i
 	}
 
 	team.TotalPoints = totalPoints
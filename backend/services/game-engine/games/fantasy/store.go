@@ -0,0 +1,731 @@
+package fantasy
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// Store persists contests and the teams entered in them. Get methods return
+// nil, nil when nothing is found.
+type Store interface {
+	CreateContest(c *Contest) error
+	GetContest(id string) (*Contest, error)
+	GetContestByInvite(code string) (*Contest, error)
+	// ListContests lists contests in a status by start time, for one match
+	// unless matchID is empty
+	ListContests(matchID, status string) ([]*Contest, error)
+	// UpdateContest saves the status
+	UpdateContest(c *Contest) error
+
+	// AddTeam enters a team, failing with ErrLineupLocked unless the contest is
+	// open and its match starts after t.CreatedAt, ErrContestFull or
+	// ErrEntryLimit, and bumps CurrentTeams
+	AddTeam(t *FantasyTeam, maxTeams, maxPerUser int) error
+	// RemoveTeam gives the place back (e.g. the entry fee could not be collected)
+	RemoveTeam(teamID string) error
+	GetTeam(id string) (*FantasyTeam, error)
+	// Teams lists a contest's teams in entry order
+	Teams(contestID string) ([]*FantasyTeam, error)
+	// UpdateLineup saves a team's players and captains, failing with
+	// ErrLineupLocked unless its contest is open and its match starts after
+	// t.UpdatedAt
+	UpdateLineup(t *FantasyTeam) error
+	// UpdateTeam saves the lineup, points, rank, prize and status
+	UpdateTeam(t *FantasyTeam) error
+
+	// SaveMatchStats records a settled match's player stats, replacing any
+	// saved before
+	SaveMatchStats(matchID string, stats map[string]PlayerStats) error
+	// MatchStats returns a settled match's player stats
+	MatchStats(matchID string) (map[string]PlayerStats, error)
+
+	// SaveMatchPlayers replaces a match's player pool
+	SaveMatchPlayers(matchID string, players []FantasyPlayer) error
+	// MatchPlayers returns a match's player pool, or nil if none was saved
+	MatchPlayers(matchID string) ([]FantasyPlayer, error)
+
+	// CreateScoringRules publishes a rules version, failing with
+	// ErrRulesVersionExists if it's taken
+	CreateScoringRules(r *ScoringRules) error
+	GetScoringRules(format string, version int) (*ScoringRules, error)
+	LatestScoringRules(format string) (*ScoringRules, error)
+}
+
+// MemoryStore keeps contests in process, for running without a database
+type MemoryStore struct {
+	contests map[string]*Contest
+	teams    map[string]*FantasyTeam
+	order    map[string][]string // contestID -> team IDs in entry order
+	stats    map[string]map[string]PlayerStats
+	rules    map[string][]*ScoringRules // Format -> versions in order
+	players  map[string][]FantasyPlayer
+	mu       sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		contests: make(map[string]*Contest),
+		teams:    make(map[string]*FantasyTeam),
+		order:    make(map[string][]string),
+		stats:    make(map[string]map[string]PlayerStats),
+		rules:    make(map[string][]*ScoringRules),
+		players:  make(map[string][]FantasyPlayer),
+	}
+}
+
+func (s *MemoryStore) CreateContest(c *Contest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *c
+	s.contests[c.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetContest(id string) (*Contest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.contests[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (s *MemoryStore) GetContestByInvite(code string) (*Contest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, c := range s.contests {
+		if c.InviteCode != "" && c.InviteCode == code {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) ListContests(matchID, status string) ([]*Contest, error) {
+	s.mu.RLock()
+	contests := []*Contest{}
+	for _, c := range s.contests {
+		if (matchID == "" || c.MatchID == matchID) && c.Status == status {
+			copied := *c
+			contests = append(contests, &copied)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(contests, func(i, j int) bool {
+		if !contests[i].StartTime.Equal(contests[j].StartTime) {
+			return contests[i].StartTime.Before(contests[j].StartTime)
+		}
+		return contests[i].ID < contests[j].ID
+	})
+	return contests, nil
+}
+
+func (s *MemoryStore) UpdateContest(c *Contest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.contests[c.ID]
+	if !ok {
+		return ErrContestNotFound
+	}
+	// The team count is only moved by AddTeam/RemoveTeam
+	copied := *c
+	copied.CurrentTeams = existing.CurrentTeams
+	s.contests[c.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) AddTeam(t *FantasyTeam, maxTeams, maxPerUser int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.contests[t.ContestID]
+	if !ok {
+		return ErrContestNotFound
+	}
+	if !c.takesLineups(t.CreatedAt) {
+		return ErrLineupLocked
+	}
+	if c.CurrentTeams >= maxTeams {
+		return ErrContestFull
+	}
+	entries := 0
+	for _, id := range s.order[t.ContestID] {
+		if s.teams[id].UserID == t.UserID {
+			entries++
+		}
+	}
+	if entries >= maxPerUser {
+		return ErrEntryLimit
+	}
+	copied := *t
+	s.teams[t.ID] = &copied
+	s.order[t.ContestID] = append(s.order[t.ContestID], t.ID)
+	c.CurrentTeams++
+	return nil
+}
+
+func (s *MemoryStore) RemoveTeam(teamID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.teams[teamID]
+	if !ok {
+		return ErrTeamNotFound
+	}
+	delete(s.teams, teamID)
+	ids := s.order[t.ContestID]
+	for i, id := range ids {
+		if id == teamID {
+			s.order[t.ContestID] = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+	s.contests[t.ContestID].CurrentTeams--
+	return nil
+}
+
+func (s *MemoryStore) GetTeam(id string) (*FantasyTeam, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.teams[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (s *MemoryStore) Teams(contestID string) ([]*FantasyTeam, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	teams := make([]*FantasyTeam, 0, len(s.order[contestID]))
+	for _, id := range s.order[contestID] {
+		copied := *s.teams[id]
+		teams = append(teams, &copied)
+	}
+	return teams, nil
+}
+
+func (s *MemoryStore) UpdateLineup(t *FantasyTeam) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.teams[t.ID]
+	if !ok {
+		return ErrTeamNotFound
+	}
+	if !s.contests[existing.ContestID].takesLineups(t.UpdatedAt) {
+		return ErrLineupLocked
+	}
+	existing.Players = append([]FantasyPlayer(nil), t.Players...)
+	existing.CaptainID = t.CaptainID
+	existing.ViceCaptainID = t.ViceCaptainID
+	existing.UpdatedAt = t.UpdatedAt
+	return nil
+}
+
+func (s *MemoryStore) UpdateTeam(t *FantasyTeam) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.teams[t.ID]; !ok {
+		return ErrTeamNotFound
+	}
+	copied := *t
+	s.teams[t.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) SaveMatchStats(matchID string, stats map[string]PlayerStats) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := make(map[string]PlayerStats, len(stats))
+	for id, st := range stats {
+		copied[id] = st
+	}
+	s.stats[matchID] = copied
+	return nil
+}
+
+func (s *MemoryStore) MatchStats(matchID string) (map[string]PlayerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	saved, ok := s.stats[matchID]
+	if !ok {
+		return nil, nil
+	}
+	stats := make(map[string]PlayerStats, len(saved))
+	for id, st := range saved {
+		stats[id] = st
+	}
+	return stats, nil
+}
+
+func (s *MemoryStore) SaveMatchPlayers(matchID string, players []FantasyPlayer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.players[matchID] = append([]FantasyPlayer(nil), players...)
+	return nil
+}
+
+func (s *MemoryStore) MatchPlayers(matchID string) ([]FantasyPlayer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	players, ok := s.players[matchID]
+	if !ok {
+		return nil, nil
+	}
+	return append([]FantasyPlayer(nil), players...), nil
+}
+
+func (s *MemoryStore) CreateScoringRules(r *ScoringRules) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := s.rules[r.Format]
+	if len(versions) > 0 && versions[len(versions)-1].Version >= r.Version {
+		return ErrRulesVersionExists
+	}
+	copied := *r
+	s.rules[r.Format] = append(versions, &copied)
+	return nil
+}
+
+func (s *MemoryStore) GetScoringRules(format string, version int) (*ScoringRules, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, r := range s.rules[format] {
+		if r.Version == version {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) LatestScoringRules(format string) (*ScoringRules, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := s.rules[format]
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	copied := *versions[len(versions)-1]
+	return &copied, nil
+}
+
+// PostgresStore keeps contests in fantasy_contests and teams in fantasy_teams
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+const contestColumns = `id, match_id, name, format, rules_version, entry_fee, prize_pool, guaranteed, max_teams, min_teams,
+	max_entries_per_user, current_teams, payout_table, status, start_time, private, COALESCE(creator_id, ''),
+	COALESCE(invite_code, ''), commission_percent, created_at, updated_at`
+
+func scanContest(row interface{ Scan(...interface{}) error }) (*Contest, error) {
+	c := &Contest{}
+	err := row.Scan(&c.ID, &c.MatchID, &c.Name, &c.Format, &c.RulesVersion, &c.EntryFee, &c.PrizePool, &c.Guaranteed, &c.MaxTeams, &c.MinTeams,
+		&c.MaxEntriesPerUser, &c.CurrentTeams, &c.PayoutTable, &c.Status, &c.StartTime, &c.Private, &c.CreatorID,
+		&c.InviteCode, &c.CommissionPercent, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *PostgresStore) CreateContest(c *Contest) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO fantasy_contests (id, match_id, name, format, rules_version, entry_fee, prize_pool, guaranteed,
+			max_teams, min_teams, max_entries_per_user, current_teams, payout_table, status, start_time, private, creator_id,
+			invite_code, commission_percent, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''), NULLIF($18, ''),
+			$19, $20, $21)
+	`, c.ID, c.MatchID, c.Name, c.Format, c.RulesVersion, c.EntryFee, c.PrizePool, c.Guaranteed, c.MaxTeams, c.MinTeams,
+		c.MaxEntriesPerUser, c.CurrentTeams, c.PayoutTable, c.Status, c.StartTime, c.Private, c.CreatorID,
+		c.InviteCode, c.CommissionPercent, c.CreatedAt, c.UpdatedAt)
+	return err
+}
+
+func (s *PostgresStore) GetContest(id string) (*Contest, error) {
+	c, err := scanContest(s.DB.QueryRow(`SELECT `+contestColumns+` FROM fantasy_contests WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (s *PostgresStore) GetContestByInvite(code string) (*Contest, error) {
+	c, err := scanContest(s.DB.QueryRow(`SELECT `+contestColumns+` FROM fantasy_contests WHERE invite_code = $1`, code))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (s *PostgresStore) ListContests(matchID, status string) ([]*Contest, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+contestColumns+`
+		FROM fantasy_contests
+		WHERE ($1 = '' OR match_id = $1) AND status = $2
+		ORDER BY start_time, id
+	`, matchID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contests := []*Contest{}
+	for rows.Next() {
+		c, err := scanContest(rows)
+		if err != nil {
+			return nil, err
+		}
+		contests = append(contests, c)
+	}
+	return contests, rows.Err()
+}
+
+func (s *PostgresStore) UpdateContest(c *Contest) error {
+	_, err := s.DB.Exec(`
+		UPDATE fantasy_contests SET status = $2, updated_at = $3 WHERE id = $1
+	`, c.ID, c.Status, c.UpdatedAt)
+	return err
+}
+
+// AddTeam locks the contest row so concurrent entries can't overfill it,
+// exceed a user's limit or enter it once lineups lock
+func (s *PostgresStore) AddTeam(t *FantasyTeam, maxTeams, maxPerUser int) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var c Contest
+	err = tx.QueryRow(`
+		SELECT status, start_time, current_teams FROM fantasy_contests WHERE id = $1 FOR UPDATE
+	`, t.ContestID).Scan(&c.Status, &c.StartTime, &c.CurrentTeams)
+	if err == sql.ErrNoRows {
+		return ErrContestNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !c.takesLineups(t.CreatedAt) {
+		return ErrLineupLocked
+	}
+	if c.CurrentTeams >= maxTeams {
+		return ErrContestFull
+	}
+	var entries int
+	if err := tx.QueryRow(`
+		SELECT COUNT(*) FROM fantasy_teams WHERE contest_id = $1 AND user_id = $2
+	`, t.ContestID, t.UserID).Scan(&entries); err != nil {
+		return err
+	}
+	if entries >= maxPerUser {
+		return ErrEntryLimit
+	}
+
+	players, err := json.Marshal(t.Players)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO fantasy_teams (id, user_id, contest_id, match_id, players, captain_id, vice_captain_id, total_cost,
+			status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, t.ID, t.UserID, t.ContestID, t.MatchID, players, t.CaptainID, t.ViceCaptainID, teamCost(t),
+		t.Status, t.CreatedAt, t.UpdatedAt); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE fantasy_contests SET current_teams = current_teams + 1, updated_at = NOW() WHERE id = $1
+	`, t.ContestID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) RemoveTeam(teamID string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var contestID string
+	err = tx.QueryRow(`DELETE FROM fantasy_teams WHERE id = $1 RETURNING contest_id`, teamID).Scan(&contestID)
+	if err == sql.ErrNoRows {
+		return ErrTeamNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE fantasy_contests SET current_teams = current_teams - 1, updated_at = NOW() WHERE id = $1
+	`, contestID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const teamColumns = `id, user_id, contest_id, match_id, players, captain_id, vice_captain_id,
+	COALESCE(total_points, 0), COALESCE(rank, 0), status, prize_amount, created_at, updated_at`
+
+func scanTeam(row interface{ Scan(...interface{}) error }) (*FantasyTeam, error) {
+	t := &FantasyTeam{}
+	var players []byte
+	err := row.Scan(&t.ID, &t.UserID, &t.ContestID, &t.MatchID, &players, &t.CaptainID, &t.ViceCaptainID,
+		&t.TotalPoints, &t.Rank, &t.Status, &t.PrizeAmount, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(players, &t.Players); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *PostgresStore) GetTeam(id string) (*FantasyTeam, error) {
+	t, err := scanTeam(s.DB.QueryRow(`SELECT `+teamColumns+` FROM fantasy_teams WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (s *PostgresStore) Teams(contestID string) ([]*FantasyTeam, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+teamColumns+`
+		FROM fantasy_teams
+		WHERE contest_id = $1
+		ORDER BY created_at, id
+	`, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []*FantasyTeam{}
+	for rows.Next() {
+		t, err := scanTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, rows.Err()
+}
+
+// UpdateLineup holds the contest row while it saves, so the contest can't lock
+// between the check and the update
+func (s *PostgresStore) UpdateLineup(t *FantasyTeam) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var c Contest
+	err = tx.QueryRow(`
+		SELECT c.status, c.start_time
+		FROM fantasy_contests c JOIN fantasy_teams t ON t.contest_id = c.id
+		WHERE t.id = $1
+		FOR SHARE OF c
+	`, t.ID).Scan(&c.Status, &c.StartTime)
+	if err == sql.ErrNoRows {
+		return ErrTeamNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !c.takesLineups(t.UpdatedAt) {
+		return ErrLineupLocked
+	}
+
+	players, err := json.Marshal(t.Players)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE fantasy_teams
+		SET players = $2, captain_id = $3, vice_captain_id = $4, total_cost = $5, updated_at = $6
+		WHERE id = $1
+	`, t.ID, players, t.CaptainID, t.ViceCaptainID, teamCost(t), t.UpdatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) UpdateTeam(t *FantasyTeam) error {
+	players, err := json.Marshal(t.Players)
+	if err != nil {
+		return err
+	}
+	_, err = s.DB.Exec(`
+		UPDATE fantasy_teams
+		SET players = $2, captain_id = $3, vice_captain_id = $4, total_cost = $5, total_points = $6,
+			rank = NULLIF($7, 0), status = $8, prize_amount = $9, updated_at = $10
+		WHERE id = $1
+	`, t.ID, players, t.CaptainID, t.ViceCaptainID, teamCost(t), t.TotalPoints,
+		t.Rank, t.Status, t.PrizeAmount, t.UpdatedAt)
+	return err
+}
+
+// SaveMatchStats upserts one fantasy_match_stats row per player
+func (s *PostgresStore) SaveMatchStats(matchID string, stats map[string]PlayerStats) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for playerID, st := range stats {
+		data, err := json.Marshal(st)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO fantasy_match_stats (match_id, player_id, stats, updated_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (match_id, player_id) DO UPDATE SET stats = EXCLUDED.stats, updated_at = NOW()
+		`, matchID, playerID, data); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) MatchStats(matchID string) (map[string]PlayerStats, error) {
+	rows, err := s.DB.Query(`SELECT player_id, stats FROM fantasy_match_stats WHERE match_id = $1`, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats map[string]PlayerStats
+	for rows.Next() {
+		var playerID string
+		var data []byte
+		if err := rows.Scan(&playerID, &data); err != nil {
+			return nil, err
+		}
+		var st PlayerStats
+		if err := json.Unmarshal(data, &st); err != nil {
+			return nil, err
+		}
+		if stats == nil {
+			stats = make(map[string]PlayerStats)
+		}
+		stats[playerID] = st
+	}
+	return stats, rows.Err()
+}
+
+// SaveMatchPlayers replaces the match's rows in fantasy_match_players
+func (s *PostgresStore) SaveMatchPlayers(matchID string, players []FantasyPlayer) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM fantasy_match_players WHERE match_id = $1`, matchID); err != nil {
+		return err
+	}
+	for _, p := range players {
+		if _, err := tx.Exec(`
+			INSERT INTO fantasy_match_players (match_id, player_id, name, team, role, credits, lineup, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NOW())
+		`, matchID, p.PlayerID, p.Name, p.Team, p.Role, p.Cost, p.Lineup); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) MatchPlayers(matchID string) ([]FantasyPlayer, error) {
+	rows, err := s.DB.Query(`
+		SELECT player_id, name, team, role, credits, COALESCE(lineup, '')
+		FROM fantasy_match_players
+		WHERE match_id = $1
+		ORDER BY team, player_id
+	`, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []FantasyPlayer
+	for rows.Next() {
+		var p FantasyPlayer
+		if err := rows.Scan(&p.PlayerID, &p.Name, &p.Team, &p.Role, &p.Cost, &p.Lineup); err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
+
+// CreateScoringRules relies on the (format, version) key to refuse a version
+// published twice
+func (s *PostgresStore) CreateScoringRules(r *ScoringRules) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	res, err := s.DB.Exec(`
+		INSERT INTO fantasy_scoring_rules (format, version, sport, rules, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (format, version) DO NOTHING
+	`, r.Format, r.Version, r.Sport, data, r.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrRulesVersionExists
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetScoringRules(format string, version int) (*ScoringRules, error) {
+	return scanScoringRules(s.DB.QueryRow(`
+		SELECT rules FROM fantasy_scoring_rules WHERE format = $1 AND version = $2
+	`, format, version))
+}
+
+func (s *PostgresStore) LatestScoringRules(format string) (*ScoringRules, error) {
+	return scanScoringRules(s.DB.QueryRow(`
+		SELECT rules FROM fantasy_scoring_rules WHERE format = $1 ORDER BY version DESC LIMIT 1
+	`, format))
+}
+
+func scanScoringRules(row *sql.Row) (*ScoringRules, error) {
+	var data []byte
+	if err := row.Scan(&data); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	r := &ScoringRules{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func teamCost(t *FantasyTeam) float64 {
+	cost := 0.0
+	for _, p := range t.Players {
+		cost += p.Cost
+	}
+	return cost
+}
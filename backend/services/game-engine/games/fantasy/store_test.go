@@ -0,0 +1,46 @@
+package fantasy
+
+import (
+	"testing"
+	"time"
+)
+
+// A contest can lock between the manager's check and the store's write, so the
+// store refuses entries and lineup changes itself
+func TestMemoryStoreRefusesTeamsOnceLocked(t *testing.T) {
+	start := time.Date(2026, 5, 1, 14, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	contest := &Contest{ID: "c1", MatchID: "m1", Status: ContestOpen, StartTime: start, MaxTeams: 10}
+	if err := store.CreateContest(contest); err != nil {
+		t.Fatal(err)
+	}
+	before := start.Add(-time.Minute)
+	team := &FantasyTeam{ID: "t1", UserID: "u1", ContestID: "c1", MatchID: "m1", CaptainID: "a", CreatedAt: before, UpdatedAt: before}
+	if err := store.AddTeam(team, 10, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.AddTeam(&FantasyTeam{ID: "t2", UserID: "u1", ContestID: "c1", CreatedAt: start}, 10, 2); err != ErrLineupLocked {
+		t.Errorf("entry at the start time: err = %v", err)
+	}
+	edit := *team
+	edit.CaptainID, edit.UpdatedAt = "b", start
+	if err := store.UpdateLineup(&edit); err != ErrLineupLocked {
+		t.Errorf("lineup change at the start time: err = %v", err)
+	}
+
+	contest.Status = ContestLive
+	if err := store.UpdateContest(contest); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddTeam(&FantasyTeam{ID: "t3", UserID: "u2", ContestID: "c1", CreatedAt: before}, 10, 2); err != ErrLineupLocked {
+		t.Errorf("entry into a live contest: err = %v", err)
+	}
+	edit.UpdatedAt = before
+	if err := store.UpdateLineup(&edit); err != ErrLineupLocked {
+		t.Errorf("lineup change in a live contest: err = %v", err)
+	}
+	if saved, _ := store.GetTeam("t1"); saved.CaptainID != "a" {
+		t.Errorf("captain changed to %s after lock", saved.CaptainID)
+	}
+}
@@ -2,6 +2,9 @@ package fantasy
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,13 +12,13 @@ import (
 
 // Constants for team constraints
 const (
-	MaxPlayers     = 11
-	Budget         = 100.0
-	MinBatsmen     = 3
-	MinBowlers     = 3
+	MaxPlayers       = 11
+	Budget           = 100.0
+	MinBatsmen       = 3
+	MinBowlers       = 3
 	MinWicketKeepers = 1
-	MinAllRounders = 1
-	MaxFromOneTeam = 7
+	MinAllRounders   = 1
+	MaxFromOneTeam   = 7
 )
 
 // Player Roles
@@ -32,36 +35,50 @@ type FantasyPlayer struct {
 	Name     string  `json:"name"`
 	Team     string  `json:"team"` // Real team (e.g., "IND", "AUS")
 	Role     string  `json:"role"`
-	Cost     float64 `json:"cost"`
+	Cost     float64 `json:"cost"`             // Credits for this match
+	Lineup   string  `json:"lineup,omitempty"` // Set once the playing XI is announced; see the Lineup Statuses
 }
 
 // FantasyTeam represents a user's team for a contest
 type FantasyTeam struct {
-	ID          string          `json:"id"`
-	UserID      string          `json:"user_id"`
-	ContestID   string          `json:"contest_id"`
-	MatchID     string          `json:"match_id"`
-	Players     []FantasyPlayer `json:"players"`
-	CaptainID   string          `json:"captain_id"`
-	ViceCaptainID string        `json:"vice_captain_id"`
-	TotalPoints float64         `json:"total_points"`
-	Rank        int             `json:"rank"`
-	CreatedAt   time.Time       `json:"created_at"`
+	ID            string          `json:"id"`
+	UserID        string          `json:"user_id"`
+	ContestID     string          `json:"contest_id"`
+	MatchID       string          `json:"match_id"`
+	Players       []FantasyPlayer `json:"players"`
+	CaptainID     string          `json:"captain_id"`
+	ViceCaptainID string          `json:"vice_captain_id"`
+	TotalPoints   float64         `json:"total_points"`
+	Rank          int             `json:"rank"`
+	Status        string          `json:"status"` // See the Team Statuses
+	PrizeAmount   float64         `json:"prize_amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
 }
 
 // TeamBuilder handles team creation and validation
-type TeamBuilder struct{}
+type TeamBuilder struct {
+	Rules TeamRules
+}
 
-// NewTeamBuilder creates a new team builder
+// NewTeamBuilder creates a team builder for cricket
 func NewTeamBuilder() *TeamBuilder {
-	return &TeamBuilder{}
+	rules, _ := DefaultTeamRules(SportCricket)
+	return &TeamBuilder{Rules: rules}
+}
+
+// NewTeamBuilderFor creates a team builder for a sport's constraints
+func NewTeamBuilderFor(rules TeamRules) *TeamBuilder {
+	return &TeamBuilder{Rules: rules}
 }
 
 // CreateTeam validates and creates a fantasy team
 func (tb *TeamBuilder) CreateTeam(userID, contestID, matchID string, players []FantasyPlayer, captainID, viceCaptainID string) (*FantasyTeam, error) {
+	rules := tb.Rules
+
 	// 1. Validate Player Count
-	if len(players) != MaxPlayers {
-		return nil, errors.New("team must have exactly 11 players")
+	if len(players) != rules.Players {
+		return nil, fmt.Errorf("team must have exactly %d players", rules.Players)
 	}
 
 	// 2. Validate Budget
@@ -69,12 +86,12 @@ func (tb *TeamBuilder) CreateTeam(userID, contestID, matchID string, players []F
 	for _, p := range players {
 		totalCost += p.Cost
 	}
-	if totalCost > Budget {
+	if totalCost > rules.Budget {
 		return nil, errors.New("team cost exceeds budget")
 	}
 
 	// 3. Validate Roles
-	batsmen, bowlers, wks, allrounders := 0, 0, 0, 0
+	roleCounts := make(map[string]int)
 	teamCounts := make(map[string]int)
 
 	playerMap := make(map[string]bool)
@@ -86,39 +103,31 @@ func (tb *TeamBuilder) CreateTeam(userID, contestID, matchID string, players []F
 		}
 		playerMap[p.PlayerID] = true
 
-		// Count roles
-		switch p.Role {
-		case RoleBatsman:
-			batsmen++
-		case RoleBowler:
-			bowlers++
-		case RoleWicketKeeper:
-			wks++
-		case RoleAllRounder:
-			allrounders++
+		if _, ok := rules.Roles[p.Role]; !ok {
+			return nil, fmt.Errorf("player %s has role %s, which %s teams don't use", p.PlayerID, p.Role, strings.ToLower(rules.Sport))
 		}
+		roleCounts[p.Role]++
 
 		// Count teams
 		teamCounts[p.Team]++
 	}
 
-	if batsmen < MinBatsmen {
-		return nil, errors.New("must have at least 3 batsmen")
+	roles := make([]string, 0, len(rules.Roles))
+	for role := range rules.Roles {
+		roles = append(roles, role)
 	}
-	if bowlers < MinBowlers {
-		return nil, errors.New("must have at least 3 bowlers")
-	}
-	if wks < MinWicketKeepers {
-		return nil, errors.New("must have at least 1 wicket keeper")
-	}
-	if allrounders < MinAllRounders {
-		return nil, errors.New("must have at least 1 all-rounder")
+	sort.Strings(roles)
+	for _, role := range roles {
+		limit := rules.Roles[role]
+		if roleCounts[role] < limit.Min || roleCounts[role] > limit.Max {
+			return nil, fmt.Errorf("must have %d to %d of %s", limit.Min, limit.Max, role)
+		}
 	}
 
 	// 4. Validate Max Players from One Team
 	for _, count := range teamCounts {
-		if count > MaxFromOneTeam {
-			return nil, errors.New("cannot have more than 7 players from one team")
+		if count > rules.MaxFromOneTeam {
+			return nil, fmt.Errorf("cannot have more than %d players from one team", rules.MaxFromOneTeam)
 		}
 	}
 
@@ -133,6 +142,7 @@ func (tb *TeamBuilder) CreateTeam(userID, contestID, matchID string, players []F
 		return nil, errors.New("captain and vice-captain must be different")
 	}
 
+	now := time.Now()
 	return &FantasyTeam{
 		ID:            uuid.New().String(),
 		UserID:        userID,
@@ -141,6 +151,8 @@ func (tb *TeamBuilder) CreateTeam(userID, contestID, matchID string, players []F
 		Players:       players,
 		CaptainID:     captainID,
 		ViceCaptainID: viceCaptainID,
-		CreatedAt:     time.Now(),
+		Status:        TeamJoined,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}, nil
 }
@@ -0,0 +1,19 @@
+package ludo
+
+import "github.com/playkaro/game-engine/internal/engine"
+
+// NewBot returns a bot for one seat. Ludo Classic has no choice to make beyond
+// rolling on your turn, so every level plays the same.
+func (g *LudoGame) NewBot(level engine.BotLevel) engine.BotStrategy {
+	return ludoBot{}
+}
+
+type ludoBot struct{}
+
+func (ludoBot) NextMove(session *engine.GameSession, botID string) (*engine.Move, error) {
+	state, ok := session.State.(*LudoState)
+	if !ok || state.Winner != "" || state.CurrentTurn != botID {
+		return nil, nil
+	}
+	return &engine.Move{PlayerID: botID, Type: "ROLL_DICE"}, nil
+}
@@ -41,12 +41,15 @@ func (g *LudoGame) Initialize() error {
 }
 
 func (g *LudoGame) Start(session *engine.GameSession) error {
-	// Deduct entry fee from all players
-	for _, p := range session.Players {
-		err := g.walletClient.Debit(p.UserID, g.entryFee, session.SessionID, "GAME_LUDO")
-		if err != nil {
-			// In production, we would refund other players and cancel session
-			return errors.New("failed to deduct entry fee for " + p.UserID)
+	// Deduct entry fee from all players, unless matchmaking already has
+	if !session.Prepaid && !session.Replay {
+		seats := wallet.ForSeats(g.walletClient, session.Players)
+		for _, p := range session.Players {
+			err := seats.Debit(p.UserID, session.EntryFee, session.SessionID, "GAME_LUDO")
+			if err != nil {
+				// In production, we would refund other players and cancel session
+				return errors.New("failed to deduct entry fee for " + p.UserID)
+			}
 		}
 	}
 
@@ -65,7 +68,7 @@ func (g *LudoGame) HandleMove(session *engine.GameSession, move engine.Move) (*e
 	}
 
 	if move.Type == "ROLL_DICE" {
-		dice := rand.Intn(6) + 1
+		dice := engine.Draw(session.RNG, func() int { return rand.Intn(6) + 1 })
 		state.DiceValue = dice
 
 		// Simple logic: pass turn to next player
@@ -100,13 +103,13 @@ func (g *LudoGame) End(session *engine.GameSession) (*engine.GameResult, error)
 	state := session.State.(*LudoState)
 
 	// Calculate Prize Pool (Platform fee 10%)
-	totalPool := g.entryFee * float64(len(session.Players))
+	totalPool := session.EntryFee * float64(len(session.Players))
 	platformFee := totalPool * 0.10
 	prize := totalPool - platformFee
 
 	// Credit Winner
-	if state.Winner != "" {
-		g.walletClient.Credit(state.Winner, prize, session.SessionID, "GAME_LUDO")
+	if state.Winner != "" && prize > 0 && !session.Replay {
+		wallet.ForSeats(g.walletClient, session.Players).Credit(state.Winner, prize, session.SessionID, "GAME_LUDO")
 	}
 
 	return &engine.GameResult{
@@ -118,3 +121,8 @@ func (g *LudoGame) End(session *engine.GameSession) (*engine.GameResult, error)
 func (g *LudoGame) GetState(session *engine.GameSession) interface{} {
 	return session.State
 }
+
+// Snapshot is the whole board; nothing in Ludo is hidden
+func (g *LudoGame) Snapshot(session *engine.GameSession) interface{} {
+	return session.State
+}
@@ -0,0 +1,222 @@
+package mines
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// Board limits
+const (
+	GridSize = fairness.MinesGridSize // 5x5
+	MinMines = 1
+	MaxMines = 24
+)
+
+// Round status
+const (
+	StatusIdle      = "IDLE"
+	StatusActive    = "ACTIVE"
+	StatusCashedOut = "CASHED_OUT"
+	StatusBusted    = "BUSTED"
+)
+
+type MinesGame struct {
+	gameID       string
+	entryFee     float64
+	houseEdge    float64
+	walletClient *wallet.WalletClient
+	settler      *settlement.Settler
+	seeds        *fairness.SeedManager
+}
+
+type MinesState struct {
+	Status         string  `json:"status"`
+	RoundID        string  `json:"round_id"`
+	Amount         float64 `json:"amount"`
+	Mines          int     `json:"mines"`
+	Revealed       []int   `json:"revealed"`
+	Multiplier     float64 `json:"multiplier"`
+	NextMultiplier float64 `json:"next_multiplier"`
+	Payout         float64 `json:"payout"`
+	ServerSeedHash string  `json:"server_seed_hash"`
+	ClientSeed     string  `json:"client_seed"`
+	Nonce          int     `json:"nonce"`
+	MinePositions  []int   `json:"mine_positions,omitempty"` // Only shown once the round is over
+
+	minePositions map[int]bool
+}
+
+// NewMinesGame creates Mines. Bets are debited from the wallet; cash-outs are
+// paid through settler, which must be running.
+func NewMinesGame(seeds *fairness.SeedManager, settler *settlement.Settler) *MinesGame {
+	return &MinesGame{
+		gameID:       "mines_classic",
+		entryFee:     1.0,
+		houseEdge:    0.01,
+		walletClient: wallet.NewWalletClient(),
+		settler:      settler,
+		seeds:        seeds,
+	}
+}
+
+func (g *MinesGame) GetGameID() string { return g.gameID }
+func (g *MinesGame) GetGameName() string { return "Mines" }
+func (g *MinesGame) GetGameType() engine.GameType { return engine.GameTypeCasino }
+func (g *MinesGame) GetMinPlayers() int { return 1 }
+func (g *MinesGame) GetMaxPlayers() int { return 1 }
+func (g *MinesGame) GetEntryFee() float64 { return g.entryFee }
+
+func (g *MinesGame) Initialize() error {
+	return nil
+}
+
+func (g *MinesGame) Start(session *engine.GameSession) error {
+	session.State = &MinesState{Status: StatusIdle, Revealed: []int{}}
+	return nil
+}
+
+func (g *MinesGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
+	state := session.State.(*MinesState)
+
+	switch move.Type {
+	case "START":
+		return g.startRound(session, state, move)
+	case "REVEAL":
+		return g.reveal(state, move)
+	case "CASHOUT":
+		return g.cashout(state, move)
+	}
+
+	return nil, errors.New("invalid move type")
+}
+
+func (g *MinesGame) startRound(session *engine.GameSession, state *MinesState, move engine.Move) (*engine.MoveResult, error) {
+	if state.Status == StatusActive {
+		return nil, errors.New("round already in progress")
+	}
+
+	amount, _ := move.Data["amount"].(float64)
+	if amount < g.entryFee {
+		return nil, errors.New("bet amount too low")
+	}
+	mines, err := parseMineCount(move.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	roundID := fmt.Sprintf("mines_%s_%d", session.SessionID, time.Now().UnixNano())
+
+	// Mines are fixed by the seed pair the moment the round starts
+	seed, err := g.seeds.NextRound(move.PlayerID)
+	if err != nil {
+		return nil, fmt.Errorf("seed unavailable: %v", err)
+	}
+
+	// Deduct Bet
+	if err := g.walletClient.Debit(move.PlayerID, amount, roundID, "GAME_MINES"); err != nil {
+		return nil, fmt.Errorf("bet failed: %v", err)
+	}
+
+	positions := fairness.MinePositions(seed.ServerSeed, seed.ClientSeed, seed.Nonce, mines)
+	placed := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		placed[p] = true
+	}
+
+	*state = MinesState{
+		Status:         StatusActive,
+		RoundID:        roundID,
+		Amount:         amount,
+		Mines:          mines,
+		Revealed:       []int{},
+		Multiplier:     1.0,
+		NextMultiplier: Multiplier(mines, 1, g.houseEdge),
+		ServerSeedHash: seed.ServerSeedHash,
+		ClientSeed:     seed.ClientSeed,
+		Nonce:          seed.Nonce,
+		minePositions:  placed,
+	}
+
+	return &engine.MoveResult{Success: true, StateUpdate: state}, nil
+}
+
+func (g *MinesGame) reveal(state *MinesState, move engine.Move) (*engine.MoveResult, error) {
+	if state.Status != StatusActive {
+		return nil, errors.New("no active round")
+	}
+
+	tileVal, ok := move.Data["tile"].(float64)
+	tile := int(tileVal)
+	if !ok || tileVal != math.Trunc(tileVal) || tile < 0 || tile >= GridSize {
+		return nil, fmt.Errorf("tile must be between 0 and %d", GridSize-1)
+	}
+	for _, t := range state.Revealed {
+		if t == tile {
+			return nil, errors.New("tile already revealed")
+		}
+	}
+
+	state.Revealed = append(state.Revealed, tile)
+
+	if state.minePositions[tile] {
+		state.Status = StatusBusted
+		state.Multiplier = 0
+		state.NextMultiplier = 0
+		state.MinePositions = state.sortedMines()
+		return &engine.MoveResult{Success: true, StateUpdate: state, GameEnded: true}, nil
+	}
+
+	safe := len(state.Revealed)
+	state.Multiplier = Multiplier(state.Mines, safe, g.houseEdge)
+
+	// Every safe tile found: nothing left to reveal, so cash out automatically
+	if safe == GridSize-state.Mines {
+		return g.cashout(state, move)
+	}
+
+	state.NextMultiplier = Multiplier(state.Mines, safe+1, g.houseEdge)
+	return &engine.MoveResult{Success: true, StateUpdate: state}, nil
+}
+
+func (g *MinesGame) cashout(state *MinesState, move engine.Move) (*engine.MoveResult, error) {
+	if state.Status != StatusActive {
+		return nil, errors.New("no active round")
+	}
+	if len(state.Revealed) == 0 {
+		return nil, errors.New("reveal at least one tile before cashing out")
+	}
+
+	state.Payout = math.Floor(state.Amount*state.Multiplier*100) / 100
+	state.Status = StatusCashedOut
+	state.NextMultiplier = 0
+	state.MinePositions = state.sortedMines()
+
+	g.settler.Pay(settlement.Credit{UserID: move.PlayerID, Amount: state.Payout, RefID: state.RoundID, RefType: "GAME_MINES"})
+
+	return &engine.MoveResult{Success: true, StateUpdate: state, GameEnded: true}, nil
+}
+
+func (s *MinesState) sortedMines() []int {
+	positions := []int{}
+	for tile := 0; tile < GridSize; tile++ {
+		if s.minePositions[tile] {
+			positions = append(positions, tile)
+		}
+	}
+	return positions
+}
+
+func (g *MinesGame) End(session *engine.GameSession) (*engine.GameResult, error) {
+	return &engine.GameResult{}, nil
+}
+
+func (g *MinesGame) GetState(session *engine.GameSession) interface{} {
+	return session.State
+}
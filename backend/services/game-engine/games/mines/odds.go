@@ -0,0 +1,37 @@
+package mines
+
+// SafeProbability is the exact chance that the first `revealed` picks on a board with
+// `mines` mines are all safe: C(25-mines, revealed) / C(25, revealed).
+func SafeProbability(mines, revealed int) float64 {
+	if revealed < 0 || revealed > GridSize-mines {
+		return 0
+	}
+
+	p := 1.0
+	for i := 0; i < revealed; i++ {
+		p *= float64(GridSize-mines-i) / float64(GridSize-i)
+	}
+	return p
+}
+
+// Multiplier pays the fair odds of surviving `revealed` picks, less the house edge.
+// The expected return of cashing out at any point is therefore exactly 1 - houseEdge.
+func Multiplier(mines, revealed int, houseEdge float64) float64 {
+	p := SafeProbability(mines, revealed)
+	if p == 0 {
+		return 0
+	}
+	if revealed == 0 {
+		return 1.0
+	}
+	return (1 - houseEdge) / p
+}
+
+// MultiplierTable lists the multiplier after each safe reveal (index 0 = first reveal)
+func MultiplierTable(mines int, houseEdge float64) []float64 {
+	table := make([]float64, 0, GridSize-mines)
+	for revealed := 1; revealed <= GridSize-mines; revealed++ {
+		table = append(table, Multiplier(mines, revealed, houseEdge))
+	}
+	return table
+}
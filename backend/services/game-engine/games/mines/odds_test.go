@@ -0,0 +1,131 @@
+package mines
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+const houseEdge = 0.01
+
+// binomial is C(n, k) exactly
+func binomial(n, k int) *big.Int {
+	return new(big.Int).Binomial(int64(n), int64(k))
+}
+
+// exactSafeProbability is C(25-mines, revealed) / C(25, revealed) as a fraction
+func exactSafeProbability(mines, revealed int) *big.Rat {
+	return new(big.Rat).SetFrac(binomial(GridSize-mines, revealed), binomial(GridSize, revealed))
+}
+
+func closeTo(got, want float64) bool {
+	return math.Abs(got-want) <= 1e-12*math.Max(1, math.Abs(want))
+}
+
+func TestSafeProbabilityIsExact(t *testing.T) {
+	for mines := MinMines; mines <= MaxMines; mines++ {
+		for revealed := 0; revealed <= GridSize-mines; revealed++ {
+			want, _ := exactSafeProbability(mines, revealed).Float64()
+			if got := SafeProbability(mines, revealed); !closeTo(got, want) {
+				t.Errorf("SafeProbability(%d, %d) = %v, want %v", mines, revealed, got, want)
+			}
+		}
+		if got := SafeProbability(mines, GridSize-mines+1); got != 0 {
+			t.Errorf("SafeProbability(%d, %d) = %v past the last safe tile, want 0", mines, GridSize-mines+1, got)
+		}
+	}
+}
+
+// TestSafeProbabilityCountsBoards checks the formula against every possible
+// placement of up to three mines
+func TestSafeProbabilityCountsBoards(t *testing.T) {
+	var place func(from, left int, board []int, visit func([]int))
+	place = func(from, left int, board []int, visit func([]int)) {
+		if left == 0 {
+			visit(board)
+			return
+		}
+		for tile := from; tile < GridSize; tile++ {
+			place(tile+1, left-1, append(board, tile), visit)
+		}
+	}
+
+	for mines := 1; mines <= 3; mines++ {
+		for revealed := 1; revealed <= GridSize-mines; revealed++ {
+			boards, safe := 0, 0
+			place(0, mines, nil, func(board []int) {
+				boards++
+				for _, tile := range board {
+					if tile < revealed {
+						return
+					}
+				}
+				safe++
+			})
+			want := float64(safe) / float64(boards)
+			if got := SafeProbability(mines, revealed); !closeTo(got, want) {
+				t.Errorf("%d mines, %d revealed: SafeProbability = %v, %d of %d boards safe", mines, revealed, got, safe, boards)
+			}
+		}
+	}
+}
+
+func TestMultiplierTable(t *testing.T) {
+	for mines := MinMines; mines <= MaxMines; mines++ {
+		table := MultiplierTable(mines, houseEdge)
+		if len(table) != GridSize-mines {
+			t.Fatalf("%d mines: %d multipliers, want %d", mines, len(table), GridSize-mines)
+		}
+		for i, mult := range table {
+			revealed := i + 1
+			// (1 - edge) * C(25, r) / C(25-mines, r)
+			exact := new(big.Rat).Mul(big.NewRat(99, 100), new(big.Rat).Inv(exactSafeProbability(mines, revealed)))
+			want, _ := exact.Float64()
+			if !closeTo(mult, want) {
+				t.Errorf("%d mines, %d revealed: multiplier %v, want %v", mines, revealed, mult, want)
+			}
+			// Cashing out at any point returns exactly 1 - house edge
+			if rtp := SafeProbability(mines, revealed) * mult; !closeTo(rtp, 1-houseEdge) {
+				t.Errorf("%d mines, %d revealed: RTP %v, want %v", mines, revealed, rtp, 1-houseEdge)
+			}
+			if i > 0 && mult <= table[i-1] {
+				t.Errorf("%d mines: multiplier %v after %d reveals does not grow from %v", mines, mult, revealed, table[i-1])
+			}
+		}
+	}
+}
+
+func TestMultiplierKnownValues(t *testing.T) {
+	tests := []struct {
+		mines, revealed int
+		want            float64
+	}{
+		{1, 1, 0.99 * 25 / 24},
+		{3, 3, 0.99 * 2300 / 1540},
+		{24, 1, 0.99 * 25},
+		{1, 24, 0.99 * 25},
+		{5, 20, 0.99 * 53130},
+		{10, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := Multiplier(tt.mines, tt.revealed, houseEdge); !closeTo(got, tt.want) {
+			t.Errorf("Multiplier(%d, %d) = %v, want %v", tt.mines, tt.revealed, got, tt.want)
+		}
+	}
+}
+
+func TestTheoreticalRTPMatchesHouseEdge(t *testing.T) {
+	game := NewMinesGame(nil, nil)
+	for mines := MinMines; mines <= MaxMines; mines++ {
+		for reveals := 1; reveals <= GridSize-mines; reveals++ {
+			bet := map[string]interface{}{"amount": 100.0, "mines": float64(mines), "reveals": float64(reveals)}
+			rtp, err := game.TheoreticalRTP(bet)
+			if err != nil {
+				t.Fatalf("%v: %v", bet, err)
+			}
+			if !closeTo(rtp, 1-houseEdge) {
+				t.Errorf("%d mines, %d reveals: RTP %v, want %v", mines, reveals, rtp, 1-houseEdge)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package mines
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// parseMineCount validates the mines field of a START payload
+func parseMineCount(data map[string]interface{}) (int, error) {
+	mines, _ := data["mines"].(float64)
+	if mines != math.Trunc(mines) || int(mines) < MinMines || int(mines) > MaxMines {
+		return 0, fmt.Errorf("mines must be between %d and %d", MinMines, MaxMines)
+	}
+	return int(mines), nil
+}
+
+// parseSimulatedRound reads a START payload plus "reveals", the number of tiles the
+// simulated player turns over before cashing out
+func parseSimulatedRound(bet map[string]interface{}) (amount float64, mines, reveals int, err error) {
+	amount, _ = bet["amount"].(float64)
+	if amount <= 0 {
+		return 0, 0, 0, errors.New("invalid bet amount")
+	}
+	if mines, err = parseMineCount(bet); err != nil {
+		return 0, 0, 0, err
+	}
+	r, _ := bet["reveals"].(float64)
+	reveals = int(r)
+	if r != math.Trunc(r) || reveals < 1 || reveals > GridSize-mines {
+		return 0, 0, 0, fmt.Errorf("reveals must be between 1 and %d", GridSize-mines)
+	}
+	return amount, mines, reveals, nil
+}
+
+// TheoreticalRTP is P(every revealed tile safe) times the cashout multiplier
+func (g *MinesGame) TheoreticalRTP(bet map[string]interface{}) (float64, error) {
+	_, mines, reveals, err := parseSimulatedRound(bet)
+	if err != nil {
+		return 0, err
+	}
+	return SafeProbability(mines, reveals) * Multiplier(mines, reveals, g.houseEdge), nil
+}
+
+// SimulateRound reveals tiles 0, 1, 2... and cashes out after the requested number.
+// Mines are uniformly placed, so a fixed reveal order is as good as any other.
+func (g *MinesGame) SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (float64, float64, error) {
+	amount, mines, reveals, err := parseSimulatedRound(bet)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, p := range fairness.MinePositions(serverSeed, clientSeed, nonce, mines) {
+		if p < reveals {
+			return amount, 0, nil
+		}
+	}
+	return amount, math.Floor(amount*Multiplier(mines, reveals, g.houseEdge)*100) / 100, nil
+}
@@ -0,0 +1,121 @@
+package plinko
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// Risk levels
+const (
+	RiskLow    = "LOW"
+	RiskMedium = "MEDIUM"
+	RiskHigh   = "HIGH"
+)
+
+// Board limits
+const (
+	MinRows = fairness.PlinkoMinRows
+	MaxRows = fairness.PlinkoMaxRows
+)
+
+// halfTables holds the left half (edge to centre, centre included) of every payout
+// table; the right half mirrors it. Values target a 1% house edge, within the
+// tolerance payouts_test.go checks: multipliers are rounded to friendly values, so
+// tables can't hit the edge exactly.
+var halfTables = map[int]map[string][]float64{
+	8: {
+		RiskLow:    {5.6, 2.1, 1.1, 1, 0.5},
+		RiskMedium: {13, 3, 1.3, 0.7, 0.4},
+		RiskHigh:   {29, 4, 1.5, 0.3, 0.2},
+	},
+	9: {
+		RiskLow:    {5.6, 2, 1.6, 1, 0.7},
+		RiskMedium: {18, 4, 1.7, 0.9, 0.5},
+		RiskHigh:   {43, 7, 2, 0.6, 0.2},
+	},
+	10: {
+		RiskLow:    {8.9, 3, 1.4, 1.1, 1, 0.5},
+		RiskMedium: {22, 5, 2, 1.4, 0.6, 0.4},
+		RiskHigh:   {76, 10, 3, 0.9, 0.3, 0.2},
+	},
+	11: {
+		RiskLow:    {8.4, 3, 1.9, 1.3, 1, 0.7},
+		RiskMedium: {24, 6, 3, 1.8, 0.7, 0.5},
+		RiskHigh:   {120, 14, 5.2, 1.4, 0.4, 0.2},
+	},
+	12: {
+		RiskLow:    {10, 3, 1.6, 1.4, 1.1, 1, 0.5},
+		RiskMedium: {33, 11, 4, 2, 1.1, 0.6, 0.3},
+		RiskHigh:   {170, 24, 8.1, 2, 0.7, 0.2, 0.2},
+	},
+	13: {
+		RiskLow:    {8.1, 4, 3, 1.9, 1.2, 0.9, 0.7},
+		RiskMedium: {43, 13, 6, 3, 1.3, 0.7, 0.4},
+		RiskHigh:   {260, 37, 11, 4, 1, 0.2, 0.2},
+	},
+	14: {
+		RiskLow:    {7.1, 4, 1.9, 1.4, 1.3, 1.1, 1, 0.5},
+		RiskMedium: {58, 15, 7, 4, 1.9, 1, 0.5, 0.2},
+		RiskHigh:   {420, 56, 18, 5, 1.9, 0.3, 0.2, 0.2},
+	},
+	15: {
+		RiskLow:    {15, 8, 3, 2, 1.5, 1.1, 1, 0.7},
+		RiskMedium: {88, 18, 11, 5, 3, 1.3, 0.5, 0.3},
+		RiskHigh:   {620, 83, 27, 8, 3, 0.5, 0.2, 0.2},
+	},
+	16: {
+		RiskLow:    {16, 9, 2, 1.4, 1.4, 1.2, 1.1, 1, 0.5},
+		RiskMedium: {110, 41, 10, 5, 3, 1.5, 1, 0.5, 0.3},
+		RiskHigh:   {1000, 130, 26, 9, 4, 2, 0.2, 0.2, 0.2},
+	},
+}
+
+// PayoutTable returns the multiplier for each of the rows+1 buckets, left to right
+func PayoutTable(rows int, risk string) ([]float64, error) {
+	byRisk, ok := halfTables[rows]
+	if !ok {
+		return nil, fmt.Errorf("rows must be between %d and %d", MinRows, MaxRows)
+	}
+	half, ok := byRisk[risk]
+	if !ok {
+		return nil, fmt.Errorf("unknown risk level %q", risk)
+	}
+
+	table := make([]float64, rows+1)
+	for i := range table {
+		if i < len(half) {
+			table[i] = half[i]
+		} else {
+			table[i] = half[rows-i]
+		}
+	}
+	return table, nil
+}
+
+// BucketProbability is the exact chance of landing in bucket k: C(rows, k) / 2^rows
+func BucketProbability(rows, k int) float64 {
+	if k < 0 || k > rows {
+		return 0
+	}
+	c := 1.0
+	for i := 1; i <= k; i++ {
+		c = c * float64(rows-k+i) / float64(i)
+	}
+	return c / math.Pow(2, float64(rows))
+}
+
+// TheoreticalRTP is the expected return of one ball: sum of P(bucket) * multiplier
+func TheoreticalRTP(rows int, risk string) (float64, error) {
+	table, err := PayoutTable(rows, risk)
+	if err != nil {
+		return 0, err
+	}
+
+	rtp := 0.0
+	for k, mult := range table {
+		rtp += BucketProbability(rows, k) * mult
+	}
+	return rtp, nil
+}
@@ -0,0 +1,81 @@
+package plinko
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// rtpTolerance is how far a table's theoretical RTP may drift from 1 - house edge
+const rtpTolerance = 0.0025
+
+// exactRTP is sum of C(rows, k) * multiplier over 2^rows, computed as a fraction
+func exactRTP(rows int, table []float64) float64 {
+	sum := new(big.Rat)
+	for k, mult := range table {
+		weight := new(big.Rat).SetInt(new(big.Int).Binomial(int64(rows), int64(k)))
+		m := new(big.Rat)
+		m.SetString(fmt.Sprint(mult))
+		sum.Add(sum, weight.Mul(weight, m))
+	}
+	sum.Quo(sum, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(rows))))
+	rtp, _ := sum.Float64()
+	return rtp
+}
+
+// TestPayoutTableRTP checks each payout table on its own against the house edge
+func TestPayoutTableRTP(t *testing.T) {
+	houseEdge := NewPlinkoGame(nil, nil).houseEdge
+	for rows := MinRows; rows <= MaxRows; rows++ {
+		for _, risk := range []string{RiskLow, RiskMedium, RiskHigh} {
+			rows, risk := rows, risk
+			t.Run(fmt.Sprintf("%d_rows_%s", rows, risk), func(t *testing.T) {
+				table, err := PayoutTable(rows, risk)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(table) != rows+1 {
+					t.Fatalf("%d buckets, want %d", len(table), rows+1)
+				}
+				for k := range table {
+					if table[k] != table[rows-k] {
+						t.Fatalf("bucket %d pays %v but its mirror %d pays %v", k, table[k], rows-k, table[rows-k])
+					}
+				}
+
+				rtp, err := TheoreticalRTP(rows, risk)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if exact := exactRTP(rows, table); math.Abs(rtp-exact) > 1e-12 {
+					t.Errorf("TheoreticalRTP = %.12f, exact binomial sum %.12f", rtp, exact)
+				}
+				if rtp >= 1 || math.Abs(rtp-(1-houseEdge)) > rtpTolerance {
+					t.Errorf("RTP %.5f, want %.4f ± %.4f", rtp, 1-houseEdge, rtpTolerance)
+				}
+			})
+		}
+	}
+}
+
+func TestBucketProbabilityIsBinomial(t *testing.T) {
+	for rows := MinRows; rows <= MaxRows; rows++ {
+		total := 0.0
+		for k := 0; k <= rows; k++ {
+			exact := new(big.Rat).SetFrac(new(big.Int).Binomial(int64(rows), int64(k)), new(big.Int).Lsh(big.NewInt(1), uint(rows)))
+			want, _ := exact.Float64()
+			got := BucketProbability(rows, k)
+			if math.Abs(got-want) > 1e-15 {
+				t.Errorf("BucketProbability(%d, %d) = %v, want %v", rows, k, got, want)
+			}
+			total += got
+		}
+		if math.Abs(total-1) > 1e-12 {
+			t.Errorf("%d rows: bucket probabilities sum to %v", rows, total)
+		}
+		if got := BucketProbability(rows, rows+1); got != 0 {
+			t.Errorf("BucketProbability(%d, %d) = %v, want 0", rows, rows+1, got)
+		}
+	}
+}
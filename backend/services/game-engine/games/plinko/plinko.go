@@ -0,0 +1,127 @@
+package plinko
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+type PlinkoGame struct {
+	gameID       string
+	entryFee     float64
+	houseEdge    float64
+	walletClient *wallet.WalletClient
+	settler      *settlement.Settler
+	seeds        *fairness.SeedManager
+}
+
+type PlinkoState struct {
+	Rows           int     `json:"rows"`
+	Risk           string  `json:"risk"`
+	Path           []int   `json:"path"` // 0 = left, 1 = right
+	Bucket         int     `json:"bucket"`
+	Multiplier     float64 `json:"multiplier"`
+	Amount         float64 `json:"amount"`
+	Payout         float64 `json:"payout"`
+	ServerSeedHash string  `json:"server_seed_hash"`
+	ClientSeed     string  `json:"client_seed"`
+	Nonce          int     `json:"nonce"`
+}
+
+// NewPlinkoGame creates Plinko. Bets are debited from the wallet; winnings are
+// paid through settler, which must be running.
+func NewPlinkoGame(seeds *fairness.SeedManager, settler *settlement.Settler) *PlinkoGame {
+	return &PlinkoGame{
+		gameID:       "plinko_classic",
+		entryFee:     1.0,
+		houseEdge:    0.01,
+		walletClient: wallet.NewWalletClient(),
+		settler:      settler,
+		seeds:        seeds,
+	}
+}
+
+func (g *PlinkoGame) GetGameID() string { return g.gameID }
+func (g *PlinkoGame) GetGameName() string { return "Plinko" }
+func (g *PlinkoGame) GetGameType() engine.GameType { return engine.GameTypeCasino }
+func (g *PlinkoGame) GetMinPlayers() int { return 1 }
+func (g *PlinkoGame) GetMaxPlayers() int { return 1 }
+func (g *PlinkoGame) GetEntryFee() float64 { return g.entryFee }
+
+func (g *PlinkoGame) Initialize() error {
+	return nil
+}
+
+func (g *PlinkoGame) Start(session *engine.GameSession) error {
+	session.State = &PlinkoState{}
+	return nil
+}
+
+func (g *PlinkoGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
+	if move.Type != "DROP" {
+		return nil, errors.New("invalid move type")
+	}
+
+	amount, rows, risk, table, err := parseDrop(move.Data)
+	if err != nil {
+		return nil, err
+	}
+	if amount < g.entryFee {
+		return nil, errors.New("bet amount too low")
+	}
+
+	roundID := fmt.Sprintf("plinko_%s_%d", session.SessionID, time.Now().UnixNano())
+
+	// The round's seeds are committed before any money moves
+	seed, err := g.seeds.NextRound(move.PlayerID)
+	if err != nil {
+		return nil, fmt.Errorf("seed unavailable: %v", err)
+	}
+
+	// 1. Deduct Bet
+	if err := g.walletClient.Debit(move.PlayerID, amount, roundID, "GAME_PLINKO"); err != nil {
+		return nil, fmt.Errorf("bet failed: %v", err)
+	}
+
+	// 2. Drop the ball
+	path, bucket := fairness.PlinkoPath(seed.ServerSeed, seed.ClientSeed, seed.Nonce, rows)
+	multiplier := table[bucket]
+	payout := math.Floor(amount*multiplier*100) / 100
+
+	// 3. Credit Winnings
+	g.settler.Pay(settlement.Credit{UserID: move.PlayerID, Amount: payout, RefID: roundID, RefType: "GAME_PLINKO"})
+
+	state := &PlinkoState{
+		Rows:           rows,
+		Risk:           risk,
+		Path:           path,
+		Bucket:         bucket,
+		Multiplier:     multiplier,
+		Amount:         amount,
+		Payout:         payout,
+		ServerSeedHash: seed.ServerSeedHash,
+		ClientSeed:     seed.ClientSeed,
+		Nonce:          seed.Nonce,
+	}
+	session.State = state
+
+	return &engine.MoveResult{
+		Success:     true,
+		StateUpdate: state,
+		GameEnded:   true,
+	}, nil
+}
+
+func (g *PlinkoGame) End(session *engine.GameSession) (*engine.GameResult, error) {
+	return &engine.GameResult{}, nil
+}
+
+func (g *PlinkoGame) GetState(session *engine.GameSession) interface{} {
+	return session.State
+}
@@ -0,0 +1,47 @@
+package plinko
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// Winnings are queued with the settler rather than credited inline
+func TestDropPaysThroughSettler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wallet.TransactionResponse{Status: "SUCCESS"})
+	}))
+	defer srv.Close()
+	t.Setenv("PAYMENT_SERVICE_URL", srv.URL)
+
+	settler := settlement.NewSettler(settlement.DefaultConfig(), wallet.NewWalletClient(), nil)
+	g := NewPlinkoGame(fairness.NewSeedManager(nil), settler)
+	session := &engine.GameSession{SessionID: "drop"}
+	if err := g.Start(session); err != nil {
+		t.Fatal(err)
+	}
+
+	paid := 0
+	for i := 0; i < 20; i++ {
+		result, err := g.HandleMove(session, engine.Move{
+			PlayerID: "p1",
+			Type:     "DROP",
+			Data:     map[string]interface{}{"amount": 10.0, "rows": 8.0, "risk": RiskLow},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.StateUpdate.(*PlinkoState).Payout > 0 {
+			paid++
+		}
+	}
+	if settler.Pending() != paid {
+		t.Errorf("%d credits queued for %d winning drops", settler.Pending(), paid)
+	}
+}
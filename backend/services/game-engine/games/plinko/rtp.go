@@ -0,0 +1,46 @@
+package plinko
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// parseDrop validates the DROP move payload and returns the payout table to use
+func parseDrop(data map[string]interface{}) (amount float64, rows int, risk string, table []float64, err error) {
+	amount, _ = data["amount"].(float64)
+	if amount <= 0 {
+		return 0, 0, "", nil, errors.New("invalid bet amount")
+	}
+	rowsVal, _ := data["rows"].(float64)
+	rows = int(rowsVal)
+	risk, _ = data["risk"].(string)
+	if rowsVal != math.Trunc(rowsVal) {
+		return 0, 0, "", nil, fmt.Errorf("rows must be between %d and %d", MinRows, MaxRows)
+	}
+	table, err = PayoutTable(rows, risk)
+	if err != nil {
+		return 0, 0, "", nil, err
+	}
+	return amount, rows, risk, table, nil
+}
+
+func (g *PlinkoGame) TheoreticalRTP(bet map[string]interface{}) (float64, error) {
+	_, rows, risk, _, err := parseDrop(bet)
+	if err != nil {
+		return 0, err
+	}
+	return TheoreticalRTP(rows, risk)
+}
+
+func (g *PlinkoGame) SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (float64, float64, error) {
+	amount, rows, _, table, err := parseDrop(bet)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	_, bucket := fairness.PlinkoPath(serverSeed, clientSeed, nonce, rows)
+	return amount, math.Floor(amount*table[bucket]*100) / 100, nil
+}
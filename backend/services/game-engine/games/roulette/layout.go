@@ -0,0 +1,154 @@
+package roulette
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Bet types
+const (
+	BetStraight = "STRAIGHT" // 1 number, 35:1
+	BetSplit    = "SPLIT"    // 2 adjacent numbers, 17:1
+	BetStreet   = "STREET"   // 3 numbers in a row (or 0-1-2 / 0-2-3), 11:1
+	BetCorner   = "CORNER"   // 4 numbers in a square (or 0-1-2-3), 8:1
+	BetSixLine  = "SIX_LINE" // 2 adjacent rows, 5:1
+	BetDozen    = "DOZEN"    // target 1-3, 2:1
+	BetColumn   = "COLUMN"   // target 1-3, 2:1
+	BetRed      = "RED"
+	BetBlack    = "BLACK"
+	BetOdd      = "ODD"
+	BetEven     = "EVEN"
+	BetLow      = "LOW"  // 1-18
+	BetHigh     = "HIGH" // 19-36
+)
+
+// Pockets on a European (single zero) wheel
+const Pockets = 37
+
+var redNumbers = map[int]bool{
+	1: true, 3: true, 5: true, 7: true, 9: true, 12: true, 14: true, 16: true, 18: true,
+	19: true, 21: true, 23: true, 25: true, 27: true, 30: true, 32: true, 34: true, 36: true,
+}
+
+// Chip is a single stake placed on the layout
+type Chip struct {
+	Type    string  `json:"type"`
+	Numbers []int   `json:"numbers,omitempty"` // Inside bets
+	Target  int     `json:"target,omitempty"`  // Dozen / column (1-3)
+	Amount  float64 `json:"amount"`
+}
+
+// IsRed reports whether a pocket is red (0 is green)
+func IsRed(n int) bool {
+	return redNumbers[n]
+}
+
+// Covered validates a chip against the layout and returns the numbers it wins on
+func Covered(chip Chip) ([]int, error) {
+	switch chip.Type {
+	case BetStraight, BetSplit, BetStreet, BetCorner, BetSixLine:
+		return coveredInside(chip)
+	case BetDozen:
+		if chip.Target < 1 || chip.Target > 3 {
+			return nil, fmt.Errorf("dozen must be 1, 2 or 3")
+		}
+		return numbersWhere(func(n int) bool { return (n-1)/12 == chip.Target-1 }), nil
+	case BetColumn:
+		if chip.Target < 1 || chip.Target > 3 {
+			return nil, fmt.Errorf("column must be 1, 2 or 3")
+		}
+		return numbersWhere(func(n int) bool { return (n-1)%3 == chip.Target-1 }), nil
+	case BetRed:
+		return numbersWhere(IsRed), nil
+	case BetBlack:
+		return numbersWhere(func(n int) bool { return !IsRed(n) }), nil
+	case BetOdd:
+		return numbersWhere(func(n int) bool { return n%2 == 1 }), nil
+	case BetEven:
+		return numbersWhere(func(n int) bool { return n%2 == 0 }), nil
+	case BetLow:
+		return numbersWhere(func(n int) bool { return n <= 18 }), nil
+	case BetHigh:
+		return numbersWhere(func(n int) bool { return n >= 19 }), nil
+	}
+	return nil, fmt.Errorf("unknown bet type %q", chip.Type)
+}
+
+// PayoutMultiplier is the total returned per unit staked on a winning chip (stake included).
+// The wheel has 37 pockets but pays as if it had 36, which is the 1/37 house edge.
+func PayoutMultiplier(covered int) float64 {
+	return 36 / float64(covered)
+}
+
+func coveredInside(chip Chip) ([]int, error) {
+	nums := append([]int(nil), chip.Numbers...)
+	sort.Ints(nums)
+
+	for i, n := range nums {
+		if n < 0 || n > 36 {
+			return nil, fmt.Errorf("number %d is not on the wheel", n)
+		}
+		if i > 0 && nums[i-1] == n {
+			return nil, fmt.Errorf("number %d repeated", n)
+		}
+	}
+
+	valid := false
+	switch chip.Type {
+	case BetStraight:
+		valid = len(nums) == 1
+	case BetSplit:
+		valid = len(nums) == 2 && isSplit(nums[0], nums[1])
+	case BetStreet:
+		valid = len(nums) == 3 && isStreet(nums)
+	case BetCorner:
+		valid = len(nums) == 4 && isCorner(nums)
+	case BetSixLine:
+		valid = len(nums) == 6 && nums[0] > 0 && col(nums[0]) == 0 && nums[5] == nums[0]+5
+	}
+	if !valid {
+		return nil, fmt.Errorf("numbers %v are not a valid %s", chip.Numbers, chip.Type)
+	}
+	return nums, nil
+}
+
+// Layout grid: 12 rows of 3, number n sits at row (n-1)/3, column (n-1)%3
+
+func row(n int) int { return (n - 1) / 3 }
+func col(n int) int { return (n - 1) % 3 }
+
+func isSplit(a, b int) bool {
+	if a == 0 {
+		return b >= 1 && b <= 3
+	}
+	sameRow := row(a) == row(b) && b == a+1
+	sameCol := b == a+3
+	return sameRow || sameCol
+}
+
+func isStreet(nums []int) bool {
+	if nums[0] == 0 {
+		// Trios: 0-1-2 and 0-2-3
+		return (nums[1] == 1 && nums[2] == 2) || (nums[1] == 2 && nums[2] == 3)
+	}
+	return col(nums[0]) == 0 && nums[1] == nums[0]+1 && nums[2] == nums[0]+2
+}
+
+func isCorner(nums []int) bool {
+	if nums[0] == 0 {
+		// First four: 0-1-2-3
+		return nums[1] == 1 && nums[2] == 2 && nums[3] == 3
+	}
+	a := nums[0]
+	return col(a) < 2 && nums[1] == a+1 && nums[2] == a+3 && nums[3] == a+4
+}
+
+func numbersWhere(pred func(int) bool) []int {
+	nums := []int{}
+	for n := 1; n <= 36; n++ {
+		if pred(n) {
+			nums = append(nums, n)
+		}
+	}
+	return nums
+}
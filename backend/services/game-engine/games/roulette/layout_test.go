@@ -0,0 +1,121 @@
+package roulette
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoveredPockets(t *testing.T) {
+	tests := []struct {
+		name   string
+		chip   Chip
+		want   []int
+		payout float64
+	}{
+		{"straight zero", Chip{Type: BetStraight, Numbers: []int{0}}, []int{0}, 36},
+		{"straight", Chip{Type: BetStraight, Numbers: []int{17}}, []int{17}, 36},
+		{"split across a row", Chip{Type: BetSplit, Numbers: []int{8, 7}}, []int{7, 8}, 18},
+		{"split down a column", Chip{Type: BetSplit, Numbers: []int{14, 17}}, []int{14, 17}, 18},
+		{"split with zero", Chip{Type: BetSplit, Numbers: []int{0, 3}}, []int{0, 3}, 18},
+		{"street", Chip{Type: BetStreet, Numbers: []int{34, 35, 36}}, []int{34, 35, 36}, 12},
+		{"trio 0-1-2", Chip{Type: BetStreet, Numbers: []int{0, 1, 2}}, []int{0, 1, 2}, 12},
+		{"trio 0-2-3", Chip{Type: BetStreet, Numbers: []int{2, 3, 0}}, []int{0, 2, 3}, 12},
+		{"corner", Chip{Type: BetCorner, Numbers: []int{5, 6, 8, 9}}, []int{5, 6, 8, 9}, 9},
+		{"first four", Chip{Type: BetCorner, Numbers: []int{0, 1, 2, 3}}, []int{0, 1, 2, 3}, 9},
+		{"six line", Chip{Type: BetSixLine, Numbers: []int{31, 32, 33, 34, 35, 36}}, []int{31, 32, 33, 34, 35, 36}, 6},
+		{"first dozen", Chip{Type: BetDozen, Target: 1}, seq(1, 12, 1), 3},
+		{"third dozen", Chip{Type: BetDozen, Target: 3}, seq(25, 36, 1), 3},
+		{"first column", Chip{Type: BetColumn, Target: 1}, seq(1, 34, 3), 3},
+		{"third column", Chip{Type: BetColumn, Target: 3}, seq(3, 36, 3), 3},
+		{"red", Chip{Type: BetRed}, []int{1, 3, 5, 7, 9, 12, 14, 16, 18, 19, 21, 23, 25, 27, 30, 32, 34, 36}, 2},
+		{"black", Chip{Type: BetBlack}, []int{2, 4, 6, 8, 10, 11, 13, 15, 17, 20, 22, 24, 26, 28, 29, 31, 33, 35}, 2},
+		{"odd", Chip{Type: BetOdd}, seq(1, 35, 2), 2},
+		{"even", Chip{Type: BetEven}, seq(2, 36, 2), 2},
+		{"low", Chip{Type: BetLow}, seq(1, 18, 1), 2},
+		{"high", Chip{Type: BetHigh}, seq(19, 36, 1), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Covered(tt.chip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("covers %v, want %v", got, tt.want)
+			}
+			if m := PayoutMultiplier(len(got)); m != tt.payout {
+				t.Errorf("pays %vx, want %vx", m, tt.payout)
+			}
+		})
+	}
+}
+
+func TestCoveredRejectsInvalidChips(t *testing.T) {
+	tests := []struct {
+		name string
+		chip Chip
+	}{
+		{"unknown type", Chip{Type: "NEIGHBOURS", Numbers: []int{1}}},
+		{"straight off the wheel", Chip{Type: BetStraight, Numbers: []int{37}}},
+		{"straight negative", Chip{Type: BetStraight, Numbers: []int{-1}}},
+		{"straight on two numbers", Chip{Type: BetStraight, Numbers: []int{1, 2}}},
+		{"split not adjacent", Chip{Type: BetSplit, Numbers: []int{1, 5}}},
+		{"split across rows", Chip{Type: BetSplit, Numbers: []int{3, 4}}},
+		{"split zero to four", Chip{Type: BetSplit, Numbers: []int{0, 4}}},
+		{"split repeated", Chip{Type: BetSplit, Numbers: []int{5, 5}}},
+		{"street across rows", Chip{Type: BetStreet, Numbers: []int{2, 3, 4}}},
+		{"trio 0-1-3", Chip{Type: BetStreet, Numbers: []int{0, 1, 3}}},
+		{"corner across the edge", Chip{Type: BetCorner, Numbers: []int{3, 4, 6, 7}}},
+		{"corner off the bottom", Chip{Type: BetCorner, Numbers: []int{34, 35, 37, 38}}},
+		{"six line across rows", Chip{Type: BetSixLine, Numbers: []int{2, 3, 4, 5, 6, 7}}},
+		{"six line with zero", Chip{Type: BetSixLine, Numbers: []int{0, 1, 2, 3, 4, 5}}},
+		{"dozen 0", Chip{Type: BetDozen, Target: 0}},
+		{"dozen 4", Chip{Type: BetDozen, Target: 4}},
+		{"column 4", Chip{Type: BetColumn, Target: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if nums, err := Covered(tt.chip); err == nil {
+				t.Errorf("accepted, covering %v", nums)
+			}
+		})
+	}
+}
+
+// Every bet type returns 36/37 of its stake in the long run
+func TestEveryBetTypeHasTheSameEdge(t *testing.T) {
+	chips := []Chip{
+		{Type: BetStraight, Numbers: []int{0}},
+		{Type: BetSplit, Numbers: []int{1, 2}},
+		{Type: BetStreet, Numbers: []int{0, 2, 3}},
+		{Type: BetCorner, Numbers: []int{0, 1, 2, 3}},
+		{Type: BetSixLine, Numbers: []int{1, 2, 3, 4, 5, 6}},
+		{Type: BetDozen, Target: 2},
+		{Type: BetColumn, Target: 2},
+		{Type: BetRed}, {Type: BetBlack}, {Type: BetOdd}, {Type: BetEven}, {Type: BetLow}, {Type: BetHigh},
+	}
+	for _, chip := range chips {
+		chip.Amount = 1
+		covered, err := Covered(chip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		returned := 0.0
+		for pocket := 0; pocket < Pockets; pocket++ {
+			_, payout := settleChips([]Chip{chip}, [][]int{covered}, pocket)
+			returned += payout
+		}
+		if returned != 36 {
+			t.Errorf("%s returns %v over every pocket, want 36", chip.Type, returned)
+		}
+	}
+}
+
+// seq lists from, from+step, ... up to to
+func seq(from, to, step int) []int {
+	nums := []int{}
+	for n := from; n <= to; n += step {
+		nums = append(nums, n)
+	}
+	return nums
+}
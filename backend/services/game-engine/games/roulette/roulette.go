@@ -0,0 +1,220 @@
+package roulette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// Table limits
+const (
+	MaxChipsPerSpin = 50
+	MaxChipAmount   = 10000.0
+)
+
+type RouletteGame struct {
+	gameID       string
+	entryFee     float64
+	walletClient *wallet.WalletClient
+	settler      *settlement.Settler
+	seeds        *fairness.SeedManager
+	store        RoundStore
+}
+
+// ChipResult is a settled chip
+type ChipResult struct {
+	Chip
+	Won    bool    `json:"won"`
+	Payout float64 `json:"payout"`
+}
+
+type RouletteState struct {
+	RoundID        string       `json:"round_id"`
+	Pocket         int          `json:"pocket"`
+	Color          string       `json:"color"` // RED, BLACK, GREEN
+	Chips          []ChipResult `json:"chips"`
+	TotalBet       float64      `json:"total_bet"`
+	TotalPayout    float64      `json:"total_payout"`
+	ServerSeedHash string       `json:"server_seed_hash"`
+	ClientSeed     string       `json:"client_seed"`
+	Nonce          int          `json:"nonce"`
+}
+
+// NewRouletteGame creates European roulette. Winnings are paid through settler,
+// which must be running. store may be nil to skip round persistence.
+func NewRouletteGame(seeds *fairness.SeedManager, settler *settlement.Settler, store RoundStore) *RouletteGame {
+	return &RouletteGame{
+		gameID:       "roulette_european",
+		entryFee:     1.0, // Min chip
+		walletClient: wallet.NewWalletClient(),
+		settler:      settler,
+		seeds:        seeds,
+		store:        store,
+	}
+}
+
+func (g *RouletteGame) GetGameID() string { return g.gameID }
+func (g *RouletteGame) GetGameName() string { return "European Roulette" }
+func (g *RouletteGame) GetGameType() engine.GameType { return engine.GameTypeCasino }
+func (g *RouletteGame) GetMinPlayers() int { return 1 }
+func (g *RouletteGame) GetMaxPlayers() int { return 1 }
+func (g *RouletteGame) GetEntryFee() float64 { return g.entryFee }
+
+func (g *RouletteGame) Initialize() error {
+	return nil
+}
+
+func (g *RouletteGame) Start(session *engine.GameSession) error {
+	session.State = &RouletteState{}
+	return nil
+}
+
+func (g *RouletteGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
+	if move.Type != "SPIN" {
+		return nil, errors.New("invalid move type")
+	}
+
+	chips, err := parseChips(move.Data["bets"])
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Validate the whole layout before taking any money
+	covered, totalBet, err := g.validateChips(chips)
+	if err != nil {
+		return nil, err
+	}
+
+	roundID := fmt.Sprintf("roulette_%s_%d", session.SessionID, time.Now().UnixNano())
+
+	// The round's seeds are committed before any money moves
+	seed, err := g.seeds.NextRound(move.PlayerID)
+	if err != nil {
+		return nil, fmt.Errorf("seed unavailable: %v", err)
+	}
+
+	// 2. Deduct Bet
+	if err := g.walletClient.Debit(move.PlayerID, totalBet, roundID, "GAME_ROULETTE"); err != nil {
+		return nil, fmt.Errorf("bet failed: %v", err)
+	}
+
+	// 3. Spin
+	pocket := fairness.RoulettePocket(seed.ServerSeed, seed.ClientSeed, seed.Nonce)
+
+	results, totalPayout := settleChips(chips, covered, pocket)
+
+	// 4. Credit Winnings
+	g.settler.Pay(settlement.Credit{UserID: move.PlayerID, Amount: totalPayout, RefID: roundID, RefType: "GAME_ROULETTE"})
+
+	state := &RouletteState{
+		RoundID:        roundID,
+		Pocket:         pocket,
+		Color:          pocketColor(pocket),
+		Chips:          results,
+		TotalBet:       totalBet,
+		TotalPayout:    totalPayout,
+		ServerSeedHash: seed.ServerSeedHash,
+		ClientSeed:     seed.ClientSeed,
+		Nonce:          seed.Nonce,
+	}
+	session.State = state
+
+	if g.store != nil {
+		round := &GameRound{
+			SessionID: session.SessionID,
+			RoundID:   roundID,
+			Bet:       totalBet,
+			Win:       totalPayout,
+			Status:    RoundCompleted,
+		}
+		if err := g.store.SaveRound(round); err != nil {
+			log.Printf("roulette %s: failed to record round: %v", roundID, err)
+		}
+	}
+
+	return &engine.MoveResult{
+		Success:     true,
+		StateUpdate: state,
+		GameEnded:   true,
+	}, nil
+}
+
+// validateChips checks every chip's limits and layout, returning the numbers each covers
+func (g *RouletteGame) validateChips(chips []Chip) ([][]int, float64, error) {
+	covered := make([][]int, len(chips))
+	totalBet := 0.0
+	for i, chip := range chips {
+		if chip.Amount < g.entryFee || chip.Amount > MaxChipAmount {
+			return nil, 0, fmt.Errorf("chip %d: amount must be between %.2f and %.2f", i+1, g.entryFee, MaxChipAmount)
+		}
+		nums, err := Covered(chip)
+		if err != nil {
+			return nil, 0, fmt.Errorf("chip %d: %v", i+1, err)
+		}
+		covered[i] = nums
+		totalBet += chip.Amount
+	}
+	return covered, totalBet, nil
+}
+
+// settleChips pays every chip covering pocket
+func settleChips(chips []Chip, covered [][]int, pocket int) ([]ChipResult, float64) {
+	results := make([]ChipResult, len(chips))
+	totalPayout := 0.0
+	for i, chip := range chips {
+		results[i] = ChipResult{Chip: chip}
+		for _, n := range covered[i] {
+			if n == pocket {
+				results[i].Won = true
+				results[i].Payout = chip.Amount * PayoutMultiplier(len(covered[i]))
+				totalPayout += results[i].Payout
+				break
+			}
+		}
+	}
+	return results, totalPayout
+}
+
+// parseChips converts the loosely typed move payload into chips
+func parseChips(raw interface{}) ([]Chip, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.New("invalid bets")
+	}
+	var chips []Chip
+	if err := json.Unmarshal(data, &chips); err != nil {
+		return nil, errors.New("invalid bets")
+	}
+	if len(chips) == 0 {
+		return nil, errors.New("place at least one chip")
+	}
+	if len(chips) > MaxChipsPerSpin {
+		return nil, fmt.Errorf("at most %d chips per spin", MaxChipsPerSpin)
+	}
+	return chips, nil
+}
+
+func pocketColor(pocket int) string {
+	switch {
+	case pocket == 0:
+		return "GREEN"
+	case IsRed(pocket):
+		return "RED"
+	}
+	return "BLACK"
+}
+
+func (g *RouletteGame) End(session *engine.GameSession) (*engine.GameResult, error) {
+	return &engine.GameResult{}, nil
+}
+
+func (g *RouletteGame) GetState(session *engine.GameSession) interface{} {
+	return session.State
+}
@@ -0,0 +1,50 @@
+package roulette
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// Winnings are queued with the settler rather than credited inline
+func TestSpinPaysThroughSettler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wallet.TransactionResponse{Status: "SUCCESS"})
+	}))
+	defer srv.Close()
+	t.Setenv("PAYMENT_SERVICE_URL", srv.URL)
+
+	settler := settlement.NewSettler(settlement.DefaultConfig(), wallet.NewWalletClient(), nil)
+	g := NewRouletteGame(fairness.NewSeedManager(nil), settler, nil)
+	session := &engine.GameSession{SessionID: "spin"}
+	if err := g.Start(session); err != nil {
+		t.Fatal(err)
+	}
+
+	won := 0
+	for i := 0; i < 20; i++ {
+		result, err := g.HandleMove(session, engine.Move{
+			PlayerID: "p1",
+			Type:     "SPIN",
+			Data: map[string]interface{}{"bets": []map[string]interface{}{
+				{"type": BetRed, "amount": 10.0},
+				{"type": BetDozen, "target": 1, "amount": 10.0},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.StateUpdate.(*RouletteState).TotalPayout > 0 {
+			won++
+		}
+	}
+	if settler.Pending() != won {
+		t.Errorf("%d credits queued for %d winning spins", settler.Pending(), won)
+	}
+}
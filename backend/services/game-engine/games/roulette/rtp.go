@@ -0,0 +1,37 @@
+package roulette
+
+import "github.com/playkaro/game-engine/internal/fairness"
+
+// TheoreticalRTP averages the SPIN layout's return over every pocket
+func (g *RouletteGame) TheoreticalRTP(bet map[string]interface{}) (float64, error) {
+	chips, err := parseChips(bet["bets"])
+	if err != nil {
+		return 0, err
+	}
+	covered, totalBet, err := g.validateChips(chips)
+	if err != nil {
+		return 0, err
+	}
+
+	returned := 0.0
+	for pocket := 0; pocket < Pockets; pocket++ {
+		_, payout := settleChips(chips, covered, pocket)
+		returned += payout / Pockets
+	}
+	return returned / totalBet, nil
+}
+
+func (g *RouletteGame) SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (float64, float64, error) {
+	chips, err := parseChips(bet["bets"])
+	if err != nil {
+		return 0, 0, err
+	}
+	covered, totalBet, err := g.validateChips(chips)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pocket := fairness.RoulettePocket(serverSeed, clientSeed, nonce)
+	_, totalPayout := settleChips(chips, covered, pocket)
+	return totalBet, totalPayout, nil
+}
@@ -0,0 +1,46 @@
+package roulette
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Round status, matching the monolith's game_rounds.status values
+const (
+	RoundPending   = "PENDING"
+	RoundCompleted = "COMPLETED"
+	RoundCancelled = "CANCELLED"
+)
+
+// GameRound mirrors a row of the monolith's game_rounds table
+type GameRound struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	RoundID   string    `json:"round_id"`
+	Bet       float64   `json:"bet"`
+	Win       float64   `json:"win"` // Total returned to the player, stake included
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RoundStore records finished spins
+type RoundStore interface {
+	SaveRound(round *GameRound) error
+}
+
+// PostgresRoundStore writes spins to game_rounds
+type PostgresRoundStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresRoundStore(db *sql.DB) *PostgresRoundStore {
+	return &PostgresRoundStore{DB: db}
+}
+
+func (s *PostgresRoundStore) SaveRound(round *GameRound) error {
+	_, err := s.DB.Exec(
+		"INSERT INTO game_rounds (session_id, round_id, bet, win, status) VALUES ($1, $2, $3, $4, $5)",
+		round.SessionID, round.RoundID, round.Bet, round.Win, round.Status,
+	)
+	return err
+}
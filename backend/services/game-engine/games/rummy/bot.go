@@ -0,0 +1,149 @@
+package rummy
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/playkaro/game-engine/games/rummy/meld"
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// NewBot returns a bot for one seat. Easy bots draw and discard at random, medium
+// ones plan with a small search and sometimes discard carelessly, hard ones keep
+// the best arrangement they can find. All of them declare a valid hand.
+func (g *RummyGame) NewBot(level engine.BotLevel) engine.BotStrategy {
+	bot := &rummyBot{rng: rand.New(rand.NewSource(rand.Int63())), shownDeal: -1}
+	switch level {
+	case engine.BotEasy:
+		bot.budget, bot.mistakes = meld.DefaultArrangeBudget/10, 1
+	case engine.BotMedium:
+		bot.budget, bot.mistakes = meld.DefaultArrangeBudget/4, 0.25
+	default:
+		bot.budget = meld.DefaultArrangeBudget
+	}
+	return bot
+}
+
+type rummyBot struct {
+	budget    int     // Arrange search budget
+	mistakes  float64 // Chance of a random draw or discard
+	rng       *rand.Rand
+	shownDeal int // Deal the bot has already shown its hand for
+}
+
+func (b *rummyBot) NextMove(session *engine.GameSession, botID string) (*engine.Move, error) {
+	table, ok := session.State.(*Table)
+	if !ok {
+		return nil, nil
+	}
+	view := table.View(botID)
+	wildRank := view.WildCard.Rank
+	if view.WildCard.IsPrintedJoker() {
+		wildRank = meld.Ace
+	}
+
+	switch view.Status {
+	case StatusWaiting:
+		// The first move deals the hand
+		return &engine.Move{PlayerID: botID, Type: "DEAL"}, nil
+	case StatusShow:
+		if view.Declarer == botID || b.shownDeal == view.Deal || seatDropped(view, botID) {
+			return nil, nil
+		}
+		b.shownDeal = view.Deal
+		arranged := meld.Arrange(view.Hand, wildRank, b.budget)
+		return &engine.Move{PlayerID: botID, Type: "SHOW", Data: map[string]interface{}{"groups": arranged.Groups}}, nil
+	case StatusPlaying:
+		if view.Turn != botID {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	if view.Phase == PhaseDraw {
+		return &engine.Move{PlayerID: botID, Type: "DRAW", Data: map[string]interface{}{"source": b.drawSource(view, wildRank)}}, nil
+	}
+
+	if card, groups, ok := b.declaration(view.Hand, wildRank); ok {
+		return &engine.Move{PlayerID: botID, Type: "DECLARE", Data: map[string]interface{}{"card": card, "groups": groups}}, nil
+	}
+	return &engine.Move{PlayerID: botID, Type: "DISCARD", Data: map[string]interface{}{"card": b.discard(view.Hand, wildRank)}}, nil
+}
+
+// drawSource picks up the open card when it would join a meld
+func (b *rummyBot) drawSource(view *PlayerView, wildRank int) string {
+	open := view.OpenCard
+	if open == nil || meld.IsJoker(*open, wildRank) {
+		return SourceStock
+	}
+	if b.rng.Float64() < b.mistakes {
+		if b.rng.Intn(4) == 0 {
+			return SourceDiscard
+		}
+		return SourceStock
+	}
+
+	arranged := meld.Arrange(append(append([]meld.Card{}, view.Hand...), *open), wildRank, b.budget)
+	for _, c := range arranged.Leftover {
+		if c == *open {
+			return SourceStock
+		}
+	}
+	return SourceDiscard
+}
+
+// declaration finds a discard that leaves a valid 13-card declaration
+func (b *rummyBot) declaration(hand []meld.Card, wildRank int) (meld.Card, [][]meld.Card, bool) {
+	// Only worth the full search when at most one card is left over
+	if meld.Arrange(hand, wildRank, b.budget).Points > 10 {
+		return meld.Card{}, nil, false
+	}
+	for i, card := range hand {
+		rest := append(append([]meld.Card{}, hand[:i]...), hand[i+1:]...)
+		arranged := meld.Arrange(rest, wildRank, b.budget)
+		if arranged.Points == 0 && meld.ValidateDeclaration(rest, arranged.Groups, wildRank).Valid {
+			return card, arranged.Groups, true
+		}
+	}
+	return meld.Card{}, nil, false
+}
+
+// discard throws the highest value card left out of the best arrangement
+func (b *rummyBot) discard(hand []meld.Card, wildRank int) meld.Card {
+	naturals := []meld.Card{}
+	for _, c := range hand {
+		if !meld.IsJoker(c, wildRank) {
+			naturals = append(naturals, c)
+		}
+	}
+	if len(naturals) == 0 {
+		return hand[0]
+	}
+	if b.rng.Float64() < b.mistakes {
+		return naturals[b.rng.Intn(len(naturals))]
+	}
+
+	candidates := []meld.Card{}
+	for _, c := range meld.Arrange(hand, wildRank, b.budget).Leftover {
+		if !meld.IsJoker(c, wildRank) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = naturals
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return meld.Value(candidates[i], wildRank) > meld.Value(candidates[j], wildRank)
+	})
+	return candidates[0]
+}
+
+func seatDropped(view *PlayerView, userID string) bool {
+	for _, s := range view.Seats {
+		if s.UserID == userID {
+			return s.Dropped || s.Eliminated
+		}
+	}
+	return false
+}
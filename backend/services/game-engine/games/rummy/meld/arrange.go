@@ -0,0 +1,145 @@
+package meld
+
+import "sort"
+
+// DefaultArrangeBudget bounds Arrange's search so a bot's move stays quick even
+// on awkward hands
+const DefaultArrangeBudget = 20000
+
+// Arrangement is a hand split into groups
+type Arrangement struct {
+	Groups   [][]Card // Melds, then the leftover cards as one last group
+	Leftover []Card   // Cards no meld could use
+	Points   int      // Uncapped, so a bot can still tell bad hands from worse ones
+}
+
+// Arrange looks for the grouping of hand that scores the fewest points, trying at
+// most budget partial arrangements. Hands with a valid declaration score 0.
+func Arrange(hand []Card, wildRank int, budget int) Arrangement {
+	cards := make([]Card, len(hand))
+	copy(cards, hand)
+	// Naturals by suit and rank, jokers last: a search step always starts from a
+	// natural card while one is left, and jokers fill in around it
+	sort.SliceStable(cards, func(i, j int) bool {
+		ji, jj := IsJoker(cards[i], wildRank), IsJoker(cards[j], wildRank)
+		if ji != jj {
+			return jj
+		}
+		if cards[i].Suit != cards[j].Suit {
+			return cards[i].Suit < cards[j].Suit
+		}
+		return cards[i].Rank < cards[j].Rank
+	})
+
+	a := &arranger{wildRank: wildRank, budget: budget}
+	a.search(cards, nil, nil)
+	return a.best
+}
+
+type arranger struct {
+	wildRank int
+	budget   int
+	best     Arrangement
+	found    bool
+}
+
+func (a *arranger) search(rest []Card, groups [][]Card, leftover []Card) {
+	if a.budget <= 0 {
+		return
+	}
+	a.budget--
+
+	if len(rest) == 0 {
+		a.consider(groups, leftover)
+		return
+	}
+
+	first, others := rest[0], rest[1:]
+	candidates := []int{}
+	for i, c := range others {
+		if fits(first, c, a.wildRank) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	// Groups of 3 to 5 cards containing first; longer runs split into these
+	for extra := 2; extra <= 4 && extra <= len(candidates); extra++ {
+		combinations(len(candidates), extra, func(picked []int) {
+			group := []Card{first}
+			used := make(map[int]bool, extra)
+			for _, p := range picked {
+				group = append(group, others[candidates[p]])
+				used[candidates[p]] = true
+			}
+			if Classify(group, a.wildRank) == Invalid {
+				return
+			}
+
+			remaining := make([]Card, 0, len(others)-extra)
+			for i, c := range others {
+				if !used[i] {
+					remaining = append(remaining, c)
+				}
+			}
+			next := make([][]Card, len(groups), len(groups)+1)
+			copy(next, groups)
+			a.search(remaining, append(next, group), leftover)
+		})
+	}
+
+	// Or leave first unmelded
+	nextLeftover := make([]Card, len(leftover), len(leftover)+1)
+	copy(nextLeftover, leftover)
+	a.search(others, groups, append(nextLeftover, first))
+}
+
+func (a *arranger) consider(groups [][]Card, leftover []Card) {
+	all := make([][]Card, 0, len(groups)+1)
+	all = append(all, groups...)
+	if len(leftover) > 0 {
+		all = append(all, leftover)
+	}
+
+	points := countPoints(classifyAll(all, a.wildRank), a.wildRank)
+	if !a.found || points < a.best.Points {
+		a.best = Arrangement{Groups: all, Leftover: leftover, Points: points}
+		a.found = true
+	}
+}
+
+// fits reports whether c could share a group with first: a joker, a card of the
+// same rank (set) or a nearby card of the same suit (sequence)
+func fits(first, c Card, wildRank int) bool {
+	if IsJoker(c, wildRank) || IsJoker(first, wildRank) || c.Rank == first.Rank {
+		return true
+	}
+	if c.Suit != first.Suit {
+		return false
+	}
+	gap := c.Rank - first.Rank
+	if gap < 0 {
+		gap = -gap
+	}
+	// A sequence of at most 5 cards spans at most 4 ranks; the ace also sits above the king
+	if first.Rank == Ace || c.Rank == Ace {
+		gap = min(gap, 14-max(first.Rank, c.Rank))
+	}
+	return gap <= 4
+}
+
+// combinations calls fn with every k-element subset of 0..n-1, in order
+func combinations(n, k int, fn func([]int)) {
+	picked := make([]int, k)
+	var walk func(start, depth int)
+	walk = func(start, depth int) {
+		if depth == k {
+			fn(picked)
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			picked[depth] = i
+			walk(i+1, depth+1)
+		}
+	}
+	walk(0, 0)
+}
@@ -0,0 +1,143 @@
+package meld
+
+import "fmt"
+
+// GroupResult is the ruling on one group of a declaration
+type GroupResult struct {
+	Cards []Card `json:"cards"`
+	Type  string `json:"type"`
+}
+
+// Result is the ruling on a declaration or a losing hand's show
+type Result struct {
+	Valid  bool          `json:"valid"`
+	Reason string        `json:"reason,omitempty"`
+	Groups []GroupResult `json:"groups"`
+	Points int           `json:"points"` // 0 for a valid declaration
+}
+
+// SameCards reports whether groups use exactly the cards of hand, each once
+func SameCards(hand []Card, groups [][]Card) bool {
+	counts := make(map[Card]int, len(hand))
+	for _, c := range hand {
+		counts[c]++
+	}
+	for _, group := range groups {
+		for _, c := range group {
+			if counts[c] == 0 {
+				return false
+			}
+			counts[c]--
+		}
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateDeclaration rules on a player's declaration: hand is the 13 cards kept after
+// the finishing discard and groups is how the player arranged them. A valid declaration
+// uses every card in a sequence or set, with at least two sequences, one of them pure.
+// An invalid declaration scores the full MaxPoints penalty.
+func ValidateDeclaration(hand []Card, groups [][]Card, wildRank int) Result {
+	result := Result{Groups: classifyAll(groups, wildRank)}
+
+	reason := ""
+	switch {
+	case len(hand) != HandSize:
+		reason = fmt.Sprintf("declaration needs %d cards, got %d", HandSize, len(hand))
+	case !SameCards(hand, groups):
+		reason = "groups do not match the hand"
+	default:
+		reason = declarationFault(result.Groups)
+	}
+
+	if reason != "" {
+		result.Reason = reason
+		result.Points = MaxPoints
+		return result
+	}
+	result.Valid = true
+	return result
+}
+
+// Score counts the points of a losing hand arranged into groups. Melds only count in
+// the player's favour once the hand has a pure sequence, and sets and impure sequences
+// only once it has a second sequence.
+func Score(groups [][]Card, wildRank int) Result {
+	result := Result{Groups: classifyAll(groups, wildRank)}
+
+	points := countPoints(result.Groups, wildRank)
+	if points > MaxPoints {
+		points = MaxPoints
+	}
+	result.Points = points
+	result.Valid = points == 0 && declarationFault(result.Groups) == ""
+	return result
+}
+
+// countPoints is the uncapped points of classified groups; see Score
+func countPoints(groups []GroupResult, wildRank int) int {
+	pure, sequences := 0, 0
+	for _, g := range groups {
+		if g.Type == PureSequence {
+			pure++
+		}
+		if IsSequence(g.Type) {
+			sequences++
+		}
+	}
+
+	points := 0
+	for _, g := range groups {
+		counted := true
+		switch {
+		case pure == 0:
+			// Nothing is melded without a pure sequence
+		case g.Type == PureSequence:
+			counted = false
+		case sequences >= 2 && g.Type != Invalid:
+			counted = false
+		}
+		if counted {
+			for _, c := range g.Cards {
+				points += Value(c, wildRank)
+			}
+		}
+	}
+	return points
+}
+
+func classifyAll(groups [][]Card, wildRank int) []GroupResult {
+	results := make([]GroupResult, 0, len(groups))
+	for _, g := range groups {
+		results = append(results, GroupResult{Cards: g, Type: Classify(g, wildRank)})
+	}
+	return results
+}
+
+// declarationFault returns why classified groups are not a winning hand, or ""
+func declarationFault(groups []GroupResult) string {
+	pure, sequences := 0, 0
+	for _, g := range groups {
+		switch {
+		case g.Type == Invalid:
+			return "every card must be in a sequence or set"
+		case g.Type == PureSequence:
+			pure++
+		}
+		if IsSequence(g.Type) {
+			sequences++
+		}
+	}
+	switch {
+	case pure == 0:
+		return "a pure sequence is required"
+	case sequences < 2:
+		return "at least two sequences are required"
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+// Package meld validates Indian Rummy groups and declarations and scores losing hands.
+// It is deliberately pure (no I/O, no randomness, no game state) so every ruling can be
+// reproduced from the cards alone when a declaration is disputed.
+package meld
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Suits. Printed jokers use SuitJoker with rank 0.
+const (
+	SuitHearts   = "H"
+	SuitDiamonds = "D"
+	SuitClubs    = "C"
+	SuitSpades   = "S"
+	SuitJoker    = "JK"
+)
+
+// Rank of the ace; it can close a sequence at either end (A-2-3 or Q-K-A)
+const Ace = 1
+
+// Group types
+const (
+	PureSequence   = "PURE_SEQUENCE"
+	ImpureSequence = "IMPURE_SEQUENCE"
+	Set            = "SET"
+	Invalid        = "INVALID"
+)
+
+// Scoring limits
+const (
+	HandSize  = 13
+	MaxPoints = 80 // Points cap for a losing hand, and the wrong declaration penalty
+)
+
+// Card is a playing card. Rank runs 1 (Ace) to 13 (King); printed jokers have rank 0.
+type Card struct {
+	Suit string `json:"suit"`
+	Rank int    `json:"rank"`
+}
+
+func (c Card) String() string {
+	if c.IsPrintedJoker() {
+		return "JK"
+	}
+	names := map[int]string{1: "A", 11: "J", 12: "Q", 13: "K"}
+	name, ok := names[c.Rank]
+	if !ok {
+		name = fmt.Sprint(c.Rank)
+	}
+	return name + c.Suit
+}
+
+// IsPrintedJoker reports whether the card is one of the deck's printed jokers
+func (c Card) IsPrintedJoker() bool {
+	return c.Suit == SuitJoker
+}
+
+// IsJoker reports whether the card can stand in for another: a printed joker,
+// or any card of the wild joker rank cut for this deal
+func IsJoker(c Card, wildRank int) bool {
+	return c.IsPrintedJoker() || c.Rank == wildRank
+}
+
+// Value is the point value of a card in a losing hand: face cards and aces 10,
+// number cards their rank, jokers nothing
+func Value(c Card, wildRank int) int {
+	if IsJoker(c, wildRank) {
+		return 0
+	}
+	if c.Rank == Ace || c.Rank >= 11 {
+		return 10
+	}
+	return c.Rank
+}
+
+// Classify returns the type of a group of cards
+func Classify(group []Card, wildRank int) string {
+	if len(group) < 3 {
+		return Invalid
+	}
+	if isPureSequence(group) {
+		return PureSequence
+	}
+
+	naturals, jokers := splitJokers(group, wildRank)
+	if isImpureSequence(naturals, jokers) {
+		return ImpureSequence
+	}
+	if isSet(naturals, jokers) {
+		return Set
+	}
+	return Invalid
+}
+
+// IsSequence reports whether a group type counts towards the two-sequence rule
+func IsSequence(groupType string) bool {
+	return groupType == PureSequence || groupType == ImpureSequence
+}
+
+// isPureSequence: 3+ cards of one suit in consecutive order with no joker standing in.
+// A wild-rank card used as itself (e.g. 5 of hearts in 4-5-6 of hearts) is still natural.
+func isPureSequence(group []Card) bool {
+	suit := group[0].Suit
+	ranks := make([]int, 0, len(group))
+	for _, c := range group {
+		if c.IsPrintedJoker() || c.Suit != suit {
+			return false
+		}
+		ranks = append(ranks, c.Rank)
+	}
+	return consecutive(ranks) || consecutive(aceHigh(ranks))
+}
+
+// isImpureSequence: the natural cards are one suit, distinct, and the jokers can fill
+// every gap between them. Extra jokers extend either end. A group of jokers alone is
+// not a sequence, so it can never make up the two sequences a declaration needs.
+func isImpureSequence(naturals []Card, jokers int) bool {
+	if len(naturals) == 0 {
+		return false
+	}
+	if len(naturals)+jokers > 13 {
+		return false
+	}
+
+	suit := naturals[0].Suit
+	ranks := make([]int, 0, len(naturals))
+	for _, c := range naturals {
+		if c.Suit != suit {
+			return false
+		}
+		ranks = append(ranks, c.Rank)
+	}
+	return fitsWithJokers(ranks, jokers) || fitsWithJokers(aceHigh(ranks), jokers)
+}
+
+// isSet: 3 or 4 cards of one rank in different suits, jokers filling the rest.
+// Three or four jokers alone also make a set.
+func isSet(naturals []Card, jokers int) bool {
+	if len(naturals)+jokers > 4 {
+		return false
+	}
+
+	seen := make(map[string]bool, len(naturals))
+	for _, c := range naturals {
+		if c.Rank != naturals[0].Rank || seen[c.Suit] {
+			return false
+		}
+		seen[c.Suit] = true
+	}
+	return true
+}
+
+func splitJokers(group []Card, wildRank int) ([]Card, int) {
+	naturals := make([]Card, 0, len(group))
+	jokers := 0
+	for _, c := range group {
+		if IsJoker(c, wildRank) {
+			jokers++
+		} else {
+			naturals = append(naturals, c)
+		}
+	}
+	return naturals, jokers
+}
+
+// consecutive reports whether ranks are distinct and form an unbroken run
+func consecutive(ranks []int) bool {
+	return fitsWithJokers(ranks, 0)
+}
+
+// fitsWithJokers reports whether distinct ranks span a run whose gaps jokers can fill
+func fitsWithJokers(ranks []int, jokers int) bool {
+	sorted := append([]int(nil), ranks...)
+	sort.Ints(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return false
+		}
+	}
+	gaps := (sorted[len(sorted)-1] - sorted[0] + 1) - len(sorted)
+	return gaps <= jokers
+}
+
+// aceHigh returns ranks with any ace counted as 14, for Q-K-A runs
+func aceHigh(ranks []int) []int {
+	high := make([]int, len(ranks))
+	for i, r := range ranks {
+		if r == Ace {
+			r = 14
+		}
+		high[i] = r
+	}
+	return high
+}
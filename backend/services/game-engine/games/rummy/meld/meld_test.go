@@ -0,0 +1,329 @@
+package meld
+
+import (
+	"fmt"
+	"testing"
+)
+
+var joker = Card{Suit: SuitJoker}
+
+func c(rank int, suit string) Card {
+	return Card{Suit: suit, Rank: rank}
+}
+
+// noWild is a wild rank no card has, so only printed jokers stand in
+const noWild = -1
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    []Card
+		wildRank int
+		want     string
+	}{
+		{"too short", []Card{c(4, "H"), c(5, "H")}, noWild, Invalid},
+		{"pure run", []Card{c(4, "H"), c(5, "H"), c(6, "H")}, noWild, PureSequence},
+		{"pure run unordered", []Card{c(6, "S"), c(4, "S"), c(5, "S")}, noWild, PureSequence},
+		{"ace low", []Card{c(Ace, "D"), c(2, "D"), c(3, "D")}, noWild, PureSequence},
+		{"ace high", []Card{c(12, "D"), c(13, "D"), c(Ace, "D")}, noWild, PureSequence},
+		{"no wrap round the ace", []Card{c(13, "D"), c(Ace, "D"), c(2, "D")}, noWild, Invalid},
+		{"wild card as itself", []Card{c(4, "H"), c(5, "H"), c(6, "H")}, 5, PureSequence},
+		{"mixed suits", []Card{c(4, "H"), c(5, "S"), c(6, "H")}, noWild, Invalid},
+		{"duplicate rank", []Card{c(4, "H"), c(4, "H"), c(5, "H")}, noWild, Invalid},
+		{"printed joker fills gap", []Card{c(4, "C"), joker, c(6, "C")}, noWild, ImpureSequence},
+		{"wild card fills gap", []Card{c(4, "C"), c(9, "S"), c(6, "C")}, 9, ImpureSequence},
+		{"joker extends run", []Card{c(12, "C"), c(13, "C"), joker}, noWild, ImpureSequence},
+		{"gap too wide", []Card{c(4, "C"), joker, c(7, "C")}, noWild, Invalid},
+		{"set of three", []Card{c(7, "H"), c(7, "S"), c(7, "D")}, noWild, Set},
+		{"set of four", []Card{c(7, "H"), c(7, "S"), c(7, "D"), c(7, "C")}, noWild, Set},
+		{"set repeats suit", []Card{c(7, "H"), c(7, "H"), c(7, "D")}, noWild, Invalid},
+		{"set of five", []Card{c(7, "H"), c(7, "S"), c(7, "D"), c(7, "C"), joker}, noWild, Invalid},
+		{"set with joker", []Card{c(7, "H"), joker, c(7, "D")}, noWild, Set},
+		{"three jokers", []Card{joker, joker, c(5, "S")}, 5, Set},
+		{"four wild cards", []Card{c(5, "H"), c(5, "S"), c(5, "D"), c(5, "C")}, 5, Set},
+		{"five jokers", []Card{joker, joker, c(5, "S"), c(5, "H"), c(5, "D")}, 5, Invalid},
+		{"thirteen card run", run("S", 1, 13), noWild, PureSequence},
+		{"fourteen cards", append(run("S", 1, 13), joker), noWild, Invalid},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.group, tt.wildRank); got != tt.want {
+			t.Errorf("%s: Classify(%v) = %s, want %s", tt.name, tt.group, got, tt.want)
+		}
+	}
+}
+
+func run(suit string, from, to int) []Card {
+	var cards []Card
+	for r := from; r <= to; r++ {
+		cards = append(cards, c(r, suit))
+	}
+	return cards
+}
+
+// Every pure run of every length in every suit, then with each card swapped for a joker
+func TestClassifyAllRuns(t *testing.T) {
+	for _, suit := range []string{SuitHearts, SuitDiamonds, SuitClubs, SuitSpades} {
+		for length := 3; length <= 13; length++ {
+			for start := 1; start+length-1 <= 14; start++ {
+				group := make([]Card, length)
+				for i := range group {
+					rank := start + i
+					if rank == 14 {
+						rank = Ace
+					}
+					group[i] = c(rank, suit)
+				}
+				if got := Classify(group, noWild); got != PureSequence {
+					t.Fatalf("Classify(%v) = %s, want pure", group, got)
+				}
+				for i := range group {
+					swapped := append([]Card(nil), group...)
+					swapped[i] = joker
+					if got := Classify(swapped, noWild); got != ImpureSequence {
+						t.Fatalf("Classify(%v) = %s, want impure", swapped, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+// deck is one of each card plus a printed joker; groups may repeat cards, as
+// two decks are dealt
+func deck() []Card {
+	cards := []Card{joker}
+	for _, suit := range []string{SuitHearts, SuitDiamonds, SuitClubs, SuitSpades} {
+		cards = append(cards, run(suit, 1, 13)...)
+	}
+	return cards
+}
+
+// Classify agrees with a brute-force reference on every 3-card group under every
+// wild rank, and every 4-card group under a few
+func TestClassifyExhaustive(t *testing.T) {
+	cards := deck()
+	check := func(group []Card, wildRank int) {
+		if got, want := Classify(group, wildRank), referenceClassify(group, wildRank); got != want {
+			t.Fatalf("Classify(%v, wild %d) = %s, reference %s", group, wildRank, got, want)
+		}
+	}
+
+	for wildRank := 1; wildRank <= 13; wildRank++ {
+		for i := range cards {
+			for j := i; j < len(cards); j++ {
+				for k := j; k < len(cards); k++ {
+					check([]Card{cards[i], cards[j], cards[k]}, wildRank)
+				}
+			}
+		}
+	}
+	for _, wildRank := range []int{noWild, Ace, 7, 13} {
+		for i := range cards {
+			for j := i; j < len(cards); j++ {
+				for k := j; k < len(cards); k++ {
+					for l := k; l < len(cards); l++ {
+						check([]Card{cards[i], cards[j], cards[k], cards[l]}, wildRank)
+					}
+				}
+			}
+		}
+	}
+}
+
+// referenceClassify rules on a group by trying every card the jokers could
+// stand for, rather than by counting gaps
+func referenceClassify(group []Card, wildRank int) string {
+	n := len(group)
+	if n < 3 {
+		return Invalid
+	}
+
+	// Pure: one suit, no printed joker, consecutive ranks with the ace low or high
+	pure := true
+	for _, card := range group {
+		if card.IsPrintedJoker() || card.Suit != group[0].Suit {
+			pure = false
+		}
+	}
+	if pure {
+		for start := 1; start+n-1 <= 14; start++ {
+			if coversWindow(group, start, n) {
+				return PureSequence
+			}
+		}
+	}
+
+	var naturals []Card
+	for _, card := range group {
+		if !IsJoker(card, wildRank) {
+			naturals = append(naturals, card)
+		}
+	}
+
+	// Impure: the naturals fit one window of n consecutive ranks in their suit,
+	// and the jokers take the ranks left over
+	if len(naturals) > 0 && n <= 13 {
+		sameSuit := true
+		for _, card := range naturals {
+			if card.Suit != naturals[0].Suit {
+				sameSuit = false
+			}
+		}
+		for start := 1; sameSuit && start+n-1 <= 14; start++ {
+			if fitsWindow(naturals, start, n) {
+				return ImpureSequence
+			}
+		}
+	}
+
+	// Set: at most four cards, naturals of one rank in distinct suits
+	if n <= 4 {
+		suits := map[string]bool{}
+		set := true
+		for _, card := range naturals {
+			if card.Rank != naturals[0].Rank || suits[card.Suit] {
+				set = false
+			}
+			suits[card.Suit] = true
+		}
+		if set {
+			return Set
+		}
+	}
+	return Invalid
+}
+
+// windowRank maps a rank into the window starting at start, counting an ace as
+// 14 when the window reaches past the king
+func windowRank(rank, start int) int {
+	if rank == Ace && start > 1 {
+		return 14
+	}
+	return rank
+}
+
+// coversWindow reports whether cards are exactly the ranks start..start+n-1
+func coversWindow(cards []Card, start, n int) bool {
+	seen := map[int]bool{}
+	for _, card := range cards {
+		r := windowRank(card.Rank, start)
+		if r < start || r >= start+n || seen[r] {
+			return false
+		}
+		seen[r] = true
+	}
+	return len(seen) == n
+}
+
+// fitsWindow reports whether cards are distinct ranks within start..start+n-1
+func fitsWindow(cards []Card, start, n int) bool {
+	seen := map[int]bool{}
+	for _, card := range cards {
+		r := windowRank(card.Rank, start)
+		if r < start || r >= start+n || seen[r] {
+			return false
+		}
+		seen[r] = true
+	}
+	return true
+}
+
+func TestValidateDeclaration(t *testing.T) {
+	pure := []Card{c(2, "H"), c(3, "H"), c(4, "H")}
+	impure := []Card{c(9, "S"), joker, c(11, "S")}
+	set := []Card{c(13, "H"), c(13, "D"), c(13, "C")}
+	four := []Card{c(5, "D"), c(6, "D"), c(7, "D"), c(8, "D")}
+	jokers := []Card{joker, joker, c(10, "C")} // wild rank 10
+
+	tests := []struct {
+		name   string
+		groups [][]Card
+		reason string
+	}{
+		{"valid", [][]Card{pure, impure, set, four}, ""},
+		{"two pure sequences", [][]Card{pure, four, set, {c(13, "S"), c(Ace, "S"), c(12, "S")}}, ""},
+		{"no pure sequence", [][]Card{impure, {c(4, "C"), joker, c(6, "C")}, set, {c(8, "C"), c(8, "S"), c(8, "H"), c(8, "D")}}, "a pure sequence is required"},
+		{"one sequence", [][]Card{pure, set, {c(6, "C"), c(6, "S"), c(6, "H")}, {c(8, "C"), c(8, "S"), c(8, "H"), c(8, "D")}}, "at least two sequences are required"},
+		{"joker set is not a sequence", [][]Card{pure, jokers, set, {c(8, "C"), c(8, "S"), c(8, "H"), c(8, "D")}}, "at least two sequences are required"},
+		{"unmelded card", [][]Card{pure, impure, set, {c(5, "D"), c(6, "D"), c(7, "D"), c(9, "D")}}, "every card must be in a sequence or set"},
+	}
+	for _, tt := range tests {
+		var hand []Card
+		for _, g := range tt.groups {
+			hand = append(hand, g...)
+		}
+		got := ValidateDeclaration(hand, tt.groups, 10)
+		if got.Reason != tt.reason || got.Valid != (tt.reason == "") {
+			t.Errorf("%s: valid %v, reason %q; want reason %q", tt.name, got.Valid, got.Reason, tt.reason)
+		}
+		if !got.Valid && got.Points != MaxPoints {
+			t.Errorf("%s: invalid declaration scored %d", tt.name, got.Points)
+		}
+	}
+}
+
+func TestValidateDeclarationChecksCards(t *testing.T) {
+	groups := [][]Card{
+		{c(2, "H"), c(3, "H"), c(4, "H")},
+		{c(9, "S"), joker, c(11, "S")},
+		{c(13, "H"), c(13, "D"), c(13, "C")},
+		{c(5, "D"), c(6, "D"), c(7, "D"), c(8, "D")},
+	}
+	var hand []Card
+	for _, g := range groups {
+		hand = append(hand, g...)
+	}
+
+	// A card the player does not hold
+	swapped := append([]Card(nil), hand...)
+	swapped[0] = c(2, "S")
+	if got := ValidateDeclaration(swapped, groups, noWild); got.Reason != "groups do not match the hand" {
+		t.Errorf("foreign card: reason %q", got.Reason)
+	}
+	if got := ValidateDeclaration(hand[:12], groups, noWild); got.Valid {
+		t.Error("12-card declaration accepted")
+	}
+}
+
+func TestScore(t *testing.T) {
+	pure := []Card{c(2, "H"), c(3, "H"), c(4, "H")}
+	set := []Card{c(13, "H"), c(13, "D"), c(13, "C")}
+	deadwood := []Card{c(Ace, "S"), c(9, "C"), c(5, "D"), joker}
+
+	tests := []struct {
+		name   string
+		groups [][]Card
+		want   int
+	}{
+		// Without a pure sequence every card counts, jokers excepted
+		{"nothing melded", [][]Card{{c(2, "H"), c(3, "S"), c(4, "H")}, set, deadwood}, 2 + 3 + 4 + 30 + 24},
+		// A pure sequence alone does not release the set
+		{"pure only", [][]Card{pure, set, deadwood}, 30 + 24},
+		{"two sequences", [][]Card{pure, {c(6, "C"), joker, c(8, "C")}, set, deadwood}, 24},
+		{"capped", [][]Card{{c(13, "S"), c(12, "S"), c(11, "S"), c(10, "S"), c(13, "D"), c(12, "D"), c(11, "D"), c(10, "D"), c(13, "C")}}, MaxPoints},
+	}
+	for _, tt := range tests {
+		if got := Score(tt.groups, noWild); got.Points != tt.want {
+			t.Errorf("%s: %d points, want %d", tt.name, got.Points, tt.want)
+		}
+	}
+}
+
+func TestValue(t *testing.T) {
+	for rank, want := range map[int]int{Ace: 10, 2: 2, 9: 9, 10: 10, 11: 10, 12: 10, 13: 10} {
+		if got := Value(c(rank, "H"), noWild); got != want {
+			t.Errorf("Value(%s) = %d, want %d", c(rank, "H"), got, want)
+		}
+	}
+	if Value(joker, noWild) != 0 || Value(c(13, "S"), 13) != 0 {
+		t.Error("jokers carry points")
+	}
+}
+
+func ExampleClassify() {
+	fmt.Println(Classify([]Card{c(Ace, "S"), c(13, "S"), c(12, "S")}, noWild))
+	fmt.Println(Classify([]Card{c(7, "H"), joker, c(7, "C")}, noWild))
+	// Output:
+	// PURE_SEQUENCE
+	// SET
+}
@@ -0,0 +1,276 @@
+package rummy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/playkaro/game-engine/games/rummy/meld"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// Wallet moves a table's buy-ins and prizes (implemented by wallet.Seated)
+type Wallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+	Credit(userID string, amount float64, refID, refType string) error
+}
+
+// RummyGame is 13-card Indian Rummy in one of the Variant formats
+type RummyGame struct {
+	variant Variant
+	config  Config
+	wallet  wallet.BotWallet
+	seeds   *fairness.SeedManager
+
+	tables map[string]*Table // sessionID -> table
+	mu     sync.Mutex
+}
+
+func NewRummyGame(variant Variant, seeds *fairness.SeedManager) *RummyGame {
+	return &RummyGame{
+		variant: variant,
+		config:  DefaultConfig(),
+		wallet:  wallet.NewWalletClient(),
+		seeds:   seeds,
+		tables:  make(map[string]*Table),
+	}
+}
+
+func (g *RummyGame) GetGameID() string            { return g.variant.GameID }
+func (g *RummyGame) GetGameName() string          { return g.variant.Name }
+func (g *RummyGame) GetGameType() engine.GameType { return engine.GameTypeSkill }
+func (g *RummyGame) GetMinPlayers() int           { return 2 }
+func (g *RummyGame) GetMaxPlayers() int           { return 6 }
+func (g *RummyGame) GetEntryFee() float64         { return g.variant.BuyIn() }
+
+func (g *RummyGame) Initialize() error {
+	return nil
+}
+
+// Start opens the table. Cards are dealt on the first move (DEAL, or any other),
+// once the session has filled up, so every seated player is debited and dealt in together.
+func (g *RummyGame) Start(session *engine.GameSession) error {
+	var table *Table
+	if session.Replay {
+		// Replays deal the recorded shuffles and settle nothing
+		table = NewTable(session.SessionID, g.variantFor(session), g.config, nil, noWallet{})
+	} else {
+		table = NewTable(session.SessionID, g.variantFor(session), g.config, g.seeds, g.wallet)
+		table.ended = session.Ended
+	}
+	table.rng = session.RNG
+
+	g.mu.Lock()
+	g.tables[session.SessionID] = table
+	g.mu.Unlock()
+
+	// The table hides every hand when serialized, so the session is safe to expose
+	session.State = table
+	return nil
+}
+
+func (g *RummyGame) tableFor(session *engine.GameSession) (*Table, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	table, ok := g.tables[session.SessionID]
+	if !ok {
+		return nil, errors.New("rummy table not open")
+	}
+	return table, nil
+}
+
+// variantFor scales the variant to a session played at a different stake: points
+// rummy changes its point value, pool and deals their buy-in. A session with no
+// stake (a tournament match, whose prizes the tournament pays) plays for nothing.
+func (g *RummyGame) variantFor(session *engine.GameSession) Variant {
+	v := g.variant
+	if session.EntryFee == v.BuyIn() {
+		return v
+	}
+	stake := math.Max(session.EntryFee, 0)
+	if v.Format == FormatPoints {
+		v.PointValue = stake / meld.MaxPoints
+	} else {
+		v.EntryFee = stake
+	}
+	return v
+}
+
+// deal debits every player's buy-in and deals the first hand
+func (g *RummyGame) deal(session *engine.GameSession, table *Table) error {
+	userIDs := make([]string, 0, len(session.Players))
+	buyIn := table.variant.BuyIn()
+	// Everyone has sat down by now, so the table knows which seats are bots
+	seats := wallet.ForSeats(g.wallet, session.Players)
+	if !session.Replay {
+		table.useWallet(seats)
+	}
+	if session.Prepaid || session.Replay || buyIn <= 0 {
+		for _, p := range session.Players {
+			userIDs = append(userIDs, p.UserID)
+		}
+		return table.Begin(userIDs)
+	}
+
+	for _, p := range session.Players {
+		if err := seats.Debit(p.UserID, buyIn, session.SessionID, "GAME_RUMMY"); err != nil {
+			// Give back what was already taken
+			for _, debited := range userIDs {
+				seats.Credit(debited, buyIn, session.SessionID, "GAME_RUMMY_REFUND")
+			}
+			return fmt.Errorf("failed to deduct buy-in for %s: %v", p.UserID, err)
+		}
+		userIDs = append(userIDs, p.UserID)
+	}
+	if err := table.Begin(userIDs); err != nil {
+		// Nothing was dealt, so the buy-ins go back
+		for _, debited := range userIDs {
+			seats.Credit(debited, buyIn, session.SessionID, "GAME_RUMMY_REFUND")
+		}
+		return err
+	}
+	return nil
+}
+
+func (g *RummyGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
+	table, err := g.tableFor(session)
+	if err != nil {
+		return nil, err
+	}
+
+	dealt := false
+	if !table.Started() {
+		if err := g.deal(session, table); err != nil {
+			return nil, err
+		}
+		dealt = true
+	}
+
+	var view *PlayerView
+	var ruling *meld.Result
+
+	switch move.Type {
+	case "DEAL":
+		if !dealt {
+			return nil, errors.New("cards already dealt")
+		}
+		view = table.View(move.PlayerID)
+	case "DRAW":
+		source, _ := move.Data["source"].(string)
+		view, err = table.Draw(move.PlayerID, source)
+	case "DISCARD":
+		card, cardErr := parseCard(move.Data["card"])
+		if cardErr != nil {
+			return nil, cardErr
+		}
+		view, err = table.Discard(move.PlayerID, card)
+	case "DECLARE":
+		card, cardErr := parseCard(move.Data["card"])
+		if cardErr != nil {
+			return nil, cardErr
+		}
+		groups, groupsErr := parseGroups(move.Data["groups"])
+		if groupsErr != nil {
+			return nil, groupsErr
+		}
+		ruling, view, err = table.Declare(move.PlayerID, card, groups)
+	case "SHOW":
+		groups, groupsErr := parseGroups(move.Data["groups"])
+		if groupsErr != nil {
+			return nil, groupsErr
+		}
+		ruling, view, err = table.Show(move.PlayerID, groups)
+	case "DROP":
+		view, err = table.Drop(move.PlayerID)
+	default:
+		return nil, errors.New("invalid move type")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	update := map[string]interface{}{"table": view}
+	if ruling != nil {
+		update["ruling"] = ruling
+	}
+
+	return &engine.MoveResult{
+		Success:     true,
+		NextTurn:    view.Turn,
+		StateUpdate: update,
+		GameEnded:   view.Status == StatusFinished,
+	}, nil
+}
+
+// End stops the turn timers and reports the winners. Prizes are credited by the table.
+func (g *RummyGame) End(session *engine.GameSession) (*engine.GameResult, error) {
+	g.mu.Lock()
+	table, ok := g.tables[session.SessionID]
+	delete(g.tables, session.SessionID)
+	g.mu.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+	table.Stop()
+
+	winners, prizes, scores := table.Result()
+	result := &engine.GameResult{Scores: scores, Prizes: prizes}
+	if len(winners) > 0 {
+		result.WinnerID = winners[0]
+	}
+	return result, nil
+}
+
+// PlayerView is the table as userID sees it, their own hand included
+func (g *RummyGame) PlayerView(session *engine.GameSession, userID string) (interface{}, error) {
+	table, err := g.tableFor(session)
+	if err != nil {
+		return nil, err
+	}
+	return table.View(userID), nil
+}
+
+func (g *RummyGame) GetState(session *engine.GameSession) interface{} {
+	return session.State
+}
+
+// Snapshot is the table with every hand showing
+func (g *RummyGame) Snapshot(session *engine.GameSession) interface{} {
+	return session.State.(*Table).Snapshot()
+}
+
+// noWallet stands in for the wallet while a recorded game is replayed
+type noWallet struct{}
+
+func (noWallet) Debit(userID string, amount float64, refID, refType string) error  { return nil }
+func (noWallet) Credit(userID string, amount float64, refID, refType string) error { return nil }
+
+func parseCard(raw interface{}) (meld.Card, error) {
+	var card meld.Card
+	data, err := json.Marshal(raw)
+	if err != nil || raw == nil {
+		return card, errors.New("invalid card")
+	}
+	if err := json.Unmarshal(data, &card); err != nil {
+		return card, errors.New("invalid card")
+	}
+	return card, nil
+}
+
+func parseGroups(raw interface{}) ([][]meld.Card, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.New("invalid groups")
+	}
+	var groups [][]meld.Card
+	if err := json.Unmarshal(data, &groups); err != nil || len(groups) == 0 {
+		return nil, errors.New("invalid groups")
+	}
+	return groups, nil
+}
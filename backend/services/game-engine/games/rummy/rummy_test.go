@@ -0,0 +1,199 @@
+package rummy
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// recordingWallet records every credit
+type recordingWallet struct {
+	credits map[string]float64
+	mu      sync.Mutex
+}
+
+func (w *recordingWallet) Debit(userID string, amount float64, refID, refType string) error {
+	return nil
+}
+
+func (w *recordingWallet) Credit(userID string, amount float64, refID, refType string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.credits[userID] += amount
+	return nil
+}
+
+func (w *recordingWallet) DebitBot(botID string, amount float64, refID, refType string) error {
+	return w.Debit("house", amount, refID, refType)
+}
+
+func (w *recordingWallet) CreditBot(botID string, amount float64, refID, refType string) error {
+	return w.Credit("house", amount, refID, refType)
+}
+
+// Tournament matches carry no stake; the tournament pays their prizes
+func TestUnstakedSessionPaysNothing(t *testing.T) {
+	for _, variant := range []Variant{PointsRummy(), Pool101Rummy(), Pool201Rummy(), DealsRummy()} {
+		g := NewRummyGame(variant, nil)
+		session := &engine.GameSession{SessionID: "match_1", EntryFee: 0, Prepaid: true}
+		v := g.variantFor(session)
+		if v.BuyIn() != 0 {
+			t.Errorf("%s: buy-in %.2f for an unstaked session", variant.GameID, v.BuyIn())
+		}
+
+		w := &recordingWallet{credits: make(map[string]float64)}
+		table := NewTable(session.SessionID, v, DefaultConfig(), nil, w)
+		table.seats = []*seat{{userID: "p1"}, {userID: "p2", dealPoints: 60}}
+		if v.Format == FormatPoints {
+			table.finishPoints("p1")
+		} else {
+			table.finish([]string{"p1"}, table.splitPool([]string{"p1"}))
+		}
+		for userID, prize := range table.prizes {
+			if prize != 0 {
+				t.Errorf("%s: %s awarded %.2f", variant.GameID, userID, prize)
+			}
+		}
+		if table.winners[0] != "p1" {
+			t.Errorf("%s: winners = %v", variant.GameID, table.winners)
+		}
+	}
+}
+
+func TestStakedSessionScalesVariant(t *testing.T) {
+	points := NewRummyGame(PointsRummy(), nil)
+	if v := points.variantFor(&engine.GameSession{EntryFee: 160}); v.PointValue != 2 {
+		t.Errorf("point value %.2f at a 160 buy-in, want 2", v.PointValue)
+	}
+	pool := NewRummyGame(Pool101Rummy(), nil)
+	if v := pool.variantFor(&engine.GameSession{EntryFee: 100}); v.BuyIn() != 100 {
+		t.Errorf("pool buy-in %.2f, want 100", v.BuyIn())
+	}
+	if v := pool.variantFor(&engine.GameSession{EntryFee: 50}); v != Pool101Rummy() {
+		t.Errorf("default stake changed the variant: %+v", v)
+	}
+}
+
+// A player timed out of a heads-up points game loses it without any move being
+// made, so the table has to tell the session itself
+func TestTimerEndReportsToSession(t *testing.T) {
+	cfg := Config{TurnTime: 5 * time.Millisecond, ShowWindow: time.Second, MaxMissedTurns: 1}
+	w := &recordingWallet{credits: make(map[string]float64)}
+	table := NewTable("timed", PointsRummy(), cfg, fairness.NewSeedManager(nil), w)
+
+	ended := make(chan struct{}, 2)
+	table.ended = func() {
+		table.Stop() // As ending the session does
+		ended <- struct{}{}
+	}
+	if err := table.Begin([]string{"p1", "p2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("session never told the game ended")
+	}
+	if !table.Finished() {
+		t.Error("table not finished")
+	}
+	select {
+	case <-ended:
+		t.Error("session told twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// flakyStore fails to save seed nonces while down is set
+type flakyStore struct {
+	*fairness.MemoryStore
+	down atomic.Bool
+}
+
+func (s *flakyStore) SaveActive(ownerID string, pair *fairness.SeedPair) error {
+	if s.down.Load() {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryStore.SaveActive(ownerID, pair)
+}
+
+func TestFirstDealRefundsWhenSeedIsNotStored(t *testing.T) {
+	store := &flakyStore{MemoryStore: fairness.NewMemoryStore()}
+	store.down.Store(true)
+	g := NewRummyGame(Pool101Rummy(), fairness.NewSeedManager(store))
+	w := &recordingWallet{credits: make(map[string]float64)}
+	g.wallet = w
+
+	session := &engine.GameSession{
+		SessionID: "unseeded",
+		EntryFee:  Pool101Rummy().BuyIn(),
+		Players:   []*engine.Player{{UserID: "p1"}, {UserID: "p2"}},
+	}
+	if err := g.Start(session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.HandleMove(session, engine.Move{PlayerID: "p1", Type: "DEAL"}); err == nil {
+		t.Fatal("cards dealt without a stored seed")
+	}
+	table, _ := g.tableFor(session)
+	if table.Started() {
+		t.Fatal("table left the waiting state")
+	}
+	for _, userID := range []string{"p1", "p2"} {
+		if w.credits[userID] != session.EntryFee {
+			t.Errorf("%s refunded %.2f, want %.2f", userID, w.credits[userID], session.EntryFee)
+		}
+	}
+
+	// Once the store is back the deal goes ahead
+	store.down.Store(false)
+	if _, err := g.HandleMove(session, engine.Move{PlayerID: "p1", Type: "DEAL"}); err != nil {
+		t.Fatal(err)
+	}
+	table.Stop()
+}
+
+func TestNextDealPausesUntilSeedIsStored(t *testing.T) {
+	store := &flakyStore{MemoryStore: fairness.NewMemoryStore()}
+	cfg := DefaultConfig()
+	cfg.RetryInterval = 5 * time.Millisecond
+	w := &recordingWallet{credits: make(map[string]float64)}
+	table := NewTable("paused", Pool101Rummy(), cfg, fairness.NewSeedManager(store), w)
+	if err := table.Begin([]string{"p1", "p2"}); err != nil {
+		t.Fatal(err)
+	}
+	defer table.Stop()
+
+	store.down.Store(true)
+	table.mu.Lock()
+	table.endDeal("p1")
+	status, deal := table.status, table.deal
+	table.mu.Unlock()
+	if status != StatusPaused || deal != 1 {
+		t.Fatalf("status %s at deal %d, want PAUSED at deal 1", status, deal)
+	}
+	if _, err := table.Draw("p1", SourceStock); err != ErrNotPlaying {
+		t.Fatalf("draw while paused: err = %v, want ErrNotPlaying", err)
+	}
+
+	store.down.Store(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		table.mu.Lock()
+		status, deal = table.status, table.deal
+		table.mu.Unlock()
+		if status == StatusPlaying && deal == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("still %s at deal %d after the store recovered", status, deal)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,889 @@
+package rummy
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/playkaro/game-engine/games/rummy/meld"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// Table status
+const (
+	StatusWaiting  = "WAITING"
+	StatusPlaying  = "PLAYING"
+	StatusShow     = "SHOW"   // A valid declaration was made; the others show their hands
+	StatusPaused   = "PAUSED" // Between deals, waiting for the next deal's seed to be stored
+	StatusFinished = "FINISHED"
+)
+
+// Turn phases
+const (
+	PhaseDraw    = "DRAW"
+	PhaseDiscard = "DISCARD"
+)
+
+// Draw sources
+const (
+	SourceStock   = "STOCK"
+	SourceDiscard = "DISCARD"
+)
+
+var (
+	ErrNotPlaying     = errors.New("no deal in progress")
+	ErrNotSeated      = errors.New("player not at this table")
+	ErrNotYourTurn    = errors.New("not your turn")
+	ErrDrawFirst      = errors.New("draw a card first")
+	ErrAlreadyDrawn   = errors.New("already drew this turn")
+	ErrCardNotInHand  = errors.New("card not in hand")
+	ErrInvalidSource  = errors.New("invalid draw source")
+	ErrJokerPick      = errors.New("jokers cannot be picked from the discard pile")
+	ErrEmptyDiscard   = errors.New("discard pile is empty")
+	ErrNotShowing     = errors.New("no declaration to show against")
+	ErrAlreadyShown   = errors.New("hand already shown")
+	ErrNotEnoughSeats = errors.New("not enough players to deal")
+)
+
+// Config controls table timing
+type Config struct {
+	TurnTime       time.Duration
+	ShowWindow     time.Duration // Time the others get to arrange and show after a declaration
+	MaxMissedTurns int           // Consecutive timeouts before a player is dropped
+	RetryInterval  time.Duration // Wait before retrying a deal whose seed couldn't be stored
+}
+
+func DefaultConfig() Config {
+	return Config{
+		TurnTime:       30 * time.Second,
+		ShowWindow:     30 * time.Second,
+		MaxMissedTurns: 3,
+		RetryInterval:  5 * time.Second,
+	}
+}
+
+// DealResult records how one deal ended
+type DealResult struct {
+	Deal       int                    `json:"deal"`
+	Winner     string                 `json:"winner"`
+	WildCard   meld.Card              `json:"wild_card"`
+	Points     map[string]int         `json:"points"`          // userID -> points this deal
+	Shows      map[string]meld.Result `json:"shows,omitempty"` // Declaration and shows, for disputes
+	Dropped    []string               `json:"dropped,omitempty"`
+	ServerSeed string                 `json:"server_seed"` // Revealed once the deal is over
+	ClientSeed string                 `json:"client_seed"`
+	Nonce      int                    `json:"nonce"`
+}
+
+// SeatView is a player's public state
+type SeatView struct {
+	UserID     string `json:"user_id"`
+	Cards      int    `json:"cards"`
+	Score      int    `json:"score"`           // Pool: cumulative points
+	Chips      int    `json:"chips,omitempty"` // Deals: chips held
+	Dropped    bool   `json:"dropped"`
+	Eliminated bool   `json:"eliminated"`
+	Missed     int    `json:"missed_turns"`
+}
+
+// TableView is the table as any player or spectator may see it
+type TableView struct {
+	Status         string             `json:"status"`
+	Format         string             `json:"format"`
+	Deal           int                `json:"deal"`
+	WildCard       meld.Card          `json:"wild_card"`
+	OpenCard       *meld.Card         `json:"open_card,omitempty"` // Top of the discard pile
+	StockCount     int                `json:"stock_count"`
+	Turn           string             `json:"turn,omitempty"`
+	Phase          string             `json:"phase,omitempty"`
+	TurnEndsIn     int                `json:"turn_ends_in"`
+	Declarer       string             `json:"declarer,omitempty"`
+	Seats          []SeatView         `json:"seats"`
+	Results        []DealResult       `json:"results"`
+	Winners        []string           `json:"winners,omitempty"`
+	Prizes         map[string]float64 `json:"prizes,omitempty"`
+	ServerSeedHash string             `json:"server_seed_hash"`
+	ClientSeed     string             `json:"client_seed"`
+	Nonce          int                `json:"nonce"`
+}
+
+// PlayerView adds the player's own hand to the table view
+type PlayerView struct {
+	TableView
+	Hand []meld.Card `json:"hand"`
+}
+
+type seat struct {
+	userID     string
+	hand       []meld.Card
+	score      int
+	chips      int
+	dropped    bool
+	eliminated bool
+	drawn      bool // Has drawn at least once this deal (first vs middle drop)
+	missed     int
+	dealPoints int
+	shown      bool
+}
+
+func (s *seat) active() bool {
+	return !s.dropped && !s.eliminated
+}
+
+// Table runs a rummy game for one session. Moves and turn timers both take mu,
+// so a timeout can never interleave with a player's move.
+type Table struct {
+	sessionID string
+	variant   Variant
+	cfg       Config
+	seeds     *fairness.SeedManager // nil when replaying a recorded game
+	wallet    Wallet
+	rng       engine.RNG // Records each deal's shuffle for replays
+	ended     func()     // Completes the session when a timer finishes the game (see engine.GameSession.Ended)
+
+	mu       sync.Mutex
+	status   string
+	seats    []*seat
+	deal     int
+	seed     fairness.RoundSeed
+	wildCard meld.Card
+	wildRank int
+	stock    []meld.Card
+	discard  []meld.Card
+
+	turn      int // Seat index
+	phase     string
+	lastDrawn meld.Card
+	turnSeq   int // Bumped on every turn change so stale timers are ignored
+	deadline  time.Time
+	timer     *time.Timer
+	declarer  string
+	shows     map[string]meld.Result
+	results   []DealResult
+	winners   []string
+	prizes    map[string]float64
+	stopped   bool
+}
+
+func NewTable(sessionID string, variant Variant, cfg Config, seeds *fairness.SeedManager, wallet Wallet) *Table {
+	return &Table{
+		sessionID: sessionID,
+		variant:   variant,
+		cfg:       cfg,
+		seeds:     seeds,
+		wallet:    wallet,
+		status:    StatusWaiting,
+		results:   []DealResult{},
+	}
+}
+
+// Begin seats the players and deals the first hand. Buy-ins must already be
+// debited. If the deal's seed can't be stored nothing is dealt and the table
+// stays waiting, so the caller can refund the buy-ins.
+func (t *Table) Begin(userIDs []string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status != StatusWaiting {
+		return errors.New("table already started")
+	}
+	if len(userIDs) < 2 {
+		return ErrNotEnoughSeats
+	}
+
+	for _, userID := range userIDs {
+		s := &seat{userID: userID}
+		if t.variant.Format == FormatDeals {
+			s.chips = meld.MaxPoints * t.variant.Deals
+		}
+		t.seats = append(t.seats, s)
+	}
+	if err := t.startDeal(); err != nil {
+		t.seats = nil
+		return err
+	}
+	return nil
+}
+
+// useWallet replaces the wallet the table pays prizes from
+func (t *Table) useWallet(w Wallet) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.wallet = w
+}
+
+// Started reports whether the first hand has been dealt
+func (t *Table) Started() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status != StatusWaiting
+}
+
+// Finished reports whether the game is over
+func (t *Table) Finished() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status == StatusFinished
+}
+
+// Stop cancels any pending turn timer
+func (t *Table) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// Draw takes the top card of the stock or the discard pile
+func (t *Table) Draw(userID, source string) (*PlayerView, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, err := t.turnSeat(userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.phase != PhaseDraw {
+		return nil, ErrAlreadyDrawn
+	}
+
+	var card meld.Card
+	switch source {
+	case SourceStock:
+		if len(t.stock) == 0 {
+			t.reshuffleDiscards()
+		}
+		if len(t.stock) == 0 {
+			return nil, errors.New("stock exhausted")
+		}
+		card, t.stock = t.stock[0], t.stock[1:]
+	case SourceDiscard:
+		if len(t.discard) == 0 {
+			return nil, ErrEmptyDiscard
+		}
+		card = t.discard[len(t.discard)-1]
+		if meld.IsJoker(card, t.wildRank) {
+			return nil, ErrJokerPick
+		}
+		t.discard = t.discard[:len(t.discard)-1]
+	default:
+		return nil, ErrInvalidSource
+	}
+
+	s.hand = append(s.hand, card)
+	s.drawn = true
+	s.missed = 0
+	t.lastDrawn = card
+	t.phase = PhaseDiscard
+	return t.view(userID), nil
+}
+
+// Discard ends the turn by throwing a card onto the open pile
+func (t *Table) Discard(userID string, card meld.Card) (*PlayerView, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, err := t.turnSeat(userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.phase != PhaseDiscard {
+		return nil, ErrDrawFirst
+	}
+	if !removeCard(s, card) {
+		return nil, ErrCardNotInHand
+	}
+
+	t.discard = append(t.discard, card)
+	t.nextTurn()
+	return t.view(userID), nil
+}
+
+// Declare finishes with card and shows the remaining 13 cards in groups. A wrong
+// declaration costs the full penalty and drops the player from the deal.
+func (t *Table) Declare(userID string, card meld.Card, groups [][]meld.Card) (*meld.Result, *PlayerView, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, err := t.turnSeat(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.phase != PhaseDiscard {
+		return nil, nil, ErrDrawFirst
+	}
+	if !removeCard(s, card) {
+		return nil, nil, ErrCardNotInHand
+	}
+
+	result := meld.ValidateDeclaration(s.hand, groups, t.wildRank)
+	t.shows[userID] = result
+
+	if !result.Valid {
+		t.discard = append(t.discard, card)
+		s.dropped = true
+		s.dealPoints = meld.MaxPoints
+		if !t.endIfLastStanding() {
+			t.nextTurn()
+		}
+		return &result, t.view(userID), nil
+	}
+
+	s.shown = true
+	t.declarer = userID
+	t.status = StatusShow
+	t.phase = ""
+	t.turnSeq++
+	t.armTimer(t.cfg.ShowWindow, t.turnSeq, t.onShowTimeout)
+	t.endIfAllShown()
+	return &result, t.view(userID), nil
+}
+
+// Show scores a losing hand after another player's valid declaration
+func (t *Table) Show(userID string, groups [][]meld.Card) (*meld.Result, *PlayerView, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status != StatusShow {
+		return nil, nil, ErrNotShowing
+	}
+	s := t.seatFor(userID)
+	if s == nil || !s.active() {
+		return nil, nil, ErrNotSeated
+	}
+	if s.shown {
+		return nil, nil, ErrAlreadyShown
+	}
+	if !meld.SameCards(s.hand, groups) {
+		return nil, nil, errors.New("groups do not match the hand")
+	}
+
+	result := meld.Score(groups, t.wildRank)
+	t.shows[userID] = result
+	s.dealPoints = result.Points
+	s.shown = true
+	t.endIfAllShown()
+	return &result, t.view(userID), nil
+}
+
+// Drop folds the hand on the player's turn, before drawing
+func (t *Table) Drop(userID string) (*PlayerView, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, err := t.turnSeat(userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.phase != PhaseDraw {
+		return nil, ErrAlreadyDrawn
+	}
+
+	t.drop(s)
+	return t.view(userID), nil
+}
+
+// View returns the table as userID sees it
+func (t *Table) View(userID string) *PlayerView {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.view(userID)
+}
+
+// MarshalJSON lets the table sit in GameSession.State without exposing any hand
+func (t *Table) MarshalJSON() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return json.Marshal(t.tableView())
+}
+
+// Result returns the winners and prizes once the game is over
+func (t *Table) Result() ([]string, map[string]float64, map[string]int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	scores := make(map[string]int, len(t.seats))
+	for _, s := range t.seats {
+		switch t.variant.Format {
+		case FormatDeals:
+			scores[s.userID] = s.chips
+		case FormatPoints:
+			scores[s.userID] = s.dealPoints
+		default:
+			scores[s.userID] = s.score
+		}
+	}
+	prizes := make(map[string]float64, len(t.prizes))
+	for userID, amount := range t.prizes {
+		prizes[userID] = amount
+	}
+	return append([]string(nil), t.winners...), prizes, scores
+}
+
+// startDeal shuffles and deals the next hand. Nothing is dealt if the deal's
+// seed can't be stored, as its nonce could repeat after a restart.
+func (t *Table) startDeal() error {
+	shuffle, err := engine.TryDraw(t.rng, func() (dealShuffle, error) {
+		seed, err := t.seeds.NextRound(t.sessionID)
+		if err != nil {
+			return dealShuffle{}, err
+		}
+		return dealShuffle{
+			Seed:       seed,
+			ServerSeed: seed.ServerSeed,
+			Order:      fairness.Shuffle(seed.ServerSeed, seed.ClientSeed, seed.Nonce, DeckCards),
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+	t.deal++
+	t.seed = shuffle.Seed
+	t.seed.ServerSeed = shuffle.ServerSeed
+	deck := make([]meld.Card, len(shuffle.Order))
+	for i, idx := range shuffle.Order {
+		deck[i] = cardAt(idx)
+	}
+
+	for _, s := range t.seats {
+		s.hand, s.dropped, s.drawn, s.missed, s.dealPoints, s.shown = nil, false, false, 0, 0, false
+		if s.eliminated {
+			continue
+		}
+		s.hand, deck = append([]meld.Card(nil), deck[:meld.HandSize]...), deck[meld.HandSize:]
+	}
+
+	// The cut card sets the wild joker rank; a printed joker cut makes aces wild
+	t.wildCard, deck = deck[0], deck[1:]
+	t.wildRank = t.wildCard.Rank
+	if t.wildCard.IsPrintedJoker() {
+		t.wildRank = meld.Ace
+	}
+	t.discard, t.stock = []meld.Card{deck[0]}, deck[1:]
+
+	t.status = StatusPlaying
+	t.declarer = ""
+	t.shows = make(map[string]meld.Result)
+
+	// The opening turn moves round the table each deal
+	t.turn = (t.deal - 1) % len(t.seats)
+	for !t.seats[t.turn].active() {
+		t.turn = (t.turn + 1) % len(t.seats)
+	}
+	t.startTurn()
+	return nil
+}
+
+// nextDeal deals the next hand of a pool or deals game. While its seed can't
+// be stored the table pauses, trying again every RetryInterval.
+func (t *Table) nextDeal() {
+	if err := t.startDeal(); err != nil {
+		log.Printf("rummy %s: failed to store seed nonce, pausing before deal %d: %v", t.sessionID, t.deal+1, err)
+		t.status = StatusPaused
+		t.phase = ""
+		t.turnSeq++
+		t.armTimer(t.cfg.RetryInterval, t.turnSeq, t.onDealRetry)
+	}
+}
+
+// onDealRetry tries again to deal the hand the table paused before
+func (t *Table) onDealRetry(seq int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopped || t.status != StatusPaused || seq != t.turnSeq {
+		return
+	}
+	t.nextDeal()
+}
+
+func (t *Table) startTurn() {
+	t.phase = PhaseDraw
+	t.turnSeq++
+	t.armTimer(t.cfg.TurnTime, t.turnSeq, t.onTurnTimeout)
+}
+
+func (t *Table) nextTurn() {
+	for i := 1; i <= len(t.seats); i++ {
+		next := (t.turn + i) % len(t.seats)
+		if t.seats[next].active() {
+			t.turn = next
+			break
+		}
+	}
+	t.startTurn()
+}
+
+func (t *Table) armTimer(d time.Duration, seq int, fire func(int)) {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.deadline = time.Now().Add(d)
+	t.timer = time.AfterFunc(d, func() { fire(seq) })
+}
+
+// onTurnTimeout plays a missed turn for the player: a drawn card goes straight back
+// to the pile, and enough consecutive misses drop them
+func (t *Table) onTurnTimeout(seq int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.reportTimedEnd()
+
+	if t.stopped || t.status != StatusPlaying || seq != t.turnSeq {
+		return
+	}
+
+	s := t.seats[t.turn]
+	s.missed++
+
+	if t.phase == PhaseDiscard {
+		removeCard(s, t.lastDrawn)
+		t.discard = append(t.discard, t.lastDrawn)
+	}
+	if s.missed >= t.cfg.MaxMissedTurns {
+		t.drop(s)
+		return
+	}
+	t.nextTurn()
+}
+
+// onShowTimeout scores every hand not shown in time as a single unmelded group
+func (t *Table) onShowTimeout(seq int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.reportTimedEnd()
+
+	if t.stopped || t.status != StatusShow || seq != t.turnSeq {
+		return
+	}
+	for _, s := range t.seats {
+		if s.active() && !s.shown {
+			result := meld.Score([][]meld.Card{s.hand}, t.wildRank)
+			t.shows[s.userID] = result
+			s.dealPoints = result.Points
+			s.shown = true
+		}
+	}
+	t.endIfAllShown()
+}
+
+// reportTimedEnd tells the session when a timer has just finished the game, as
+// no move will. Caller must hold mu.
+func (t *Table) reportTimedEnd() {
+	if t.status == StatusFinished && !t.stopped && t.ended != nil {
+		go t.ended() // Ending the session stops the table, which takes mu
+	}
+}
+
+func (t *Table) drop(s *seat) {
+	s.dropped = true
+	s.dealPoints = t.variant.MiddleDrop
+	if !s.drawn {
+		s.dealPoints = t.variant.FirstDrop
+	}
+	if !t.endIfLastStanding() {
+		t.nextTurn()
+	}
+}
+
+// endIfLastStanding wins the deal for the only player left in it
+func (t *Table) endIfLastStanding() bool {
+	var last *seat
+	for _, s := range t.seats {
+		if s.active() {
+			if last != nil {
+				return false
+			}
+			last = s
+		}
+	}
+	if last != nil {
+		t.endDeal(last.userID)
+	}
+	return true
+}
+
+func (t *Table) endIfAllShown() {
+	for _, s := range t.seats {
+		if s.active() && !s.shown {
+			return
+		}
+	}
+	t.endDeal(t.declarer)
+}
+
+func (t *Table) endDeal(winner string) {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	result := DealResult{
+		Deal:       t.deal,
+		Winner:     winner,
+		WildCard:   t.wildCard,
+		Points:     make(map[string]int),
+		Shows:      t.shows,
+		ServerSeed: t.seed.ServerSeed,
+		ClientSeed: t.seed.ClientSeed,
+		Nonce:      t.seed.Nonce,
+	}
+	won := 0
+	for _, s := range t.seats {
+		if s.eliminated {
+			continue
+		}
+		if s.userID == winner {
+			s.dealPoints = 0
+		}
+		if s.dropped {
+			result.Dropped = append(result.Dropped, s.userID)
+		}
+		result.Points[s.userID] = s.dealPoints
+		won += s.dealPoints
+	}
+	t.results = append(t.results, result)
+
+	// Each deal commits to a fresh server seed
+	if t.seeds != nil {
+		t.seeds.Rotate(t.sessionID, "")
+	}
+
+	switch t.variant.Format {
+	case FormatPoints:
+		t.finishPoints(winner)
+	case FormatPool:
+		t.scorePool()
+	case FormatDeals:
+		t.scoreDeals(winner, won)
+	}
+}
+
+// finishPoints settles a points game: losers pay their points, the winner
+// collects them less the platform fee, and unused reserves are refunded
+func (t *Table) finishPoints(winner string) {
+	buyIn := t.variant.BuyIn()
+	prizes := make(map[string]float64, len(t.seats))
+	won := 0.0
+	for _, s := range t.seats {
+		if s.userID == winner {
+			continue
+		}
+		loss := float64(s.dealPoints) * t.variant.PointValue
+		won += loss
+		if refund := buyIn - loss; refund > 0 {
+			prizes[s.userID] = roundMoney(refund)
+		}
+	}
+	prizes[winner] = roundMoney(buyIn + won*(1-PlatformFee))
+	t.finish([]string{winner}, prizes)
+}
+
+// scorePool adds the deal to each player's running score and eliminates
+// anyone who reaches the pool limit
+func (t *Table) scorePool() {
+	var remaining []string
+	for _, s := range t.seats {
+		if s.eliminated {
+			continue
+		}
+		s.score += s.dealPoints
+		if s.score >= t.variant.PoolLimit {
+			s.eliminated = true
+			continue
+		}
+		remaining = append(remaining, s.userID)
+	}
+
+	if len(remaining) > 1 {
+		t.nextDeal()
+		return
+	}
+	t.finish(remaining, t.splitPool(remaining))
+}
+
+// scoreDeals moves the losers' points to the winner as chips
+func (t *Table) scoreDeals(winner string, won int) {
+	for _, s := range t.seats {
+		if s.userID == winner {
+			s.chips += won
+		} else {
+			s.chips -= s.dealPoints
+		}
+	}
+	if t.deal < t.variant.Deals {
+		t.nextDeal()
+		return
+	}
+
+	best := math.MinInt
+	var leaders []string
+	for _, s := range t.seats {
+		switch {
+		case s.chips > best:
+			best, leaders = s.chips, []string{s.userID}
+		case s.chips == best:
+			leaders = append(leaders, s.userID)
+		}
+	}
+	t.finish(leaders, t.splitPool(leaders))
+}
+
+// splitPool shares the buy-ins, less the platform fee, between the winners
+func (t *Table) splitPool(winners []string) map[string]float64 {
+	prizes := make(map[string]float64, len(winners))
+	if len(winners) == 0 {
+		return prizes
+	}
+	pool := t.variant.BuyIn() * float64(len(t.seats)) * (1 - PlatformFee)
+	share := roundMoney(pool / float64(len(winners)))
+	for _, userID := range winners {
+		prizes[userID] = share
+	}
+	return prizes
+}
+
+func (t *Table) finish(winners []string, prizes map[string]float64) {
+	t.status = StatusFinished
+	t.phase = ""
+	t.winners = winners
+	t.prizes = prizes
+	go t.settle(prizes)
+}
+
+// settle credits prizes without holding the table lock over wallet I/O
+func (t *Table) settle(prizes map[string]float64) {
+	for userID, amount := range prizes {
+		if amount <= 0 {
+			continue // Nothing staked (e.g. a tournament match)
+		}
+		if err := t.wallet.Credit(userID, amount, t.sessionID, "GAME_RUMMY"); err != nil {
+			log.Printf("rummy %s: failed to credit %.2f to %s: %v", t.sessionID, amount, userID, err)
+		}
+	}
+}
+
+// reshuffleDiscards turns the discard pile, bar the open card, into a new stock
+func (t *Table) reshuffleDiscards() {
+	top := t.discard[len(t.discard)-1]
+	t.stock = append(t.stock, t.discard[:len(t.discard)-1]...)
+	t.discard = []meld.Card{top}
+}
+
+func (t *Table) turnSeat(userID string) (*seat, error) {
+	if t.status != StatusPlaying {
+		return nil, ErrNotPlaying
+	}
+	s := t.seatFor(userID)
+	if s == nil {
+		return nil, ErrNotSeated
+	}
+	if t.seats[t.turn] != s {
+		return nil, ErrNotYourTurn
+	}
+	return s, nil
+}
+
+func (t *Table) seatFor(userID string) *seat {
+	for _, s := range t.seats {
+		if s.userID == userID {
+			return s
+		}
+	}
+	return nil
+}
+
+func (t *Table) tableView() TableView {
+	view := TableView{
+		Status:         t.status,
+		Format:         t.variant.Format,
+		Deal:           t.deal,
+		WildCard:       t.wildCard,
+		StockCount:     len(t.stock),
+		Phase:          t.phase,
+		Declarer:       t.declarer,
+		Seats:          make([]SeatView, 0, len(t.seats)),
+		Results:        append([]DealResult(nil), t.results...),
+		Winners:        t.winners,
+		Prizes:         t.prizes,
+		ServerSeedHash: t.seed.ServerSeedHash,
+		ClientSeed:     t.seed.ClientSeed,
+		Nonce:          t.seed.Nonce,
+	}
+	if len(t.discard) > 0 {
+		open := t.discard[len(t.discard)-1]
+		view.OpenCard = &open
+	}
+	if t.status == StatusPlaying {
+		view.Turn = t.seats[t.turn].userID
+	}
+	if t.status == StatusPlaying || t.status == StatusShow {
+		view.TurnEndsIn = int(math.Ceil(time.Until(t.deadline).Seconds()))
+	}
+	for _, s := range t.seats {
+		view.Seats = append(view.Seats, SeatView{
+			UserID:     s.userID,
+			Cards:      len(s.hand),
+			Score:      s.score,
+			Chips:      s.chips,
+			Dropped:    s.dropped,
+			Eliminated: s.eliminated,
+			Missed:     s.missed,
+		})
+	}
+	return view
+}
+
+// dealShuffle is one deal's seed and the deck order it gave, recorded whole so a
+// replay deals the same cards. The server seed is revealed when the deal ends anyway.
+type dealShuffle struct {
+	Seed       fairness.RoundSeed `json:"seed"`
+	ServerSeed string             `json:"server_seed"`
+	Order      []int              `json:"order"`
+}
+
+// TableSnapshot is the whole table, every hand included, for replays
+type TableSnapshot struct {
+	TableView
+	Hands map[string][]meld.Card `json:"hands"`
+}
+
+// Snapshot returns the table with every hand and without the turn clock
+func (t *Table) Snapshot() TableSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshot := TableSnapshot{TableView: t.tableView(), Hands: make(map[string][]meld.Card, len(t.seats))}
+	snapshot.TurnEndsIn = 0
+	for _, s := range t.seats {
+		snapshot.Hands[s.userID] = append([]meld.Card{}, s.hand...)
+	}
+	return snapshot
+}
+
+func (t *Table) view(userID string) *PlayerView {
+	view := &PlayerView{TableView: t.tableView(), Hand: []meld.Card{}}
+	if s := t.seatFor(userID); s != nil {
+		view.Hand = append(view.Hand, s.hand...)
+	}
+	return view
+}
+
+func removeCard(s *seat, card meld.Card) bool {
+	for i, c := range s.hand {
+		if c == card {
+			s.hand = append(s.hand[:i], s.hand[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Floor(amount*100) / 100
+}
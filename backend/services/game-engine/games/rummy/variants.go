@@ -0,0 +1,100 @@
+package rummy
+
+import (
+	"github.com/playkaro/game-engine/games/rummy/meld"
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// Formats
+const (
+	FormatPoints = "POINTS" // One deal, losers pay their points at a fixed value per point
+	FormatPool   = "POOL"   // Deals continue until one player stays under the pool limit
+	FormatDeals  = "DEALS"  // A fixed number of deals played for chips
+)
+
+// Two standard decks plus two printed jokers, dealt to 2-6 players
+const DeckCards = 2*fairness.DeckSize + 2
+
+// Platform fee taken from the prize pool, as in Ludo
+const PlatformFee = 0.10
+
+// Variant describes one rummy table format
+type Variant struct {
+	GameID     string
+	Name       string
+	Format     string
+	EntryFee   float64 // Pool and deals buy-in
+	PointValue float64 // Points rummy: money per point
+	PoolLimit  int     // Pool rummy: eliminated at or above this score
+	Deals      int     // Deals rummy: number of deals played
+	FirstDrop  int     // Points for dropping before the first draw
+	MiddleDrop int     // Points for dropping after drawing
+}
+
+// BuyIn is what each player is debited to sit down. Points rummy reserves the
+// worst possible loss and refunds the difference when the deal is settled.
+func (v Variant) BuyIn() float64 {
+	if v.Format == FormatPoints {
+		return float64(meld.MaxPoints) * v.PointValue
+	}
+	return v.EntryFee
+}
+
+func PointsRummy() Variant {
+	return Variant{
+		GameID:     "rummy_points",
+		Name:       "Points Rummy",
+		Format:     FormatPoints,
+		PointValue: 1.0,
+		FirstDrop:  20,
+		MiddleDrop: 40,
+	}
+}
+
+func Pool101Rummy() Variant {
+	return Variant{
+		GameID:     "rummy_pool_101",
+		Name:       "Pool Rummy 101",
+		Format:     FormatPool,
+		EntryFee:   50.0,
+		PoolLimit:  101,
+		FirstDrop:  20,
+		MiddleDrop: 40,
+	}
+}
+
+func Pool201Rummy() Variant {
+	return Variant{
+		GameID:     "rummy_pool_201",
+		Name:       "Pool Rummy 201",
+		Format:     FormatPool,
+		EntryFee:   50.0,
+		PoolLimit:  201,
+		FirstDrop:  25,
+		MiddleDrop: 50,
+	}
+}
+
+func DealsRummy() Variant {
+	return Variant{
+		GameID:     "rummy_deals",
+		Name:       "Deals Rummy",
+		Format:     FormatDeals,
+		EntryFee:   50.0,
+		Deals:      2,
+		FirstDrop:  20,
+		MiddleDrop: 40,
+	}
+}
+
+// cardSuits in deck index order
+var cardSuits = []string{meld.SuitHearts, meld.SuitDiamonds, meld.SuitClubs, meld.SuitSpades}
+
+// cardAt maps a shuffled deck index (0 to DeckCards-1) to a card
+func cardAt(idx int) meld.Card {
+	if idx >= 2*fairness.DeckSize {
+		return meld.Card{Suit: meld.SuitJoker}
+	}
+	idx %= fairness.DeckSize
+	return meld.Card{Suit: cardSuits[idx/13], Rank: idx%13 + 1}
+}
@@ -0,0 +1,101 @@
+package teenpatti
+
+import (
+	"math/rand"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// NewBot returns a bot for one seat. Easy bots bet at random; medium and hard
+// bots look at their cards and play them, medium ones with some randomness.
+func (g *TableGame) NewBot(level engine.BotLevel) engine.BotStrategy {
+	bot := &teenPattiBot{rng: rand.New(rand.NewSource(rand.Int63()))}
+	switch level {
+	case engine.BotEasy:
+		bot.randomness = 1
+	case engine.BotMedium:
+		bot.randomness = 0.2
+	}
+	return bot
+}
+
+type teenPattiBot struct {
+	randomness float64 // Chance of a random action instead of the considered one
+	rng        *rand.Rand
+}
+
+func (b *teenPattiBot) NextMove(session *engine.GameSession, botID string) (*engine.Move, error) {
+	hand, ok := session.State.(*Hand)
+	if !ok {
+		return nil, nil
+	}
+	if hand.Round == nil {
+		// The first move deals the cards
+		return &engine.Move{PlayerID: botID, Type: "VIEW"}, nil
+	}
+	round := hand.Round
+	if round.State == StateFinished || round.CurrentTurn != botID {
+		return nil, nil
+	}
+
+	me := round.Players[botID]
+	call := CallAmount(round, botID)
+	headsUp := activePlayers(round) == 2
+
+	// Out of chips to stay in: show if the rules allow it, otherwise fold
+	if me.Balance < call {
+		return &engine.Move{PlayerID: botID, Type: "PACK"}, nil
+	}
+
+	if b.rng.Float64() < b.randomness {
+		switch r := b.rng.Float64(); {
+		case r < 0.1:
+			return &engine.Move{PlayerID: botID, Type: "PACK"}, nil
+		case headsUp && r < 0.3:
+			return &engine.Move{PlayerID: botID, Type: "SHOW"}, nil
+		case r < 0.8:
+			return b.bet(botID, call), nil
+		default:
+			return b.bet(botID, raiseAmount(me, call)), nil
+		}
+	}
+
+	if me.IsBlind {
+		return &engine.Move{PlayerID: botID, Type: "SEE"}, nil
+	}
+
+	rank := GetHandRank(me.Cards)
+	switch {
+	case rank.Type == HighCard && rank.Values[0] < 12 && round.CurrentStake > round.BootAmount:
+		// Weak hand facing a raise
+		return &engine.Move{PlayerID: botID, Type: "PACK"}, nil
+	case headsUp && rank.Type >= Pair:
+		return &engine.Move{PlayerID: botID, Type: "SHOW"}, nil
+	case rank.Type >= Color:
+		return b.bet(botID, raiseAmount(me, call)), nil
+	default:
+		return b.bet(botID, call), nil
+	}
+}
+
+func (b *teenPattiBot) bet(botID string, amount float64) *engine.Move {
+	return &engine.Move{PlayerID: botID, Type: "BET", Data: map[string]interface{}{"amount": amount}}
+}
+
+// raiseAmount doubles the call if the bot's stack allows it
+func raiseAmount(me *Player, call float64) float64 {
+	if me.Balance >= call*2 {
+		return call * 2
+	}
+	return call
+}
+
+func activePlayers(round *TeenPattiGame) int {
+	n := 0
+	for _, p := range round.Players {
+		if p.Status == PlayerStatusActive {
+			n++
+		}
+	}
+	return n
+}
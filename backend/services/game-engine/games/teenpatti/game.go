@@ -0,0 +1,271 @@
+package teenpatti
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// PlatformFee is the rake taken from the pot
+const PlatformFee = 0.10
+
+// Wallet moves buy-ins and winnings, booking bots' to the house (implemented
+// by wallet.WalletClient)
+type Wallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+	Credit(userID string, amount float64, refID, refType string) error
+	DebitBot(botID string, amount float64, refID, refType string) error
+	CreditBot(botID string, amount float64, refID, refType string) error
+}
+
+// TableGame plays one hand of Teen Patti per session through the IGame API.
+// Every player buys in for the entry fee and plays from that stack; the pot
+// (less the rake) and any unplayed stack are paid out when the hand ends.
+type TableGame struct {
+	gameID     string
+	buyIn      float64
+	bootAmount float64
+	wallet     Wallet
+}
+
+func NewTableGame(buyIn, bootAmount float64) *TableGame {
+	return &TableGame{
+		gameID:     "teen_patti",
+		buyIn:      buyIn,
+		bootAmount: bootAmount,
+		wallet:     wallet.NewWalletClient(),
+	}
+}
+
+func (g *TableGame) GetGameID() string            { return g.gameID }
+func (g *TableGame) GetGameName() string          { return "Teen Patti" }
+func (g *TableGame) GetGameType() engine.GameType { return engine.GameTypeSkill }
+func (g *TableGame) GetMinPlayers() int           { return MinPlayers }
+func (g *TableGame) GetMaxPlayers() int           { return MaxPlayers }
+func (g *TableGame) GetEntryFee() float64         { return g.buyIn }
+
+func (g *TableGame) Initialize() error {
+	return nil
+}
+
+// Hand is the session state. Cards stay hidden when it is serialized until the
+// hand is over; each player sees their own through their move results.
+type Hand struct {
+	Round   *TeenPattiGame
+	BuyIn   float64
+	Prizes  map[string]float64
+	settled bool
+}
+
+// MarshalJSON hides every player's cards until the hand is finished
+func (h *Hand) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.view(""))
+}
+
+// HandView is the hand as one player sees it
+type HandView struct {
+	State        string             `json:"state"`
+	Pot          float64            `json:"pot"`
+	BootAmount   float64            `json:"boot_amount"`
+	CurrentStake float64            `json:"current_stake"`
+	CurrentTurn  string             `json:"current_turn,omitempty"`
+	Players      []Player           `json:"players"`
+	Winner       string             `json:"winner,omitempty"`
+	Prizes       map[string]float64 `json:"prizes,omitempty"`
+}
+
+func (h *Hand) view(userID string) HandView {
+	if h.Round == nil {
+		return HandView{State: StateWaiting, Players: []Player{}}
+	}
+	r := h.Round
+	view := HandView{
+		State:        r.State,
+		Pot:          r.Pot,
+		BootAmount:   r.BootAmount,
+		CurrentStake: r.CurrentStake,
+		Players:      make([]Player, 0, len(r.ActivePlayers)),
+		Winner:       r.Winner,
+		Prizes:       h.Prizes,
+	}
+	if r.State != StateFinished {
+		view.CurrentTurn = r.CurrentTurn
+	}
+	for _, id := range r.ActivePlayers {
+		p := *r.Players[id]
+		if r.State != StateFinished && !(id == userID && p.SeenCards) {
+			p.Cards = nil
+		}
+		view.Players = append(view.Players, p)
+	}
+	return view
+}
+
+// Start opens the hand. Cards are dealt on the first move, once the table has filled.
+func (g *TableGame) Start(session *engine.GameSession) error {
+	session.State = &Hand{BuyIn: session.EntryFee}
+	return nil
+}
+
+// deal debits every buy-in (unless matchmaking already has) and deals the cards
+func (g *TableGame) deal(session *engine.GameSession, hand *Hand) error {
+	if len(session.Players) < MinPlayers {
+		return errors.New("not enough players")
+	}
+
+	if !session.Prepaid && !session.Replay {
+		seats := wallet.ForSeats(g.wallet, session.Players)
+		debited := []string{}
+		for _, p := range session.Players {
+			if err := seats.Debit(p.UserID, hand.BuyIn, session.SessionID, "GAME_TEENPATTI"); err != nil {
+				for _, userID := range debited {
+					seats.Credit(userID, hand.BuyIn, session.SessionID, "GAME_TEENPATTI_REFUND")
+				}
+				return fmt.Errorf("failed to deduct buy-in for %s: %v", p.UserID, err)
+			}
+			debited = append(debited, p.UserID)
+		}
+	}
+
+	round := NewGame(session.SessionID, g.bootAmount)
+	round.Deck = engine.Draw(session.RNG, func() []Card {
+		deck := NewDeck()
+		Shuffle(deck)
+		return deck
+	})
+	for _, p := range session.Players {
+		if err := round.AddPlayer(p.UserID, p.Username, hand.BuyIn); err != nil {
+			return err
+		}
+	}
+	if err := round.StartGame(); err != nil {
+		return err
+	}
+	hand.Round = round
+	return nil
+}
+
+func (g *TableGame) HandleMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
+	hand := session.State.(*Hand)
+	if hand.Round == nil {
+		if err := g.deal(session, hand); err != nil {
+			return nil, err
+		}
+	}
+	round := hand.Round
+	if round.State == StateFinished {
+		return nil, errors.New("hand is over")
+	}
+	if _, ok := round.Players[move.PlayerID]; !ok {
+		return nil, errors.New("player not at this table")
+	}
+
+	var err error
+	switch move.Type {
+	case "VIEW":
+	case "SEE":
+		err = round.SeeCards(move.PlayerID)
+	case "BET":
+		amount, _ := move.Data["amount"].(float64)
+		if amount == 0 {
+			amount = CallAmount(round, move.PlayerID)
+		}
+		err = round.PlaceBet(move.PlayerID, amount)
+	case "PACK":
+		err = round.Pack(move.PlayerID)
+	case "SHOW":
+		err = g.show(round, move.PlayerID)
+	default:
+		return nil, errors.New("invalid move type")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	view := hand.view(move.PlayerID)
+	return &engine.MoveResult{
+		Success:     true,
+		NextTurn:    view.CurrentTurn,
+		StateUpdate: view,
+		GameEnded:   round.State == StateFinished,
+	}, nil
+}
+
+// show asks for a showdown against the one other player left
+func (g *TableGame) show(round *TeenPattiGame, userID string) error {
+	if round.CurrentTurn != userID {
+		return errors.New("not your turn")
+	}
+	for _, id := range round.ActivePlayers {
+		if id != userID && round.Players[id].Status == PlayerStatusActive {
+			return round.Showdown(userID, id)
+		}
+	}
+	return errors.New("no opponent to show against")
+}
+
+// CallAmount is what the player must put in to stay in: half the stake while playing blind
+func CallAmount(round *TeenPattiGame, userID string) float64 {
+	if round.Players[userID].IsBlind {
+		return round.CurrentStake / 2
+	}
+	return round.CurrentStake
+}
+
+// End pays the winner the pot less the rake and returns every unplayed stack
+func (g *TableGame) End(session *engine.GameSession) (*engine.GameResult, error) {
+	hand := session.State.(*Hand)
+	if hand.Round == nil || hand.settled {
+		return nil, nil
+	}
+	hand.settled = true
+	round := hand.Round
+
+	prize := 0.0
+	if round.Winner != "" {
+		prize = math.Floor(round.Pot*(1-PlatformFee)*100) / 100
+		hand.Prizes = map[string]float64{round.Winner: prize}
+	}
+
+	seats := wallet.ForSeats(g.wallet, session.Players)
+	scores := make(map[string]int, len(round.Players))
+	for id, p := range round.Players {
+		payout := p.Balance
+		if id == round.Winner {
+			payout += prize
+		}
+		scores[id] = int(math.Round(payout - hand.BuyIn))
+		if payout > 0 && !session.Replay {
+			if err := seats.Credit(id, payout, session.SessionID, "GAME_TEENPATTI"); err != nil {
+				log.Printf("teen patti %s: failed to pay %.2f to %s: %v", session.SessionID, payout, id, err)
+			}
+		}
+	}
+
+	return &engine.GameResult{
+		WinnerID: round.Winner,
+		Scores:   scores,
+		Prizes:   hand.Prizes,
+	}, nil
+}
+
+func (g *TableGame) GetState(session *engine.GameSession) interface{} {
+	return session.State
+}
+
+// Snapshot is the hand with every player's cards showing
+func (g *TableGame) Snapshot(session *engine.GameSession) interface{} {
+	hand := session.State.(*Hand)
+	view := hand.view("")
+	if hand.Round != nil {
+		for i := range view.Players {
+			view.Players[i].Cards = hand.Round.Players[view.Players[i].ID].Cards
+		}
+	}
+	return view
+}
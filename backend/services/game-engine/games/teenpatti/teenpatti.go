@@ -1,7 +1,9 @@
 package teenpatti
 
 import (
+	"crypto/rand"
 	"errors"
+	"math/big"
 	"time"
 
 	"github.com/google/uuid"
@@ -113,8 +115,11 @@ func (g *TeenPattiGame) StartGame() error {
 	}
 
 	g.State = StateDealing
-	g.Deck = NewDeck()
-	Shuffle(g.Deck)
+	// A deck set beforehand (e.g. from a replay) is dealt as it is
+	if g.Deck == nil {
+		g.Deck = NewDeck()
+		Shuffle(g.Deck)
+	}
 
 	// Collect Boot Amount
 	for _, pID := range g.ActivePlayers {
@@ -314,7 +319,13 @@ func NewDeck() []Card {
 	return deck
 }
 
+// Shuffle does a Fisher-Yates shuffle with crypto/rand
 func Shuffle(deck []Card) {
-	// In production use crypto/rand
-	// For now simple shuffle logic
+	for i := len(deck) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			panic(err)
+		}
+		deck[i], deck[j.Int64()] = deck[j.Int64()], deck[i]
+	}
 }
@@ -0,0 +1,75 @@
+// Package actor holds what live tables share: one goroutine owns a table's
+// state and takes commands and clock ticks in turn, while every other goroutine
+// reads immutable snapshots it publishes.
+package actor
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Loop delivers commands of type C and clock ticks to a table's goroutine
+type Loop[C any] struct {
+	cmds chan C
+	done chan struct{}
+}
+
+func NewLoop[C any]() *Loop[C] {
+	return &Loop[C]{
+		cmds: make(chan C),
+		done: make(chan struct{}),
+	}
+}
+
+// Send hands cmd to the table goroutine. It returns false if the loop has stopped.
+func (l *Loop[C]) Send(cmd C) bool {
+	select {
+	case l.cmds <- cmd:
+		return true
+	case <-l.done:
+		return false
+	}
+}
+
+// Run calls handle for each command and tick every interval, one at a time,
+// until ctx is cancelled. Sends fail once it returns.
+func (l *Loop[C]) Run(ctx context.Context, interval time.Duration, handle func(cmd C, now time.Time), tick func(now time.Time)) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cmd := <-l.cmds:
+			handle(cmd, time.Now())
+		case now := <-ticker.C:
+			tick(now)
+		}
+	}
+}
+
+// Snapshot holds the latest published state of a table
+type Snapshot[T any] struct {
+	v atomic.Pointer[T]
+}
+
+// Load returns the latest state. Callers must not modify it.
+func (s *Snapshot[T]) Load() *T {
+	return s.v.Load()
+}
+
+// Store publishes a new state, which must not be modified afterwards
+func (s *Snapshot[T]) Store(state *T) {
+	s.v.Store(state)
+}
+
+// MarshalJSON serializes the latest state, so a table can sit in
+// GameSession.State and be read while it plays
+func (s *Snapshot[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Load())
+}
@@ -0,0 +1,148 @@
+// Package anticheat runs detection rules over live sessions, files alerts for
+// review and suspends accounts that keep tripping high-severity rules.
+package anticheat
+
+import (
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// Alert severity levels
+const (
+	SeverityLow    = "LOW"
+	SeverityMedium = "MEDIUM"
+	SeverityHigh   = "HIGH"
+)
+
+// Alert types
+const (
+	AlertTypeTiming       = "TIMING"
+	AlertTypeWinRate      = "WIN_RATE"
+	AlertTypeInvalidMove  = "INVALID_MOVE"
+	AlertTypeStatistical  = "STATISTICAL"
+	AlertTypeCollusion    = "COLLUSION"     // Same players keep sharing tables
+	AlertTypeChipDumping  = "CHIP_DUMPING"  // A player keeps throwing games to the same opponent
+	AlertTypeSharedDevice = "SHARED_DEVICE" // Players at one table share a device or IP
+)
+
+// Review outcomes of an alert
+const (
+	AlertOpen      = "OPEN"
+	AlertConfirmed = "CONFIRMED"
+	AlertDismissed = "DISMISSED"
+)
+
+// Alert is one suspicious finding about a player
+type Alert struct {
+	ID            int64                  `json:"id"`
+	UserID        string                 `json:"user_id"`
+	SessionID     string                 `json:"session_id,omitempty"`
+	GameID        string                 `json:"game_id,omitempty"`
+	AlertType     string                 `json:"alert_type"`
+	Severity      string                 `json:"severity"`
+	Details       map[string]interface{} `json:"details"`
+	Status        string                 `json:"status"`
+	ReviewedBy    string                 `json:"reviewed_by,omitempty"`
+	ReviewerNotes string                 `json:"reviewer_notes,omitempty"`
+	ReviewedAt    *time.Time             `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// Table is what the detector has seen of one session
+type Table struct {
+	SessionID string
+	GameID    string
+	Players   []string               // Seating order, bots included
+	Bots      []string               // Players the session seated as server-side bots
+	Stake     float64                // What each player paid to sit down
+	Moves     map[string][]time.Time // userID -> when each accepted move was made
+	// State is the game's own state after the latest move. Move rules check it,
+	// never what the client sent, and must not modify it.
+	State interface{}
+	// Memo keeps what move rules need between moves, by rule name
+	Memo map[string]interface{}
+}
+
+// IsBot reports whether the session seated userID as a server-side bot
+func (t *Table) IsBot(userID string) bool {
+	return contains(t.Bots, userID)
+}
+
+// Humans returns the players who are not server-side bots
+func (t *Table) Humans() []string {
+	humans := []string{}
+	for _, userID := range t.Players {
+		if !t.IsBot(userID) {
+			humans = append(humans, userID)
+		}
+	}
+	return humans
+}
+
+// HasBots reports whether a bot sat at the table
+func (t *Table) HasBots() bool {
+	return len(t.Humans()) < len(t.Players)
+}
+
+// SessionSummary is a finished session as kept for rules that look at history
+type SessionSummary struct {
+	SessionID   string         `json:"session_id"`
+	GameID      string         `json:"game_id"`
+	Players     []string       `json:"players"`
+	Bots        []string       `json:"bots,omitempty"`
+	Winners     []string       `json:"winners"`
+	Moves       map[string]int `json:"moves"` // userID -> moves made
+	CompletedAt time.Time      `json:"completed_at"`
+}
+
+// Won reports whether userID was among the winners
+func (s *SessionSummary) Won(userID string) bool {
+	for _, w := range s.Winners {
+		if w == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBots reports whether a bot sat at the table
+func (s *SessionSummary) HasBots() bool {
+	return len(s.Bots) > 0
+}
+
+// Client is a device and address a player was seen playing from
+type Client struct {
+	UserID     string    `json:"user_id"`
+	DeviceHash string    `json:"device_hash"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+// Lookup is what rules can read beyond the table in front of them
+type Lookup interface {
+	// Sessions returns the player's sessions finished since the given time, newest first
+	Sessions(userID string, since time.Time) ([]*SessionSummary, error)
+	// Clients returns every device and address the players were seen on
+	Clients(userIDs []string) ([]Client, error)
+}
+
+// Rule is a detection rule. It implements MoveRule, SessionRule or both.
+type Rule interface {
+	Name() string
+}
+
+// MoveRule checks every accepted move. It runs with the session locked, so it
+// must not do I/O.
+type MoveRule interface {
+	Rule
+	CheckMove(table *Table, move engine.Move) []Alert
+}
+
+// SessionRule checks a session once it has ended. The session is already in
+// the history Lookup returns.
+type SessionRule interface {
+	Rule
+	CheckSession(table *Table, result *engine.GameResult, lookup Lookup) []Alert
+}
@@ -0,0 +1,207 @@
+package anticheat
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// CollusionRules are the rules for multiplayer card games, where players at one
+// table can team up against the rest
+func CollusionRules() []Rule {
+	return []Rule{NewSharedClientRule(), NewCoPlayRule(), NewChipDumpingRule()}
+}
+
+// SharedClientRule flags players at one table who have played from the same
+// device or IP address
+type SharedClientRule struct{}
+
+func NewSharedClientRule() *SharedClientRule {
+	return &SharedClientRule{}
+}
+
+func (r *SharedClientRule) Name() string { return "shared_client" }
+
+func (r *SharedClientRule) CheckSession(table *Table, result *engine.GameResult, lookup Lookup) []Alert {
+	humans := table.Humans()
+	if len(humans) < 2 {
+		return nil
+	}
+	clients, err := lookup.Clients(humans)
+	if err != nil {
+		log.Printf("anticheat: clients at %s: %v", table.SessionID, err)
+		return nil
+	}
+
+	byDevice := make(map[string]map[string]bool)
+	byIP := make(map[string]map[string]bool)
+	for _, c := range clients {
+		addTo(byDevice, c.DeviceHash, c.UserID)
+		addTo(byIP, c.IPAddress, c.UserID)
+	}
+
+	alerts := []Alert{}
+	// A shared device is hard to explain away; a shared address may be a household or an office
+	for _, shared := range []struct {
+		kind     string
+		severity string
+		users    map[string]map[string]bool
+	}{{"device", SeverityHigh, byDevice}, {"ip", SeverityMedium, byIP}} {
+		for value, users := range shared.users {
+			if value == "" || len(users) < 2 {
+				continue
+			}
+			for userID := range users {
+				alerts = append(alerts, Alert{
+					UserID:    userID,
+					AlertType: AlertTypeSharedDevice,
+					Severity:  shared.severity,
+					Details: map[string]interface{}{
+						"shared":  shared.kind,
+						"players": sortedKeys(users),
+						"reason":  "Players at the same table share a " + shared.kind,
+					},
+				})
+			}
+		}
+	}
+	return alerts
+}
+
+// CoPlayRule flags pairs of players who keep ending up at the same table
+type CoPlayRule struct {
+	Window    time.Duration
+	MaxShared int // Sessions together within Window before the pair is flagged
+}
+
+func NewCoPlayRule() *CoPlayRule {
+	return &CoPlayRule{Window: 7 * 24 * time.Hour, MaxShared: 10}
+}
+
+func (r *CoPlayRule) Name() string { return "co_play" }
+
+func (r *CoPlayRule) CheckSession(table *Table, result *engine.GameResult, lookup Lookup) []Alert {
+	humans := table.Humans()
+	if len(humans) < 2 {
+		return nil
+	}
+
+	alerts := []Alert{}
+	for i, userID := range humans {
+		sessions, err := lookup.Sessions(userID, time.Now().Add(-r.Window))
+		if err != nil {
+			log.Printf("anticheat: sessions of %s: %v", userID, err)
+			continue
+		}
+		for _, partner := range humans[i+1:] {
+			shared := 0
+			for _, s := range sessions {
+				if contains(s.Players, partner) {
+					shared++
+				}
+			}
+			// Flag at every multiple of the limit rather than after every game
+			if shared == 0 || shared%r.MaxShared != 0 {
+				continue
+			}
+			for _, pair := range [][2]string{{userID, partner}, {partner, userID}} {
+				alerts = append(alerts, Alert{
+					UserID:    pair[0],
+					AlertType: AlertTypeCollusion,
+					Severity:  SeverityMedium,
+					Details: map[string]interface{}{
+						"partner":         pair[1],
+						"shared_sessions": shared,
+						"window_hours":    r.Window.Hours(),
+						"reason":          "Repeatedly sharing tables with the same player",
+					},
+				})
+			}
+		}
+	}
+	return alerts
+}
+
+// ChipDumpingRule flags a player who keeps losing to the same opponent while
+// barely playing (e.g. packing or dropping straight away), which moves money
+// from one account to the other
+type ChipDumpingRule struct {
+	Window   time.Duration
+	MaxMoves int // A loser who made at most this many moves gave the game away
+	MinDumps int // Such losses to one opponent within Window before flagging
+}
+
+func NewChipDumpingRule() *ChipDumpingRule {
+	return &ChipDumpingRule{Window: 7 * 24 * time.Hour, MaxMoves: 2, MinDumps: 3}
+}
+
+func (r *ChipDumpingRule) Name() string { return "chip_dumping" }
+
+func (r *ChipDumpingRule) CheckSession(table *Table, result *engine.GameResult, lookup Lookup) []Alert {
+	if result == nil || result.WinnerID == "" || table.IsBot(result.WinnerID) {
+		return nil
+	}
+	winner := result.WinnerID
+
+	alerts := []Alert{}
+	for _, loser := range table.Humans() {
+		if loser == winner || len(table.Moves[loser]) > r.MaxMoves {
+			continue
+		}
+
+		sessions, err := lookup.Sessions(loser, time.Now().Add(-r.Window))
+		if err != nil {
+			log.Printf("anticheat: sessions of %s: %v", loser, err)
+			continue
+		}
+		dumps := 0
+		for _, s := range sessions {
+			if s.Won(winner) && !s.Won(loser) && s.Moves[loser] <= r.MaxMoves {
+				dumps++
+			}
+		}
+		if dumps < r.MinDumps {
+			continue
+		}
+
+		details := map[string]interface{}{
+			"loser":     loser,
+			"winner":    winner,
+			"dumps":     dumps,
+			"max_moves": r.MaxMoves,
+			"reason":    "Repeatedly losing to the same player without playing",
+		}
+		alerts = append(alerts,
+			Alert{UserID: loser, AlertType: AlertTypeChipDumping, Severity: SeverityHigh, Details: details},
+			Alert{UserID: winner, AlertType: AlertTypeChipDumping, Severity: SeverityHigh, Details: details},
+		)
+	}
+	return alerts
+}
+
+func addTo(index map[string]map[string]bool, key, userID string) {
+	if index[key] == nil {
+		index[key] = make(map[string]bool)
+	}
+	index[key][userID] = true
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
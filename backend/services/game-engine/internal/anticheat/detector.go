@@ -0,0 +1,363 @@
+package anticheat
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// Suspension states
+const (
+	SuspensionActive = "ACTIVE" // Account suspended and wallet frozen, pending review
+	SuspensionUpheld = "UPHELD" // Review confirmed the ban
+	SuspensionLifted = "LIFTED" // Review cleared the player
+)
+
+var ErrNotSuspended = errors.New("no suspension pending review")
+
+// Suspension is an account suspended by AutoBan
+type Suspension struct {
+	ID         int64      `json:"id"`
+	UserID     string     `json:"user_id"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	Notes      string     `json:"notes,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// Wallet freezes the money of suspended players (implemented by wallet.WalletClient)
+type Wallet interface {
+	Freeze(userID, reason string) error
+	Unfreeze(userID string) error
+}
+
+// Config controls automatic suspensions
+type Config struct {
+	// A player with BanAfter high-severity alerts within BanWindow is suspended
+	BanAfter  int
+	BanWindow time.Duration
+	// How long a suspension check is cached for the request guard
+	StatusCacheTTL time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BanAfter:       3,
+		BanWindow:      7 * 24 * time.Hour,
+		StatusCacheTTL: time.Minute,
+	}
+}
+
+// Detector runs the rules registered for each game over live sessions
+type Detector struct {
+	cfg      Config
+	store    Store
+	wallet   Wallet
+	defaults []Rule            // Run for every game
+	rules    map[string][]Rule // gameID -> rules for that game only
+	tables   map[string]*Table // sessionID -> table, while the session runs
+	status   map[string]cachedStatus
+	mu       sync.Mutex
+}
+
+type cachedStatus struct {
+	suspended bool
+	checked   time.Time
+}
+
+func NewDetector(cfg Config, store Store, wallet Wallet) *Detector {
+	return &Detector{
+		cfg:    cfg,
+		store:  store,
+		wallet: wallet,
+		rules:  make(map[string][]Rule),
+		tables: make(map[string]*Table),
+		status: make(map[string]cachedStatus),
+	}
+}
+
+// RegisterDefault adds rules that run for every game
+func (d *Detector) RegisterDefault(rules ...Rule) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.defaults = append(d.defaults, rules...)
+}
+
+// Register adds rules that run for one game only
+func (d *Detector) Register(gameID string, rules ...Rule) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rules[gameID] = append(d.rules[gameID], rules...)
+}
+
+func (d *Detector) rulesFor(gameID string) []Rule {
+	rules := make([]Rule, 0, len(d.defaults)+len(d.rules[gameID]))
+	rules = append(rules, d.defaults...)
+	return append(rules, d.rules[gameID]...)
+}
+
+// SeenClient records the device and address a player is playing from
+func (d *Detector) SeenClient(client Client) {
+	if err := d.store.SaveClient(client); err != nil {
+		log.Printf("anticheat: failed to record client of %s: %v", client.UserID, err)
+	}
+}
+
+// HandleMove runs the move rules (see session.SessionManager.OnMove)
+func (d *Detector) HandleMove(session *engine.GameSession, move engine.Move) {
+	d.mu.Lock()
+	table, ok := d.tables[session.SessionID]
+	if !ok {
+		table = newTable(session)
+		d.tables[session.SessionID] = table
+	}
+	table.Players, table.Bots = playerIDs(session)
+	table.State = session.State
+	table.Moves[move.PlayerID] = append(table.Moves[move.PlayerID], time.Now())
+	rules := d.rulesFor(session.GameID)
+	// The alerts are filed after the next move may have updated the table
+	raised := table.seating()
+	d.mu.Unlock()
+
+	alerts := []Alert{}
+	for _, rule := range rules {
+		if r, ok := rule.(MoveRule); ok {
+			alerts = append(alerts, r.CheckMove(table, move)...)
+		}
+	}
+	if len(alerts) > 0 {
+		go d.raise(raised, alerts)
+	}
+}
+
+// HandleGameEnd records the finished session and runs the session rules (see
+// session.SessionManager.OnGameEnd)
+func (d *Detector) HandleGameEnd(session *engine.GameSession, result *engine.GameResult) {
+	d.mu.Lock()
+	table, ok := d.tables[session.SessionID]
+	if ok {
+		// A copy, as the session's last move may still be raising alerts
+		ended := *table
+		table = &ended
+	}
+	delete(d.tables, session.SessionID)
+	rules := d.rulesFor(session.GameID)
+	d.mu.Unlock()
+	if !ok {
+		table = newTable(session)
+	}
+	table.Players, table.Bots = playerIDs(session)
+
+	summary := &SessionSummary{
+		SessionID:   session.SessionID,
+		GameID:      session.GameID,
+		Players:     table.Players,
+		Bots:        table.Bots,
+		Winners:     winners(table, result),
+		Moves:       make(map[string]int, len(table.Players)),
+		CompletedAt: time.Now(),
+	}
+	for _, userID := range table.Players {
+		summary.Moves[userID] = len(table.Moves[userID])
+	}
+	if err := d.store.SaveSession(summary); err != nil {
+		log.Printf("anticheat: failed to record session %s: %v", session.SessionID, err)
+	}
+
+	alerts := []Alert{}
+	for _, rule := range rules {
+		if r, ok := rule.(SessionRule); ok {
+			alerts = append(alerts, r.CheckSession(table, result, d.store)...)
+		}
+	}
+	d.raise(table, alerts)
+}
+
+// raise files the alerts and suspends anyone they push over the ban threshold
+func (d *Detector) raise(table *Table, alerts []Alert) {
+	flagged := make(map[string]bool)
+	for i := range alerts {
+		alert := &alerts[i]
+		// Bots are the house's own players and never flagged
+		if table.IsBot(alert.UserID) {
+			continue
+		}
+		if alert.SessionID == "" {
+			alert.SessionID = table.SessionID
+		}
+		if alert.GameID == "" {
+			alert.GameID = table.GameID
+		}
+		alert.Status = AlertOpen
+		alert.CreatedAt = time.Now()
+
+		if err := d.store.SaveAlert(alert); err != nil {
+			log.Printf("anticheat: failed to save %s alert for %s: %v", alert.AlertType, alert.UserID, err)
+			continue
+		}
+		if alert.Severity == SeverityHigh {
+			flagged[alert.UserID] = true
+		}
+	}
+
+	for userID := range flagged {
+		if _, err := d.AutoBan(userID); err != nil {
+			log.Printf("anticheat: auto-ban check for %s failed: %v", userID, err)
+		}
+	}
+}
+
+// AutoBan suspends the player and freezes their wallet, pending review, once they
+// have BanAfter high-severity alerts within BanWindow
+func (d *Detector) AutoBan(userID string) (bool, error) {
+	count, err := d.store.CountAlerts(userID, SeverityHigh, time.Now().Add(-d.cfg.BanWindow))
+	if err != nil {
+		return false, err
+	}
+	if count < d.cfg.BanAfter {
+		return false, nil
+	}
+
+	existing, err := d.store.Suspension(userID)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		return false, nil
+	}
+
+	reason := fmt.Sprintf("%d high-severity anticheat alerts in %s", count, d.cfg.BanWindow)
+	suspension := &Suspension{UserID: userID, Reason: reason, Status: SuspensionActive, CreatedAt: time.Now()}
+	if err := d.store.Suspend(suspension); err != nil {
+		return false, err
+	}
+	d.cacheStatus(userID, true)
+
+	if err := d.wallet.Freeze(userID, reason); err != nil {
+		// The suspension stands; the wallet is frozen again when a reviewer upholds it
+		log.Printf("anticheat: suspended %s but failed to freeze wallet: %v", userID, err)
+	}
+	log.Printf("anticheat: suspended %s pending review: %s", userID, reason)
+	return true, nil
+}
+
+// Suspended reports whether the player's account is suspended, caching the
+// answer for StatusCacheTTL
+func (d *Detector) Suspended(userID string) bool {
+	d.mu.Lock()
+	cached, ok := d.status[userID]
+	d.mu.Unlock()
+	if ok && time.Since(cached.checked) < d.cfg.StatusCacheTTL {
+		return cached.suspended
+	}
+
+	suspension, err := d.store.Suspension(userID)
+	if err != nil {
+		log.Printf("anticheat: failed to check suspension of %s: %v", userID, err)
+		return ok && cached.suspended
+	}
+	d.cacheStatus(userID, suspension != nil)
+	return suspension != nil
+}
+
+func (d *Detector) cacheStatus(userID string, suspended bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status[userID] = cachedStatus{suspended: suspended, checked: time.Now()}
+}
+
+// Alerts lists alerts for review
+func (d *Detector) Alerts(filter AlertFilter, limit, offset int) ([]*Alert, error) {
+	return d.store.Alerts(filter, limit, offset)
+}
+
+// ReviewAlert records a reviewer's verdict on an alert
+func (d *Detector) ReviewAlert(alertID int64, reviewerID, status, notes string) (*Alert, error) {
+	if status != AlertConfirmed && status != AlertDismissed {
+		return nil, errors.New("status must be CONFIRMED or DISMISSED")
+	}
+	return d.store.ReviewAlert(alertID, reviewerID, status, notes)
+}
+
+// Suspensions lists suspensions, only those pending review if pending is set
+func (d *Detector) Suspensions(pending bool, limit, offset int) ([]*Suspension, error) {
+	return d.store.Suspensions(pending, limit, offset)
+}
+
+// ReviewSuspension upholds a suspension, keeping the account suspended and the
+// wallet frozen, or lifts it and unfreezes the wallet
+func (d *Detector) ReviewSuspension(userID, reviewerID, status, notes string) (*Suspension, error) {
+	if status != SuspensionUpheld && status != SuspensionLifted {
+		return nil, errors.New("status must be UPHELD or LIFTED")
+	}
+
+	suspension, err := d.store.ReviewSuspension(userID, reviewerID, status, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == SuspensionLifted {
+		d.cacheStatus(userID, false)
+		if err := d.wallet.Unfreeze(userID); err != nil {
+			return suspension, fmt.Errorf("suspension lifted but wallet still frozen: %v", err)
+		}
+	}
+	return suspension, nil
+}
+
+func newTable(session *engine.GameSession) *Table {
+	return &Table{
+		SessionID: session.SessionID,
+		GameID:    session.GameID,
+		Stake:     session.EntryFee,
+		Moves:     make(map[string][]time.Time),
+		Memo:      make(map[string]interface{}),
+	}
+}
+
+// seating copies who sat at the table, which is all raise needs
+func (t *Table) seating() *Table {
+	return &Table{
+		SessionID: t.SessionID,
+		GameID:    t.GameID,
+		Players:   append([]string(nil), t.Players...),
+		Bots:      append([]string(nil), t.Bots...),
+	}
+}
+
+// playerIDs returns the session's players in seating order, and which were bots
+func playerIDs(session *engine.GameSession) (players, bots []string) {
+	players = make([]string, len(session.Players))
+	for i, p := range session.Players {
+		players[i] = p.UserID
+		if p.IsBot {
+			bots = append(bots, p.UserID)
+		}
+	}
+	return players, bots
+}
+
+// winners are the declared winner and anyone else paid more than their stake. A
+// prize that only returns part of the stake, like the unused reserve refunded to
+// a Points Rummy loser, is not a win.
+func winners(table *Table, result *engine.GameResult) []string {
+	ids := []string{}
+	if result == nil {
+		return ids
+	}
+	if result.WinnerID != "" {
+		ids = append(ids, result.WinnerID)
+	}
+	for userID, prize := range result.Prizes {
+		if prize > table.Stake && userID != result.WinnerID {
+			ids = append(ids, userID)
+		}
+	}
+	return ids
+}
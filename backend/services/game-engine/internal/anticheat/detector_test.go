@@ -0,0 +1,106 @@
+package anticheat
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+func TestWinnersComparePrizeWithStake(t *testing.T) {
+	// A Points Rummy table: the winner takes the pot, losers get back what is
+	// left of their reserve
+	table := &Table{Stake: 100}
+	result := &engine.GameResult{
+		WinnerID: "alice",
+		Prizes:   map[string]float64{"alice": 250, "bob": 40, "carol": 100, "dave": 120},
+	}
+
+	got := winners(table, result)
+	sort.Strings(got[1:])
+	if want := []string{"alice", "dave"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("winners = %v, want %v", got, want)
+	}
+}
+
+func TestTimingRuleUsesItsThresholds(t *testing.T) {
+	start := time.Now()
+	moves := func(n int, gap time.Duration) []time.Time {
+		at := make([]time.Time, n)
+		for i := range at {
+			at[i] = start.Add(time.Duration(i) * gap)
+		}
+		return at
+	}
+	table := &Table{
+		Players: []string{"fast", "slow", "few", "bot_1"},
+		Bots:    []string{"bot_1"},
+		Moves: map[string][]time.Time{
+			"fast":  moves(10, 100*time.Millisecond),
+			"slow":  moves(10, 2*time.Second),
+			"few":   moves(3, 10*time.Millisecond),
+			"bot_1": moves(10, time.Millisecond),
+		},
+	}
+
+	alerts := NewTimingRule(5, 500*time.Millisecond, SeverityMedium).CheckSession(table, nil, nil)
+	if len(alerts) != 1 || alerts[0].UserID != "fast" {
+		t.Fatalf("alerts = %+v, want one for fast", alerts)
+	}
+	if alerts[0].Severity != SeverityMedium {
+		t.Fatalf("severity = %s, want %s", alerts[0].Severity, SeverityMedium)
+	}
+}
+
+// everyMoveRule flags every move
+type everyMoveRule struct{}
+
+func (everyMoveRule) Name() string { return "every_move" }
+
+func (everyMoveRule) CheckMove(table *Table, move engine.Move) []Alert {
+	return []Alert{{UserID: move.PlayerID, AlertType: AlertTypeTiming, Severity: SeverityLow}}
+}
+
+type noWallet struct{}
+
+func (noWallet) Freeze(userID, reason string) error { return nil }
+func (noWallet) Unfreeze(userID string) error       { return nil }
+
+// Alerts raised in the background are filed while later moves and the end of
+// the session update the table (run with -race)
+func TestMoveAlertsRaisedWhileTableChanges(t *testing.T) {
+	store := NewMemoryStore()
+	d := NewDetector(DefaultConfig(), store, noWallet{})
+	d.Register("ludo_classic", everyMoveRule{})
+	session := &engine.GameSession{
+		SessionID: "raced",
+		GameID:    "ludo_classic",
+		Players:   []*engine.Player{{UserID: "alice"}, {UserID: "bot_1", IsBot: true}},
+	}
+
+	const moves = 50
+	for i := 0; i < moves; i++ {
+		d.HandleMove(session, engine.Move{PlayerID: "alice"})
+	}
+	d.HandleGameEnd(session, &engine.GameResult{WinnerID: "alice"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		alerts, err := store.Alerts(AlertFilter{UserID: "alice"}, 2*moves, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(alerts) == moves {
+			if alerts[0].SessionID != "raced" || alerts[0].GameID != "ludo_classic" {
+				t.Fatalf("alert filed as %s/%s", alerts[0].SessionID, alerts[0].GameID)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d alerts filed, want %d", len(alerts), moves)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,111 @@
+package anticheat
+
+import (
+	"log"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// TimingRule flags players whose moves come faster than a person could make them.
+// How fast a person plays depends on the game, so it is registered per game.
+type TimingRule struct {
+	MinMoves   int           // Moves needed before the average means anything
+	MinAverage time.Duration // Average time between moves below this is bot-like
+	Severity   string
+}
+
+func NewTimingRule(minMoves int, minAverage time.Duration, severity string) *TimingRule {
+	return &TimingRule{MinMoves: minMoves, MinAverage: minAverage, Severity: severity}
+}
+
+func (r *TimingRule) Name() string { return "timing" }
+
+func (r *TimingRule) CheckSession(table *Table, result *engine.GameResult, lookup Lookup) []Alert {
+	alerts := []Alert{}
+	for _, userID := range table.Humans() {
+		moves := table.Moves[userID]
+		if len(moves) < r.MinMoves {
+			continue // Not enough data
+		}
+
+		avg := moves[len(moves)-1].Sub(moves[0]) / time.Duration(len(moves)-1)
+		if avg < r.MinAverage {
+			alerts = append(alerts, Alert{
+				UserID:    userID,
+				AlertType: AlertTypeTiming,
+				Severity:  r.Severity,
+				Details: map[string]interface{}{
+					"avg_move_time_ms": avg.Milliseconds(),
+					"moves":            len(moves),
+					"threshold_ms":     r.MinAverage.Milliseconds(),
+					"reason":           "Suspiciously fast moves (possible bot)",
+				},
+			})
+		}
+	}
+	return alerts
+}
+
+// WinRateRule flags winners whose recent win rate is statistically improbable.
+// Games against bots are not counted.
+type WinRateRule struct {
+	Window     time.Duration
+	MaxGames   int // Most recent games looked at
+	MinGames   int // Games needed before the rate means anything
+	MaxWinRate float64
+}
+
+func NewWinRateRule() *WinRateRule {
+	return &WinRateRule{Window: 30 * 24 * time.Hour, MaxGames: 100, MinGames: 20, MaxWinRate: 0.80}
+}
+
+func (r *WinRateRule) Name() string { return "win_rate" }
+
+func (r *WinRateRule) CheckSession(table *Table, result *engine.GameResult, lookup Lookup) []Alert {
+	alerts := []Alert{}
+	for _, userID := range winners(table, result) {
+		if table.IsBot(userID) {
+			continue
+		}
+
+		sessions, err := lookup.Sessions(userID, time.Now().Add(-r.Window))
+		if err != nil {
+			log.Printf("anticheat: win rate of %s: %v", userID, err)
+			continue
+		}
+		total, wins := 0, 0
+		for _, s := range sessions {
+			if s.HasBots() {
+				continue
+			}
+			total++
+			if s.Won(userID) {
+				wins++
+			}
+			if total == r.MaxGames {
+				break
+			}
+		}
+		if total < r.MinGames {
+			continue // Not enough data
+		}
+
+		winRate := float64(wins) / float64(total)
+		if winRate > r.MaxWinRate {
+			alerts = append(alerts, Alert{
+				UserID:    userID,
+				AlertType: AlertTypeWinRate,
+				Severity:  SeverityMedium,
+				Details: map[string]interface{}{
+					"win_rate":    winRate,
+					"total_games": total,
+					"wins":        wins,
+					"threshold":   r.MaxWinRate,
+					"reason":      "Suspiciously high win rate",
+				},
+			})
+		}
+	}
+	return alerts
+}
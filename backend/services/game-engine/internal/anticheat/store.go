@@ -0,0 +1,469 @@
+package anticheat
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AlertFilter narrows the review queue; empty fields match everything
+type AlertFilter struct {
+	Status   string
+	UserID   string
+	Severity string
+}
+
+func (f AlertFilter) matches(a *Alert) bool {
+	return (f.Status == "" || a.Status == f.Status) &&
+		(f.UserID == "" || a.UserID == f.UserID) &&
+		(f.Severity == "" || a.Severity == f.Severity)
+}
+
+var ErrAlertNotFound = errors.New("alert not found")
+
+// Store persists alerts, the session history rules look at, player clients and
+// suspensions
+type Store interface {
+	Lookup
+
+	SaveSession(summary *SessionSummary) error
+	SaveClient(client Client) error
+
+	// SaveAlert stores a new alert and sets its ID
+	SaveAlert(alert *Alert) error
+	// Alerts lists alerts, newest first
+	Alerts(filter AlertFilter, limit, offset int) ([]*Alert, error)
+	ReviewAlert(alertID int64, reviewerID, status, notes string) (*Alert, error)
+	CountAlerts(userID, severity string, since time.Time) (int, error)
+
+	// Suspend stores a new suspension and sets its ID
+	Suspend(suspension *Suspension) error
+	// Suspension returns the player's active or upheld suspension, or nil
+	Suspension(userID string) (*Suspension, error)
+	// Suspensions lists suspensions, newest first; only active ones if pending
+	Suspensions(pending bool, limit, offset int) ([]*Suspension, error)
+	// ReviewSuspension moves the player's current suspension to status
+	ReviewSuspension(userID, reviewerID, status, notes string) (*Suspension, error)
+}
+
+// MemoryStore keeps everything in process, for running without a database
+type MemoryStore struct {
+	sessions    []*SessionSummary // Oldest first
+	clients     map[string]Client // userID|device|ip -> client
+	alerts      []*Alert          // Oldest first; ID is index+1
+	suspensions []*Suspension
+	mu          sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{clients: make(map[string]Client)}
+}
+
+func (s *MemoryStore) SaveSession(summary *SessionSummary) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = append(s.sessions, summary)
+	return nil
+}
+
+func (s *MemoryStore) Sessions(userID string, since time.Time) ([]*SessionSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions := []*SessionSummary{}
+	for i := len(s.sessions) - 1; i >= 0 && !s.sessions[i].CompletedAt.Before(since); i-- {
+		if contains(s.sessions[i].Players, userID) {
+			sessions = append(sessions, s.sessions[i])
+		}
+	}
+	return sessions, nil
+}
+
+func (s *MemoryStore) SaveClient(client Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[client.UserID+"|"+client.DeviceHash+"|"+client.IPAddress] = client
+	return nil
+}
+
+func (s *MemoryStore) Clients(userIDs []string) ([]Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clients := []Client{}
+	for _, c := range s.clients {
+		if contains(userIDs, c.UserID) {
+			clients = append(clients, c)
+		}
+	}
+	return clients, nil
+}
+
+func (s *MemoryStore) SaveAlert(alert *Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert.ID = int64(len(s.alerts) + 1)
+	copied := *alert
+	s.alerts = append(s.alerts, &copied)
+	return nil
+}
+
+func (s *MemoryStore) Alerts(filter AlertFilter, limit, offset int) ([]*Alert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alerts := []*Alert{}
+	for i := len(s.alerts) - 1; i >= 0 && len(alerts) < limit; i-- {
+		if !filter.matches(s.alerts[i]) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		copied := *s.alerts[i]
+		alerts = append(alerts, &copied)
+	}
+	return alerts, nil
+}
+
+func (s *MemoryStore) ReviewAlert(alertID int64, reviewerID, status, notes string) (*Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if alertID < 1 || alertID > int64(len(s.alerts)) {
+		return nil, ErrAlertNotFound
+	}
+	alert := s.alerts[alertID-1]
+	now := time.Now()
+	alert.Status, alert.ReviewedBy, alert.ReviewerNotes, alert.ReviewedAt = status, reviewerID, notes, &now
+	copied := *alert
+	return &copied, nil
+}
+
+func (s *MemoryStore) CountAlerts(userID, severity string, since time.Time) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, a := range s.alerts {
+		if a.UserID == userID && a.Severity == severity && !a.CreatedAt.Before(since) && a.Status != AlertDismissed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *MemoryStore) Suspend(suspension *Suspension) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suspension.ID = int64(len(s.suspensions) + 1)
+	copied := *suspension
+	s.suspensions = append(s.suspensions, &copied)
+	return nil
+}
+
+func (s *MemoryStore) current(userID string) *Suspension {
+	for i := len(s.suspensions) - 1; i >= 0; i-- {
+		sus := s.suspensions[i]
+		if sus.UserID == userID && sus.Status != SuspensionLifted {
+			return sus
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Suspension(userID string) (*Suspension, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sus := s.current(userID)
+	if sus == nil {
+		return nil, nil
+	}
+	copied := *sus
+	return &copied, nil
+}
+
+func (s *MemoryStore) Suspensions(pending bool, limit, offset int) ([]*Suspension, error) {
+	s.mu.RLock()
+	all := []*Suspension{}
+	for _, sus := range s.suspensions {
+		if !pending || sus.Status == SuspensionActive {
+			copied := *sus
+			all = append(all, &copied)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+	if offset >= len(all) {
+		return []*Suspension{}, nil
+	}
+	all = all[offset:]
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	return all, nil
+}
+
+func (s *MemoryStore) ReviewSuspension(userID, reviewerID, status, notes string) (*Suspension, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sus := s.current(userID)
+	if sus == nil || (status == SuspensionUpheld && sus.Status != SuspensionActive) {
+		return nil, ErrNotSuspended
+	}
+	now := time.Now()
+	sus.Status, sus.ReviewedBy, sus.Notes, sus.ReviewedAt = status, reviewerID, notes, &now
+	copied := *sus
+	return &copied, nil
+}
+
+// PostgresStore keeps alerts in anticheat_alerts, history in anticheat_sessions,
+// clients in device_fingerprints and suspensions in account_suspensions
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+func (s *PostgresStore) SaveSession(summary *SessionSummary) error {
+	moves, err := json.Marshal(summary.Moves)
+	if err != nil {
+		return err
+	}
+	_, err = s.DB.Exec(`
+		INSERT INTO anticheat_sessions (session_id, game_id, players, bots, winners, moves, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (session_id) DO NOTHING
+	`, summary.SessionID, summary.GameID, pq.Array(summary.Players), pq.Array(summary.Bots), pq.Array(summary.Winners), moves, summary.CompletedAt)
+	return err
+}
+
+func (s *PostgresStore) Sessions(userID string, since time.Time) ([]*SessionSummary, error) {
+	rows, err := s.DB.Query(`
+		SELECT session_id, game_id, players, bots, winners, moves, completed_at
+		FROM anticheat_sessions
+		WHERE $1 = ANY(players) AND completed_at >= $2
+		ORDER BY completed_at DESC
+	`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*SessionSummary{}
+	for rows.Next() {
+		summary := &SessionSummary{}
+		var moves []byte
+		if err := rows.Scan(&summary.SessionID, &summary.GameID, pq.Array(&summary.Players), pq.Array(&summary.Bots), pq.Array(&summary.Winners), &moves, &summary.CompletedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(moves, &summary.Moves); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, summary)
+	}
+	return sessions, rows.Err()
+}
+
+func (s *PostgresStore) SaveClient(client Client) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO device_fingerprints (user_id, device_hash, ip_address, user_agent, last_seen)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, device_hash) DO UPDATE
+		SET last_seen = EXCLUDED.last_seen, ip_address = EXCLUDED.ip_address
+	`, client.UserID, client.DeviceHash, client.IPAddress, client.UserAgent, client.LastSeen)
+	return err
+}
+
+func (s *PostgresStore) Clients(userIDs []string) ([]Client, error) {
+	rows, err := s.DB.Query(`
+		SELECT user_id, device_hash, COALESCE(ip_address, ''), COALESCE(user_agent, ''), last_seen
+		FROM device_fingerprints WHERE user_id = ANY($1)
+	`, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients := []Client{}
+	for rows.Next() {
+		var c Client
+		if err := rows.Scan(&c.UserID, &c.DeviceHash, &c.IPAddress, &c.UserAgent, &c.LastSeen); err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return clients, rows.Err()
+}
+
+func (s *PostgresStore) SaveAlert(alert *Alert) error {
+	details, err := json.Marshal(alert.Details)
+	if err != nil {
+		return err
+	}
+	return s.DB.QueryRow(`
+		INSERT INTO anticheat_alerts (user_id, session_id, game_id, alert_type, severity, details, status, created_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, $8)
+		RETURNING id
+	`, alert.UserID, alert.SessionID, alert.GameID, alert.AlertType, alert.Severity, details, alert.Status, alert.CreatedAt).Scan(&alert.ID)
+}
+
+const alertColumns = `id, user_id, COALESCE(session_id, ''), COALESCE(game_id, ''), alert_type, severity, details,
+	status, COALESCE(reviewed_by, ''), COALESCE(reviewer_notes, ''), reviewed_at, created_at`
+
+func scanAlert(row interface{ Scan(...interface{}) error }) (*Alert, error) {
+	a := &Alert{}
+	var details []byte
+	var reviewedAt sql.NullTime
+	err := row.Scan(&a.ID, &a.UserID, &a.SessionID, &a.GameID, &a.AlertType, &a.Severity, &details,
+		&a.Status, &a.ReviewedBy, &a.ReviewerNotes, &reviewedAt, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(details) > 0 {
+		if err := json.Unmarshal(details, &a.Details); err != nil {
+			return nil, err
+		}
+	}
+	if reviewedAt.Valid {
+		a.ReviewedAt = &reviewedAt.Time
+	}
+	return a, nil
+}
+
+func (s *PostgresStore) Alerts(filter AlertFilter, limit, offset int) ([]*Alert, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+alertColumns+`
+		FROM anticheat_alerts
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR user_id = $2) AND ($3 = '' OR severity = $3)
+		ORDER BY created_at DESC, id DESC LIMIT $4 OFFSET $5
+	`, filter.Status, filter.UserID, filter.Severity, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []*Alert{}
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+func (s *PostgresStore) ReviewAlert(alertID int64, reviewerID, status, notes string) (*Alert, error) {
+	a, err := scanAlert(s.DB.QueryRow(`
+		UPDATE anticheat_alerts
+		SET status = $2, reviewed = TRUE, reviewed_by = $3, reviewer_notes = $4, reviewed_at = NOW()
+		WHERE id = $1
+		RETURNING `+alertColumns, alertID, status, reviewerID, notes))
+	if err == sql.ErrNoRows {
+		return nil, ErrAlertNotFound
+	}
+	return a, err
+}
+
+func (s *PostgresStore) CountAlerts(userID, severity string, since time.Time) (int, error) {
+	var count int
+	err := s.DB.QueryRow(`
+		SELECT COUNT(*) FROM anticheat_alerts
+		WHERE user_id = $1 AND severity = $2 AND created_at >= $3 AND status <> $4
+	`, userID, severity, since, AlertDismissed).Scan(&count)
+	return count, err
+}
+
+func (s *PostgresStore) Suspend(suspension *Suspension) error {
+	return s.DB.QueryRow(`
+		INSERT INTO account_suspensions (user_id, reason, status, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, suspension.UserID, suspension.Reason, suspension.Status, suspension.CreatedAt).Scan(&suspension.ID)
+}
+
+const suspensionColumns = `id, user_id, reason, status, COALESCE(reviewed_by, ''), COALESCE(notes, ''), created_at, reviewed_at`
+
+func scanSuspension(row interface{ Scan(...interface{}) error }) (*Suspension, error) {
+	sus := &Suspension{}
+	var reviewedAt sql.NullTime
+	err := row.Scan(&sus.ID, &sus.UserID, &sus.Reason, &sus.Status, &sus.ReviewedBy, &sus.Notes, &sus.CreatedAt, &reviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	if reviewedAt.Valid {
+		sus.ReviewedAt = &reviewedAt.Time
+	}
+	return sus, nil
+}
+
+func (s *PostgresStore) Suspension(userID string) (*Suspension, error) {
+	sus, err := scanSuspension(s.DB.QueryRow(`
+		SELECT `+suspensionColumns+`
+		FROM account_suspensions
+		WHERE user_id = $1 AND status <> $2
+		ORDER BY created_at DESC LIMIT 1
+	`, userID, SuspensionLifted))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return sus, err
+}
+
+func (s *PostgresStore) Suspensions(pending bool, limit, offset int) ([]*Suspension, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+suspensionColumns+`
+		FROM account_suspensions
+		WHERE NOT $1 OR status = $2
+		ORDER BY created_at DESC, id DESC LIMIT $3 OFFSET $4
+	`, pending, SuspensionActive, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suspensions := []*Suspension{}
+	for rows.Next() {
+		sus, err := scanSuspension(rows)
+		if err != nil {
+			return nil, err
+		}
+		suspensions = append(suspensions, sus)
+	}
+	return suspensions, rows.Err()
+}
+
+func (s *PostgresStore) ReviewSuspension(userID, reviewerID, status, notes string) (*Suspension, error) {
+	// Only a pending suspension can be upheld; an upheld one can still be lifted
+	from := []string{SuspensionActive}
+	if status == SuspensionLifted {
+		from = append(from, SuspensionUpheld)
+	}
+	sus, err := scanSuspension(s.DB.QueryRow(`
+		UPDATE account_suspensions
+		SET status = $2, reviewed_by = $3, notes = $4, reviewed_at = NOW()
+		WHERE id = (
+			SELECT id FROM account_suspensions
+			WHERE user_id = $1 AND status = ANY($5)
+			ORDER BY created_at DESC LIMIT 1
+		)
+		RETURNING `+suspensionColumns, userID, status, reviewerID, notes, pq.Array(from)))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotSuspended
+	}
+	return sus, err
+}
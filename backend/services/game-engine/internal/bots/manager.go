@@ -0,0 +1,229 @@
+// Package bots seats server-side bots at skill-game tables that cannot fill
+// with real players and plays their moves through the session manager.
+package bots
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/session"
+)
+
+// StrategyFactory makes a strategy for one bot seat, replacing the game's own (see RegisterStrategy)
+type StrategyFactory func(level engine.BotLevel) engine.BotStrategy
+
+// Config controls how bots play
+type Config struct {
+	// Pause before each move, so bots play at a human pace
+	ThinkTime map[engine.BotLevel]time.Duration
+	// A bot gives up on a session in which it has had nothing to do for this long
+	IdleTimeout time.Duration
+	// Players rated below EasyBelow get easy bots, at or above HardFrom hard ones
+	EasyBelow float64
+	HardFrom  float64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		ThinkTime: map[engine.BotLevel]time.Duration{
+			engine.BotEasy:   3 * time.Second,
+			engine.BotMedium: 2 * time.Second,
+			engine.BotHard:   1500 * time.Millisecond,
+		},
+		IdleTimeout: 10 * time.Minute,
+		EasyBelow:   1400,
+		HardFrom:    1650,
+	}
+}
+
+// Manager hands out bot seats and plays them
+type Manager struct {
+	cfg        Config
+	sessions   *session.SessionManager
+	strategies map[string]StrategyFactory // gameID -> override
+	levels     map[string]engine.BotLevel // botID -> level, until the bot is seated
+	seq        atomic.Uint64
+	mu         sync.Mutex
+}
+
+func NewManager(cfg Config, sessions *session.SessionManager) *Manager {
+	return &Manager{
+		cfg:        cfg,
+		sessions:   sessions,
+		strategies: make(map[string]StrategyFactory),
+		levels:     make(map[string]engine.BotLevel),
+	}
+}
+
+// RegisterStrategy plugs in a strategy for a game instead of the game's own bots
+func (m *Manager) RegisterStrategy(gameID string, factory StrategyFactory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.strategies[gameID] = factory
+}
+
+// Supports reports whether bots can play gameID
+func (m *Manager) Supports(gameID string) bool {
+	m.mu.Lock()
+	_, ok := m.strategies[gameID]
+	m.mu.Unlock()
+	if ok {
+		return true
+	}
+
+	game, err := registry.GetRegistry().GetGame(gameID)
+	if err != nil {
+		return false
+	}
+	_, ok = game.(engine.BotGame)
+	return ok
+}
+
+// LevelFor picks a bot level to match a player of the given rating
+func (m *Manager) LevelFor(rating float64) engine.BotLevel {
+	switch {
+	case rating < m.cfg.EasyBelow:
+		return engine.BotEasy
+	case rating >= m.cfg.HardFrom:
+		return engine.BotHard
+	default:
+		return engine.BotMedium
+	}
+}
+
+// Reserve creates count bot IDs for gameID at a level suited to rating. Seat
+// them with session.WithBots and call Play once the session exists.
+func (m *Manager) Reserve(gameID string, count int, rating float64) ([]string, error) {
+	if !m.Supports(gameID) {
+		return nil, fmt.Errorf("bots cannot play %s", gameID)
+	}
+
+	level := m.LevelFor(rating)
+	botIDs := make([]string, count)
+	m.mu.Lock()
+	for i := range botIDs {
+		botIDs[i] = fmt.Sprintf("%s%d_%d", engine.BotIDPrefix, time.Now().UnixNano(), m.seq.Add(1))
+		m.levels[botIDs[i]] = level
+	}
+	m.mu.Unlock()
+	return botIDs, nil
+}
+
+// Release forgets reserved bots that were never seated
+func (m *Manager) Release(botIDs []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range botIDs {
+		delete(m.levels, id)
+	}
+}
+
+// Play starts playing every bot seated in the session
+func (m *Manager) Play(sessionID string) error {
+	var gameID string
+	var botIDs []string
+	err := m.sessions.ReadSession(sessionID, func(s *engine.GameSession) {
+		gameID = s.GameID
+		for _, p := range s.Players {
+			if p.IsBot {
+				botIDs = append(botIDs, p.UserID)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(botIDs) == 0 {
+		return errors.New("no bots in session")
+	}
+
+	players := make([]*player, 0, len(botIDs))
+	for _, botID := range botIDs {
+		m.mu.Lock()
+		level, ok := m.levels[botID]
+		delete(m.levels, botID)
+		m.mu.Unlock()
+		if !ok {
+			level = engine.BotMedium
+		}
+
+		strategy, err := m.newStrategy(gameID, level)
+		if err != nil {
+			return err
+		}
+		players = append(players, &player{id: botID, level: level, strategy: strategy})
+	}
+
+	go m.run(sessionID, players)
+	return nil
+}
+
+func (m *Manager) newStrategy(gameID string, level engine.BotLevel) (engine.BotStrategy, error) {
+	m.mu.Lock()
+	factory, ok := m.strategies[gameID]
+	m.mu.Unlock()
+	if ok {
+		return factory(level), nil
+	}
+
+	game, err := registry.GetRegistry().GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	botGame, ok := game.(engine.BotGame)
+	if !ok {
+		return nil, fmt.Errorf("bots cannot play %s", gameID)
+	}
+	return botGame.NewBot(level), nil
+}
+
+type player struct {
+	id       string
+	level    engine.BotLevel
+	strategy engine.BotStrategy
+}
+
+// run plays the session's bots until it completes or they have been idle too long
+func (m *Manager) run(sessionID string, players []*player) {
+	think := m.cfg.ThinkTime[players[0].level]
+	lastMove := time.Now()
+
+	for time.Since(lastMove) < m.cfg.IdleTimeout {
+		time.Sleep(think)
+
+		var moves []engine.Move
+		completed := false
+		err := m.sessions.ReadSession(sessionID, func(s *engine.GameSession) {
+			if s.Status == "COMPLETED" {
+				completed = true
+				return
+			}
+			for _, p := range players {
+				move, err := p.strategy.NextMove(s, p.id)
+				if err != nil {
+					log.Printf("bot %s in %s: %v", p.id, sessionID, err)
+					continue
+				}
+				if move != nil {
+					moves = append(moves, *move)
+				}
+			}
+		})
+		if err != nil || completed {
+			return
+		}
+
+		for _, move := range moves {
+			// The state may have moved on since the bot looked; it tries again next time
+			if _, err := m.sessions.ProcessMove(sessionID, move); err == nil {
+				lastMove = time.Now()
+			}
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package engine
+
+import "strings"
+
+// BotIDPrefix starts the user ID of every server-side bot. It only names bots:
+// a player is a bot if their session seated them as one (Player.IsBot), and
+// user IDs with this prefix are refused from clients (see ReservedUserID).
+const BotIDPrefix = "bot_"
+
+// ReservedUserID reports whether userID is in the namespace kept for bots, and
+// so must not be accepted from a client
+func ReservedUserID(userID string) bool {
+	return strings.HasPrefix(userID, BotIDPrefix)
+}
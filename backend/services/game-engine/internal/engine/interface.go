@@ -1,8 +1,6 @@
 package engine
 
-import (
-	"time"
-)
+import "time"
 
 // GameType defines the category of game
 type GameType string
@@ -33,6 +31,112 @@ type IGame interface {
 	GetState(session *GameSession) interface{}
 }
 
+// SharedRoundGame is implemented by table games where every player at the table
+// plays the same round (e.g. Crash, Andar Bahar). The session manager keeps one open
+// table per game for them instead of a session per player.
+type SharedRoundGame interface {
+	IGame
+	IsSharedRound() bool
+}
+
+// CertifiableGame is implemented by games of chance that the RTP simulator
+// (cmd/simulate) can certify. bet has the same shape as the Data of the game's
+// betting move.
+type CertifiableGame interface {
+	IGame
+
+	// TheoreticalRTP is the expected return per unit staked on bet
+	TheoreticalRTP(bet map[string]interface{}) (float64, error)
+
+	// SimulateRound settles one round of bet from the given seed without touching
+	// a wallet or session, returning the total staked and the total returned
+	SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (stake, payout float64, err error)
+}
+
+// ReplayableGame is implemented by games whose sessions can be replayed move by
+// move and checked for determinism. Such a game takes every random input through
+// Draw, moves no money in a session with Replay set, and keeps clocks and timers
+// out of its snapshot.
+type ReplayableGame interface {
+	IGame
+
+	// Snapshot returns the whole state of the session, hidden cards included
+	Snapshot(session *GameSession) interface{}
+}
+
+// ViewableGame is implemented by games where each player sees more than the
+// shared state, e.g. their own cards. Views are read-only: they change nothing
+// and are never recorded as moves.
+type ViewableGame interface {
+	IGame
+
+	// PlayerView returns what userID, seated in the session, may see of it
+	PlayerView(session *GameSession, userID string) (interface{}, error)
+}
+
+// RNG records a session's random inputs so a replay can feed the same ones back
+type RNG interface {
+	// Replay fills into with the next recorded input and reports whether there was one
+	Replay(into interface{}) bool
+	// Record keeps an input that was just drawn
+	Record(value interface{})
+}
+
+// Draw returns a random input made by draw, or the recorded one when replaying
+func Draw[T any](rng RNG, draw func() T) T {
+	var value T
+	if rng != nil && rng.Replay(&value) {
+		return value
+	}
+	value = draw()
+	if rng != nil {
+		rng.Record(value)
+	}
+	return value
+}
+
+// TryDraw is Draw for inputs that can fail to be made. A failed draw isn't
+// recorded, so a replay sees only the input that was used.
+func TryDraw[T any](rng RNG, draw func() (T, error)) (T, error) {
+	var value T
+	if rng != nil && rng.Replay(&value) {
+		return value, nil
+	}
+	value, err := draw()
+	if err != nil {
+		return value, err
+	}
+	if rng != nil {
+		rng.Record(value)
+	}
+	return value, nil
+}
+
+
+// BotLevel is a bot's playing strength
+type BotLevel string
+
+const (
+	BotEasy   BotLevel = "EASY"
+	BotMedium BotLevel = "MEDIUM"
+	BotHard   BotLevel = "HARD"
+)
+
+// BotStrategy decides a bot's moves. It is called with the session read-locked
+// and must not modify it.
+type BotStrategy interface {
+	// NextMove returns the bot's next move, or nil while it has nothing to do
+	NextMove(session *GameSession, botID string) (*Move, error)
+}
+
+// BotGame is implemented by games whose tables can be filled with server-side bots
+type BotGame interface {
+	IGame
+
+	// NewBot returns a strategy for one bot seat at the given level
+	NewBot(level BotLevel) BotStrategy
+}
+
 // GameSession represents an active game instance
 type GameSession struct {
 	SessionID string
@@ -40,7 +144,14 @@ type GameSession struct {
 	Players   []*Player
 	State     interface{} // Game-specific state
 	Status    string
+	Shared    bool // One table for all players; see SharedRoundGame
 	EntryFee  float64
+	Prepaid   bool // Entry fees were collected before the session started (matchmaking)
+	Replay    bool // Re-run from a recording: the game must not move money
+	RNG       RNG  `json:"-"` // Random inputs go through here when set (see Draw)
+	// Ended, when set, is called by a game that ends without a move (e.g. on a
+	// turn timer) so the session completes. It must not be called under a game lock.
+	Ended     func() `json:"-"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -51,6 +162,7 @@ type Player struct {
 	Username string
 	Score    int
 	IsTurn   bool
+	IsBot    bool // Server-side bot filling an empty seat
 }
 
 // Move represents an action taken by a player
@@ -0,0 +1,115 @@
+package fairness
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrHashChainExhausted = errors.New("hash chain exhausted")
+
+// HashChain is a pre-generated sequence of server seeds where every seed is the
+// SHA-256 of the one played after it. Publishing the hash of the first seed up front
+// commits the operator to every future round: once round N is revealed, anyone can
+// check that hashing it gives round N-1's seed.
+type HashChain struct {
+	seeds      []string // Play order: seeds[0] is played first
+	next       int
+	createdAt  time.Time
+	Commitment string // SHA-256 of seeds[0], published before the first round
+	Salt       string // Public client seed mixed into every round
+	mu         sync.Mutex
+}
+
+// ChainState is a HashChain as stored: the terminal seed regenerates every other
+// seed, so it stays secret until the chain is played out
+type ChainState struct {
+	OwnerID      string
+	TerminalSeed string
+	Length       int
+	Salt         string
+	Commitment   string
+	Position     int // Rounds handed out so far
+	CreatedAt    time.Time
+}
+
+// NewHashChain generates a chain of the given length from a crypto/rand terminal seed
+func NewHashChain(length int, salt string) *HashChain {
+	if salt == "" {
+		salt = GenerateClientSeed()
+	}
+	return buildHashChain(GenerateServerSeed(), length, salt, time.Now())
+}
+
+// RestoreHashChain rebuilds a stored chain, resuming after its last handed out round
+func RestoreHashChain(state *ChainState) (*HashChain, error) {
+	c := buildHashChain(state.TerminalSeed, state.Length, state.Salt, state.CreatedAt)
+	if c.Commitment != state.Commitment {
+		return nil, errors.New("hash chain does not match its commitment")
+	}
+	c.next = min(state.Position, state.Length)
+	return c, nil
+}
+
+func buildHashChain(terminal string, length int, salt string, createdAt time.Time) *HashChain {
+	seeds := make([]string, length)
+	seed := terminal
+	for i := length - 1; i >= 0; i-- {
+		seeds[i] = seed
+		seed = HashServerSeed(seed)
+	}
+	return &HashChain{
+		seeds:      seeds,
+		createdAt:  createdAt,
+		Commitment: seed,
+		Salt:       salt,
+	}
+}
+
+// State returns the chain in its stored form
+func (c *HashChain) State(ownerID string) *ChainState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state := &ChainState{
+		OwnerID:    ownerID,
+		Length:     len(c.seeds),
+		Salt:       c.Salt,
+		Commitment: c.Commitment,
+		Position:   c.next,
+		CreatedAt:  c.createdAt,
+	}
+	if len(c.seeds) > 0 {
+		state.TerminalSeed = c.seeds[len(c.seeds)-1]
+	}
+	return state
+}
+
+// Next returns the seed for the next round and its 1-based position in the chain
+func (c *HashChain) Next() (string, int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.next >= len(c.seeds) {
+		return "", 0, ErrHashChainExhausted
+	}
+	seed := c.seeds[c.next]
+	c.next++
+	return seed, c.next, nil
+}
+
+// Remaining returns how many rounds can still be played from this chain
+func (c *HashChain) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.seeds) - c.next
+}
+
+// VerifyChainLink reports whether seed is the round played right after previous
+// (i.e. SHA-256(seed) == previous). Use the chain commitment as previous for round 1.
+func VerifyChainLink(seed, previous string) bool {
+	hash := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(hash[:]) == previous
+}
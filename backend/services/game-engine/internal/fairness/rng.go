@@ -2,18 +2,31 @@ package fairness
 
 import (
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strconv"
 )
 
-// GenerateServerSeed creates a new random server seed
+// GenerateServerSeed creates a new random server seed (32 bytes from crypto/rand)
 func GenerateServerSeed() string {
-	// In production, use crypto/rand
-	// For demo, we'll use a simple hash of time
-	// TODO: Replace with secure random generation
-	return hex.EncodeToString(sha256.New().Sum([]byte("random_seed")))
+	return randomHex(32)
+}
+
+// GenerateClientSeed creates a default client seed for users who have not chosen one
+func GenerateClientSeed() string {
+	return randomHex(16)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand never fails on supported platforms; a predictable seed is worse than a crash
+		panic(fmt.Sprintf("fairness: crypto/rand unavailable: %v", err))
+	}
+	return hex.EncodeToString(buf)
 }
 
 // HashServerSeed creates the public hash of the server seed
@@ -44,6 +57,23 @@ func GenerateFloat(serverSeed, clientSeed string, nonce int) float64 {
 	return float64(decimalValue) / 4294967296.0
 }
 
+// GenerateFloats returns count floats for a single round (mines layout, plinko path, ...).
+// The first is GenerateFloat itself; each following one uses the same HMAC with a
+// cursor appended to the message, i.e. "client_seed:nonce:cursor".
+func GenerateFloats(serverSeed, clientSeed string, nonce, count int) []float64 {
+	floats := make([]float64, count)
+	if count == 0 {
+		return floats
+	}
+
+	floats[0] = GenerateFloat(serverSeed, clientSeed, nonce)
+	roundSeed := fmt.Sprintf("%s:%d", clientSeed, nonce)
+	for cursor := 1; cursor < count; cursor++ {
+		floats[cursor] = GenerateFloat(serverSeed, roundSeed, cursor)
+	}
+	return floats
+}
+
 // CrashPoint calculates the crash multiplier from the hash
 // Formula: E = 2^52, h = hash; crash = (100 * E - h) / (E - h)
 // Simplified Stake formula: 0.99 / (1 - X)
@@ -58,5 +88,6 @@ func CalculateCrashPoint(serverSeed, clientSeed string, nonce int) float64 {
 		multiplier = 1.00
 	}
 
-	return multiplier
+	// Round down to the 2 decimals shown to players
+	return math.Floor(multiplier*100) / 100
 }
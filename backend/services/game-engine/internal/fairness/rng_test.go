@@ -0,0 +1,145 @@
+package fairness
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Fixed seeds for the known vectors below. The expected values were computed
+// independently of this package (HMAC-SHA256 over "client_seed:nonce", first 4
+// bytes / 2^32), so a change to the algorithm shows up here before it breaks
+// players' ability to verify old rounds.
+const (
+	vectorServerSeed = "b3f1c4a9d2e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2"
+	vectorClientSeed = "playkaro"
+)
+
+func TestHashServerSeed(t *testing.T) {
+	// SHA-256 of "abc" from FIPS 180-2
+	if got := HashServerSeed("abc"); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("HashServerSeed(abc) = %s", got)
+	}
+	if got := HashServerSeed(vectorServerSeed); got != "b475e3b65b888307a7fe523b7c0c224423af23d97b8fa1f6d08291c5894240ff" {
+		t.Errorf("HashServerSeed(vector) = %s", got)
+	}
+}
+
+func TestGenerateFloatVectors(t *testing.T) {
+	tests := []struct {
+		nonce int
+		float float64
+		dice  float64
+		crash float64
+		slot  int
+	}{
+		{1, 0.46521169203333557, 46.52, 1.85, 17},
+		{2, 0.17647182825021446, 17.64, 1.20, 6},
+		{42, 0.3937253085896373, 39.37, 1.63, 14},
+	}
+	for _, tt := range tests {
+		if got := GenerateFloat(vectorServerSeed, vectorClientSeed, tt.nonce); got != tt.float {
+			t.Errorf("nonce %d: GenerateFloat = %v, want %v", tt.nonce, got, tt.float)
+		}
+		if got := DiceRoll(vectorServerSeed, vectorClientSeed, tt.nonce); got != tt.dice {
+			t.Errorf("nonce %d: DiceRoll = %v, want %v", tt.nonce, got, tt.dice)
+		}
+		if got := CalculateCrashPoint(vectorServerSeed, vectorClientSeed, tt.nonce); got != tt.crash {
+			t.Errorf("nonce %d: CalculateCrashPoint = %v, want %v", tt.nonce, got, tt.crash)
+		}
+		if got := RoulettePocket(vectorServerSeed, vectorClientSeed, tt.nonce); got != tt.slot {
+			t.Errorf("nonce %d: RoulettePocket = %d, want %d", tt.nonce, got, tt.slot)
+		}
+	}
+}
+
+func TestGenerateFloatsVector(t *testing.T) {
+	want := []float64{0.9365954687818885, 0.05383458407595754, 0.08244165172800422}
+	if got := GenerateFloats(vectorServerSeed, vectorClientSeed, 7, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("GenerateFloats = %v, want %v", got, want)
+	}
+}
+
+func TestShuffleVectors(t *testing.T) {
+	deck := Shuffle(vectorServerSeed, vectorClientSeed, 3, DeckSize)
+	if want := []int{22, 18, 16, 13, 23, 9, 15, 34, 8, 49}; !reflect.DeepEqual(deck[:10], want) {
+		t.Errorf("Shuffle deck starts %v, want %v", deck[:10], want)
+	}
+	seen := make(map[int]bool, DeckSize)
+	for _, card := range deck {
+		if card < 0 || card >= DeckSize || seen[card] {
+			t.Fatalf("Shuffle is not a permutation: %v", deck)
+		}
+		seen[card] = true
+	}
+
+	if got, want := MinePositions(vectorServerSeed, vectorClientSeed, 5, 5), []int{2, 5, 15, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MinePositions = %v, want %v", got, want)
+	}
+}
+
+func TestPlinkoPathVector(t *testing.T) {
+	path, bucket := PlinkoPath(vectorServerSeed, vectorClientSeed, 9, 16)
+	if want := []int{1, 0, 1, 0, 1, 0, 1, 0, 1, 1, 0, 0, 0, 0, 1, 0}; !reflect.DeepEqual(path, want) {
+		t.Errorf("PlinkoPath = %v, want %v", path, want)
+	}
+	if bucket != 7 {
+		t.Errorf("bucket = %d, want 7", bucket)
+	}
+}
+
+func TestVerifyMatchesGames(t *testing.T) {
+	res, err := Verify(VerifyRequest{
+		Game:           GameCrash,
+		ServerSeed:     vectorServerSeed,
+		ServerSeedHash: HashServerSeed(vectorServerSeed),
+		ClientSeed:     vectorClientSeed,
+		Nonce:          1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.HashMatches || res.Outcome != 1.85 {
+		t.Errorf("Verify crash = %+v", res)
+	}
+
+	res, err = Verify(VerifyRequest{Game: GameDice, ServerSeed: vectorServerSeed, ServerSeedHash: "not-the-hash", ClientSeed: vectorClientSeed, Nonce: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.HashMatches || res.Outcome != 17.64 {
+		t.Errorf("Verify dice = %+v", res)
+	}
+
+	if _, err := Verify(VerifyRequest{Game: GameMines, ServerSeed: vectorServerSeed, Mines: MinesGridSize}); err != ErrInvalidParams {
+		t.Errorf("Verify mines with a full board: err = %v", err)
+	}
+	for _, rows := range []int{0, PlinkoMinRows - 1, PlinkoMaxRows + 1, 1 << 30} {
+		if _, err := Verify(VerifyRequest{Game: GamePlinko, ServerSeed: vectorServerSeed, Rows: rows}); err != ErrInvalidParams {
+			t.Errorf("Verify plinko with %d rows: err = %v", rows, err)
+		}
+	}
+	if _, err := Verify(VerifyRequest{Game: "poker"}); err != ErrUnknownGame {
+		t.Errorf("Verify unknown game: err = %v", err)
+	}
+}
+
+func TestHashChainLinks(t *testing.T) {
+	chain := NewHashChain(5, "salt")
+	previous := chain.Commitment
+	for i := 1; i <= 5; i++ {
+		seed, nonce, err := chain.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nonce != i {
+			t.Errorf("round %d: nonce %d", i, nonce)
+		}
+		if !VerifyChainLink(seed, previous) {
+			t.Fatalf("round %d does not hash to the previous round", i)
+		}
+		previous = seed
+	}
+	if _, _, err := chain.Next(); err != ErrHashChainExhausted {
+		t.Errorf("exhausted chain: err = %v", err)
+	}
+}
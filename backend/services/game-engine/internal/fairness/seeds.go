@@ -0,0 +1,202 @@
+package fairness
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSeedNotRevealed   = errors.New("server seed not revealed yet, rotate your seed pair first")
+	ErrInvalidClientSeed = errors.New("client seed must be 1-64 characters")
+)
+
+// SeedPair is the committed seed state for one owner (a user, or a shared game table)
+type SeedPair struct {
+	ServerSeed     string    `json:"-"`
+	ServerSeedHash string    `json:"server_seed_hash"`
+	ClientSeed     string    `json:"client_seed"`
+	Nonce          int       `json:"nonce"` // Nonce of the last round played with this pair
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RoundSeed is everything needed to compute (and later verify) a single round
+type RoundSeed struct {
+	ServerSeed     string `json:"-"`
+	ServerSeedHash string `json:"server_seed_hash"`
+	ClientSeed     string `json:"client_seed"`
+	Nonce          int    `json:"nonce"`
+}
+
+// RevealedSeed is a retired seed pair whose server seed is now public
+type RevealedSeed struct {
+	ServerSeed     string    `json:"server_seed"`
+	ServerSeedHash string    `json:"server_seed_hash"`
+	ClientSeed     string    `json:"client_seed"`
+	LastNonce      int       `json:"last_nonce"`
+	RevealedAt     time.Time `json:"revealed_at"`
+}
+
+// SeedManager owns the active seed pair of every owner. Pairs and revealed
+// seeds are kept in a SeedStore; the manager caches active pairs and serializes
+// nonce increments.
+type SeedManager struct {
+	store  SeedStore
+	active map[string]*SeedPair
+	mu     sync.Mutex
+}
+
+// NewSeedManager creates a manager backed by store, or by a MemoryStore if nil
+func NewSeedManager(store SeedStore) *SeedManager {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &SeedManager{
+		store:  store,
+		active: make(map[string]*SeedPair),
+	}
+}
+
+func newSeedPair(clientSeed string) *SeedPair {
+	serverSeed := GenerateServerSeed()
+	if clientSeed == "" {
+		clientSeed = GenerateClientSeed()
+	}
+	return &SeedPair{
+		ServerSeed:     serverSeed,
+		ServerSeedHash: HashServerSeed(serverSeed),
+		ClientSeed:     clientSeed,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// pair returns the owner's active pair, loading or creating it on first use. Caller must hold mu.
+func (m *SeedManager) pair(ownerID string) (*SeedPair, error) {
+	if p, ok := m.active[ownerID]; ok {
+		return p, nil
+	}
+
+	p, err := m.store.LoadActive(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = newSeedPair("")
+		if err := m.store.SaveActive(ownerID, p); err != nil {
+			return nil, err
+		}
+	}
+	m.active[ownerID] = p
+	return p, nil
+}
+
+// GetActive returns a copy of the owner's active seed pair (server seed stays hidden in JSON)
+func (m *SeedManager) GetActive(ownerID string) (SeedPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pair(ownerID)
+	if err != nil {
+		return SeedPair{}, err
+	}
+	return *p, nil
+}
+
+// NextRound increments the nonce, stores it and returns the seeds for the round about
+// to be played. On error the round must not be played: a nonce that isn't stored
+// could repeat after a restart.
+func (m *SeedManager) NextRound(ownerID string) (RoundSeed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.pair(ownerID)
+	if err != nil {
+		return RoundSeed{}, err
+	}
+	p.Nonce++
+	seed := RoundSeed{
+		ServerSeed:     p.ServerSeed,
+		ServerSeedHash: p.ServerSeedHash,
+		ClientSeed:     p.ClientSeed,
+		Nonce:          p.Nonce,
+	}
+	return seed, m.store.SaveActive(ownerID, p)
+}
+
+// Rotate retires the active pair, revealing its server seed, and commits to a new one.
+// An empty clientSeed keeps the current client seed.
+func (m *SeedManager) Rotate(ownerID, clientSeed string) (*RevealedSeed, SeedPair, error) {
+	if len(clientSeed) > 64 {
+		return nil, SeedPair{}, ErrInvalidClientSeed
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, err := m.pair(ownerID)
+	if err != nil {
+		return nil, SeedPair{}, err
+	}
+	if clientSeed == "" {
+		clientSeed = old.ClientSeed
+	}
+
+	revealed := &RevealedSeed{
+		ServerSeed:     old.ServerSeed,
+		ServerSeedHash: old.ServerSeedHash,
+		ClientSeed:     old.ClientSeed,
+		LastNonce:      old.Nonce,
+		RevealedAt:     time.Now(),
+	}
+	next := newSeedPair(clientSeed)
+	if err := m.store.Retire(ownerID, revealed, next); err != nil {
+		return nil, SeedPair{}, err
+	}
+	m.active[ownerID] = next
+
+	return revealed, *next, nil
+}
+
+// SetClientSeed changes the client seed. Like Rotate, this reveals the current server seed,
+// so a client seed can never be chosen after the server seed outcomes are known.
+func (m *SeedManager) SetClientSeed(ownerID, clientSeed string) (*RevealedSeed, SeedPair, error) {
+	if clientSeed == "" {
+		return nil, SeedPair{}, ErrInvalidClientSeed
+	}
+	return m.Rotate(ownerID, clientSeed)
+}
+
+// Reveal archives a seed played outside a seed pair, such as a Crash hash-chain
+// round, so it can be looked up like any rotated seed
+func (m *SeedManager) Reveal(revealed *RevealedSeed) error {
+	return m.store.SaveRevealed(revealed)
+}
+
+// LookupRevealed returns the server seed for a hash once it has been revealed
+func (m *SeedManager) LookupRevealed(serverSeedHash string) (*RevealedSeed, error) {
+	return m.store.LookupRevealed(serverSeedHash)
+}
+
+// HashChain resumes the owner's stored chain, or generates and stores a new one.
+// A resumed chain continues after the last round handed out, so no seed is
+// played twice.
+func (m *SeedManager) HashChain(ownerID string, length int) (*HashChain, error) {
+	state, err := m.store.LoadChain(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil {
+		return RestoreHashChain(state)
+	}
+
+	chain := NewHashChain(length, "")
+	if err := m.store.SaveChain(chain.State(ownerID)); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// SaveChain stores how far the owner's chain has been played
+func (m *SeedManager) SaveChain(ownerID string, chain *HashChain) error {
+	return m.store.SaveChain(chain.State(ownerID))
+}
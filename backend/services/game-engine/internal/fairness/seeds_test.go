@@ -0,0 +1,121 @@
+package fairness
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeedManagerResumesFromStore(t *testing.T) {
+	store := NewMemoryStore()
+	m := NewSeedManager(store)
+
+	first, err := m.NextRound("user_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := m.NextRound("user_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Nonce != 1 || second.Nonce != 2 || first.ServerSeed != second.ServerSeed {
+		t.Fatalf("rounds = %+v, %+v", first, second)
+	}
+
+	// A restarted service continues the same pair without reusing a nonce
+	restarted := NewSeedManager(store)
+	third, err := restarted.NextRound("user_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.ServerSeed != first.ServerSeed || third.Nonce != 3 {
+		t.Errorf("after restart = %+v, want nonce 3 on the same seed", third)
+	}
+}
+
+func TestSeedManagerRevealsAcrossRestart(t *testing.T) {
+	store := NewMemoryStore()
+	m := NewSeedManager(store)
+
+	played, err := m.NextRound("user_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.LookupRevealed(played.ServerSeedHash); err != ErrSeedNotRevealed {
+		t.Fatalf("active seed looked up: err = %v", err)
+	}
+
+	revealed, active, err := m.Rotate("user_1", "my-seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revealed.ServerSeed != played.ServerSeed || revealed.LastNonce != 1 {
+		t.Errorf("revealed = %+v", revealed)
+	}
+	if active.ClientSeed != "my-seed" || active.Nonce != 0 || active.ServerSeedHash == played.ServerSeedHash {
+		t.Errorf("new pair = %+v", active)
+	}
+
+	restarted := NewSeedManager(store)
+	found, err := restarted.LookupRevealed(played.ServerSeedHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ServerSeed != played.ServerSeed || found.ClientSeed != played.ClientSeed {
+		t.Errorf("looked up %+v", found)
+	}
+	got, err := restarted.GetActive("user_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ServerSeedHash != active.ServerSeedHash {
+		t.Errorf("active pair after restart = %s, want %s", got.ServerSeedHash, active.ServerSeedHash)
+	}
+}
+
+func TestSeedManagerRevealsChainRounds(t *testing.T) {
+	m := NewSeedManager(nil)
+	chain := NewHashChain(3, "salt")
+	seed, nonce, _ := chain.Next()
+
+	if err := m.Reveal(&RevealedSeed{ServerSeed: seed, ServerSeedHash: HashServerSeed(seed), ClientSeed: chain.Salt, LastNonce: nonce, RevealedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	found, err := m.LookupRevealed(HashServerSeed(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ServerSeed != seed || found.ClientSeed != "salt" || found.LastNonce != 1 {
+		t.Errorf("looked up %+v", found)
+	}
+}
+
+func TestMemoryStoreBoundsRevealed(t *testing.T) {
+	store := NewMemoryStore()
+	store.MaxRevealed = 2
+	for _, seed := range []string{"a", "b", "c"} {
+		store.SaveRevealed(&RevealedSeed{ServerSeed: seed, ServerSeedHash: HashServerSeed(seed)})
+	}
+
+	if _, err := store.LookupRevealed(HashServerSeed("a")); err != ErrSeedNotRevealed {
+		t.Errorf("oldest seed kept: err = %v", err)
+	}
+	for _, seed := range []string{"b", "c"} {
+		if _, err := store.LookupRevealed(HashServerSeed(seed)); err != nil {
+			t.Errorf("seed %s: %v", seed, err)
+		}
+	}
+}
+
+func TestClientSeedValidation(t *testing.T) {
+	m := NewSeedManager(nil)
+	if _, _, err := m.SetClientSeed("user_1", ""); err != ErrInvalidClientSeed {
+		t.Errorf("empty client seed: err = %v", err)
+	}
+	long := make([]byte, 65)
+	for i := range long {
+		long[i] = 'x'
+	}
+	if _, _, err := m.Rotate("user_1", string(long)); err != ErrInvalidClientSeed {
+		t.Errorf("65 character client seed: err = %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package fairness
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// SeedStore persists active seed pairs and revealed seeds, so past rounds stay
+// verifiable and nonces never repeat across restarts
+type SeedStore interface {
+	// LoadActive returns nil if the owner has no active pair yet
+	LoadActive(ownerID string) (*SeedPair, error)
+	// SaveActive stores the owner's active pair, including its current nonce
+	SaveActive(ownerID string, pair *SeedPair) error
+	// Retire reveals the owner's active pair and replaces it with next, atomically
+	Retire(ownerID string, revealed *RevealedSeed, next *SeedPair) error
+	// SaveRevealed archives a seed revealed outside a seed pair (e.g. a Crash chain round)
+	SaveRevealed(revealed *RevealedSeed) error
+	// LookupRevealed returns ErrSeedNotRevealed for unknown or unrevealed hashes
+	LookupRevealed(serverSeedHash string) (*RevealedSeed, error)
+	// SaveChain stores a hash chain, or how far it has been played
+	SaveChain(state *ChainState) error
+	// LoadChain returns the owner's newest chain with rounds left, or nil
+	LoadChain(ownerID string) (*ChainState, error)
+}
+
+// DefaultMemoryRevealed is how many revealed seeds a MemoryStore keeps
+const DefaultMemoryRevealed = 100000
+
+// MemoryStore keeps seeds in process, for running without a database. Only the
+// newest MaxRevealed revealed seeds are kept.
+type MemoryStore struct {
+	MaxRevealed int
+
+	active   map[string]*SeedPair
+	revealed map[string]*RevealedSeed
+	order    []string               // Revealed hashes, oldest first
+	chains   map[string]*ChainState // Owner -> newest chain
+	mu       sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		MaxRevealed: DefaultMemoryRevealed,
+		active:      make(map[string]*SeedPair),
+		revealed:    make(map[string]*RevealedSeed),
+		chains:      make(map[string]*ChainState),
+	}
+}
+
+func (s *MemoryStore) LoadActive(ownerID string) (*SeedPair, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.active[ownerID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (s *MemoryStore) SaveActive(ownerID string, pair *SeedPair) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *pair
+	s.active[ownerID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) Retire(ownerID string, revealed *RevealedSeed, next *SeedPair) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reveal(revealed)
+	copied := *next
+	s.active[ownerID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) SaveRevealed(revealed *RevealedSeed) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reveal(revealed)
+	return nil
+}
+
+// reveal archives a seed, dropping the oldest past MaxRevealed. Caller must hold mu.
+func (s *MemoryStore) reveal(revealed *RevealedSeed) {
+	if _, ok := s.revealed[revealed.ServerSeedHash]; !ok {
+		s.order = append(s.order, revealed.ServerSeedHash)
+	}
+	copied := *revealed
+	s.revealed[revealed.ServerSeedHash] = &copied
+
+	for s.MaxRevealed > 0 && len(s.order) > s.MaxRevealed {
+		delete(s.revealed, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (s *MemoryStore) LookupRevealed(serverSeedHash string) (*RevealedSeed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.revealed[serverSeedHash]
+	if !ok {
+		return nil, ErrSeedNotRevealed
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (s *MemoryStore) SaveChain(state *ChainState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *state
+	s.chains[state.OwnerID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) LoadChain(ownerID string) (*ChainState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.chains[ownerID]
+	if !ok || c.Position >= c.Length {
+		return nil, nil
+	}
+	copied := *c
+	return &copied, nil
+}
+
+// PostgresStore keeps seeds in fairness_seed_pairs, fairness_revealed_seeds and
+// fairness_hash_chains
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+func (s *PostgresStore) LoadActive(ownerID string) (*SeedPair, error) {
+	p := &SeedPair{}
+	err := s.DB.QueryRow(`
+		SELECT server_seed, server_seed_hash, client_seed, nonce, created_at
+		FROM fairness_seed_pairs WHERE owner_id = $1
+	`, ownerID).Scan(&p.ServerSeed, &p.ServerSeedHash, &p.ClientSeed, &p.Nonce, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *PostgresStore) SaveActive(ownerID string, pair *SeedPair) error {
+	return saveActive(s.DB, ownerID, pair)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func saveActive(db execer, ownerID string, pair *SeedPair) error {
+	_, err := db.Exec(`
+		INSERT INTO fairness_seed_pairs (owner_id, server_seed, server_seed_hash, client_seed, nonce, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (owner_id) DO UPDATE SET
+			server_seed = EXCLUDED.server_seed, server_seed_hash = EXCLUDED.server_seed_hash,
+			client_seed = EXCLUDED.client_seed, nonce = EXCLUDED.nonce, created_at = EXCLUDED.created_at
+	`, ownerID, pair.ServerSeed, pair.ServerSeedHash, pair.ClientSeed, pair.Nonce, pair.CreatedAt)
+	return err
+}
+
+func saveRevealed(db execer, revealed *RevealedSeed) error {
+	_, err := db.Exec(`
+		INSERT INTO fairness_revealed_seeds (server_seed_hash, server_seed, client_seed, last_nonce, revealed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (server_seed_hash) DO NOTHING
+	`, revealed.ServerSeedHash, revealed.ServerSeed, revealed.ClientSeed, revealed.LastNonce, revealed.RevealedAt)
+	return err
+}
+
+// Retire stores the reveal and the new pair in one transaction, so a seed is
+// never revealed while still active
+func (s *PostgresStore) Retire(ownerID string, revealed *RevealedSeed, next *SeedPair) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveRevealed(tx, revealed); err != nil {
+		return err
+	}
+	if err := saveActive(tx, ownerID, next); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) SaveRevealed(revealed *RevealedSeed) error {
+	return saveRevealed(s.DB, revealed)
+}
+
+func (s *PostgresStore) LookupRevealed(serverSeedHash string) (*RevealedSeed, error) {
+	r := &RevealedSeed{}
+	err := s.DB.QueryRow(`
+		SELECT server_seed, server_seed_hash, client_seed, last_nonce, revealed_at
+		FROM fairness_revealed_seeds WHERE server_seed_hash = $1
+	`, serverSeedHash).Scan(&r.ServerSeed, &r.ServerSeedHash, &r.ClientSeed, &r.LastNonce, &r.RevealedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrSeedNotRevealed
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *PostgresStore) SaveChain(state *ChainState) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO fairness_hash_chains (commitment, owner_id, terminal_seed, length, salt, position, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (commitment) DO UPDATE SET position = GREATEST(fairness_hash_chains.position, EXCLUDED.position)
+	`, state.Commitment, state.OwnerID, state.TerminalSeed, state.Length, state.Salt, state.Position, state.CreatedAt)
+	return err
+}
+
+func (s *PostgresStore) LoadChain(ownerID string) (*ChainState, error) {
+	c := &ChainState{}
+	err := s.DB.QueryRow(`
+		SELECT owner_id, terminal_seed, length, salt, commitment, position, created_at
+		FROM fairness_hash_chains
+		WHERE owner_id = $1 AND position < length
+		ORDER BY created_at DESC LIMIT 1
+	`, ownerID).Scan(&c.OwnerID, &c.TerminalSeed, &c.Length, &c.Salt, &c.Commitment, &c.Position, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
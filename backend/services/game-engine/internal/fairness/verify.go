@@ -0,0 +1,142 @@
+package fairness
+
+import (
+	"errors"
+	"math"
+)
+
+// Verifiable games
+const (
+	GameDice       = "dice"
+	GameCrash      = "crash"
+	GameMines      = "mines"
+	GamePlinko     = "plinko"
+	GameRoulette   = "roulette"
+	GameAndarBahar = "andar_bahar"
+)
+
+// Cards in a standard deck
+const DeckSize = 52
+
+// Mines board size (5x5)
+const MinesGridSize = 25
+
+// Plinko board limits
+const (
+	PlinkoMinRows = 8
+	PlinkoMaxRows = 16
+)
+
+var (
+	ErrUnknownGame   = errors.New("game does not support verification")
+	ErrInvalidParams = errors.New("invalid game parameters")
+)
+
+// VerifyRequest describes one past round to recompute
+type VerifyRequest struct {
+	Game           string `json:"game"`
+	ServerSeed     string `json:"server_seed"`
+	ServerSeedHash string `json:"server_seed_hash,omitempty"` // Optional: checked against ServerSeed
+	ClientSeed     string `json:"client_seed"`
+	Nonce          int    `json:"nonce"`
+
+	// Game specific parameters
+	Mines int `json:"mines,omitempty"`
+	Rows  int `json:"rows,omitempty"`
+}
+
+// VerifyResult is the recomputed outcome of a round
+type VerifyResult struct {
+	Game           string  `json:"game"`
+	ServerSeedHash string  `json:"server_seed_hash"`
+	HashMatches    bool    `json:"hash_matches"`
+	Float          float64 `json:"float"`
+	Outcome        float64 `json:"outcome"`             // Dice roll (0-100), crash multiplier, plinko bucket or roulette pocket
+	Positions      []int   `json:"positions,omitempty"` // Mine tiles, plinko path (0 = left, 1 = right) or deck order
+}
+
+// Verify recomputes a round outcome from its seeds. It has no dependencies on
+// server state, so players (or auditors) can run the same code offline.
+func Verify(req VerifyRequest) (*VerifyResult, error) {
+	hash := HashServerSeed(req.ServerSeed)
+	result := &VerifyResult{
+		Game:           req.Game,
+		ServerSeedHash: hash,
+		HashMatches:    req.ServerSeedHash == "" || req.ServerSeedHash == hash,
+		Float:          GenerateFloat(req.ServerSeed, req.ClientSeed, req.Nonce),
+	}
+
+	switch req.Game {
+	case GameDice:
+		result.Outcome = DiceRoll(req.ServerSeed, req.ClientSeed, req.Nonce)
+	case GameCrash:
+		result.Outcome = CalculateCrashPoint(req.ServerSeed, req.ClientSeed, req.Nonce)
+	case GameMines:
+		if req.Mines < 1 || req.Mines >= MinesGridSize {
+			return nil, ErrInvalidParams
+		}
+		result.Positions = MinePositions(req.ServerSeed, req.ClientSeed, req.Nonce, req.Mines)
+	case GamePlinko:
+		if req.Rows < PlinkoMinRows || req.Rows > PlinkoMaxRows {
+			return nil, ErrInvalidParams
+		}
+		path, bucket := PlinkoPath(req.ServerSeed, req.ClientSeed, req.Nonce, req.Rows)
+		result.Positions = path
+		result.Outcome = float64(bucket)
+	case GameRoulette:
+		result.Outcome = float64(RoulettePocket(req.ServerSeed, req.ClientSeed, req.Nonce))
+	case GameAndarBahar:
+		result.Positions = Shuffle(req.ServerSeed, req.ClientSeed, req.Nonce, DeckSize)
+	default:
+		return nil, ErrUnknownGame
+	}
+
+	return result, nil
+}
+
+// DiceRoll maps a round to a dice result in [0, 100) with two decimal places
+func DiceRoll(serverSeed, clientSeed string, nonce int) float64 {
+	return math.Floor(GenerateFloat(serverSeed, clientSeed, nonce)*10000) / 100
+}
+
+// MinePositions places mines on the 5x5 grid: the first `mines` tiles of a shuffled
+// board. Tiles are numbered 0-24, row by row.
+func MinePositions(serverSeed, clientSeed string, nonce, mines int) []int {
+	return Shuffle(serverSeed, clientSeed, nonce, MinesGridSize)[:mines]
+}
+
+// PlinkoPath drops a ball through `rows` pegs, one float per row: below 0.5 bounces
+// left (0), otherwise right (1). The landing bucket is the number of right bounces.
+func PlinkoPath(serverSeed, clientSeed string, nonce, rows int) ([]int, int) {
+	path := make([]int, rows)
+	bucket := 0
+	for i, f := range GenerateFloats(serverSeed, clientSeed, nonce, rows) {
+		if f >= 0.5 {
+			path[i] = 1
+			bucket++
+		}
+	}
+	return path, bucket
+}
+
+// RoulettePocket picks one of the 37 pockets (0-36) of a European wheel
+func RoulettePocket(serverSeed, clientSeed string, nonce int) int {
+	return int(GenerateFloat(serverSeed, clientSeed, nonce) * 37)
+}
+
+// Shuffle returns a permutation of 0..n-1 (deck order, board layout) with a Fisher-Yates
+// draw: the i-th float picks one of the n-i items not placed yet
+func Shuffle(serverSeed, clientSeed string, nonce, n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+
+	order := make([]int, 0, n)
+	for _, f := range GenerateFloats(serverSeed, clientSeed, nonce, n) {
+		idx := int(f * float64(len(items)))
+		order = append(order, items[idx])
+		items = append(items[:idx], items[idx+1:]...)
+	}
+	return order
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/internal/anticheat"
+)
+
+type AntiCheatHandler struct {
+	Detector *anticheat.Detector
+}
+
+func NewAntiCheatHandler(detector *anticheat.Detector) *AntiCheatHandler {
+	return &AntiCheatHandler{Detector: detector}
+}
+
+// Guard records the device and address each player plays from, for the shared
+// device checks, and turns away suspended accounts. It runs after auth.
+func (h *AntiCheatHandler) Guard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		if h.Detector.Suspended(userID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account suspended pending review"})
+			return
+		}
+
+		// Clients without a fingerprint are told apart by user agent alone
+		fingerprint := sha256.Sum256([]byte(c.GetHeader("X-Device-Fingerprint") + "|" + c.Request.UserAgent()))
+		h.Detector.SeenClient(anticheat.Client{
+			UserID:     userID,
+			DeviceHash: hex.EncodeToString(fingerprint[:]),
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			LastSeen:   time.Now(),
+		})
+		c.Next()
+	}
+}
+
+// ListAlerts is the review queue (?status=OPEN&user_id=&severity=)
+func (h *AntiCheatHandler) ListAlerts(c *gin.Context) {
+	filter := anticheat.AlertFilter{
+		Status:   c.DefaultQuery("status", anticheat.AlertOpen),
+		UserID:   c.Query("user_id"),
+		Severity: c.Query("severity"),
+	}
+	limit := queryInt(c, "limit", 50, 200)
+	offset := queryInt(c, "offset", 0, -1)
+
+	alerts, err := h.Detector.Alerts(filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+}
+
+type ReviewRequest struct {
+	Status string `json:"status" binding:"required"`
+	Notes  string `json:"notes"`
+}
+
+// ReviewAlert confirms or dismisses an alert
+func (h *AntiCheatHandler) ReviewAlert(c *gin.Context) {
+	alertID, err := strconv.ParseInt(c.Param("alert_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert_id"})
+		return
+	}
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alert, err := h.Detector.ReviewAlert(alertID, c.GetString("adminID"), req.Status, req.Notes)
+	if errors.Is(err, anticheat.ErrAlertNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// ListSuspensions lists suspended accounts, only those awaiting review with ?pending=true
+func (h *AntiCheatHandler) ListSuspensions(c *gin.Context) {
+	pending := c.Query("pending") == "true"
+	limit := queryInt(c, "limit", 50, 200)
+	offset := queryInt(c, "offset", 0, -1)
+
+	suspensions, err := h.Detector.Suspensions(pending, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"suspensions": suspensions})
+}
+
+// ReviewSuspension upholds a suspension or lifts it and unfreezes the wallet
+func (h *AntiCheatHandler) ReviewSuspension(c *gin.Context) {
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suspension, err := h.Detector.ReviewSuspension(c.Param("user_id"), c.GetString("adminID"), req.Status, req.Notes)
+	if errors.Is(err, anticheat.ErrNotSuspended) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil && suspension == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		// Lifted, but the wallet could not be unfrozen
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "suspension": suspension})
+		return
+	}
+	c.JSON(http.StatusOK, suspension)
+}
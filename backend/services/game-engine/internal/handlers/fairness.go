@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+type FairnessHandler struct {
+	Seeds *fairness.SeedManager
+}
+
+func NewFairnessHandler(seeds *fairness.SeedManager) *FairnessHandler {
+	return &FairnessHandler{Seeds: seeds}
+}
+
+// GetActiveSeed returns the hash of the user's active server seed, their client seed and nonce
+func (h *FairnessHandler) GetActiveSeed(c *gin.Context) {
+	active, err := h.Seeds.GetActive(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load seed pair"})
+		return
+	}
+	c.JSON(http.StatusOK, active)
+}
+
+// SetClientSeed changes the user's client seed (revealing the current server seed)
+func (h *FairnessHandler) SetClientSeed(c *gin.Context) {
+	var req struct {
+		ClientSeed string `json:"client_seed" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	revealed, active, err := h.Seeds.SetClientSeed(c.GetString("userID"), req.ClientSeed)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revealed": revealed, "active": active})
+}
+
+// RotateSeed reveals the current server seed and commits to a new one
+func (h *FairnessHandler) RotateSeed(c *gin.Context) {
+	var req struct {
+		ClientSeed string `json:"client_seed"` // Optional
+	}
+	// Empty body is allowed
+	_ = c.ShouldBindJSON(&req)
+
+	revealed, active, err := h.Seeds.Rotate(c.GetString("userID"), req.ClientSeed)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revealed": revealed, "active": active})
+}
+
+// Verify recomputes the outcome of a past round.
+// Either server_seed or a server_seed_hash that has already been rotated out must be given.
+func (h *FairnessHandler) Verify(c *gin.Context) {
+	var req fairness.VerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.ServerSeed == "" {
+		if req.ServerSeedHash == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "server_seed or server_seed_hash is required"})
+			return
+		}
+		revealed, err := h.Seeds.LookupRevealed(req.ServerSeedHash)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		req.ServerSeed = revealed.ServerSeed
+		if req.ClientSeed == "" {
+			req.ClientSeed = revealed.ClientSeed
+		}
+	}
+
+	result, err := fairness.Verify(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/games/fantasy"
+)
+
+type FantasyHandler struct {
+	Contests *fantasy.ContestManager
+}
+
+func NewFantasyHandler(contests *fantasy.ContestManager) *FantasyHandler {
+	return &FantasyHandler{Contests: contests}
+}
+
+// contestStatus maps contest errors to HTTP statuses
+func contestStatus(err error) int {
+	switch {
+	case errors.Is(err, fantasy.ErrContestNotFound), errors.Is(err, fantasy.ErrTeamNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, fantasy.ErrNotTeamOwner), errors.Is(err, fantasy.ErrNotCreator):
+		return http.StatusForbidden
+	case errors.Is(err, fantasy.ErrContestFull), errors.Is(err, fantasy.ErrEntryLimit),
+		errors.Is(err, fantasy.ErrLineupLocked), errors.Is(err, fantasy.ErrInvalidStatus),
+		errors.Is(err, fantasy.ErrInviteRequired):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// lineupRequest is a team picked from the match's player pool
+type lineupRequest struct {
+	PlayerIDs     []string `json:"player_ids" binding:"required"`
+	CaptainID     string   `json:"captain_id" binding:"required"`
+	ViceCaptainID string   `json:"vice_captain_id" binding:"required"`
+}
+
+// CreateContest opens a contest on a match (admin)
+func (h *FantasyHandler) CreateContest(c *gin.Context) {
+	var req struct {
+		MatchID           string    `json:"match_id" binding:"required"`
+		Name              string    `json:"name" binding:"required"`
+		Format            string    `json:"format"`
+		EntryFee          float64   `json:"entry_fee"`
+		PrizePool         float64   `json:"prize_pool"`
+		Guaranteed        bool      `json:"guaranteed"`
+		MaxTeams          int       `json:"max_teams" binding:"required"`
+		MinTeams          int       `json:"min_teams"`
+		MaxEntriesPerUser int       `json:"max_entries_per_user"`
+		PayoutTable       string    `json:"payout_table"`
+		StartTime         time.Time `json:"start_time" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contest := &fantasy.Contest{
+		MatchID:           req.MatchID,
+		Name:              req.Name,
+		Format:            req.Format,
+		EntryFee:          req.EntryFee,
+		PrizePool:         req.PrizePool,
+		Guaranteed:        req.Guaranteed,
+		MaxTeams:          req.MaxTeams,
+		MinTeams:          req.MinTeams,
+		MaxEntriesPerUser: req.MaxEntriesPerUser,
+		PayoutTable:       req.PayoutTable,
+		StartTime:         req.StartTime,
+	}
+	if err := h.Contests.CreateContest(contest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, contest)
+}
+
+// GetScoringRules returns a format's current scoring rules, or ?version
+func (h *FantasyHandler) GetScoringRules(c *gin.Context) {
+	format := strings.ToUpper(c.Param("format"))
+	var rules *fantasy.ScoringRules
+	var err error
+	if version := queryInt(c, "version", 0, -1); version > 0 {
+		rules, err = h.Contests.Rules(format, version)
+	} else {
+		rules, err = h.Contests.CurrentRules(format)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// PublishScoringRules publishes the next version of a format's scoring rules (admin)
+func (h *FantasyHandler) PublishScoringRules(c *gin.Context) {
+	var rules fantasy.ScoringRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rules.Format = strings.ToUpper(rules.Format)
+	rules.Sport = strings.ToUpper(rules.Sport)
+
+	if err := h.Contests.PublishRules(&rules); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, fantasy.ErrRulesVersionExists) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rules)
+}
+
+// ListContests lists a match's contests open for entry
+func (h *FantasyHandler) ListContests(c *gin.Context) {
+	open, err := h.Contests.Store.ListContests(c.Param("match_id"), fantasy.ContestOpen)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	contests := make([]*fantasy.Contest, 0, len(open))
+	for _, contest := range open {
+		if !contest.Private {
+			contests = append(contests, contest)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"contests": contests})
+}
+
+// GetSquad returns the match's player pool to pick teams from
+func (h *FantasyHandler) GetSquad(c *gin.Context) {
+	players, err := h.Contests.Squad(c.Param("match_id"))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"match_id": c.Param("match_id"), "players": players})
+}
+
+// GetContest returns a contest with its leaderboard
+func (h *FantasyHandler) GetContest(c *gin.Context) {
+	contest, err := h.Contests.GetContest(c.Param("contest_id"))
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	contest.InviteCode = "" // Only shared by the league's creator
+	board, err := h.Contests.Leaderboard(contest.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"contest": contest, "pool": contest.Pool(), "leaderboard": board.GetTopTeams(queryInt(c, "limit", 100, 1000))})
+}
+
+// JoinContest enters a team and pays the entry fee
+func (h *FantasyHandler) JoinContest(c *gin.Context) {
+	var req lineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := h.Contests.Join(c.Param("contest_id"), c.GetString("userID"), req.PlayerIDs, req.CaptainID, req.ViceCaptainID)
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, team)
+}
+
+// EditLineup changes one of the caller's teams until the match starts
+func (h *FantasyHandler) EditLineup(c *gin.Context) {
+	var req lineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := h.Contests.EditLineup(c.Param("team_id"), c.GetString("userID"), req.PlayerIDs, req.CaptainID, req.ViceCaptainID)
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// CancelContest cancels a contest and refunds every entry (admin)
+func (h *FantasyHandler) CancelContest(c *gin.Context) {
+	if err := h.Contests.CancelContest(c.Param("contest_id")); err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": fantasy.ContestCancelled})
+}
+
+// CompleteMatch settles every live contest on a finished match (admin)
+func (h *FantasyHandler) CompleteMatch(c *gin.Context) {
+	if err := h.Contests.CompleteMatch(c.Param("match_id")); err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": fantasy.ContestCompleted, "match_id": c.Param("match_id")})
+}
+
+// CorrectStats applies an official scoring correction to a settled match,
+// reranking its contests and adjusting payouts (admin)
+func (h *FantasyHandler) CorrectStats(c *gin.Context) {
+	var req struct {
+		Players map[string]fantasy.PlayerStats `json:"players" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Contests.CorrectStats(c.Param("match_id"), req.Players); err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"match_id": c.Param("match_id"), "corrected": len(req.Players)})
+}
+
+// StreamRanks pushes a contest's leaderboard over WebSocket whenever points
+// change. ?limit caps the top of the board sent; ?user_id adds that user's
+// own teams wherever they rank.
+func (h *FantasyHandler) StreamRanks(c *gin.Context) {
+	board, err := h.Contests.Leaderboard(c.Param("contest_id"))
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	limit := queryInt(c, "limit", 100, 1000)
+	userID := c.Query("user_id")
+
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Println("Failed to upgrade to WebSocket:", err)
+		return
+	}
+	defer ws.Close()
+
+	updates, stop := h.Contests.Ranks.Subscribe(board.ContestID)
+	defer stop()
+
+	// The client only listens; reading just notices it going away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	entries := board.Entries
+	for {
+		top := entries[:min(limit, len(entries))]
+		mine := []fantasy.LeaderboardEntry{}
+		for _, entry := range entries {
+			if userID != "" && entry.UserID == userID {
+				mine = append(mine, entry)
+			}
+		}
+		if err := ws.WriteJSON(gin.H{"contest_id": board.ContestID, "leaderboard": top, "mine": mine}); err != nil {
+			return
+		}
+
+		select {
+		case entries = <-updates:
+		case <-closed:
+			return
+		}
+	}
+}
+
+// CreateLeague opens a private league for the caller, returning its invite code
+func (h *FantasyHandler) CreateLeague(c *gin.Context) {
+	var req struct {
+		MatchID     string  `json:"match_id" binding:"required"`
+		Name        string  `json:"name" binding:"required"`
+		Format      string  `json:"format"`
+		EntryFee    float64 `json:"entry_fee"`
+		MaxTeams    int     `json:"max_teams" binding:"required"`
+		MinTeams    int     `json:"min_teams"`
+		PayoutTable string  `json:"payout_table"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	league := &fantasy.Contest{
+		MatchID:     req.MatchID,
+		Name:        req.Name,
+		Format:      req.Format,
+		EntryFee:    req.EntryFee,
+		MaxTeams:    req.MaxTeams,
+		MinTeams:    req.MinTeams,
+		PayoutTable: req.PayoutTable,
+	}
+	if err := h.Contests.CreateLeague(c.GetString("userID"), league); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, league)
+}
+
+// GetLeague looks a league up by invite code, for a user deciding to join
+func (h *FantasyHandler) GetLeague(c *gin.Context) {
+	league, err := h.Contests.League(c.Param("invite_code"))
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"contest": league, "pool": league.Pool()})
+}
+
+// JoinLeague enters a team in a league by invite code and pays the entry fee
+func (h *FantasyHandler) JoinLeague(c *gin.Context) {
+	var req lineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := h.Contests.JoinLeague(c.Param("invite_code"), c.GetString("userID"), req.PlayerIDs, req.CaptainID, req.ViceCaptainID)
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, team)
+}
+
+// LockLeague closes the caller's league to entries ahead of the match
+func (h *FantasyHandler) LockLeague(c *gin.Context) {
+	league, err := h.Contests.LockLeague(c.Param("contest_id"), c.GetString("userID"))
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, league)
+}
+
+// CancelLeague cancels the caller's league and refunds every entry
+func (h *FantasyHandler) CancelLeague(c *gin.Context) {
+	league, err := h.Contests.CancelLeague(c.Param("contest_id"), c.GetString("userID"))
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, league)
+}
+
+// GetSelections returns each player's selection and captaincy percentages and
+// points across a contest's teams, once lineups lock
+func (h *FantasyHandler) GetSelections(c *gin.Context) {
+	selections, err := h.Contests.SelectionStats(c.Param("contest_id"))
+	if err != nil {
+		c.JSON(contestStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"contest_id": c.Param("contest_id"), "players": selections})
+}
+
+// IngestSquad replaces a match's player pool and credits (admin)
+func (h *FantasyHandler) IngestSquad(c *gin.Context) {
+	var req struct {
+		Players []fantasy.FantasyPlayer `json:"players" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Contests.IngestSquad(c.Param("match_id"), req.Players); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"match_id": c.Param("match_id"), "players": req.Players})
+}
+
+// AnnounceLineup records a match's playing XI and notifies users whose teams
+// picked anyone left out (admin)
+func (h *FantasyHandler) AnnounceLineup(c *gin.Context) {
+	var req struct {
+		Playing []string `json:"playing" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	affected, err := h.Contests.AnnounceLineup(c.Param("match_id"), req.Playing)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"match_id": c.Param("match_id"), "teams_notified": affected})
+}
@@ -97,6 +97,20 @@ func (h *GameHandler) MakeMove(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetPlayerView returns what the caller sees of the session, e.g. their own hand
+func (h *GameHandler) GetPlayerView(c *gin.Context) {
+	sessionID := c.Param("session_id")
+	userID := c.GetString("userID")
+
+	view, err := h.SessionManager.ViewSession(sessionID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
 // GetSessionState returns current game state
 func (h *GameHandler) GetSessionState(c *gin.Context) {
 	sessionID := c.Param("session_id")
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/games/mines"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/session"
+	"github.com/playkaro/game-engine/internal/settlement"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// fakeWallet accepts every transaction the wallet client sends
+func fakeWallet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wallet.TransactionResponse{Status: "SUCCESS"})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("PAYMENT_SERVICE_URL", srv.URL)
+}
+
+func gameRouter(h *GameHandler, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+	r.POST("/games/sessions", h.CreateSession)
+	r.POST("/sessions/:session_id/move", h.MakeMove)
+	return r
+}
+
+func post(t *testing.T, r http.Handler, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+	return w
+}
+
+// A single-player game takes bets as soon as its session is created, and pays
+// a cash-out through the settler
+func TestSinglePlayerSessionTakesBets(t *testing.T) {
+	fakeWallet(t)
+	seeds := fairness.NewSeedManager(nil)
+	settler := settlement.NewSettler(settlement.DefaultConfig(), wallet.NewWalletClient(), nil)
+	registry.GetRegistry().RegisterGame(mines.NewMinesGame(seeds, settler))
+	r := gameRouter(NewGameHandler(session.NewSessionManager(), nil), "player_1")
+
+	w := post(t, r, "/games/sessions", map[string]string{"game_id": "mines_classic"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create session: %d %s", w.Code, w.Body)
+	}
+	var created struct {
+		SessionID string
+		Status    string
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Status != "IN_PROGRESS" {
+		t.Fatalf("new session %s, want IN_PROGRESS", created.Status)
+	}
+
+	w = post(t, r, "/sessions/"+created.SessionID+"/move", map[string]interface{}{
+		"type": "START",
+		"data": map[string]interface{}{"amount": 10, "mines": 3},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("bet: %d %s", w.Code, w.Body)
+	}
+	var result struct {
+		Success     bool
+		StateUpdate struct {
+			Status string  `json:"status"`
+			Amount float64 `json:"amount"`
+		}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success || result.StateUpdate.Status != mines.StatusActive || result.StateUpdate.Amount != 10 {
+		t.Fatalf("bet result %s", w.Body)
+	}
+
+	// Reveal a tile the seed left safe, then cash out
+	pair, err := seeds.GetActive("player_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined := map[int]bool{}
+	for _, tile := range fairness.MinePositions(pair.ServerSeed, pair.ClientSeed, pair.Nonce, 3) {
+		mined[tile] = true
+	}
+	safe := 0
+	for mined[safe] {
+		safe++
+	}
+	for _, move := range []map[string]interface{}{
+		{"type": "REVEAL", "data": map[string]interface{}{"tile": safe}},
+		{"type": "CASHOUT"},
+	} {
+		if w = post(t, r, "/sessions/"+created.SessionID+"/move", move); w.Code != http.StatusOK {
+			t.Fatalf("%s: %d %s", move["type"], w.Code, w.Body)
+		}
+	}
+	if n := settler.Pending(); n != 1 {
+		t.Errorf("%d credits queued after cashing out, want 1", n)
+	}
+}
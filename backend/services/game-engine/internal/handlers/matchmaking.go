@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/internal/matchmaking"
+)
+
+type MatchmakingHandler struct {
+	Matchmaker *matchmaking.Matchmaker
+}
+
+func NewMatchmakingHandler(matchmaker *matchmaking.Matchmaker) *MatchmakingHandler {
+	return &MatchmakingHandler{Matchmaker: matchmaker}
+}
+
+// Enqueue joins the queue for a game, stake and table size. The entry fee is
+// taken once the player is matched.
+func (h *MatchmakingHandler) Enqueue(c *gin.Context) {
+	var req struct {
+		GameID   string  `json:"game_id" binding:"required"`
+		EntryFee float64 `json:"entry_fee"` // Optional, defaults to the game's entry fee
+		Players  int     `json:"players"`   // Optional, defaults to the game's minimum
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ticket, err := h.Matchmaker.Enqueue(c.GetString("userID"), req.GameID, req.EntryFee, req.Players)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, matchmaking.ErrAlreadyQueued) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// GetTicket polls a ticket; once matched it carries the session to join
+func (h *MatchmakingHandler) GetTicket(c *gin.Context) {
+	ticket, err := h.Matchmaker.Ticket(c.GetString("userID"), c.Param("ticket_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+// CancelTicket leaves the queue
+func (h *MatchmakingHandler) CancelTicket(c *gin.Context) {
+	ticket, err := h.Matchmaker.Cancel(c.GetString("userID"), c.Param("ticket_id"))
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, matchmaking.ErrTicketNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, matchmaking.ErrNotQueued):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
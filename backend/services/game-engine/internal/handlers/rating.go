@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/internal/rating"
+)
+
+type RatingHandler struct {
+	Ratings *rating.Service
+}
+
+func NewRatingHandler(ratings *rating.Service) *RatingHandler {
+	return &RatingHandler{Ratings: ratings}
+}
+
+// GetPlayerRating returns a player's rating in a game with their recent rating changes
+func (h *RatingHandler) GetPlayerRating(c *gin.Context) {
+	gameID, userID := c.Param("game_id"), c.Param("user_id")
+
+	player, err := h.Ratings.Player(userID, gameID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	history, err := h.Ratings.History(userID, gameID, queryInt(c, "history", 20, 100))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rating":      player,
+		"provisional": player.Provisional(),
+		"history":     history,
+	})
+}
+
+// GetLadder lists a game's players by rating
+func (h *RatingHandler) GetLadder(c *gin.Context) {
+	limit := queryInt(c, "limit", 50, 100)
+	offset := queryInt(c, "offset", 0, -1)
+
+	ladder, err := h.Ratings.Ladder(c.Param("game_id"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries := make([]gin.H, len(ladder))
+	for i, r := range ladder {
+		entries[i] = gin.H{
+			"rank":        offset + i + 1,
+			"rating":      r,
+			"provisional": r.Provisional(),
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"game_id": c.Param("game_id"), "ladder": entries})
+}
+
+// queryInt reads a non-negative integer query parameter, capped at max unless max is negative
+func queryInt(c *gin.Context, name string, def, max int) int {
+	n, err := strconv.Atoi(c.Query(name))
+	if err != nil || n < 0 {
+		return def
+	}
+	if max >= 0 && n > max {
+		return max
+	}
+	return n
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/replay"
+)
+
+type ReplayHandler struct {
+	Replays replay.Store
+}
+
+func NewReplayHandler(replays replay.Store) *ReplayHandler {
+	return &ReplayHandler{Replays: replays}
+}
+
+// ListReplays lists finished sessions, optionally of one game or one player.
+// Shared-round games have no replays, so asking for one is an error.
+func (h *ReplayHandler) ListReplays(c *gin.Context) {
+	filter := replay.Filter{GameID: c.Query("game_id"), UserID: c.Query("user_id")}
+	if game, err := registry.GetRegistry().GetGame(filter.GameID); err == nil {
+		if shared, ok := game.(engine.SharedRoundGame); ok && shared.IsSharedRound() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Shared-round games are not recorded; verify their rounds with /fairness/verify"})
+			return
+		}
+	}
+	limit := queryInt(c, "limit", 20, 100)
+	offset := queryInt(c, "offset", 0, -1)
+
+	replays, err := h.Replays.List(filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"replays": replays})
+}
+
+// GetReplay returns a session's whole replay
+func (h *ReplayHandler) GetReplay(c *gin.Context) {
+	r, ok := h.load(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// GetFrame returns the state at one step of a replay (?step=N), or at a time into
+// the game (?at_ms=M)
+func (h *ReplayHandler) GetFrame(c *gin.Context) {
+	r, ok := h.load(c)
+	if !ok {
+		return
+	}
+	player := replay.NewPlayer(r)
+
+	if at := c.Query("at_ms"); at != "" {
+		ms, err := strconv.ParseInt(at, 10, 64)
+		if err != nil || ms < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid at_ms"})
+			return
+		}
+		c.JSON(http.StatusOK, player.SeekTime(time.Duration(ms)*time.Millisecond))
+		return
+	}
+
+	step, err := strconv.Atoi(c.DefaultQuery("step", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid step"})
+		return
+	}
+	frame, err := player.Seek(step)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, frame)
+}
+
+// VerifyReplay replays the move log and reports whether it rebuilds the recorded game
+func (h *ReplayHandler) VerifyReplay(c *gin.Context) {
+	r, ok := h.load(c)
+	if !ok {
+		return
+	}
+
+	verification, err := replay.Verify(r)
+	if errors.Is(err, replay.ErrNotReplayable) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, verification)
+}
+
+func (h *ReplayHandler) load(c *gin.Context) (*replay.Replay, bool) {
+	r, err := h.Replays.Get(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if r == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Replay not found"})
+		return nil, false
+	}
+	return r, true
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/games/crash"
+	"github.com/playkaro/game-engine/games/mines"
+	"github.com/playkaro/game-engine/internal/fairness"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/replay"
+)
+
+// Shared-round tables aren't recorded, so there is nothing to list for them
+func TestListReplaysRejectsSharedRoundGames(t *testing.T) {
+	registry.GetRegistry().RegisterGame(crash.NewCrashGame(nil, fairness.NewSeedManager(nil), nil))
+	registry.GetRegistry().RegisterGame(mines.NewMinesGame(fairness.NewSeedManager(nil), nil))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/replays", NewReplayHandler(replay.NewMemoryStore()).ListReplays)
+
+	for query, want := range map[string]int{
+		"":                       http.StatusOK,
+		"?game_id=mines_classic": http.StatusOK,
+		"?game_id=crash_aviator": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/replays"+query, nil))
+		if w.Code != want {
+			t.Errorf("GET /replays%s: %d %s, want %d", query, w.Code, w.Body, want)
+		}
+	}
+}
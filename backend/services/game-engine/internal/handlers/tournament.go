@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/game-engine/tournament"
+)
+
+type TournamentHandler struct {
+	Manager   *tournament.TournamentManager
+	Scheduler *tournament.Scheduler
+}
+
+func NewTournamentHandler(manager *tournament.TournamentManager, scheduler *tournament.Scheduler) *TournamentHandler {
+	return &TournamentHandler{Manager: manager, Scheduler: scheduler}
+}
+
+// tournamentStatus maps manager errors to HTTP statuses
+func tournamentStatus(err error) int {
+	switch {
+	case errors.Is(err, tournament.ErrTournamentNotFound), errors.Is(err, tournament.ErrMatchNotFound),
+		errors.Is(err, tournament.ErrTemplateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tournament.ErrTournamentFull), errors.Is(err, tournament.ErrAlreadyRegistered),
+		errors.Is(err, tournament.ErrRegistrationClosed), errors.Is(err, tournament.ErrInvalidStatus),
+		errors.Is(err, tournament.ErrMatchNotReady), errors.Is(err, tournament.ErrNotEnoughPlayers),
+		errors.Is(err, tournament.ErrEntryLimit):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// CreateTournament schedules a tournament (admin)
+func (h *TournamentHandler) CreateTournament(c *gin.Context) {
+	var req struct {
+		Name              string                      `json:"name" binding:"required"`
+		GameID            string                      `json:"game_id" binding:"required"`
+		EntryFee          float64                     `json:"entry_fee"`
+		PrizePool         float64                     `json:"prize_pool"`
+		MaxPlayers        int                         `json:"max_players" binding:"required"`
+		RegistrationOpens time.Time                   `json:"registration_opens"` // Optional, defaults to now
+		StartTime         time.Time                   `json:"start_time" binding:"required"`
+		Config            tournament.TournamentConfig `json:"config"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	t, err := h.Manager.CreateTournament(req.Name, req.GameID, req.EntryFee, req.PrizePool, req.MaxPlayers,
+		req.RegistrationOpens, req.StartTime, req.Config)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// ListTournaments lists tournaments by start time (?status=REGISTRATION)
+func (h *TournamentHandler) ListTournaments(c *gin.Context) {
+	limit := queryInt(c, "limit", 20, 100)
+	offset := queryInt(c, "offset", 0, -1)
+
+	tournaments, err := h.Manager.Store.ListTournaments(c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tournaments": tournaments})
+}
+
+// GetTournament returns a tournament with its participants
+func (h *TournamentHandler) GetTournament(c *gin.Context) {
+	t, err := h.Manager.GetTournament(c.Param("tournament_id"))
+	if err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	participants, err := h.Manager.Store.Participants(t.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tournament": t, "participants": participants})
+}
+
+// GetBracket returns the tournament's matches by round
+func (h *TournamentHandler) GetBracket(c *gin.Context) {
+	t, err := h.Manager.GetTournament(c.Param("tournament_id"))
+	if err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	matches, err := h.Manager.Store.Matches(t.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tournament_id": t.ID, "status": t.Status, "matches": matches})
+}
+
+// Register enters the caller and pays their entry fee
+func (h *TournamentHandler) Register(c *gin.Context) {
+	tournamentID := c.Param("tournament_id")
+	if err := h.Manager.RegisterParticipant(tournamentID, c.GetString("userID")); err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "registered", "tournament_id": tournamentID})
+}
+
+// StartTournament starts a tournament ahead of schedule (admin)
+func (h *TournamentHandler) StartTournament(c *gin.Context) {
+	if err := h.Manager.StartTournament(c.Param("tournament_id")); err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	h.GetBracket(c)
+}
+
+// CancelTournament cancels a tournament before it starts and refunds entries (admin)
+func (h *TournamentHandler) CancelTournament(c *gin.Context) {
+	if err := h.Manager.CancelTournament(c.Param("tournament_id")); err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": tournament.StatusCancelled})
+}
+
+// ReportResult settles a match by hand, e.g. after a dispute (admin)
+func (h *TournamentHandler) ReportResult(c *gin.Context) {
+	var req struct {
+		WinnerID string `json:"winner_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Manager.AdvanceMatch(c.Param("match_id"), req.WinnerID); err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "advanced", "winner_id": req.WinnerID})
+}
+
+// GetStandings returns the standings table, ordered by points and the
+// tournament's tie-breakers
+func (h *TournamentHandler) GetStandings(c *gin.Context) {
+	standings, err := h.Manager.Standings(c.Param("tournament_id"))
+	if err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"standings": standings})
+}
+
+// CreateTemplate adds a sit-and-go or scheduled tournament template (admin)
+func (h *TournamentHandler) CreateTemplate(c *gin.Context) {
+	var tmpl tournament.Template
+	if err := c.ShouldBindJSON(&tmpl); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.Scheduler.CreateTemplate(&tmpl); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, tmpl)
+}
+
+// ListTemplates lists tournament templates, paused ones included (admin)
+func (h *TournamentHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.Manager.Store.ListTemplates(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// SetTemplateActive pauses or resumes a template (admin)
+func (h *TournamentHandler) SetTemplateActive(c *gin.Context) {
+	var req struct {
+		Active *bool `json:"active" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tmpl, err := h.Scheduler.SetActive(c.Param("template_id"), *req.Active)
+	if err != nil {
+		c.JSON(tournamentStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tmpl)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/playkaro/game-engine/internal/engine"
 )
 
 type LeaderboardService struct {
@@ -20,13 +21,18 @@ func NewLeaderboardService(redisURL string) (*LeaderboardService, error) {
 	return &LeaderboardService{Redis: redis.NewClient(opt)}, nil
 }
 
-// UpdateScore adds points to a user's score (Weekly Leaderboard)
-func (s *LeaderboardService) UpdateScore(userID string, points float64) error {
+// UpdateScore adds points to a session player's score (Weekly Leaderboard)
+func (s *LeaderboardService) UpdateScore(player *engine.Player, points float64) error {
+	// Bots never appear on leaderboards
+	if player.IsBot {
+		return nil
+	}
+
 	ctx := context.Background()
 	_, week := time.Now().ISOWeek()
 	key := fmt.Sprintf("leaderboard:weekly:%d", week)
 
-	return s.Redis.ZIncrBy(ctx, key, points, userID).Err()
+	return s.Redis.ZIncrBy(ctx, key, points, player.UserID).Err()
 }
 
 // GetTopPlayers returns the top N players
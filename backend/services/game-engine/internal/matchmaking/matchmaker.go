@@ -0,0 +1,467 @@
+package matchmaking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/session"
+)
+
+// Wallet moves entry fees, booking bots' to the house (implemented by
+// wallet.WalletClient)
+type Wallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+	Credit(userID string, amount float64, refID, refType string) error
+	DebitBot(botID string, amount float64, refID, refType string) error
+	CreditBot(botID string, amount float64, refID, refType string) error
+}
+
+// SessionCreator opens the session for a match (implemented by session.SessionManager)
+type SessionCreator interface {
+	CreateSession(gameID, userID string, opts ...session.SessionOption) (*engine.GameSession, error)
+}
+
+// BotSeater fills seats nobody queued for with server-side bots (implemented by bots.Manager)
+type BotSeater interface {
+	Supports(gameID string) bool
+	Reserve(gameID string, count int, rating float64) ([]string, error)
+	Release(botIDs []string)
+	Play(sessionID string) error
+}
+
+// Config controls how quickly the rating band widens
+type Config struct {
+	InitialBand   float64       // Rating difference accepted straight away
+	BandStep      float64       // Added to the band...
+	BandInterval  time.Duration // ...for every interval a ticket has waited
+	MaxBand       float64
+	MatchInterval time.Duration // How often Run looks for matches
+	BotFillAfter  time.Duration // Wait before empty seats go to bots; 0 never uses bots
+}
+
+func DefaultConfig() Config {
+	return Config{
+		InitialBand:   100,
+		BandStep:      50,
+		BandInterval:  5 * time.Second,
+		MaxBand:       800,
+		MatchInterval: time.Second,
+		BotFillAfter:  45 * time.Second,
+	}
+}
+
+// Band is the rating difference a ticket accepts after waiting for waited
+func (c Config) Band(waited time.Duration) float64 {
+	if waited < 0 {
+		waited = 0
+	}
+	band := c.InitialBand + c.BandStep*math.Floor(float64(waited)/float64(c.BandInterval))
+	return math.Min(band, c.MaxBand)
+}
+
+// Matchmaker pairs queued players and starts their sessions
+type Matchmaker struct {
+	cfg      Config
+	backend  Backend
+	sessions SessionCreator
+	wallet   Wallet
+	ratings  RatingSource
+	clock    Clock
+	bots     BotSeater
+	seq      atomic.Uint64
+}
+
+// NewMatchmaker creates a matchmaker. ratings and clock may be nil for
+// FixedRatings and the wall clock.
+func NewMatchmaker(cfg Config, backend Backend, sessions SessionCreator, wallet Wallet, ratings RatingSource, clock Clock) *Matchmaker {
+	if ratings == nil {
+		ratings = FixedRatings{}
+	}
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &Matchmaker{
+		cfg:      cfg,
+		backend:  backend,
+		sessions: sessions,
+		wallet:   wallet,
+		ratings:  ratings,
+		clock:    clock,
+	}
+}
+
+// SetBots lets tickets that wait longer than Config.BotFillAfter play against bots
+func (m *Matchmaker) SetBots(bots BotSeater) {
+	m.bots = bots
+}
+
+// Enqueue queues the user. The entry fee is only taken once they are matched, so
+// waiting and cancelling cost nothing. entryFee and players default to the game's
+// entry fee and minimum table size when zero.
+func (m *Matchmaker) Enqueue(userID, gameID string, entryFee float64, players int) (*Ticket, error) {
+	game, err := registry.GetRegistry().GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if shared, ok := game.(engine.SharedRoundGame); ok && shared.IsSharedRound() {
+		return nil, errors.New("shared tables do not use matchmaking; join the table directly")
+	}
+	if game.GetMaxPlayers() < 2 {
+		return nil, errors.New("single-player games do not use matchmaking")
+	}
+
+	if entryFee == 0 {
+		entryFee = game.GetEntryFee()
+	}
+	if entryFee < game.GetEntryFee() {
+		return nil, fmt.Errorf("entry fee must be at least %.2f", game.GetEntryFee())
+	}
+	if players == 0 {
+		players = game.GetMinPlayers()
+	}
+	if players < 2 || players < game.GetMinPlayers() || players > game.GetMaxPlayers() {
+		return nil, fmt.Errorf("players must be between %d and %d", max(2, game.GetMinPlayers()), game.GetMaxPlayers())
+	}
+
+	rating, err := m.ratings.Rating(userID, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rating: %v", err)
+	}
+
+	now := m.clock.Now()
+	ticket := &Ticket{
+		ID:         fmt.Sprintf("mm_%d_%d", now.UnixNano(), m.seq.Add(1)),
+		UserID:     userID,
+		GameID:     gameID,
+		EntryFee:   entryFee,
+		Players:    players,
+		Rating:     rating,
+		Status:     StatusQueued,
+		EnqueuedAt: now,
+	}
+
+	if err := m.backend.Add(ticket); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// Cancel leaves the queue. Matched tickets can no longer be cancelled: their fee
+// is locked into the session.
+func (m *Matchmaker) Cancel(userID, ticketID string) (*Ticket, error) {
+	ticket, err := m.Ticket(userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket, err = m.backend.Remove(ticketID); err != nil {
+		return nil, err
+	}
+	ticket.Status = StatusCancelled
+	if err := m.backend.Update(ticket); err != nil {
+		log.Printf("matchmaking: failed to save cancelled ticket %s: %v", ticket.ID, err)
+	}
+	return ticket, nil
+}
+
+// Ticket returns one of the user's tickets
+func (m *Matchmaker) Ticket(userID, ticketID string) (*Ticket, error) {
+	ticket, err := m.backend.Get(ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.UserID != userID {
+		return nil, ErrTicketNotFound
+	}
+	return ticket, nil
+}
+
+// Run looks for matches every MatchInterval until ctx is cancelled
+func (m *Matchmaker) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.MatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.MatchOnce(); err != nil {
+				log.Printf("matchmaking: %v", err)
+			}
+		}
+	}
+}
+
+// MatchOnce makes one pass over every queue and returns the sessions it started
+func (m *Matchmaker) MatchOnce() ([]*engine.GameSession, error) {
+	keys, err := m.backend.Queues()
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.clock.Now()
+	started := []*engine.GameSession{}
+	for _, key := range keys {
+		tickets, err := m.backend.Queued(key)
+		if err != nil {
+			return started, err
+		}
+		if len(tickets) == 0 {
+			continue
+		}
+
+		groups := FormGroups(tickets, tickets[0].Players, now, m.cfg)
+		if m.bots != nil && m.cfg.BotFillAfter > 0 && m.bots.Supports(tickets[0].GameID) {
+			groups = append(groups, FormBotGroups(unmatched(tickets, groups), tickets[0].Players, now, m.cfg)...)
+		}
+
+		for _, group := range groups {
+			session, err := m.startMatch(key, group, now)
+			if err != nil {
+				log.Printf("matchmaking %s: %v", key, err)
+				continue
+			}
+			if session != nil {
+				started = append(started, session)
+			}
+		}
+	}
+	return started, nil
+}
+
+// FormBotGroups seats tickets that have waited at least BotFillAfter with whoever
+// else is left within their band; bots take the remaining seats of each table
+func FormBotGroups(tickets []*Ticket, players int, now time.Time, cfg Config) [][]*Ticket {
+	used := make(map[string]bool, len(tickets))
+	groups := [][]*Ticket{}
+
+	for _, anchor := range tickets {
+		if used[anchor.ID] || now.Sub(anchor.EnqueuedAt) < cfg.BotFillAfter {
+			continue
+		}
+		band := cfg.Band(now.Sub(anchor.EnqueuedAt))
+
+		group := []*Ticket{anchor}
+		used[anchor.ID] = true
+		for _, t := range tickets {
+			if len(group) == players-1 {
+				break
+			}
+			if !used[t.ID] && math.Abs(t.Rating-anchor.Rating) <= band {
+				group = append(group, t)
+				used[t.ID] = true
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// unmatched returns the tickets not in any of groups, keeping their order
+func unmatched(tickets []*Ticket, groups [][]*Ticket) []*Ticket {
+	matched := make(map[string]bool)
+	for _, group := range groups {
+		for _, t := range group {
+			matched[t.ID] = true
+		}
+	}
+	rest := []*Ticket{}
+	for _, t := range tickets {
+		if !matched[t.ID] {
+			rest = append(rest, t)
+		}
+	}
+	return rest
+}
+
+// FormGroups picks tables of size players from tickets (oldest first). The oldest
+// unmatched ticket anchors each table and takes the closest-rated players within
+// its band; since it has waited longest, its band is the widest in the queue.
+func FormGroups(tickets []*Ticket, players int, now time.Time, cfg Config) [][]*Ticket {
+	used := make(map[string]bool, len(tickets))
+	groups := [][]*Ticket{}
+
+	for _, anchor := range tickets {
+		if used[anchor.ID] {
+			continue
+		}
+		band := cfg.Band(now.Sub(anchor.EnqueuedAt))
+
+		candidates := []*Ticket{}
+		for _, t := range tickets {
+			if t.ID != anchor.ID && !used[t.ID] && math.Abs(t.Rating-anchor.Rating) <= band {
+				candidates = append(candidates, t)
+			}
+		}
+		if len(candidates) < players-1 {
+			continue
+		}
+
+		// Closest rating first; the queue order already breaks ties by age
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return math.Abs(candidates[i].Rating-anchor.Rating) < math.Abs(candidates[j].Rating-anchor.Rating)
+		})
+
+		group := append([]*Ticket{anchor}, candidates[:players-1]...)
+		for _, t := range group {
+			used[t.ID] = true
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// startMatch claims the tickets, locks their entry fees and opens their session,
+// with bots in any seats the group leaves empty. If a player cancelled in the
+// meantime nothing is claimed and the rest wait for the next pass.
+func (m *Matchmaker) startMatch(key string, group []*Ticket, now time.Time) (*engine.GameSession, error) {
+	ids := make([]string, len(group))
+	for i, t := range group {
+		ids[i] = t.ID
+	}
+	claimed, err := m.backend.Claim(key, ids)
+	if err != nil || !claimed {
+		return nil, err
+	}
+	if err := m.lockFees(group); err != nil {
+		return nil, err
+	}
+
+	first := group[0]
+	others := make([]string, 0, len(group)-1)
+	for _, t := range group[1:] {
+		others = append(others, t.UserID)
+	}
+
+	var botIDs []string
+	if seats := first.Players - len(group); seats > 0 {
+		botIDs, err = m.seatBots(first, seats)
+	}
+
+	var sess *engine.GameSession
+	if err == nil {
+		sess, err = m.sessions.CreateSession(first.GameID, first.UserID,
+			session.WithPlayers(others...),
+			session.WithBots(botIDs...),
+			session.WithEntryFee(first.EntryFee),
+			session.WithPrepaidEntry(),
+		)
+		if err != nil {
+			m.unseatBots(first, botIDs)
+		}
+	}
+
+	for _, t := range group {
+		if err != nil {
+			t.Status = StatusCancelled
+			t.Reason = "failed to start session"
+			m.refund(t)
+		} else {
+			t.Status = StatusMatched
+			t.SessionID = sess.SessionID
+			t.MatchedAt = now
+		}
+		if updateErr := m.backend.Update(t); updateErr != nil {
+			log.Printf("matchmaking: failed to save ticket %s: %v", t.ID, updateErr)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %v", err)
+	}
+
+	if len(botIDs) > 0 {
+		if err := m.bots.Play(sess.SessionID); err != nil {
+			log.Printf("matchmaking: bots failed to join %s: %v", sess.SessionID, err)
+		}
+	}
+	return sess, nil
+}
+
+// lockFees takes every player's entry fee now that they have a table. If one of
+// them can't pay, their ticket is cancelled and everyone else gets their fee back
+// and returns to the queue, keeping their place.
+func (m *Matchmaker) lockFees(group []*Ticket) error {
+	for i, t := range group {
+		err := m.wallet.Debit(t.UserID, t.EntryFee, t.ID, "MATCHMAKING_ENTRY")
+		if err == nil {
+			continue
+		}
+
+		for _, paid := range group[:i] {
+			m.refund(paid)
+		}
+		t.Status = StatusCancelled
+		t.Reason = "entry fee failed"
+		if updateErr := m.backend.Update(t); updateErr != nil {
+			log.Printf("matchmaking: failed to save ticket %s: %v", t.ID, updateErr)
+		}
+		for _, other := range group {
+			if other != t {
+				m.requeue(other)
+			}
+		}
+		return fmt.Errorf("entry fee failed for %s: %v", t.ID, err)
+	}
+	return nil
+}
+
+// requeue puts a claimed ticket back in its queue. Its enqueue time is kept, so
+// it keeps its place and its widened band.
+func (m *Matchmaker) requeue(ticket *Ticket) {
+	err := m.backend.Add(ticket)
+	if err == nil {
+		return
+	}
+	// The player queued again elsewhere while this ticket was claimed
+	ticket.Status = StatusCancelled
+	ticket.Reason = "failed to rejoin the queue"
+	if updateErr := m.backend.Update(ticket); updateErr != nil {
+		log.Printf("matchmaking: failed to save ticket %s: %v", ticket.ID, updateErr)
+	}
+	log.Printf("matchmaking: failed to requeue %s: %v", ticket.ID, err)
+}
+
+// seatBots reserves bots for the empty seats and takes their entry fees from
+// the house account
+func (m *Matchmaker) seatBots(anchor *Ticket, seats int) ([]string, error) {
+	botIDs, err := m.bots.Reserve(anchor.GameID, seats, anchor.Rating)
+	if err != nil {
+		return nil, err
+	}
+	for i, botID := range botIDs {
+		if err := m.wallet.DebitBot(botID, anchor.EntryFee, anchor.ID, "MATCHMAKING_ENTRY"); err != nil {
+			m.unseatBots(anchor, botIDs[:i])
+			m.bots.Release(botIDs)
+			return nil, fmt.Errorf("bot entry fee failed: %v", err)
+		}
+	}
+	return botIDs, nil
+}
+
+// unseatBots refunds the entry fees taken for bots whose session never started
+func (m *Matchmaker) unseatBots(anchor *Ticket, botIDs []string) {
+	if len(botIDs) == 0 {
+		return
+	}
+	for _, botID := range botIDs {
+		if err := m.wallet.CreditBot(botID, anchor.EntryFee, anchor.ID, "MATCHMAKING_REFUND"); err != nil {
+			log.Printf("matchmaking: failed to refund bot entry for %s: %v", anchor.ID, err)
+		}
+	}
+	m.bots.Release(botIDs)
+}
+
+func (m *Matchmaker) refund(ticket *Ticket) {
+	if err := m.wallet.Credit(ticket.UserID, ticket.EntryFee, ticket.ID, "MATCHMAKING_REFUND"); err != nil {
+		log.Printf("matchmaking: failed to refund %.2f to %s for %s: %v", ticket.EntryFee, ticket.UserID, ticket.ID, err)
+	}
+}
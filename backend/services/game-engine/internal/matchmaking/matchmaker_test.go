@@ -0,0 +1,377 @@
+package matchmaking
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/session"
+)
+
+const testGameID = "matchmaking_test"
+
+// testGame is a two-to-four player skill game; only its metadata is used
+type testGame struct{ engine.IGame }
+
+func (testGame) GetGameID() string            { return testGameID }
+func (testGame) GetMinPlayers() int           { return 2 }
+func (testGame) GetMaxPlayers() int           { return 4 }
+func (testGame) GetEntryFee() float64         { return 10 }
+func (testGame) GetGameName() string          { return "Matchmaking Test" }
+func (testGame) GetGameType() engine.GameType { return engine.GameTypeSkill }
+
+func init() {
+	registry.GetRegistry().RegisterGame(testGame{})
+}
+
+type fakeClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// fakeWallet keeps balances; users without one can't pay
+type fakeWallet struct {
+	balances map[string]float64
+	mu       sync.Mutex
+}
+
+func newFakeWallet(balances map[string]float64) *fakeWallet {
+	return &fakeWallet{balances: balances}
+}
+
+func (w *fakeWallet) Debit(userID string, amount float64, refID, refType string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.balances[userID] < amount {
+		return errors.New("insufficient balance")
+	}
+	w.balances[userID] -= amount
+	return nil
+}
+
+func (w *fakeWallet) Credit(userID string, amount float64, refID, refType string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.balances[userID] += amount
+	return nil
+}
+
+func (w *fakeWallet) DebitBot(botID string, amount float64, refID, refType string) error {
+	return w.Debit("house", amount, refID, refType)
+}
+
+func (w *fakeWallet) CreditBot(botID string, amount float64, refID, refType string) error {
+	return w.Credit("house", amount, refID, refType)
+}
+
+func (w *fakeWallet) balance(userID string) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.balances[userID]
+}
+
+// fakeSessions opens sessions without running a game
+type fakeSessions struct {
+	created []*engine.GameSession
+	fail    bool
+}
+
+func (s *fakeSessions) CreateSession(gameID, userID string, opts ...session.SessionOption) (*engine.GameSession, error) {
+	if s.fail {
+		return nil, errors.New("game unavailable")
+	}
+	sess := &engine.GameSession{
+		SessionID: fmt.Sprintf("session_%d", len(s.created)+1),
+		GameID:    gameID,
+		Players:   []*engine.Player{{UserID: userID}},
+	}
+	for _, opt := range opts {
+		opt(sess)
+	}
+	s.created = append(s.created, sess)
+	return sess, nil
+}
+
+// fakeBots hands out numbered bots
+type fakeBots struct {
+	reserved []string
+	played   []string
+}
+
+func (b *fakeBots) Supports(gameID string) bool { return gameID == testGameID }
+
+func (b *fakeBots) Reserve(gameID string, count int, rating float64) ([]string, error) {
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("bot_%d", len(b.reserved)+1)
+		b.reserved = append(b.reserved, ids[i])
+	}
+	return ids, nil
+}
+
+func (b *fakeBots) Release(botIDs []string) {}
+
+func (b *fakeBots) Play(sessionID string) error {
+	b.played = append(b.played, sessionID)
+	return nil
+}
+
+type staticRatings map[string]float64
+
+func (r staticRatings) Rating(userID, gameID string) (float64, error) {
+	if rating, ok := r[userID]; ok {
+		return rating, nil
+	}
+	return DefaultRating, nil
+}
+
+type harness struct {
+	mm       *Matchmaker
+	backend  *MemoryBackend
+	clock    *fakeClock
+	wallet   *fakeWallet
+	sessions *fakeSessions
+}
+
+func newHarness(cfg Config, ratings staticRatings, balances map[string]float64) *harness {
+	h := &harness{
+		backend:  NewMemoryBackend(),
+		clock:    newFakeClock(),
+		wallet:   newFakeWallet(balances),
+		sessions: &fakeSessions{},
+	}
+	h.backend.clock = h.clock
+	h.mm = NewMatchmaker(cfg, h.backend, h.sessions, h.wallet, ratings, h.clock)
+	return h
+}
+
+func (h *harness) enqueue(t *testing.T, userID string) *Ticket {
+	t.Helper()
+	ticket, err := h.mm.Enqueue(userID, testGameID, 0, 0)
+	if err != nil {
+		t.Fatalf("enqueue %s: %v", userID, err)
+	}
+	return ticket
+}
+
+func (h *harness) match(t *testing.T) []*engine.GameSession {
+	t.Helper()
+	started, err := h.mm.MatchOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return started
+}
+
+func (h *harness) ticket(t *testing.T, id string) *Ticket {
+	t.Helper()
+	ticket, err := h.backend.Get(id)
+	if err != nil {
+		t.Fatalf("ticket %s: %v", id, err)
+	}
+	return ticket
+}
+
+func noBots() Config {
+	cfg := DefaultConfig()
+	cfg.BotFillAfter = 0
+	return cfg
+}
+
+func TestBandWidensWithWaitingTime(t *testing.T) {
+	cfg := noBots() // 100 at once, +50 every 5s
+	h := newHarness(cfg, staticRatings{"alice": 1500, "bob": 1720}, map[string]float64{"alice": 100, "bob": 100})
+
+	alice := h.enqueue(t, "alice")
+	h.clock.Advance(2 * time.Second)
+	h.enqueue(t, "bob")
+
+	// alice's band: 100 at 2s, 200 at 10s, 250 at 15s
+	for _, at := range []time.Duration{2 * time.Second, 10 * time.Second} {
+		h.clock.Advance(at - h.clock.Now().Sub(alice.EnqueuedAt))
+		if started := h.match(t); len(started) != 0 {
+			t.Fatalf("matched 220 apart after %s", at)
+		}
+	}
+
+	h.clock.Advance(5 * time.Second)
+	started := h.match(t)
+	if len(started) != 1 || len(started[0].Players) != 2 {
+		t.Fatalf("started %v, want one two-player session after 15s", started)
+	}
+	if got := h.ticket(t, alice.ID); got.Status != StatusMatched || !got.MatchedAt.Equal(h.clock.Now()) {
+		t.Fatalf("alice's ticket %+v, want matched at %s", got, h.clock.Now())
+	}
+}
+
+func TestEntryFeeTakenOnMatch(t *testing.T) {
+	h := newHarness(noBots(), nil, map[string]float64{"alice": 100, "bob": 100, "carol": 100})
+
+	h.enqueue(t, "alice")
+	carol := h.enqueue(t, "carol")
+	if h.wallet.balance("alice") != 100 {
+		t.Fatalf("alice paid %.2f just to queue", 100-h.wallet.balance("alice"))
+	}
+
+	// Cancelling costs nothing and refunds nothing
+	if _, err := h.mm.Cancel("carol", carol.ID); err != nil {
+		t.Fatal(err)
+	}
+	if h.wallet.balance("carol") != 100 {
+		t.Fatalf("carol has %.2f after cancelling, want 100", h.wallet.balance("carol"))
+	}
+	if _, err := h.mm.Cancel("carol", carol.ID); !errors.Is(err, ErrNotQueued) {
+		t.Fatalf("second cancel: err = %v, want ErrNotQueued", err)
+	}
+
+	h.enqueue(t, "bob")
+	started := h.match(t)
+	if len(started) != 1 {
+		t.Fatalf("started %d sessions, want 1", len(started))
+	}
+	if s := started[0]; !s.Prepaid || s.EntryFee != 10 {
+		t.Fatalf("session prepaid=%v fee=%.2f, want prepaid at 10", s.Prepaid, s.EntryFee)
+	}
+	for _, user := range []string{"alice", "bob"} {
+		if got := h.wallet.balance(user); got != 90 {
+			t.Errorf("%s has %.2f after the match, want 90", user, got)
+		}
+	}
+}
+
+func TestPlayerWhoCannotPayIsDropped(t *testing.T) {
+	h := newHarness(noBots(), nil, map[string]float64{"alice": 100, "bob": 5, "carol": 100})
+
+	alice := h.enqueue(t, "alice")
+	h.clock.Advance(time.Second)
+	bob := h.enqueue(t, "bob")
+	h.clock.Advance(time.Second)
+
+	if started := h.match(t); len(started) != 0 {
+		t.Fatalf("started %d sessions with a player who can't pay", len(started))
+	}
+	if got := h.ticket(t, bob.ID); got.Status != StatusCancelled || got.Reason == "" {
+		t.Fatalf("bob's ticket %+v, want cancelled with a reason", got)
+	}
+	if got := h.ticket(t, alice.ID); got.Status != StatusQueued {
+		t.Fatalf("alice's ticket %+v, want queued again", got)
+	}
+	if h.wallet.balance("alice") != 100 {
+		t.Fatalf("alice has %.2f, want her fee back", h.wallet.balance("alice"))
+	}
+
+	// alice kept her place: she anchors the next table
+	h.enqueue(t, "carol")
+	started := h.match(t)
+	if len(started) != 1 || started[0].Players[0].UserID != "alice" {
+		t.Fatalf("started %v, want alice's table", started)
+	}
+}
+
+func TestFailedSessionRefundsEveryone(t *testing.T) {
+	h := newHarness(noBots(), nil, map[string]float64{"alice": 100, "bob": 100})
+	h.sessions.fail = true
+
+	alice := h.enqueue(t, "alice")
+	h.enqueue(t, "bob")
+	h.match(t)
+
+	if got := h.ticket(t, alice.ID); got.Status != StatusCancelled {
+		t.Fatalf("alice's ticket %+v, want cancelled", got)
+	}
+	for _, user := range []string{"alice", "bob"} {
+		if got := h.wallet.balance(user); got != 100 {
+			t.Errorf("%s has %.2f, want 100", user, got)
+		}
+	}
+}
+
+func TestBotsFillSeatsAfterWaiting(t *testing.T) {
+	cfg := DefaultConfig() // Bots after 45s
+	h := newHarness(cfg, nil, map[string]float64{"alice": 100, "house": 100})
+	bots := &fakeBots{}
+	h.mm.SetBots(bots)
+
+	alice := h.enqueue(t, "alice")
+	h.clock.Advance(44 * time.Second)
+	if started := h.match(t); len(started) != 0 {
+		t.Fatal("bots seated before BotFillAfter")
+	}
+
+	h.clock.Advance(time.Second)
+	started := h.match(t)
+	if len(started) != 1 {
+		t.Fatalf("started %d sessions, want 1", len(started))
+	}
+	players := started[0].Players
+	if len(players) != 2 || players[0].UserID != "alice" || !players[1].IsBot {
+		t.Fatalf("players %+v, want alice and a bot", players)
+	}
+	if len(bots.played) != 1 || bots.played[0] != started[0].SessionID {
+		t.Fatalf("bots played %v, want %s", bots.played, started[0].SessionID)
+	}
+	if got := h.ticket(t, alice.ID); got.Status != StatusMatched {
+		t.Fatalf("alice's ticket %+v, want matched", got)
+	}
+	if got := h.wallet.balance("house"); got != 90 {
+		t.Fatalf("house paid %.2f for the bot, want the 10 entry fee", 100-got)
+	}
+}
+
+func TestFinishedTicketsAreEvicted(t *testing.T) {
+	h := newHarness(noBots(), nil, map[string]float64{})
+
+	ticket := h.enqueue(t, "alice")
+	if _, err := h.mm.Cancel("alice", ticket.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	h.clock.Advance(finishedTicketTTL - time.Second)
+	h.enqueue(t, "bob")
+	if _, err := h.mm.Ticket("alice", ticket.ID); err != nil {
+		t.Fatalf("cancelled ticket gone before its TTL: %v", err)
+	}
+
+	h.clock.Advance(2 * time.Second)
+	h.enqueue(t, "carol")
+	if _, err := h.mm.Ticket("alice", ticket.ID); !errors.Is(err, ErrTicketNotFound) {
+		t.Fatalf("err = %v, want the cancelled ticket evicted", err)
+	}
+	if len(h.backend.tickets) != 2 || len(h.backend.left) != 0 {
+		t.Fatalf("%d tickets and %d departures kept, want only the 2 queued", len(h.backend.tickets), len(h.backend.left))
+	}
+}
+
+func TestRequeuedTicketIsNotEvicted(t *testing.T) {
+	h := newHarness(noBots(), nil, map[string]float64{"alice": 100})
+
+	alice := h.enqueue(t, "alice")
+	h.enqueue(t, "bob") // Can't pay, so alice goes back to the queue
+	h.match(t)
+
+	h.clock.Advance(2 * finishedTicketTTL)
+	h.enqueue(t, "carol")
+	if got := h.ticket(t, alice.ID); got.Status != StatusQueued {
+		t.Fatalf("alice's ticket %+v, want still queued", got)
+	}
+}
@@ -0,0 +1,173 @@
+package matchmaking
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryBackend keeps queues in process, for a single game-engine instance.
+// Tickets that left their queue are forgotten after finishedTicketTTL, like the
+// Redis backend lets them expire.
+type MemoryBackend struct {
+	tickets  map[string]*Ticket         // ticketID -> ticket
+	queues   map[string]map[string]bool // queue key -> queued ticket IDs
+	users    map[string]string          // userID -> queued ticket ID
+	left     map[string]time.Time       // ticketID -> when it last left its queue
+	finished []departure                // Tickets that left their queue, in the order they left
+	clock    Clock
+	mu       sync.Mutex
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		tickets: make(map[string]*Ticket),
+		queues:  make(map[string]map[string]bool),
+		users:   make(map[string]string),
+		left:    make(map[string]time.Time),
+		clock:   realClock{},
+	}
+}
+
+func (b *MemoryBackend) Add(ticket *Ticket) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.evict()
+	if _, ok := b.users[ticket.UserID]; ok {
+		return ErrAlreadyQueued
+	}
+
+	stored := *ticket
+	key := ticket.QueueKey()
+	if b.queues[key] == nil {
+		b.queues[key] = make(map[string]bool)
+	}
+	b.queues[key][ticket.ID] = true
+	b.tickets[ticket.ID] = &stored
+	b.users[ticket.UserID] = ticket.ID
+	delete(b.left, ticket.ID) // Requeued after a failed match
+	return nil
+}
+
+func (b *MemoryBackend) Remove(ticketID string) (*Ticket, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ticket, ok := b.tickets[ticketID]
+	if !ok {
+		return nil, ErrTicketNotFound
+	}
+	key := ticket.QueueKey()
+	if !b.queues[key][ticketID] {
+		return nil, ErrNotQueued
+	}
+
+	b.dequeue(key, ticket)
+	copied := *ticket
+	return &copied, nil
+}
+
+func (b *MemoryBackend) Claim(key string, ticketIDs []string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, id := range ticketIDs {
+		if !b.queues[key][id] {
+			return false, nil
+		}
+	}
+	for _, id := range ticketIDs {
+		b.dequeue(key, b.tickets[id])
+	}
+	return true, nil
+}
+
+func (b *MemoryBackend) dequeue(key string, ticket *Ticket) {
+	delete(b.queues[key], ticket.ID)
+	if len(b.queues[key]) == 0 {
+		delete(b.queues, key)
+	}
+	delete(b.users, ticket.UserID)
+	now := b.clock.Now()
+	b.left[ticket.ID] = now
+	b.finished = append(b.finished, departure{ticketID: ticket.ID, at: now})
+}
+
+type departure struct {
+	ticketID string
+	at       time.Time
+}
+
+// evict forgets tickets that left their queue more than finishedTicketTTL ago
+func (b *MemoryBackend) evict() {
+	cutoff := b.clock.Now().Add(-finishedTicketTTL)
+	for len(b.finished) > 0 && b.finished[0].at.Before(cutoff) {
+		d := b.finished[0]
+		b.finished = b.finished[1:]
+		// Skip tickets requeued since, or that left again later
+		if left, ok := b.left[d.ticketID]; ok && left.Equal(d.at) {
+			delete(b.left, d.ticketID)
+			delete(b.tickets, d.ticketID)
+		}
+	}
+}
+
+func (b *MemoryBackend) Queued(key string) ([]*Ticket, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tickets := make([]*Ticket, 0, len(b.queues[key]))
+	for id := range b.queues[key] {
+		copied := *b.tickets[id]
+		tickets = append(tickets, &copied)
+	}
+	sortOldestFirst(tickets)
+	return tickets, nil
+}
+
+func (b *MemoryBackend) Queues() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	keys := make([]string, 0, len(b.queues))
+	for key := range b.queues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (b *MemoryBackend) Get(ticketID string) (*Ticket, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ticket, ok := b.tickets[ticketID]
+	if !ok {
+		return nil, ErrTicketNotFound
+	}
+	copied := *ticket
+	return &copied, nil
+}
+
+func (b *MemoryBackend) Update(ticket *Ticket) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.tickets[ticket.ID]; !ok {
+		return ErrTicketNotFound
+	}
+	stored := *ticket
+	b.tickets[ticket.ID] = &stored
+	return nil
+}
+
+// sortOldestFirst orders tickets by enqueue time, then ID for a stable order
+func sortOldestFirst(tickets []*Ticket) {
+	sort.Slice(tickets, func(i, j int) bool {
+		if !tickets[i].EnqueuedAt.Equal(tickets[j].EnqueuedAt) {
+			return tickets[i].EnqueuedAt.Before(tickets[j].EnqueuedAt)
+		}
+		return tickets[i].ID < tickets[j].ID
+	})
+}
@@ -0,0 +1,207 @@
+package matchmaking
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Keys:
+//
+//	mm:queues           set of queue keys
+//	mm:queue:<key>      sorted set of ticket IDs scored by enqueue time
+//	mm:ticket:<id>      ticket JSON
+//	mm:user:<userID>    ID of the user's queued ticket
+const (
+	redisQueuesKey = "mm:queues"
+
+	// How long tickets that left their queue stay readable
+	finishedTicketTTL = time.Hour
+)
+
+func redisQueueKey(key string) string   { return "mm:queue:" + key }
+func redisTicketKey(id string) string   { return "mm:ticket:" + id }
+func redisUserKey(userID string) string { return "mm:user:" + userID }
+
+// removeScript dequeues one ticket if it is still queued.
+// KEYS: queue, user. ARGV: ticket ID.
+var removeScript = redis.NewScript(`
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+if redis.call("GET", KEYS[2]) == ARGV[1] then
+	redis.call("DEL", KEYS[2])
+end
+return 1
+`)
+
+// claimScript dequeues every ticket or none.
+// KEYS: queue, then one user key per ticket. ARGV: ticket IDs in the same order.
+var claimScript = redis.NewScript(`
+for i = 1, #ARGV do
+	if not redis.call("ZSCORE", KEYS[1], ARGV[i]) then
+		return 0
+	end
+end
+for i = 1, #ARGV do
+	redis.call("ZREM", KEYS[1], ARGV[i])
+	if redis.call("GET", KEYS[i + 1]) == ARGV[i] then
+		redis.call("DEL", KEYS[i + 1])
+	end
+end
+return 1
+`)
+
+// RedisBackend shares queues between game-engine instances
+type RedisBackend struct {
+	Redis *redis.Client
+}
+
+func NewRedisBackend(redisURL string) (*RedisBackend, error) {
+	opt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisBackend{Redis: redis.NewClient(opt)}, nil
+}
+
+func (b *RedisBackend) Add(ticket *Ticket) error {
+	ctx := context.Background()
+
+	// One queue per user at a time
+	ok, err := b.Redis.SetNX(ctx, redisUserKey(ticket.UserID), ticket.ID, 0).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAlreadyQueued
+	}
+
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+	key := ticket.QueueKey()
+	_, err = b.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redisTicketKey(ticket.ID), data, 0)
+		pipe.ZAdd(ctx, redisQueueKey(key), &redis.Z{Score: float64(ticket.EnqueuedAt.UnixNano()), Member: ticket.ID})
+		pipe.SAdd(ctx, redisQueuesKey, key)
+		return nil
+	})
+	if err != nil {
+		b.Redis.Del(ctx, redisUserKey(ticket.UserID))
+	}
+	return err
+}
+
+func (b *RedisBackend) Remove(ticketID string) (*Ticket, error) {
+	ticket, err := b.Get(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{redisQueueKey(ticket.QueueKey()), redisUserKey(ticket.UserID)}
+	removed, err := removeScript.Run(context.Background(), b.Redis, keys, ticketID).Int()
+	if err != nil {
+		return nil, err
+	}
+	if removed == 0 {
+		return nil, ErrNotQueued
+	}
+	return ticket, nil
+}
+
+func (b *RedisBackend) Claim(key string, ticketIDs []string) (bool, error) {
+	ctx := context.Background()
+
+	keys := []string{redisQueueKey(key)}
+	args := make([]interface{}, 0, len(ticketIDs))
+	for _, id := range ticketIDs {
+		ticket, err := b.Get(id)
+		if err != nil {
+			return false, err
+		}
+		keys = append(keys, redisUserKey(ticket.UserID))
+		args = append(args, id)
+	}
+
+	claimed, err := claimScript.Run(ctx, b.Redis, keys, args...).Int()
+	if err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+func (b *RedisBackend) Queued(key string) ([]*Ticket, error) {
+	ctx := context.Background()
+
+	ids, err := b.Redis.ZRange(ctx, redisQueueKey(key), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		// Drop empty queues from the index; a racing Add re-adds its key
+		b.Redis.SRem(ctx, redisQueuesKey, key)
+		return []*Ticket{}, nil
+	}
+
+	ticketKeys := make([]string, len(ids))
+	for i, id := range ids {
+		ticketKeys[i] = redisTicketKey(id)
+	}
+	values, err := b.Redis.MGet(ctx, ticketKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make([]*Ticket, 0, len(values))
+	for _, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			continue // Expired or removed since ZRANGE
+		}
+		var ticket Ticket
+		if err := json.Unmarshal([]byte(data), &ticket); err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, &ticket)
+	}
+	sortOldestFirst(tickets)
+	return tickets, nil
+}
+
+func (b *RedisBackend) Queues() ([]string, error) {
+	keys, err := b.Redis.SMembers(context.Background(), redisQueuesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (b *RedisBackend) Get(ticketID string) (*Ticket, error) {
+	data, err := b.Redis.Get(context.Background(), redisTicketKey(ticketID)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrTicketNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ticket Ticket
+	if err := json.Unmarshal(data, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (b *RedisBackend) Update(ticket *Ticket) error {
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+	return b.Redis.Set(context.Background(), redisTicketKey(ticket.ID), data, finishedTicketTTL).Err()
+}
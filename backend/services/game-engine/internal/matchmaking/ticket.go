@@ -0,0 +1,88 @@
+// Package matchmaking queues players for skill games and seats them together
+// once it finds opponents of a similar rating.
+package matchmaking
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ticket status
+const (
+	StatusQueued    = "QUEUED"
+	StatusMatched   = "MATCHED"
+	StatusCancelled = "CANCELLED"
+)
+
+var (
+	ErrAlreadyQueued  = errors.New("already in a matchmaking queue")
+	ErrTicketNotFound = errors.New("ticket not found")
+	ErrNotQueued      = errors.New("ticket is no longer queued")
+)
+
+// Ticket is one player's place in a queue
+type Ticket struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	GameID     string    `json:"game_id"`
+	EntryFee   float64   `json:"entry_fee"`
+	Players    int       `json:"players"` // Table size being queued for
+	Rating     float64   `json:"rating"`
+	Status     string    `json:"status"`
+	SessionID  string    `json:"session_id,omitempty"` // Set once matched
+	Reason     string    `json:"reason,omitempty"`     // Why a ticket was cancelled by the system
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	MatchedAt  time.Time `json:"matched_at,omitempty"`
+}
+
+// QueueKey identifies the queue a ticket waits in: players only meet others
+// queued for the same game, stake and table size
+func (t *Ticket) QueueKey() string {
+	return fmt.Sprintf("%s:%.2f:%d", t.GameID, t.EntryFee, t.Players)
+}
+
+// Backend stores the queues. Add, Remove and Claim must be atomic, since a
+// player may cancel while the matcher is claiming their ticket.
+type Backend interface {
+	// Add queues the ticket, failing with ErrAlreadyQueued if the user already waits
+	Add(ticket *Ticket) error
+	// Remove takes a queued ticket out of its queue, failing with ErrNotQueued if
+	// it was already matched or cancelled
+	Remove(ticketID string) (*Ticket, error)
+	// Claim removes all of ticketIDs from queue key together, or none of them
+	// (returning false) if any has left the queue
+	Claim(key string, ticketIDs []string) (bool, error)
+	// Queued lists a queue's tickets, oldest first
+	Queued(key string) ([]*Ticket, error)
+	// Queues lists the keys of queues that may hold tickets
+	Queues() ([]string, error)
+	// Get returns a ticket in any status
+	Get(ticketID string) (*Ticket, error)
+	// Update saves a ticket that has left its queue
+	Update(ticket *Ticket) error
+}
+
+// Clock is the matcher's time source, swapped for a fake one in tests
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// RatingSource supplies a player's skill rating for a game
+type RatingSource interface {
+	Rating(userID, gameID string) (float64, error)
+}
+
+// DefaultRating is the rating of a player with no history
+const DefaultRating = 1500.0
+
+// FixedRatings rates every player the same, so queues match first come first served
+type FixedRatings struct{}
+
+func (FixedRatings) Rating(userID, gameID string) (float64, error) {
+	return DefaultRating, nil
+}
@@ -0,0 +1,118 @@
+// Package rating keeps Glicko-2 skill ratings per player per game.
+package rating
+
+import "math"
+
+// Glicko-2 defaults for a new player
+const (
+	DefaultRating     = 1500.0
+	DefaultRD         = 350.0
+	DefaultVolatility = 0.06
+
+	// Tau limits how fast volatility changes; Glickman suggests 0.3-1.2
+	Tau = 0.5
+
+	glickoScale = 173.7178
+	convergence = 0.000001
+)
+
+// Glicko is a rating with its deviation and volatility
+type Glicko struct {
+	Rating     float64
+	RD         float64
+	Volatility float64
+}
+
+// NewGlicko returns the rating of a player with no games
+func NewGlicko() Glicko {
+	return Glicko{Rating: DefaultRating, RD: DefaultRD, Volatility: DefaultVolatility}
+}
+
+// Opponent is one game against another player: Score is 1 for a win, 0.5 for a
+// draw and 0 for a loss
+type Opponent struct {
+	Glicko
+	Score float64
+}
+
+// Update returns the player's rating after a rating period against opponents.
+// Every finished session is rated as its own period.
+func Update(player Glicko, opponents []Opponent) Glicko {
+	mu := (player.Rating - DefaultRating) / glickoScale
+	phi := player.RD / glickoScale
+
+	if len(opponents) == 0 {
+		// No games: only the deviation grows
+		return Glicko{
+			Rating:     player.Rating,
+			RD:         math.Sqrt(phi*phi+player.Volatility*player.Volatility) * glickoScale,
+			Volatility: player.Volatility,
+		}
+	}
+
+	var vInv, improvement float64
+	for _, o := range opponents {
+		muJ := (o.Rating - DefaultRating) / glickoScale
+		gJ := g(o.RD / glickoScale)
+		e := expectedScore(mu, muJ, gJ)
+		vInv += gJ * gJ * e * (1 - e)
+		improvement += gJ * (o.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * improvement
+
+	sigma := newVolatility(phi, player.Volatility, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phiNew := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	muNew := mu + phiNew*phiNew*improvement
+
+	return Glicko{
+		Rating:     muNew*glickoScale + DefaultRating,
+		RD:         phiNew * glickoScale,
+		Volatility: sigma,
+	}
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expectedScore(mu, muJ, gJ float64) float64 {
+	return 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+}
+
+// newVolatility solves for the new volatility with the Illinois algorithm
+// (step 5 of Glickman's "Example of the Glicko-2 system")
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(Tau*Tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*Tau) < 0 {
+			k++
+		}
+		B = a - k*Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
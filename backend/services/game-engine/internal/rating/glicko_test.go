@@ -0,0 +1,47 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+// TestUpdateMatchesGlickmansExample checks Update against the worked example
+// in Glickman's "Example of the Glicko-2 system" (tau 0.5)
+func TestUpdateMatchesGlickmansExample(t *testing.T) {
+	player := Glicko{Rating: 1500, RD: 200, Volatility: 0.06}
+	got := Update(player, []Opponent{
+		{Glicko: Glicko{Rating: 1400, RD: 30, Volatility: 0.06}, Score: 1},
+		{Glicko: Glicko{Rating: 1550, RD: 100, Volatility: 0.06}, Score: 0},
+		{Glicko: Glicko{Rating: 1700, RD: 300, Volatility: 0.06}, Score: 0},
+	})
+
+	checks := []struct {
+		name      string
+		got, want float64
+		tolerance float64
+	}{
+		{"rating", got.Rating, 1464.06, 0.01},
+		{"RD", got.RD, 151.52, 0.01},
+		{"volatility", got.Volatility, 0.05999, 0.00001},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > c.tolerance {
+			t.Errorf("%s = %.5f, want %.5f", c.name, c.got, c.want)
+		}
+	}
+}
+
+// A period without games only widens the deviation, by the volatility
+func TestUpdateWithoutGames(t *testing.T) {
+	player := Glicko{Rating: 1620, RD: 200, Volatility: 0.06}
+	got := Update(player, nil)
+
+	phi := 200 / glickoScale
+	wantRD := math.Sqrt(phi*phi+0.06*0.06) * glickoScale
+	if got.Rating != 1620 || got.Volatility != 0.06 || math.Abs(got.RD-wantRD) > 1e-9 {
+		t.Fatalf("got %+v, want rating and volatility kept and RD %.4f", got, wantRD)
+	}
+	if got.RD <= player.RD || got.RD > 201 {
+		t.Fatalf("RD %.4f, want slightly above 200", got.RD)
+	}
+}
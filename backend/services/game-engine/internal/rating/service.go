@@ -0,0 +1,165 @@
+package rating
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+)
+
+// Outcomes recorded in the history
+const (
+	OutcomeWin  = "WIN"
+	OutcomeLoss = "LOSS"
+)
+
+// Service rates skill-game players from their finished sessions
+type Service struct {
+	store Store
+	mu    sync.Mutex // Serializes updates so concurrent sessions don't lose each other's changes
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// Player returns the player's rating, or the default one if they have not played the game
+func (s *Service) Player(userID, gameID string) (*PlayerRating, error) {
+	r, err := s.store.Get(userID, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		initial := NewGlicko()
+		r = &PlayerRating{
+			UserID:     userID,
+			GameID:     gameID,
+			Rating:     initial.Rating,
+			RD:         initial.RD,
+			Volatility: initial.Volatility,
+		}
+	}
+	return r, nil
+}
+
+// Rating returns just the rating value (matchmaking and tournament seeding)
+func (s *Service) Rating(userID, gameID string) (float64, error) {
+	r, err := s.Player(userID, gameID)
+	if err != nil {
+		return 0, err
+	}
+	return r.Rating, nil
+}
+
+func (s *Service) History(userID, gameID string, limit int) ([]*HistoryEntry, error) {
+	return s.store.History(userID, gameID, limit)
+}
+
+func (s *Service) Ladder(gameID string, limit, offset int) ([]*PlayerRating, error) {
+	return s.store.Ladder(gameID, limit, offset)
+}
+
+// HandleGameEnd is the session manager hook; see RecordResult
+func (s *Service) HandleGameEnd(session *engine.GameSession, result *engine.GameResult) {
+	if err := s.RecordResult(session, result); err != nil {
+		log.Printf("rating: failed to rate session %s: %v", session.SessionID, err)
+	}
+}
+
+// RecordResult rates a finished skill-game session. The winners (WinnerID and
+// anyone paid a prize) each beat every other player; players with the same
+// outcome are not compared. Bots are neither rated nor rated against, and
+// sessions without both a winning and a losing player are not rated.
+func (s *Service) RecordResult(session *engine.GameSession, result *engine.GameResult) error {
+	players := []*engine.Player{}
+	for _, p := range session.Players {
+		if !p.IsBot {
+			players = append(players, p)
+		}
+	}
+	if result == nil || session.Shared || len(players) < 2 {
+		return nil
+	}
+	game, err := registry.GetRegistry().GetGame(session.GameID)
+	if err != nil {
+		return err
+	}
+	if game.GetGameType() != engine.GameTypeSkill {
+		return nil
+	}
+
+	won := make(map[string]bool, len(players))
+	if result.WinnerID != "" {
+		won[result.WinnerID] = true
+	}
+	for userID, prize := range result.Prizes {
+		if prize > 0 {
+			won[userID] = true
+		}
+	}
+	winners := 0
+	for _, p := range players {
+		if won[p.UserID] {
+			winners++
+		}
+	}
+	if winners == 0 || winners == len(players) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Everyone is rated against the ratings from before this session
+	before := make([]*PlayerRating, len(players))
+	for i, p := range players {
+		if before[i], err = s.Player(p.UserID, session.GameID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	ratings := make([]*PlayerRating, 0, len(before))
+	history := make([]*HistoryEntry, 0, len(before))
+	for i, player := range before {
+		opponents := []Opponent{}
+		for j, other := range before {
+			if i == j || won[player.UserID] == won[other.UserID] {
+				continue
+			}
+			score := 0.0
+			if won[player.UserID] {
+				score = 1
+			}
+			opponents = append(opponents, Opponent{Glicko: other.Glicko(), Score: score})
+		}
+
+		updated := Update(player.Glicko(), opponents)
+		next := *player
+		next.Rating, next.RD, next.Volatility = updated.Rating, updated.RD, updated.Volatility
+		next.GamesPlayed++
+		outcome := OutcomeLoss
+		if won[player.UserID] {
+			next.Wins++
+			outcome = OutcomeWin
+		}
+		next.UpdatedAt = now
+
+		ratings = append(ratings, &next)
+		history = append(history, &HistoryEntry{
+			UserID:       player.UserID,
+			GameID:       session.GameID,
+			SessionID:    session.SessionID,
+			Outcome:      outcome,
+			RatingBefore: player.Rating,
+			RatingAfter:  next.Rating,
+			RD:           next.RD,
+			Volatility:   next.Volatility,
+			CreatedAt:    now,
+		})
+	}
+
+	return s.store.Save(ratings, history)
+}
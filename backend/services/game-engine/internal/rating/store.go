@@ -0,0 +1,246 @@
+package rating
+
+import (
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PlayerRating is a player's current rating in one game
+type PlayerRating struct {
+	UserID      string    `json:"user_id"`
+	GameID      string    `json:"game_id"`
+	Rating      float64   `json:"rating"`
+	RD          float64   `json:"rd"`
+	Volatility  float64   `json:"volatility"`
+	GamesPlayed int       `json:"games_played"`
+	Wins        int       `json:"wins"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Glicko returns the rating parameters
+func (p *PlayerRating) Glicko() Glicko {
+	return Glicko{Rating: p.Rating, RD: p.RD, Volatility: p.Volatility}
+}
+
+// Provisional is true until the rating has settled enough to be ranked with confidence
+func (p *PlayerRating) Provisional() bool {
+	return p.RD > ProvisionalRD
+}
+
+// ProvisionalRD is the deviation above which a rating counts as provisional
+const ProvisionalRD = 110.0
+
+// HistoryEntry records one rating change
+type HistoryEntry struct {
+	UserID       string    `json:"user_id"`
+	GameID       string    `json:"game_id"`
+	SessionID    string    `json:"session_id"`
+	Outcome      string    `json:"outcome"` // WIN or LOSS
+	RatingBefore float64   `json:"rating_before"`
+	RatingAfter  float64   `json:"rating_after"`
+	RD           float64   `json:"rd"`
+	Volatility   float64   `json:"volatility"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Store persists ratings and their history
+type Store interface {
+	// Get returns nil if the player has no rating in the game yet
+	Get(userID, gameID string) (*PlayerRating, error)
+	// Save stores the new ratings of one session together with their history
+	Save(ratings []*PlayerRating, history []*HistoryEntry) error
+	// History returns the player's latest rating changes, newest first
+	History(userID, gameID string, limit int) ([]*HistoryEntry, error)
+	// Ladder lists a game's players by rating, highest first
+	Ladder(gameID string, limit, offset int) ([]*PlayerRating, error)
+}
+
+// MemoryStore keeps ratings in process, for running without a database
+type MemoryStore struct {
+	ratings map[string]*PlayerRating   // gameID:userID -> rating
+	history map[string][]*HistoryEntry // gameID:userID -> changes, oldest first
+	mu      sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		ratings: make(map[string]*PlayerRating),
+		history: make(map[string][]*HistoryEntry),
+	}
+}
+
+func memoryKey(userID, gameID string) string {
+	return gameID + ":" + userID
+}
+
+func (s *MemoryStore) Get(userID, gameID string) (*PlayerRating, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.ratings[memoryKey(userID, gameID)]
+	if !ok {
+		return nil, nil
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (s *MemoryStore) Save(ratings []*PlayerRating, history []*HistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range ratings {
+		copied := *r
+		s.ratings[memoryKey(r.UserID, r.GameID)] = &copied
+	}
+	for _, h := range history {
+		key := memoryKey(h.UserID, h.GameID)
+		copied := *h
+		s.history[key] = append(s.history[key], &copied)
+	}
+	return nil
+}
+
+func (s *MemoryStore) History(userID, gameID string, limit int) ([]*HistoryEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := s.history[memoryKey(userID, gameID)]
+	entries := []*HistoryEntry{}
+	for i := len(all) - 1; i >= 0 && len(entries) < limit; i-- {
+		copied := *all[i]
+		entries = append(entries, &copied)
+	}
+	return entries, nil
+}
+
+func (s *MemoryStore) Ladder(gameID string, limit, offset int) ([]*PlayerRating, error) {
+	s.mu.RLock()
+	all := []*PlayerRating{}
+	for _, r := range s.ratings {
+		if r.GameID == gameID {
+			copied := *r
+			all = append(all, &copied)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Rating != all[j].Rating {
+			return all[i].Rating > all[j].Rating
+		}
+		return all[i].UserID < all[j].UserID
+	})
+	if offset >= len(all) {
+		return []*PlayerRating{}, nil
+	}
+	all = all[offset:]
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	return all, nil
+}
+
+// PostgresStore keeps ratings in player_ratings / rating_history
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+func (s *PostgresStore) Get(userID, gameID string) (*PlayerRating, error) {
+	r := &PlayerRating{}
+	err := s.DB.QueryRow(`
+		SELECT user_id, game_id, rating, rd, volatility, games_played, wins, updated_at
+		FROM player_ratings WHERE user_id = $1 AND game_id = $2
+	`, userID, gameID).Scan(&r.UserID, &r.GameID, &r.Rating, &r.RD, &r.Volatility, &r.GamesPlayed, &r.Wins, &r.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Save upserts the ratings and appends the history in one transaction
+func (s *PostgresStore) Save(ratings []*PlayerRating, history []*HistoryEntry) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, r := range ratings {
+		_, err = tx.Exec(`
+			INSERT INTO player_ratings (user_id, game_id, rating, rd, volatility, games_played, wins, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (user_id, game_id) DO UPDATE SET
+				rating = EXCLUDED.rating, rd = EXCLUDED.rd, volatility = EXCLUDED.volatility,
+				games_played = EXCLUDED.games_played, wins = EXCLUDED.wins, updated_at = EXCLUDED.updated_at
+		`, r.UserID, r.GameID, r.Rating, r.RD, r.Volatility, r.GamesPlayed, r.Wins, r.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, h := range history {
+		_, err = tx.Exec(`
+			INSERT INTO rating_history (user_id, game_id, session_id, outcome, rating_before, rating_after, rd, volatility, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, h.UserID, h.GameID, h.SessionID, h.Outcome, h.RatingBefore, h.RatingAfter, h.RD, h.Volatility, h.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresStore) History(userID, gameID string, limit int) ([]*HistoryEntry, error) {
+	rows, err := s.DB.Query(`
+		SELECT user_id, game_id, session_id, outcome, rating_before, rating_after, rd, volatility, created_at
+		FROM rating_history WHERE user_id = $1 AND game_id = $2
+		ORDER BY created_at DESC, id DESC LIMIT $3
+	`, userID, gameID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*HistoryEntry{}
+	for rows.Next() {
+		h := &HistoryEntry{}
+		if err := rows.Scan(&h.UserID, &h.GameID, &h.SessionID, &h.Outcome, &h.RatingBefore, &h.RatingAfter, &h.RD, &h.Volatility, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, h)
+	}
+	return entries, rows.Err()
+}
+
+func (s *PostgresStore) Ladder(gameID string, limit, offset int) ([]*PlayerRating, error) {
+	rows, err := s.DB.Query(`
+		SELECT user_id, game_id, rating, rd, volatility, games_played, wins, updated_at
+		FROM player_ratings WHERE game_id = $1
+		ORDER BY rating DESC, user_id LIMIT $2 OFFSET $3
+	`, gameID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ladder := []*PlayerRating{}
+	for rows.Next() {
+		r := &PlayerRating{}
+		if err := rows.Scan(&r.UserID, &r.GameID, &r.Rating, &r.RD, &r.Volatility, &r.GamesPlayed, &r.Wins, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		ladder = append(ladder, r)
+	}
+	return ladder, rows.Err()
+}
@@ -0,0 +1,90 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Frame is the game as it stood at one step of a replay
+type Frame struct {
+	Step     int             `json:"step"` // 0 is the start, n the state after the nth move
+	Steps    int             `json:"steps"`
+	Move     *Move           `json:"move,omitempty"` // The move that led here
+	State    json.RawMessage `json:"state"`
+	OffsetMS int64           `json:"offset_ms"` // Time since the game started
+}
+
+// Player steps through a replay
+type Player struct {
+	replay *Replay
+	step   int
+}
+
+func NewPlayer(replay *Replay) *Player {
+	return &Player{replay: replay}
+}
+
+// Steps is the number of the last step
+func (p *Player) Steps() int {
+	return len(p.replay.States) - 1
+}
+
+// Frame returns the current step
+func (p *Player) Frame() Frame {
+	frame := Frame{Step: p.step, Steps: p.Steps()}
+	if p.step < len(p.replay.States) {
+		frame.State = p.replay.States[p.step]
+	}
+	if p.step > 0 {
+		move := p.replay.Moves[p.step-1]
+		frame.Move = &move
+		frame.OffsetMS = move.Timestamp.Sub(p.replay.StartedAt).Milliseconds()
+	}
+	return frame
+}
+
+// Next moves one step forward, reporting false at the end
+func (p *Player) Next() (Frame, bool) {
+	if p.step >= p.Steps() {
+		return p.Frame(), false
+	}
+	p.step++
+	return p.Frame(), true
+}
+
+// Prev moves one step back, reporting false at the start
+func (p *Player) Prev() (Frame, bool) {
+	if p.step == 0 {
+		return p.Frame(), false
+	}
+	p.step--
+	return p.Frame(), true
+}
+
+// Seek jumps to a step
+func (p *Player) Seek(step int) (Frame, error) {
+	if step < 0 || step > p.Steps() {
+		return Frame{}, fmt.Errorf("step %d out of range 0-%d", step, p.Steps())
+	}
+	p.step = step
+	return p.Frame(), nil
+}
+
+// SeekTime jumps to the last step played within offset of the start
+func (p *Player) SeekTime(offset time.Duration) Frame {
+	at := p.replay.StartedAt.Add(offset)
+	p.step = 0
+	for p.step < p.Steps() && !p.replay.Moves[p.step].Timestamp.After(at) {
+		p.step++
+	}
+	return p.Frame()
+}
+
+// Progress is how far through the replay the player is, from 0 to 1
+func (p *Player) Progress() float64 {
+	if p.Steps() <= 0 {
+		return 0
+	}
+	return float64(p.step) / float64(p.Steps())
+}
@@ -0,0 +1,183 @@
+// Package replay records every move of a session, with the random inputs the game
+// drew for it, so finished games can be stepped through and replayed to check that
+// the same moves rebuild the same game.
+package replay
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// MoveJoin is the recorded move of a player joining after the session started
+const MoveJoin = "JOIN"
+
+// Move is one recorded move
+type Move struct {
+	Seq       int                    `json:"seq"`
+	PlayerID  string                 `json:"player_id"`
+	Type      string                 `json:"type"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	RNG       []json.RawMessage      `json:"rng,omitempty"` // Random inputs drawn for the move, in order
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Replay is the full record of one session
+type Replay struct {
+	SessionID   string            `json:"session_id"`
+	GameID      string            `json:"game_id"`
+	Players     []string          `json:"players"` // Seating order, late joiners last
+	Bots        []string          `json:"bots,omitempty"` // Players that were server-side bots
+	EntryFee    float64           `json:"entry_fee"`
+	StartRNG    []json.RawMessage `json:"start_rng,omitempty"` // Random inputs drawn as the game started
+	Moves       []Move            `json:"moves"`
+	States      []json.RawMessage `json:"states"` // Snapshot at the start, then after each move
+	Winner      string            `json:"winner,omitempty"`
+	StartedAt   time.Time         `json:"started_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// Summary describes a replay for listings
+type Summary struct {
+	SessionID   string     `json:"session_id"`
+	GameID      string     `json:"game_id"`
+	Players     []string   `json:"players"`
+	Moves       int        `json:"moves"`
+	Winner      string     `json:"winner,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func (r *Replay) Summary() *Summary {
+	return &Summary{
+		SessionID:   r.SessionID,
+		GameID:      r.GameID,
+		Players:     r.Players,
+		Moves:       len(r.Moves),
+		Winner:      r.Winner,
+		StartedAt:   r.StartedAt,
+		CompletedAt: r.CompletedAt,
+	}
+}
+
+// Snapshot serializes the session's state: the game's own snapshot when it is a
+// ReplayableGame, otherwise whatever GetState returns
+func Snapshot(game engine.IGame, session *engine.GameSession) json.RawMessage {
+	var state interface{}
+	if replayable, ok := game.(engine.ReplayableGame); ok {
+		state = replayable.Snapshot(session)
+	} else {
+		state = game.GetState(session)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Printf("replay %s: failed to snapshot state: %v", session.SessionID, err)
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// Recorder records a live session. It is the session's RNG, so every random
+// input the game draws is kept with the move that drew it.
+type Recorder struct {
+	replay  *Replay
+	pending []json.RawMessage // Drawn since the last recorded move
+	mu      sync.Mutex
+}
+
+// NewRecorder starts recording a session that is about to be started
+func NewRecorder(session *engine.GameSession) *Recorder {
+	players := make([]string, len(session.Players))
+	var bots []string
+	for i, p := range session.Players {
+		players[i] = p.UserID
+		if p.IsBot {
+			bots = append(bots, p.UserID)
+		}
+	}
+
+	return &Recorder{
+		replay: &Replay{
+			SessionID: session.SessionID,
+			GameID:    session.GameID,
+			Players:   players,
+			Bots:      bots,
+			EntryFee:  session.EntryFee,
+			Moves:     []Move{},
+			States:    []json.RawMessage{},
+			StartedAt: session.CreatedAt,
+		},
+	}
+}
+
+// Replay reports that a live session has nothing recorded to feed back
+func (r *Recorder) Replay(into interface{}) bool {
+	return false
+}
+
+// Record keeps a random input the game just drew
+func (r *Recorder) Record(value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("replay %s: failed to record random input: %v", r.replay.SessionID, err)
+		data = json.RawMessage("null")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, data)
+}
+
+// Started records the state the game started in
+func (r *Recorder) Started(snapshot json.RawMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.replay.StartRNG = r.pending
+	r.pending = nil
+	r.replay.States = append(r.replay.States, snapshot)
+}
+
+// Joined records a player joining the started session
+func (r *Recorder) Joined(userID string, snapshot json.RawMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.replay.Players = append(r.replay.Players, userID)
+	r.add(engine.Move{PlayerID: userID, Type: MoveJoin}, snapshot)
+}
+
+// Moved records an accepted move and the state it left
+func (r *Recorder) Moved(move engine.Move, snapshot json.RawMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(move, snapshot)
+}
+
+func (r *Recorder) add(move engine.Move, snapshot json.RawMessage) {
+	r.replay.Moves = append(r.replay.Moves, Move{
+		Seq:       len(r.replay.Moves) + 1,
+		PlayerID:  move.PlayerID,
+		Type:      move.Type,
+		Data:      move.Data,
+		RNG:       r.pending,
+		Timestamp: time.Now(),
+	})
+	r.pending = nil
+	r.replay.States = append(r.replay.States, snapshot)
+}
+
+// Complete closes the recording and returns it
+func (r *Recorder) Complete(winner string) *Replay {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.replay.Winner = winner
+	r.replay.CompletedAt = &now
+	return r.replay
+}
@@ -0,0 +1,182 @@
+package replay
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// Filter narrows a replay listing; empty fields match everything
+type Filter struct {
+	GameID string
+	UserID string
+}
+
+func (f Filter) matches(r *Replay) bool {
+	if f.GameID != "" && r.GameID != f.GameID {
+		return false
+	}
+	if f.UserID == "" {
+		return true
+	}
+	for _, userID := range r.Players {
+		if userID == f.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// Store persists finished replays
+type Store interface {
+	Save(replay *Replay) error
+	// Get returns nil if the session has no replay
+	Get(sessionID string) (*Replay, error)
+	// List returns the most recently finished replays first
+	List(filter Filter, limit, offset int) ([]*Summary, error)
+}
+
+// MemoryStore keeps replays in process, for running without a database
+type MemoryStore struct {
+	replays map[string]*Replay
+	order   []string // Session IDs, oldest first
+	mu      sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{replays: make(map[string]*Replay)}
+}
+
+func (s *MemoryStore) Save(replay *Replay) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.replays[replay.SessionID]; !ok {
+		s.order = append(s.order, replay.SessionID)
+	}
+	s.replays[replay.SessionID] = replay
+	return nil
+}
+
+func (s *MemoryStore) Get(sessionID string) (*Replay, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.replays[sessionID], nil
+}
+
+func (s *MemoryStore) List(filter Filter, limit, offset int) ([]*Summary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summaries := []*Summary{}
+	for i := len(s.order) - 1; i >= 0 && len(summaries) < limit; i-- {
+		r := s.replays[s.order[i]]
+		if !filter.matches(r) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		summaries = append(summaries, r.Summary())
+	}
+	return summaries, nil
+}
+
+// PostgresStore keeps replays in game_replays
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+func (s *PostgresStore) Save(replay *Replay) error {
+	moves, err := json.Marshal(replay.Moves)
+	if err != nil {
+		return err
+	}
+	states, err := json.Marshal(replay.States)
+	if err != nil {
+		return err
+	}
+	startRNG, err := json.Marshal(replay.StartRNG)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.DB.Exec(`
+		INSERT INTO game_replays (session_id, game_type, players, bots, entry_fee, start_rng, moves, states, winner, started_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11)
+		ON CONFLICT (session_id) DO UPDATE SET
+			players = EXCLUDED.players, start_rng = EXCLUDED.start_rng, moves = EXCLUDED.moves,
+			states = EXCLUDED.states, winner = EXCLUDED.winner, completed_at = EXCLUDED.completed_at
+	`, replay.SessionID, replay.GameID, pq.Array(replay.Players), pq.Array(replay.Bots), replay.EntryFee, startRNG, moves, states,
+		replay.Winner, replay.StartedAt, replay.CompletedAt)
+	return err
+}
+
+func (s *PostgresStore) Get(sessionID string) (*Replay, error) {
+	r := &Replay{}
+	var startRNG, moves, states []byte
+	var winner sql.NullString
+	var completedAt sql.NullTime
+	err := s.DB.QueryRow(`
+		SELECT session_id, game_type, players, bots, entry_fee, start_rng, moves, states, winner, started_at, completed_at
+		FROM game_replays WHERE session_id = $1
+	`, sessionID).Scan(&r.SessionID, &r.GameID, pq.Array(&r.Players), pq.Array(&r.Bots), &r.EntryFee, &startRNG, &moves, &states,
+		&winner, &r.StartedAt, &completedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(startRNG, &r.StartRNG); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(moves, &r.Moves); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(states, &r.States); err != nil {
+		return nil, err
+	}
+	r.Winner = winner.String
+	if completedAt.Valid {
+		r.CompletedAt = &completedAt.Time
+	}
+	return r, nil
+}
+
+func (s *PostgresStore) List(filter Filter, limit, offset int) ([]*Summary, error) {
+	rows, err := s.DB.Query(`
+		SELECT session_id, game_type, players, jsonb_array_length(moves), winner, started_at, completed_at
+		FROM game_replays
+		WHERE ($1 = '' OR game_type = $1) AND ($2 = '' OR $2 = ANY(players))
+		ORDER BY completed_at DESC NULLS LAST LIMIT $3 OFFSET $4
+	`, filter.GameID, filter.UserID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []*Summary{}
+	for rows.Next() {
+		r := &Summary{}
+		var winner sql.NullString
+		var completedAt sql.NullTime
+		if err := rows.Scan(&r.SessionID, &r.GameID, pq.Array(&r.Players), &r.Moves, &winner, &r.StartedAt, &completedAt); err != nil {
+			return nil, err
+		}
+		r.Winner = winner.String
+		if completedAt.Valid {
+			r.CompletedAt = &completedAt.Time
+		}
+		summaries = append(summaries, r)
+	}
+	return summaries, rows.Err()
+}
@@ -0,0 +1,176 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+)
+
+var ErrNotReplayable = errors.New("game does not support replay verification")
+
+// Verification is the outcome of replaying a recorded session
+type Verification struct {
+	SessionID     string          `json:"session_id"`
+	Deterministic bool            `json:"deterministic"`
+	StepsReplayed int             `json:"steps_replayed"`
+	DivergedAt    *int            `json:"diverged_at,omitempty"` // First step whose state differs
+	Reason        string          `json:"reason,omitempty"`
+	Expected      json.RawMessage `json:"expected,omitempty"`
+	Actual        json.RawMessage `json:"actual,omitempty"`
+}
+
+// Verify replays the move log on a fresh session, feeding back the recorded
+// random inputs, and checks every state against the recorded one. The replayed
+// session moves no money.
+func Verify(r *Replay) (*Verification, error) {
+	game, err := registry.GetRegistry().GetGame(r.GameID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := game.(engine.ReplayableGame); !ok {
+		return nil, ErrNotReplayable
+	}
+	if len(r.States) != len(r.Moves)+1 {
+		return nil, errors.New("replay is missing states")
+	}
+
+	isBot := make(map[string]bool, len(r.Bots))
+	for _, botID := range r.Bots {
+		isBot[botID] = true
+	}
+	joined := make(map[string]bool)
+	for _, m := range r.Moves {
+		if m.Type == MoveJoin {
+			joined[m.PlayerID] = true
+		}
+	}
+	rng := &playback{}
+	session := &engine.GameSession{
+		SessionID: fmt.Sprintf("replay_%s_%d", r.SessionID, time.Now().UnixNano()),
+		GameID:    r.GameID,
+		Status:    "WAITING",
+		EntryFee:  r.EntryFee,
+		Replay:    true,
+		RNG:       rng,
+		CreatedAt: r.StartedAt,
+		UpdatedAt: r.StartedAt,
+	}
+	for _, userID := range r.Players {
+		if !joined[userID] {
+			session.Players = append(session.Players, &engine.Player{UserID: userID, IsBot: isBot[userID]})
+		}
+	}
+	if len(session.Players) > 0 {
+		session.Players[0].IsTurn = true
+	}
+	if len(session.Players) > 1 && len(session.Players) >= game.GetMinPlayers() {
+		session.Status = "IN_PROGRESS"
+	}
+
+	v := &Verification{SessionID: r.SessionID}
+
+	rng.inputs = r.StartRNG
+	if err := game.Start(session); err != nil {
+		return v.diverged(0, "game failed to start: "+err.Error(), r.States[0], nil), nil
+	}
+	ended := false
+	defer func() {
+		// Let the game release the session (e.g. turn timers)
+		if !ended {
+			game.End(session)
+		}
+	}()
+	if d := v.check(0, rng, r.States[0], Snapshot(game, session)); d != nil {
+		return d, nil
+	}
+
+	for i, m := range r.Moves {
+		step := i + 1
+		rng.inputs, rng.extra = m.RNG, false
+
+		if m.Type == MoveJoin {
+			session.Players = append(session.Players, &engine.Player{UserID: m.PlayerID, IsBot: isBot[m.PlayerID]})
+			if len(session.Players) >= game.GetMinPlayers() {
+				session.Status = "IN_PROGRESS"
+			}
+		} else {
+			result, err := game.HandleMove(session, engine.Move{PlayerID: m.PlayerID, Type: m.Type, Data: m.Data})
+			if err != nil {
+				return v.diverged(step, "move rejected: "+err.Error(), r.States[step], nil), nil
+			}
+			if result.GameEnded && !ended {
+				session.Status = "COMPLETED"
+				ended = true
+				if _, err := game.End(session); err != nil {
+					return v.diverged(step, "game failed to end: "+err.Error(), r.States[step], nil), nil
+				}
+			}
+		}
+
+		if d := v.check(step, rng, r.States[step], Snapshot(game, session)); d != nil {
+			return d, nil
+		}
+	}
+
+	v.Deterministic = true
+	return v, nil
+}
+
+// check compares one step, which must also have used up exactly its recorded random inputs
+func (v *Verification) check(step int, rng *playback, expected, actual json.RawMessage) *Verification {
+	v.StepsReplayed = step + 1
+	if rng.extra {
+		return v.diverged(step, "game drew more random inputs than were recorded", expected, actual)
+	}
+	if len(rng.inputs) > 0 {
+		return v.diverged(step, "game drew fewer random inputs than were recorded", expected, actual)
+	}
+	if !sameJSON(expected, actual) {
+		return v.diverged(step, "state differs from the recording", expected, actual)
+	}
+	return nil
+}
+
+func (v *Verification) diverged(step int, reason string, expected, actual json.RawMessage) *Verification {
+	v.Deterministic = false
+	v.DivergedAt = &step
+	v.Reason = reason
+	v.Expected = expected
+	v.Actual = actual
+	return v
+}
+
+// sameJSON compares two documents by value, so key order and spacing (e.g. after
+// a round trip through JSONB) do not matter
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// playback is a replayed session's RNG: it hands back the inputs recorded for
+// the current step and notes any draw beyond them
+type playback struct {
+	inputs []json.RawMessage
+	extra  bool
+}
+
+func (p *playback) Replay(into interface{}) bool {
+	if len(p.inputs) == 0 {
+		return false
+	}
+	input := p.inputs[0]
+	p.inputs = p.inputs[1:]
+	return json.Unmarshal(input, into) == nil
+}
+
+func (p *playback) Record(value interface{}) {
+	p.extra = true
+}
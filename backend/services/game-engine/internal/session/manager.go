@@ -3,32 +3,115 @@ package session
 import (
 	"errors"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	"github.com/playkaro/game-engine/internal/engine"
 	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/replay"
 )
 
 type SessionManager struct {
 	sessions map[string]*engine.GameSession
+	shared   map[string]string // gameID -> sessionID of the open shared table
+	onMove   []MoveHandler
+	onEnd    []ResultHandler
+	replays  replay.Store
+	recorded map[string]*replay.Recorder // sessionID -> recording, until the session ends
 	mu       sync.RWMutex
 }
 
+// ResultHandler is told about every session that ends with a result (e.g. ratings)
+type ResultHandler func(session *engine.GameSession, result *engine.GameResult)
+
+// MoveHandler is told about every accepted move (e.g. anticheat)
+type MoveHandler func(session *engine.GameSession, move engine.Move)
+
 func NewSessionManager() *SessionManager {
 	return &SessionManager{
 		sessions: make(map[string]*engine.GameSession),
+		shared:   make(map[string]string),
+		replays:  replay.NewMemoryStore(),
+		recorded: make(map[string]*replay.Recorder),
+	}
+}
+
+// SetReplayStore sets where finished sessions' replays are saved (in memory by default)
+func (sm *SessionManager) SetReplayStore(store replay.Store) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.replays = store
+}
+
+func isSharedRound(game engine.IGame) bool {
+	shared, ok := game.(engine.SharedRoundGame)
+	return ok && shared.IsSharedRound()
+}
+
+// OnGameEnd registers a handler called, in its own goroutine, after a session ends
+func (sm *SessionManager) OnGameEnd(handler ResultHandler) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onEnd = append(sm.onEnd, handler)
+}
+
+// OnMove registers a handler called after every accepted move, in order and with
+// the session locked, so it must return quickly and not call back into the manager
+func (sm *SessionManager) OnMove(handler MoveHandler) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onMove = append(sm.onMove, handler)
+}
+
+// SessionOption adjusts a session before the game starts it
+type SessionOption func(*engine.GameSession)
+
+// WithPlayers seats more players alongside the creator
+func WithPlayers(userIDs ...string) SessionOption {
+	return func(session *engine.GameSession) {
+		for _, userID := range userIDs {
+			session.Players = append(session.Players, &engine.Player{UserID: userID})
+		}
+	}
+}
+
+// WithBots seats server-side bots alongside the creator
+func WithBots(botIDs ...string) SessionOption {
+	return func(session *engine.GameSession) {
+		for _, botID := range botIDs {
+			session.Players = append(session.Players, &engine.Player{UserID: botID, IsBot: true})
+		}
+	}
+}
+
+// WithEntryFee sets the stake for a session played at a non-default entry fee
+func WithEntryFee(fee float64) SessionOption {
+	return func(session *engine.GameSession) {
+		session.EntryFee = fee
+	}
+}
+
+// WithPrepaidEntry tells the game every player's entry fee is already collected
+func WithPrepaidEntry() SessionOption {
+	return func(session *engine.GameSession) {
+		session.Prepaid = true
 	}
 }
 
-// CreateSession initializes a new game session
-func (sm *SessionManager) CreateSession(gameID string, userID string) (*engine.GameSession, error) {
+// CreateSession initializes a new game session.
+// For shared-round games the caller is seated at the game's open table instead.
+func (sm *SessionManager) CreateSession(gameID string, userID string, opts ...SessionOption) (*engine.GameSession, error) {
 	reg := registry.GetRegistry()
 	game, err := reg.GetGame(gameID)
 	if err != nil {
 		return nil, err
 	}
 
+	if isSharedRound(game) {
+		return sm.joinSharedTable(game, userID)
+	}
+
 	sessionID := fmt.Sprintf("sess_%d", time.Now().UnixNano())
 	session := &engine.GameSession{
 		SessionID: sessionID,
@@ -39,14 +122,31 @@ func (sm *SessionManager) CreateSession(gameID string, userID string) (*engine.G
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	for _, opt := range opts {
+		opt(session)
+	}
+	if len(session.Players) > game.GetMaxPlayers() {
+		return nil, errors.New("too many players for this game")
+	}
+	// Single-player games start straight away; the others once enough are seated
+	if len(session.Players) >= game.GetMinPlayers() {
+		session.Status = "IN_PROGRESS"
+	}
+
+	// Record the session for replays; the recorder also keeps every random input
+	recorder := replay.NewRecorder(session)
+	session.RNG = recorder
+	session.Ended = func() { sm.endSession(sessionID) }
 
 	// Initialize game state
 	if err := game.Start(session); err != nil {
 		return nil, err
 	}
+	recorder.Started(replay.Snapshot(game, session))
 
 	sm.mu.Lock()
 	sm.sessions[sessionID] = session
+	sm.recorded[sessionID] = recorder
 	sm.mu.Unlock()
 
 	return session, nil
@@ -62,6 +162,10 @@ func (sm *SessionManager) JoinSession(sessionID, userID string) (*engine.GameSes
 		return nil, errors.New("session not found")
 	}
 
+	if session.Shared {
+		return sm.seatPlayer(session, userID)
+	}
+
 	if session.Status != "WAITING" {
 		return nil, errors.New("session not open for joining")
 	}
@@ -74,10 +178,8 @@ func (sm *SessionManager) JoinSession(sessionID, userID string) (*engine.GameSes
 	}
 
 	// Check if user already joined
-	for _, p := range session.Players {
-		if p.UserID == userID {
-			return nil, errors.New("user already in session")
-		}
+	if hasPlayer(session, userID) {
+		return nil, errors.New("user already in session")
 	}
 
 	session.Players = append(session.Players, &engine.Player{UserID: userID})
@@ -88,6 +190,10 @@ func (sm *SessionManager) JoinSession(sessionID, userID string) (*engine.GameSes
 		session.Status = "IN_PROGRESS"
 	}
 
+	if recorder, ok := sm.recorded[sessionID]; ok {
+		recorder.Joined(userID, replay.Snapshot(game, session))
+	}
+
 	return session, nil
 }
 
@@ -103,16 +209,57 @@ func (sm *SessionManager) GetSession(sessionID string) (*engine.GameSession, err
 	return session, nil
 }
 
+// ReadSession calls fn with the session read-locked, so fn sees a consistent
+// state between moves. fn must not modify the session.
+func (sm *SessionManager) ReadSession(sessionID string, fn func(session *engine.GameSession)) error {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	session, exists := sm.sessions[sessionID]
+	if !exists {
+		return errors.New("session not found")
+	}
+	fn(session)
+	return nil
+}
+
+// ViewSession returns what a seated player sees of a session (see engine.ViewableGame)
+func (sm *SessionManager) ViewSession(sessionID, userID string) (interface{}, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	session, exists := sm.sessions[sessionID]
+	if !exists {
+		return nil, errors.New("session not found")
+	}
+	if !hasPlayer(session, userID) {
+		return nil, errors.New("player not seated in this session")
+	}
+
+	reg := registry.GetRegistry()
+	game, _ := reg.GetGame(session.GameID)
+	viewable, ok := game.(engine.ViewableGame)
+	if !ok {
+		return game.GetState(session), nil
+	}
+	return viewable.PlayerView(session, userID)
+}
+
 // ProcessMove handles a player's move
 func (sm *SessionManager) ProcessMove(sessionID string, move engine.Move) (*engine.MoveResult, error) {
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
 
 	session, exists := sm.sessions[sessionID]
 	if !exists {
+		sm.mu.Unlock()
 		return nil, errors.New("session not found")
 	}
 
+	if session.Shared {
+		return sm.processSharedMove(session, move)
+	}
+	defer sm.mu.Unlock()
+
 	if session.Status != "IN_PROGRESS" {
 		return nil, errors.New("game not in progress")
 	}
@@ -126,11 +273,186 @@ func (sm *SessionManager) ProcessMove(sessionID string, move engine.Move) (*engi
 	}
 
 	session.UpdatedAt = time.Now()
+	for _, handler := range sm.onMove {
+		handler(session, move)
+	}
 
+	var gameResult *engine.GameResult
 	if result.GameEnded {
-		session.Status = "COMPLETED"
-		// TODO: Handle game end (prizes, etc.)
+		gameResult = sm.complete(game, session)
 	}
 
+	sm.recordMove(game, session, move, gameResult)
 	return result, nil
 }
+
+// recordMove adds an accepted move to the session's replay, saving the replay
+// once the game is over. Caller must hold mu.
+func (sm *SessionManager) recordMove(game engine.IGame, session *engine.GameSession, move engine.Move, result *engine.GameResult) {
+	recorder, ok := sm.recorded[session.SessionID]
+	if !ok {
+		return
+	}
+	recorder.Moved(move, replay.Snapshot(game, session))
+	if session.Status == "COMPLETED" {
+		sm.saveReplay(session, result)
+	}
+}
+
+// endSession completes a session the game ended without a move (see
+// engine.GameSession.Ended). Sessions already completed are left alone.
+func (sm *SessionManager) endSession(sessionID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session, ok := sm.sessions[sessionID]
+	if !ok || session.Status != "IN_PROGRESS" {
+		return
+	}
+	game, err := registry.GetRegistry().GetGame(session.GameID)
+	if err != nil {
+		return
+	}
+	sm.saveReplay(session, sm.complete(game, session))
+}
+
+// complete ends a finished game and tells the result handlers. Caller must hold mu.
+func (sm *SessionManager) complete(game engine.IGame, session *engine.GameSession) *engine.GameResult {
+	session.Status = "COMPLETED"
+
+	result, err := game.End(session)
+	if err != nil {
+		log.Printf("Failed to end session %s: %v", session.SessionID, err)
+		return nil
+	}
+	if result != nil {
+		for _, handler := range sm.onEnd {
+			go handler(session, result)
+		}
+	}
+	return result
+}
+
+// saveReplay saves a completed session's recording. Caller must hold mu.
+func (sm *SessionManager) saveReplay(session *engine.GameSession, result *engine.GameResult) {
+	recorder, ok := sm.recorded[session.SessionID]
+	if !ok {
+		return
+	}
+	delete(sm.recorded, session.SessionID)
+	winner := ""
+	if result != nil {
+		winner = result.WinnerID
+	}
+	rec, store := recorder.Complete(winner), sm.replays
+	go func() {
+		if err := store.Save(rec); err != nil {
+			log.Printf("Failed to save replay of session %s: %v", rec.SessionID, err)
+		}
+	}()
+}
+
+// joinSharedTable seats the user at the game's open table, opening one if needed.
+// Shared tables are not recorded for replays: their rounds run on the game's own
+// clock rather than on moves, and each round is checked from its seeds instead
+// (see fairness.Verify).
+func (sm *SessionManager) joinSharedTable(game engine.IGame, userID string) (*engine.GameSession, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sessionID, ok := sm.shared[game.GetGameID()]; ok {
+		if session, ok := sm.sessions[sessionID]; ok && session.Status == "IN_PROGRESS" {
+			return sm.seatPlayer(session, userID)
+		}
+	}
+
+	sessionID := fmt.Sprintf("table_%s_%d", game.GetGameID(), time.Now().UnixNano())
+	session := &engine.GameSession{
+		SessionID: sessionID,
+		GameID:    game.GetGameID(),
+		Players:   []*engine.Player{{UserID: userID}},
+		Status:    "IN_PROGRESS", // Shared tables run continuously
+		Shared:    true,
+		EntryFee:  game.GetEntryFee(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := game.Start(session); err != nil {
+		return nil, err
+	}
+
+	sm.sessions[sessionID] = session
+	sm.shared[game.GetGameID()] = sessionID
+
+	return session, nil
+}
+
+// seatPlayer adds a player to a shared table. Rejoining is a no-op. Caller must hold mu.
+func (sm *SessionManager) seatPlayer(session *engine.GameSession, userID string) (*engine.GameSession, error) {
+	if hasPlayer(session, userID) {
+		return session, nil
+	}
+
+	reg := registry.GetRegistry()
+	game, _ := reg.GetGame(session.GameID)
+	if len(session.Players) >= game.GetMaxPlayers() {
+		return nil, errors.New("table full")
+	}
+
+	session.Players = append(session.Players, &engine.Player{UserID: userID})
+	session.UpdatedAt = time.Now()
+	return session, nil
+}
+
+// processSharedMove runs a move on a shared table. Called with mu held; the lock is
+// released before the game runs because shared-round games synchronize their own
+// state, and one slow wallet call must not stall every other table.
+func (sm *SessionManager) processSharedMove(session *engine.GameSession, move engine.Move) (*engine.MoveResult, error) {
+	if !hasPlayer(session, move.PlayerID) {
+		sm.mu.Unlock()
+		return nil, errors.New("player not seated at this table")
+	}
+	session.UpdatedAt = time.Now()
+	sm.mu.Unlock()
+
+	reg := registry.GetRegistry()
+	game, _ := reg.GetGame(session.GameID)
+
+	return game.HandleMove(session, move)
+}
+
+// CloseSharedTables ends every shared table, e.g. on shutdown, so their games
+// can settle or refund the bets still in play
+func (sm *SessionManager) CloseSharedTables() {
+	sm.mu.Lock()
+	var tables []*engine.GameSession
+	for gameID, sessionID := range sm.shared {
+		if session, ok := sm.sessions[sessionID]; ok && session.Status == "IN_PROGRESS" {
+			session.Status = "COMPLETED"
+			tables = append(tables, session)
+		}
+		delete(sm.shared, gameID)
+	}
+	sm.mu.Unlock()
+
+	reg := registry.GetRegistry()
+	for _, session := range tables {
+		game, err := reg.GetGame(session.GameID)
+		if err != nil {
+			continue
+		}
+		if _, err := game.End(session); err != nil {
+			log.Printf("Failed to close table %s: %v", session.SessionID, err)
+		}
+	}
+}
+
+func hasPlayer(session *engine.GameSession, userID string) bool {
+	for _, p := range session.Players {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
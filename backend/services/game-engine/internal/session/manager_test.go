@@ -0,0 +1,82 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/games/ludo"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/replay"
+	"github.com/playkaro/game-engine/internal/wallet"
+)
+
+// A recorded session replays to the same states, move for move
+func TestRecordedSessionReplaysDeterministically(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wallet.TransactionResponse{Status: "SUCCESS"})
+	}))
+	defer srv.Close()
+	t.Setenv("PAYMENT_SERVICE_URL", srv.URL)
+
+	registry.GetRegistry().RegisterGame(ludo.NewLudoGame())
+	replays := replay.NewMemoryStore()
+	sm := NewSessionManager()
+	sm.SetReplayStore(replays)
+
+	session, err := sm.CreateSession("ludo_classic", "p1", WithPlayers("p2"), WithPrepaidEntry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := []string{"p1", "p2"}
+	for turn := 0; ; turn++ {
+		if turn == 1000 {
+			t.Fatal("no one rolled a six")
+		}
+		result, err := sm.ProcessMove(session.SessionID, engine.Move{PlayerID: players[turn%2], Type: "ROLL_DICE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.GameEnded {
+			break
+		}
+	}
+
+	// The replay is saved in the background
+	var r *replay.Replay
+	for deadline := time.Now().Add(5 * time.Second); r == nil; time.Sleep(time.Millisecond) {
+		if r, err = replays.Get(session.SessionID); err != nil {
+			t.Fatal(err)
+		}
+		if r == nil && time.Now().After(deadline) {
+			t.Fatal("replay never saved")
+		}
+	}
+
+	v, err := replay.Verify(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Deterministic || v.StepsReplayed != len(r.States) {
+		t.Fatalf("verification %+v of %d states", v, len(r.States))
+	}
+
+	// A different first roll no longer rebuilds the recorded game
+	var roll int
+	if err := json.Unmarshal(r.Moves[0].RNG[0], &roll); err != nil {
+		t.Fatal(err)
+	}
+	tampered := *r
+	tampered.Moves = append([]replay.Move(nil), r.Moves...)
+	tampered.Moves[0].RNG = []json.RawMessage{json.RawMessage(fmt.Sprint(roll%5 + 1))}
+	if v, err = replay.Verify(&tampered); err != nil {
+		t.Fatal(err)
+	}
+	if v.Deterministic || v.DivergedAt == nil || *v.DivergedAt != 1 {
+		t.Fatalf("tampered roll: verification %+v", v)
+	}
+}
@@ -0,0 +1,202 @@
+// Package settlement pays players what games owe them. Tables, tournaments and
+// contests hand credits to a Settler instead of calling the wallet themselves,
+// so a live table's actor goroutine never waits on it, and the Settler retries
+// each one until the wallet accepts it.
+package settlement
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Credit is money owed to a player: winnings, or a refunded stake
+type Credit struct {
+	UserID  string  `json:"user_id"`
+	Amount  float64 `json:"amount"`
+	RefID   string  `json:"reference_id"`
+	RefType string  `json:"reference_type"`
+}
+
+// Wallet pays credits (implemented by wallet.WalletClient)
+type Wallet interface {
+	Credit(userID string, amount float64, refID, refType string) error
+}
+
+// Config controls retry backoff
+type Config struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		InitialBackoff: time.Second,
+		MaxBackoff:     2 * time.Minute,
+	}
+}
+
+type pendingCredit struct {
+	Credit
+	attempts int
+	retryAt  time.Time
+}
+
+// Settler queues credits and pays them in order, retrying failures with
+// exponential backoff. Pay never blocks, so a wallet outage can't stall a table.
+// Credits still unpaid at shutdown are saved to the Store and paid after the
+// next start.
+type Settler struct {
+	cfg    Config
+	wallet Wallet
+	store  Store
+
+	pending []*pendingCredit
+	wake    chan struct{}
+	mu      sync.Mutex
+}
+
+// NewSettler creates a settler. store may be nil, in which case credits still
+// unpaid at shutdown are only logged.
+func NewSettler(cfg Config, wallet Wallet, store Store) *Settler {
+	return &Settler{
+		cfg:    cfg,
+		wallet: wallet,
+		store:  store,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Pay queues a credit
+func (s *Settler) Pay(c Credit) {
+	if c.Amount <= 0 {
+		return
+	}
+	s.mu.Lock()
+	s.pending = append(s.pending, &pendingCredit{Credit: c})
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pending returns how many credits have not been paid yet
+func (s *Settler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Run pays credits until ctx is cancelled, then makes one last attempt at each
+// and saves whatever is left
+func (s *Settler) Run(ctx context.Context) {
+	s.resume()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		timer.Reset(s.payDue(time.Now(), false))
+		select {
+		case <-ctx.Done():
+			s.payDue(time.Now(), true)
+			s.save()
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// resume queues the credits a previous run could not pay
+func (s *Settler) resume() {
+	if s.store == nil {
+		return
+	}
+	credits, err := s.store.TakeUnsettled()
+	if err != nil {
+		log.Printf("settlement: failed to load unsettled credits: %v", err)
+		return
+	}
+	for _, c := range credits {
+		s.Pay(c)
+	}
+	if len(credits) > 0 {
+		log.Printf("settlement: resumed %d unsettled credits", len(credits))
+	}
+}
+
+// payDue attempts every credit due by now (all of them if force) and returns
+// how long until the next retry is due
+func (s *Settler) payDue(now time.Time, force bool) time.Duration {
+	s.mu.Lock()
+	var due, waiting []*pendingCredit
+	for _, p := range s.pending {
+		if force || !now.Before(p.retryAt) {
+			due = append(due, p)
+		} else {
+			waiting = append(waiting, p)
+		}
+	}
+	s.pending = waiting
+	s.mu.Unlock()
+
+	var failed []*pendingCredit
+	for _, p := range due {
+		if err := s.wallet.Credit(p.UserID, p.Amount, p.RefID, p.RefType); err != nil {
+			p.attempts++
+			p.retryAt = time.Now().Add(s.backoff(p.attempts))
+			log.Printf("settlement: credit of %.2f to %s for %s failed (attempt %d): %v", p.Amount, p.UserID, p.RefID, p.attempts, err)
+			failed = append(failed, p)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Failed credits go back ahead of anything queued meanwhile
+	s.pending = append(failed, s.pending...)
+
+	next := time.Hour
+	for _, p := range s.pending {
+		if wait := p.retryAt.Sub(time.Now()); wait < next {
+			next = max(wait, 0)
+		}
+	}
+	return next
+}
+
+func (s *Settler) backoff(attempts int) time.Duration {
+	d := s.cfg.InitialBackoff
+	for i := 1; i < attempts && d < s.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, s.cfg.MaxBackoff)
+}
+
+// save stores the credits left unpaid at shutdown
+func (s *Settler) save() {
+	s.mu.Lock()
+	credits := make([]Credit, len(s.pending))
+	for i, p := range s.pending {
+		credits[i] = p.Credit
+	}
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(credits) == 0 {
+		return
+	}
+	if s.store != nil {
+		err := s.store.SaveUnsettled(credits)
+		if err == nil {
+			log.Printf("settlement: saved %d unsettled credits for the next start", len(credits))
+			return
+		}
+		log.Printf("settlement: failed to save unsettled credits: %v", err)
+	}
+	for _, c := range credits {
+		log.Printf("settlement: UNSETTLED credit of %.2f to %s for %s (%s)", c.Amount, c.UserID, c.RefID, c.RefType)
+	}
+}
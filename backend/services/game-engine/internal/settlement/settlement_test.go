@@ -0,0 +1,125 @@
+package settlement
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyWallet fails each user's first `failures` credits
+type flakyWallet struct {
+	failures int
+	attempts map[string]int
+	paid     []Credit
+	mu       sync.Mutex
+}
+
+func newFlakyWallet(failures int) *flakyWallet {
+	return &flakyWallet{failures: failures, attempts: make(map[string]int)}
+}
+
+func (w *flakyWallet) Credit(userID string, amount float64, refID, refType string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.attempts[userID]++
+	if w.attempts[userID] <= w.failures {
+		return errors.New("wallet unavailable")
+	}
+	w.paid = append(w.paid, Credit{UserID: userID, Amount: amount, RefID: refID, RefType: refType})
+	return nil
+}
+
+func (w *flakyWallet) total() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	sum := 0.0
+	for _, c := range w.paid {
+		sum += c.Amount
+	}
+	return sum
+}
+
+var fastRetries = Config{InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSettlerRetriesFailedCredits(t *testing.T) {
+	w := newFlakyWallet(3)
+	s := NewSettler(fastRetries, w, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	s.Pay(Credit{UserID: "u1", Amount: 25, RefID: "round_1", RefType: "GAME_CRASH"})
+	s.Pay(Credit{UserID: "u2", Amount: 10, RefID: "round_1", RefType: "GAME_CRASH_REFUND"})
+	waitFor(t, func() bool { return s.Pending() == 0 })
+
+	if got := w.total(); got != 35 {
+		t.Errorf("paid %.2f, want 35", got)
+	}
+	if w.attempts["u1"] != 4 {
+		t.Errorf("u1 credited after %d attempts, want 4", w.attempts["u1"])
+	}
+}
+
+func TestSettlerSavesUnpaidCreditsAtShutdown(t *testing.T) {
+	store := NewMemoryStore()
+	down := newFlakyWallet(1 << 30)
+	s := NewSettler(Config{InitialBackoff: time.Hour, MaxBackoff: time.Hour}, down, store)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(stopped)
+	}()
+
+	s.Pay(Credit{UserID: "u1", Amount: 50, RefID: "round_1", RefType: "GAME_ANDAR_BAHAR"})
+	waitFor(t, func() bool {
+		down.mu.Lock()
+		defer down.mu.Unlock()
+		return down.attempts["u1"] > 0
+	})
+	cancel()
+	<-stopped
+
+	// The next start pays what the last one could not
+	up := newFlakyWallet(0)
+	next := NewSettler(fastRetries, up, store)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go next.Run(ctx)
+	waitFor(t, func() bool { return up.total() == 50 })
+
+	if left, _ := store.TakeUnsettled(); len(left) != 0 {
+		t.Errorf("credits left in store: %v", left)
+	}
+}
+
+func TestSettlerIgnoresEmptyCredits(t *testing.T) {
+	s := NewSettler(fastRetries, newFlakyWallet(0), nil)
+	s.Pay(Credit{UserID: "u1", Amount: 0})
+	if s.Pending() != 0 {
+		t.Errorf("zero credit queued")
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	s := NewSettler(Config{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}, nil, nil)
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 50: 10 * time.Second} {
+		if got := s.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
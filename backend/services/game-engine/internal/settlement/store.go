@@ -0,0 +1,87 @@
+package settlement
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// Store keeps credits the wallet had not accepted when the service stopped
+type Store interface {
+	SaveUnsettled(credits []Credit) error
+	// TakeUnsettled removes and returns every saved credit
+	TakeUnsettled() ([]Credit, error)
+}
+
+// MemoryStore keeps unsettled credits in process, for tests
+type MemoryStore struct {
+	credits []Credit
+	mu      sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) SaveUnsettled(credits []Credit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credits = append(s.credits, credits...)
+	return nil
+}
+
+func (s *MemoryStore) TakeUnsettled() ([]Credit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	credits := s.credits
+	s.credits = nil
+	return credits, nil
+}
+
+// PostgresStore keeps unsettled credits in unsettled_credits
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+func (s *PostgresStore) SaveUnsettled(credits []Credit) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range credits {
+		_, err := tx.Exec(`
+			INSERT INTO unsettled_credits (user_id, amount, reference_id, reference_type)
+			VALUES ($1, $2, $3, $4)
+		`, c.UserID, c.Amount, c.RefID, c.RefType)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) TakeUnsettled() ([]Credit, error) {
+	rows, err := s.DB.Query(`
+		DELETE FROM unsettled_credits
+		RETURNING user_id, amount, reference_id, reference_type
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credits := []Credit{}
+	for rows.Next() {
+		var c Credit
+		if err := rows.Scan(&c.UserID, &c.Amount, &c.RefID, &c.RefType); err != nil {
+			return nil, err
+		}
+		credits = append(credits, c)
+	}
+	return credits, rows.Err()
+}
@@ -0,0 +1,156 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// Report is the certification result of one simulation run
+type Report struct {
+	GameID         string                 `json:"game_id"`
+	GameName       string                 `json:"game_name"`
+	Strategy       string                 `json:"strategy"`
+	Bet            map[string]interface{} `json:"bet"`
+	Rounds         int                    `json:"rounds"`
+	ServerSeed     string                 `json:"server_seed"` // Simulation seeds are not secret; publish them to reproduce the run
+	ServerSeedHash string                 `json:"server_seed_hash"`
+	ClientSeed     string                 `json:"client_seed"`
+
+	TotalStaked    float64 `json:"total_staked"`
+	TotalReturned  float64 `json:"total_returned"`
+	RTP            float64 `json:"rtp"`
+	TheoreticalRTP float64 `json:"theoretical_rtp"` // Stake-weighted over the bets the strategy placed
+	Difference     float64 `json:"difference"`
+	HitFrequency   float64 `json:"hit_frequency"` // Share of rounds returning anything
+	Variance       float64 `json:"variance"`      // Of the per-round return multiple
+	StdDev         float64 `json:"std_dev"`
+	MaxMultiplier  float64 `json:"max_multiplier"`
+	MaxWin         float64 `json:"max_win"`
+	StandardError  float64 `json:"standard_error"`
+	Confidence     float64 `json:"confidence"`
+	CILow          float64 `json:"ci_low"`
+	CIHigh         float64 `json:"ci_high"`
+	Tolerance      float64 `json:"tolerance"`
+	Status         string  `json:"status"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+	DurationMS  int64     `json:"duration_ms"`
+}
+
+func newReport(game engine.CertifiableGame, cfg Config, s *stats, elapsed time.Duration) *Report {
+	n := float64(s.rounds)
+	rtp := s.returned / s.staked
+	theoretical := s.expected / s.staked
+
+	// Standard error of a ratio estimator: sum of squared residuals p - R*s
+	se := 0.0
+	if s.rounds > 1 {
+		residuals := s.sumP2 - 2*rtp*s.sumPS + rtp*rtp*s.sumS2
+		meanStake := s.staked / n
+		se = math.Sqrt(math.Max(residuals, 0)/(n*(n-1))) / meanStake
+	}
+	z := math.Sqrt2 * math.Erfinv(cfg.Confidence)
+	halfWidth := z * se
+
+	variance := 0.0
+	if s.rounds > 1 {
+		variance = s.m2 / (n - 1)
+	}
+
+	r := &Report{
+		GameID:         game.GetGameID(),
+		GameName:       game.GetGameName(),
+		Strategy:       cfg.Strategy,
+		Bet:            cfg.Bet,
+		Rounds:         s.rounds,
+		ServerSeed:     cfg.ServerSeed,
+		ServerSeedHash: fairness.HashServerSeed(cfg.ServerSeed),
+		ClientSeed:     cfg.ClientSeed,
+		TotalStaked:    s.staked,
+		TotalReturned:  s.returned,
+		RTP:            rtp,
+		TheoreticalRTP: theoretical,
+		Difference:     rtp - theoretical,
+		HitFrequency:   float64(s.hits) / n,
+		Variance:       variance,
+		StdDev:         math.Sqrt(variance),
+		MaxMultiplier:  s.maxMultiple,
+		MaxWin:         s.maxWin,
+		StandardError:  se,
+		Confidence:     cfg.Confidence,
+		CILow:          rtp - halfWidth,
+		CIHigh:         rtp + halfWidth,
+		Tolerance:      cfg.Tolerance,
+		GeneratedAt:    time.Now().UTC(),
+		DurationMS:     elapsed.Milliseconds(),
+	}
+	r.Status = certify(math.Abs(r.Difference), halfWidth, cfg.Tolerance)
+	return r
+}
+
+// certify passes a run that is within tolerance and measured precisely enough to
+// say so, and fails one that is both out of tolerance and statistically significant
+func certify(diff, halfWidth, tolerance float64) string {
+	switch {
+	case diff <= tolerance && halfWidth <= tolerance:
+		return StatusPass
+	case diff > tolerance && diff > halfWidth:
+		return StatusFail
+	}
+	return StatusInconclusive
+}
+
+// Failed reports whether any run's RTP is significantly outside tolerance
+func Failed(reports []*Report) bool {
+	for _, r := range reports {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes the reports as an indented JSON array
+func WriteJSON(w io.Writer, reports []*Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+var csvHeader = []string{
+	"game_id", "strategy", "bet", "rounds", "server_seed_hash", "client_seed",
+	"total_staked", "total_returned", "rtp", "theoretical_rtp", "difference",
+	"hit_frequency", "variance", "std_dev", "max_multiplier", "max_win",
+	"confidence", "ci_low", "ci_high", "tolerance", "status",
+}
+
+// WriteCSV writes one row per report
+func WriteCSV(w io.Writer, reports []*Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, r := range reports {
+		bet, _ := json.Marshal(r.Bet)
+		row := []string{
+			r.GameID, r.Strategy, string(bet), strconv.Itoa(r.Rounds), r.ServerSeedHash, r.ClientSeed,
+			f(r.TotalStaked), f(r.TotalReturned), f(r.RTP), f(r.TheoreticalRTP), f(r.Difference),
+			f(r.HitFrequency), f(r.Variance), f(r.StdDev), f(r.MaxMultiplier), f(r.MaxWin),
+			f(r.Confidence), f(r.CILow), f(r.CIHigh), f(r.Tolerance), r.Status,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,204 @@
+// Package simulation runs games of chance offline for millions of rounds to measure
+// their return to player and certify it against the declared theoretical RTP.
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// Certification status
+const (
+	StatusPass         = "PASS"
+	StatusFail         = "FAIL"         // Theoretical RTP lies outside the confidence interval
+	StatusInconclusive = "INCONCLUSIVE" // Consistent, but too few rounds to be within tolerance
+)
+
+// ErrRTPAboveOne is returned for a bet whose declared theoretical RTP is above 1:
+// the house would lose money on it, so the game can never be certified
+var ErrRTPAboveOne = errors.New("theoretical RTP above 1")
+
+// Config describes one simulation run
+type Config struct {
+	Rounds     int
+	Workers    int // 0 = one per CPU
+	ServerSeed string
+	ClientSeed string
+	Strategy   string
+	Bet        map[string]interface{}
+	Tolerance  float64 // Allowed |simulated - theoretical| RTP
+	Confidence float64 // Two-sided confidence level of the interval, e.g. 0.99
+}
+
+// stats accumulates one worker's rounds. The sums give the RTP's standard error
+// as a ratio estimator, since stakes may vary from round to round.
+type stats struct {
+	rounds   int
+	hits     int
+	staked   float64
+	returned float64
+	expected float64 // Sum of stake * theoretical RTP of that round's bet
+	sumP2    float64
+	sumPS    float64
+	sumS2    float64
+
+	// Welford running moments of the per-round return multiple (payout / stake)
+	mean float64
+	m2   float64
+
+	maxMultiple float64
+	maxWin      float64
+}
+
+func (s *stats) add(stake, payout, rtp float64) {
+	s.rounds++
+	if payout > 0 {
+		s.hits++
+	}
+	s.staked += stake
+	s.returned += payout
+	s.expected += stake * rtp
+	s.sumP2 += payout * payout
+	s.sumPS += payout * stake
+	s.sumS2 += stake * stake
+
+	multiple := payout / stake
+	delta := multiple - s.mean
+	s.mean += delta / float64(s.rounds)
+	s.m2 += delta * (multiple - s.mean)
+
+	if multiple > s.maxMultiple {
+		s.maxMultiple = multiple
+	}
+	if payout > s.maxWin {
+		s.maxWin = payout
+	}
+}
+
+// merge folds o into s (Chan et al. parallel variance)
+func (s *stats) merge(o *stats) {
+	if o.rounds == 0 {
+		return
+	}
+	n := float64(s.rounds + o.rounds)
+	delta := o.mean - s.mean
+	s.m2 += o.m2 + delta*delta*float64(s.rounds)*float64(o.rounds)/n
+	s.mean += delta * float64(o.rounds) / n
+
+	s.rounds += o.rounds
+	s.hits += o.hits
+	s.staked += o.staked
+	s.returned += o.returned
+	s.expected += o.expected
+	s.sumP2 += o.sumP2
+	s.sumPS += o.sumPS
+	s.sumS2 += o.sumS2
+	s.maxMultiple = math.Max(s.maxMultiple, o.maxMultiple)
+	s.maxWin = math.Max(s.maxWin, o.maxWin)
+}
+
+// Run plays cfg.Rounds rounds of game. Round i uses nonce i, so a run is fully
+// reproducible from its seeds, and splitting it across workers does not change
+// which outcomes are drawn.
+func Run(game engine.CertifiableGame, cfg Config) (*Report, error) {
+	if cfg.Rounds <= 0 {
+		return nil, errors.New("rounds must be positive")
+	}
+	if cfg.ServerSeed == "" || cfg.ClientSeed == "" {
+		return nil, errors.New("server and client seeds are required")
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > cfg.Rounds {
+		workers = cfg.Rounds
+	}
+
+	// Fail fast on a bad bet or strategy before spinning up workers
+	if _, err := NewStrategy(cfg.Strategy, cfg.Bet); err != nil {
+		return nil, err
+	}
+	if _, err := theoreticalRTP(game, cfg.Bet); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	results := make([]*stats, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		first := 1 + w*cfg.Rounds/workers
+		last := (w + 1) * cfg.Rounds / workers
+		wg.Add(1)
+		go func(w, first, last int) {
+			defer wg.Done()
+			results[w], errs[w] = runRange(game, cfg, first, last)
+		}(w, first, last)
+	}
+	wg.Wait()
+
+	total := &stats{}
+	for w := range results {
+		if errs[w] != nil {
+			return nil, errs[w]
+		}
+		total.merge(results[w])
+	}
+
+	return newReport(game, cfg, total, time.Since(started)), nil
+}
+
+// runRange plays nonces first to last inclusive
+func runRange(game engine.CertifiableGame, cfg Config, first, last int) (*stats, error) {
+	strategy, err := NewStrategy(cfg.Strategy, cfg.Bet)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &stats{}
+	rtps := make(map[string]float64) // Theoretical RTP per distinct bet
+	var outcome *Outcome
+
+	for nonce := first; nonce <= last; nonce++ {
+		bet := strategy.NextBet(outcome)
+
+		encoded, _ := json.Marshal(bet)
+		key := string(encoded)
+		rtp, ok := rtps[key]
+		if !ok {
+			if rtp, err = theoreticalRTP(game, bet); err != nil {
+				return nil, err
+			}
+			rtps[key] = rtp
+		}
+
+		stake, payout, err := game.SimulateRound(cfg.ServerSeed, cfg.ClientSeed, nonce, bet)
+		if err != nil {
+			return nil, err
+		}
+		s.add(stake, payout, rtp)
+		outcome = &Outcome{Stake: stake, Payout: payout}
+	}
+	return s, nil
+}
+
+// theoreticalRTP is the game's declared RTP for bet, refusing one above 1
+func theoreticalRTP(game engine.CertifiableGame, bet map[string]interface{}) (float64, error) {
+	rtp, err := game.TheoreticalRTP(bet)
+	if err != nil {
+		return 0, err
+	}
+	if rtp > 1 {
+		return 0, fmt.Errorf("%w: %.6f for %v", ErrRTPAboveOne, rtp, bet)
+	}
+	return rtp, nil
+}
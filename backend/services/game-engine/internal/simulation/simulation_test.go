@@ -0,0 +1,95 @@
+package simulation
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/fairness"
+)
+
+// coinGame pays `pays` times the stake on heads, while declaring `declared` as
+// its theoretical RTP
+type coinGame struct {
+	engine.IGame
+	pays     float64
+	declared float64
+}
+
+func (g coinGame) GetGameID() string   { return "coin" }
+func (g coinGame) GetGameName() string { return "Coin" }
+
+func (g coinGame) TheoreticalRTP(map[string]interface{}) (float64, error) {
+	return g.declared, nil
+}
+
+func (g coinGame) SimulateRound(serverSeed, clientSeed string, nonce int, bet map[string]interface{}) (float64, float64, error) {
+	amount := bet["amount"].(float64)
+	if fairness.GenerateFloat(serverSeed, clientSeed, nonce) < 0.5 {
+		return amount, amount * g.pays, nil
+	}
+	return amount, 0, nil
+}
+
+func coinConfig(rounds int) Config {
+	return Config{
+		Rounds:     rounds,
+		Workers:    4,
+		ServerSeed: "server",
+		ClientSeed: "client",
+		Strategy:   "flat",
+		Bet:        map[string]interface{}{"amount": 10.0},
+		Tolerance:  0.01,
+		Confidence: 0.99,
+	}
+}
+
+func TestRunPassesGameMatchingItsDeclaredRTP(t *testing.T) {
+	report, err := Run(coinGame{pays: 1.96, declared: 0.98}, coinConfig(200000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusPass {
+		t.Fatalf("status = %s (RTP %.4f, CI [%.4f, %.4f]), want PASS", report.Status, report.RTP, report.CILow, report.CIHigh)
+	}
+}
+
+func TestRunFailsGameOutOfTolerance(t *testing.T) {
+	// Pays 94% but claims 98%
+	report, err := Run(coinGame{pays: 1.88, declared: 0.98}, coinConfig(200000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusFail || !Failed([]*Report{report}) {
+		t.Fatalf("status = %s (RTP %.4f), want FAIL", report.Status, report.RTP)
+	}
+}
+
+func TestRunRefusesTheoreticalRTPAboveOne(t *testing.T) {
+	_, err := Run(coinGame{pays: 2.04, declared: 1.02}, coinConfig(1000))
+	if !errors.Is(err, ErrRTPAboveOne) {
+		t.Fatalf("err = %v, want ErrRTPAboveOne", err)
+	}
+}
+
+func TestRunIsReproducibleAcrossWorkers(t *testing.T) {
+	game := coinGame{pays: 1.96, declared: 0.98}
+	one := coinConfig(10000)
+	one.Workers = 1
+	many := coinConfig(10000)
+	many.Workers = 7
+
+	a, err := Run(game, one)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Run(game, many)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Same outcomes; only the order of the floating-point sums differs
+	if a.HitFrequency != b.HitFrequency || math.Abs(a.TotalReturned-b.TotalReturned) > 1e-6 {
+		t.Fatalf("1 worker returned %.2f, 7 workers %.2f", a.TotalReturned, b.TotalReturned)
+	}
+}
@@ -0,0 +1,120 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Strategy decides the bet for each simulated round. Strategies may keep state
+// between rounds; every worker gets its own instance.
+type Strategy interface {
+	// NextBet returns the bet for the next round given the last one's outcome
+	// (nil before the first round). The returned map must not be modified later.
+	NextBet(last *Outcome) map[string]interface{}
+}
+
+// Outcome is what a strategy sees of a settled round
+type Outcome struct {
+	Stake  float64
+	Payout float64
+}
+
+// StrategyFactory builds a strategy around a base bet
+type StrategyFactory func(base map[string]interface{}) Strategy
+
+var strategies = map[string]StrategyFactory{
+	"flat":       func(base map[string]interface{}) Strategy { return flat{bet: base} },
+	"martingale": func(base map[string]interface{}) Strategy { return &progression{base: base, onLoss: true, maxSteps: 6} },
+	"paroli": func(base map[string]interface{}) Strategy {
+		return &progression{base: base, onLoss: false, maxSteps: 3}
+	},
+}
+
+// RegisterStrategy makes a strategy available by name
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategies[name] = factory
+}
+
+// NewStrategy builds the named strategy around base
+func NewStrategy(name string, base map[string]interface{}) (Strategy, error) {
+	factory, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q (have %v)", name, StrategyNames())
+	}
+	return factory(base), nil
+}
+
+// StrategyNames lists the registered strategies
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// flat repeats the same bet every round
+type flat struct {
+	bet map[string]interface{}
+}
+
+func (s flat) NextBet(*Outcome) map[string]interface{} {
+	return s.bet
+}
+
+// progression doubles every stake in the bet after a loss (martingale) or a win
+// (paroli), resetting after the other result or maxSteps doublings
+type progression struct {
+	base     map[string]interface{}
+	onLoss   bool
+	maxSteps int
+
+	steps int
+	cache map[int]map[string]interface{}
+}
+
+func (s *progression) NextBet(last *Outcome) map[string]interface{} {
+	if last != nil {
+		won := last.Payout > last.Stake
+		if won != s.onLoss && s.steps < s.maxSteps {
+			s.steps++
+		} else {
+			s.steps = 0
+		}
+	}
+
+	if s.cache == nil {
+		s.cache = make(map[int]map[string]interface{})
+	}
+	bet, ok := s.cache[s.steps]
+	if !ok {
+		bet = scaleAmounts(s.base, float64(int(1)<<s.steps)).(map[string]interface{})
+		s.cache[s.steps] = bet
+	}
+	return bet
+}
+
+// scaleAmounts deep-copies a bet, multiplying every "amount" field by factor so
+// nested layouts (roulette chips) scale too
+func scaleAmounts(v interface{}, factor float64) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, field := range v {
+			if amount, ok := field.(float64); ok && k == "amount" {
+				out[k] = amount * factor
+				continue
+			}
+			out[k] = scaleAmounts(field, factor)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = scaleAmounts(item, factor)
+		}
+		return out
+	}
+	return v
+}
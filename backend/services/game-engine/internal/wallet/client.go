@@ -11,8 +11,9 @@ import (
 )
 
 type WalletClient struct {
-	BaseURL    string
-	HTTPClient *http.Client
+	BaseURL        string
+	HouseAccountID string // Books the stakes and winnings of server-side bots
+	HTTPClient     *http.Client
 }
 
 type TransactionRequest struct {
@@ -35,8 +36,13 @@ func NewWalletClient() *WalletClient {
 	if baseURL == "" {
 		baseURL = "http://payment-service:8081"
 	}
+	houseAccount := os.Getenv("HOUSE_ACCOUNT_ID")
+	if houseAccount == "" {
+		houseAccount = "house"
+	}
 	return &WalletClient{
-		BaseURL: baseURL,
+		BaseURL:        baseURL,
+		HouseAccountID: houseAccount,
 		HTTPClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -53,6 +59,17 @@ func (c *WalletClient) Credit(userID string, amount float64, refID, refType stri
 	return c.sendTransaction(userID, amount, "WIN", refID, refType)
 }
 
+// DebitBot books a server-side bot's stake. Bots have no wallet: their money
+// moves through the house account.
+func (c *WalletClient) DebitBot(botID string, amount float64, refID, refType string) error {
+	return c.sendTransaction(c.HouseAccountID, amount, "BET", refID, "BOT_"+refType)
+}
+
+// CreditBot books a server-side bot's winnings or refund to the house account
+func (c *WalletClient) CreditBot(botID string, amount float64, refID, refType string) error {
+	return c.sendTransaction(c.HouseAccountID, amount, "WIN", refID, "BOT_"+refType)
+}
+
 func (c *WalletClient) sendTransaction(userID string, amount float64, txType, refID, refType string) error {
 	txID := fmt.Sprintf("tx_%s_%s_%d", txType, refID, time.Now().UnixNano())
 
@@ -94,3 +111,39 @@ func (c *WalletClient) sendTransaction(userID string, amount float64, txType, re
 
 	return nil
 }
+
+type FreezeRequest struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Freeze blocks bets and withdrawals from the user's wallet (account suspended)
+func (c *WalletClient) Freeze(userID, reason string) error {
+	return c.sendFreeze("/v1/payments/internal/wallets/freeze", FreezeRequest{UserID: userID, Reason: reason})
+}
+
+// Unfreeze lifts a freeze
+func (c *WalletClient) Unfreeze(userID string) error {
+	return c.sendFreeze("/v1/payments/internal/wallets/unfreeze", FreezeRequest{UserID: userID})
+}
+
+func (c *WalletClient) sendFreeze(path string, reqBody FreezeRequest) error {
+	jsonBody, _ := json.Marshal(reqBody)
+
+	req, err := http.NewRequest("POST", c.BaseURL+path, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("wallet freeze failed: " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package wallet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/playkaro/game-engine/internal/engine"
+)
+
+// A user's money is booked to the house only when the session seated them as a bot
+func TestBotStakesGoToHouseOnlyForSeatedBots(t *testing.T) {
+	var booked []TransactionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TransactionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		booked = append(booked, req)
+		json.NewEncoder(w).Encode(TransactionResponse{Status: "SUCCESS"})
+	}))
+	defer srv.Close()
+
+	c := &WalletClient{BaseURL: srv.URL, HouseAccountID: "house", HTTPClient: srv.Client()}
+	seats := ForSeats(c, []*engine.Player{
+		{UserID: "bot_seated", IsBot: true},
+		{UserID: "bot_claimed_by_client"},
+	})
+
+	for _, userID := range []string{"bot_seated", "bot_claimed_by_client"} {
+		if err := seats.Debit(userID, 10, "sess_1", "GAME_RUMMY"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := seats.Credit("bot_seated", 18, "sess_1", "GAME_RUMMY"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []TransactionRequest{
+		{UserID: "house", Type: "BET", ReferenceType: "BOT_GAME_RUMMY"},
+		{UserID: "bot_claimed_by_client", Type: "BET", ReferenceType: "GAME_RUMMY"},
+		{UserID: "house", Type: "WIN", ReferenceType: "BOT_GAME_RUMMY"},
+	}
+	if len(booked) != len(want) {
+		t.Fatalf("booked %d transactions, want %d", len(booked), len(want))
+	}
+	for i, w := range want {
+		if got := booked[i]; got.UserID != w.UserID || got.Type != w.Type || got.ReferenceType != w.ReferenceType {
+			t.Errorf("transaction %d booked as %s %s/%s, want %s %s/%s", i, got.Type, got.UserID, got.ReferenceType, w.Type, w.UserID, w.ReferenceType)
+		}
+	}
+}
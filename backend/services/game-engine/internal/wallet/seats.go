@@ -0,0 +1,46 @@
+package wallet
+
+import "github.com/playkaro/game-engine/internal/engine"
+
+// BotWallet moves users' money and, through the house account, bots'
+// (implemented by WalletClient)
+type BotWallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+	Credit(userID string, amount float64, refID, refType string) error
+	DebitBot(botID string, amount float64, refID, refType string) error
+	CreditBot(botID string, amount float64, refID, refType string) error
+}
+
+// Seated is a wallet for one session's players. Whether a player is a bot is
+// taken from how the session seated them (engine.Player.IsBot, saved with the
+// session), never from the user ID, so a client can't have its money booked to
+// the house by picking a bot-like ID.
+type Seated struct {
+	wallet BotWallet
+	bots   map[string]bool
+}
+
+// ForSeats returns the wallet for the given players
+func ForSeats(w BotWallet, players []*engine.Player) *Seated {
+	bots := make(map[string]bool)
+	for _, p := range players {
+		if p.IsBot {
+			bots[p.UserID] = true
+		}
+	}
+	return &Seated{wallet: w, bots: bots}
+}
+
+func (s *Seated) Debit(userID string, amount float64, refID, refType string) error {
+	if s.bots[userID] {
+		return s.wallet.DebitBot(userID, amount, refID, refType)
+	}
+	return s.wallet.Debit(userID, amount, refID, refType)
+}
+
+func (s *Seated) Credit(userID string, amount float64, refID, refType string) error {
+	if s.bots[userID] {
+		return s.wallet.CreditBot(userID, amount, refID, refType)
+	}
+	return s.wallet.Credit(userID, amount, refID, refType)
+}
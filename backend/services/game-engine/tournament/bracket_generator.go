@@ -5,31 +5,95 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// RatingSource supplies player skill ratings for seeding (implemented by rating.Service)
+type RatingSource interface {
+	Rating(userID, gameID string) (float64, error)
+}
+
 // BracketGenerator handles tournament bracket creation
-type BracketGenerator struct{}
+type BracketGenerator struct {
+	Ratings RatingSource // Optional; without it seeding is random
+	Rand    *rand.Rand   // Optional source for the shuffle, e.g. fixed-seed for reproducible pairings
+}
 
 // NewBracketGenerator creates a new generator
 func NewBracketGenerator() *BracketGenerator {
 	return &BracketGenerator{}
 }
 
-// GenerateBracket creates a single-elimination bracket
-func (bg *BracketGenerator) GenerateBracket(tournamentID string, participants []string) ([]TournamentMatch, error) {
+// NewSeededBracketGenerator creates a generator that seeds players by rating
+func NewSeededBracketGenerator(ratings RatingSource) *BracketGenerator {
+	return &BracketGenerator{Ratings: ratings}
+}
+
+// SeedParticipants orders participants from top seed down: by rating in gameID
+// when ratings are available, otherwise randomly
+func (bg *BracketGenerator) SeedParticipants(gameID string, participants []string) ([]string, error) {
+	seeded := make([]string, len(participants))
+	copy(seeded, participants)
+
+	shuffle := rand.Shuffle
+	if bg.Rand != nil {
+		shuffle = bg.Rand.Shuffle
+	}
+	shuffle(len(seeded), func(i, j int) {
+		seeded[i], seeded[j] = seeded[j], seeded[i]
+	})
+	if bg.Ratings == nil {
+		return seeded, nil
+	}
+
+	ratings := make(map[string]float64, len(seeded))
+	for _, userID := range seeded {
+		r, err := bg.Ratings.Rating(userID, gameID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rating for %s: %v", userID, err)
+		}
+		ratings[userID] = r
+	}
+	// Stable after the shuffle, so equally rated players are seeded randomly
+	sort.SliceStable(seeded, func(i, j int) bool {
+		return ratings[seeded[i]] > ratings[seeded[j]]
+	})
+	return seeded, nil
+}
+
+// seedSlots returns the seed (1-based) placed in each first-round slot, so that
+// seeds 1 and 2 can only meet in the final: 1v8, 4v5, 2v7, 3v6 for 8 slots
+func seedSlots(bracketSize int) []int {
+	slots := []int{1}
+	for len(slots) < bracketSize {
+		next := make([]int, 0, len(slots)*2)
+		for _, seed := range slots {
+			next = append(next, seed, len(slots)*2+1-seed)
+		}
+		slots = next
+	}
+	return slots
+}
+
+// GenerateBracket creates a single-elimination bracket. Players are seeded by
+// their rating in gameID; the top seeds get any byes.
+func (bg *BracketGenerator) GenerateBracket(tournamentID, gameID string, participants []string) ([]TournamentMatch, error) {
 	if len(participants) < 2 {
 		return nil, errors.New("need at least 2 participants")
 	}
 
-	// Shuffle participants
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(participants), func(i, j int) {
-		participants[i], participants[j] = participants[j], participants[i]
-	})
+	participants, err := bg.SeedParticipants(gameID, participants)
+	if err != nil {
+		return nil, err
+	}
+	return singleEliminationBracket(tournamentID, participants), nil
+}
 
+// singleEliminationBracket lays out the bracket for participants in seed order
+func singleEliminationBracket(tournamentID string, participants []string) []TournamentMatch {
 	// Calculate bracket size (next power of 2)
 	numPlayers := len(participants)
 	bracketSize := 1
@@ -106,28 +170,9 @@ func (bg *BracketGenerator) GenerateBracket(tournamentID string, participants []
 
 	// Assign players to Round 1
 	// We have 'bracketSize' slots in Round 1.
-	// Slots 0 to bracketSize-1.
-	// Match 0 has slots 0, 1. Match 1 has slots 2, 3.
-
-	// Distribute Byes.
-	// Byes should ideally be distributed to top seeds, but here random.
-	// Number of byes = bracketSize - numPlayers.
-	// Byes mean the opponent is NULL, and the player auto-advances.
-
-	// We place players in the first 'numPlayers' slots? No.
-	// Standard seeding for byes:
-	// If 4 slots, 3 players. Bye = 1.
-	// Match 0: P1 vs P2
-	// Match 1: P3 vs Bye
-
-	// Let's fill slots 0 to numPlayers-1 with players.
-	// Slots numPlayers to bracketSize-1 are Byes (Empty).
-
-	// Wait, if Match 1 is P3 vs Bye, P3 auto-wins.
-	// If Match 1 is Bye vs Bye, that's invalid (shouldn't happen if bracketSize < 2*numPlayers).
-
-	// Assign players to matches in Round 1
-	playerIdx := 0
+	// Match i has slots 2i and 2i+1; seeds above numPlayers are byes, and
+	// seedSlots pairs each of them with a top seed.
+	slots := seedSlots(bracketSize)
 	round1Matches := bracketSize / 2
 
 	for i := 0; i < round1Matches; i++ {
@@ -141,17 +186,15 @@ func (bg *BracketGenerator) GenerateBracket(tournamentID string, participants []
 		}
 
 		// Slot 1 (2*i)
-		if playerIdx < len(participants) {
-			pID := participants[playerIdx]
+		if seed := slots[2*i]; seed <= numPlayers {
+			pID := participants[seed-1]
 			match.Player1ID = &pID
-			playerIdx++
 		}
 
 		// Slot 2 (2*i + 1)
-		if playerIdx < len(participants) {
-			pID := participants[playerIdx]
+		if seed := slots[2*i+1]; seed <= numPlayers {
+			pID := participants[seed-1]
 			match.Player2ID = &pID
-			playerIdx++
 		}
 
 		// Handle Byes / Auto-win
@@ -163,7 +206,7 @@ func (bg *BracketGenerator) GenerateBracket(tournamentID string, participants []
 			// Or let the manager handle it. Let's let the manager handle it.
 			// But for initial generation, we can mark it.
 		} else if match.Player1ID == nil && match.Player2ID != nil {
-			// Should not happen with sequential filling
+			// Should not happen: seedSlots puts the lower seed in slot 1
 			match.Status = MatchCompleted
 			match.WinnerID = match.Player2ID
 		} else if match.Player1ID == nil && match.Player2ID == nil {
@@ -172,5 +215,5 @@ func (bg *BracketGenerator) GenerateBracket(tournamentID string, participants []
 		}
 	}
 
-	return matches, nil
+	return matches
 }
@@ -0,0 +1,339 @@
+package tournament
+
+import (
+	"errors"
+	"sort"
+)
+
+// SingleElimination knocks players out on their first loss
+type SingleElimination struct{}
+
+func (SingleElimination) Start(t *Tournament, seeded []string) ([]TournamentMatch, error) {
+	if len(seeded) < 2 {
+		return nil, errors.New("need at least 2 participants")
+	}
+	matches := singleEliminationBracket(t.ID, seeded)
+	advanceByes(matches)
+	return matches, nil
+}
+
+func (SingleElimination) Advance(t *Tournament, participants []Participant, matches []TournamentMatch, completed *TournamentMatch) (*Progress, error) {
+	progress := &Progress{Eliminated: []string{loserOf(completed)}}
+	// If NextMatchID is nil, this was the final
+	if completed.NextMatchID == nil {
+		progress.Done = true
+		return progress, nil
+	}
+
+	for i := range matches {
+		if matches[i].ID == *completed.NextMatchID {
+			seat(&matches[i], completed, *completed.WinnerID)
+			progress.Updated = append(progress.Updated, matches[i])
+			return progress, nil
+		}
+	}
+	return nil, ErrMatchNotFound
+}
+
+func (SingleElimination) Rank(t *Tournament, participants []Participant, matches []TournamentMatch) (map[string]int, error) {
+	return rankPlayers(matches, t.Config.TiedRanks)
+}
+
+// advanceByes moves players with a first-round bye into their next match
+func advanceByes(matches []TournamentMatch) {
+	byID := make(map[string]*TournamentMatch, len(matches))
+	for i := range matches {
+		byID[matches[i].ID] = &matches[i]
+	}
+	for i := range matches {
+		m := &matches[i]
+		if m.Round == 1 && m.Status == MatchCompleted && m.WinnerID != nil && m.NextMatchID != nil {
+			seat(byID[*m.NextMatchID], m, *m.WinnerID)
+		}
+	}
+}
+
+// seat puts the winner of from into next: the even match of each pair feeds
+// player 1, the odd one player 2
+func seat(next, from *TournamentMatch, winnerID string) {
+	if from.MatchIndex%2 == 0 {
+		next.Player1ID = &winnerID
+	} else {
+		next.Player2ID = &winnerID
+	}
+}
+
+// rankPlayers ranks everyone by the round they went out in. Players who went
+// out in the same round are ordered by the final rank of whoever beat them, so
+// losing to the champion ranks highest, unless tied makes them share the rank.
+func rankPlayers(matches []TournamentMatch, tied bool) (map[string]int, error) {
+	lastRound := 0
+	var final *TournamentMatch
+	for i := range matches {
+		if matches[i].Round > lastRound {
+			lastRound = matches[i].Round
+			final = &matches[i]
+		}
+	}
+	if final == nil || final.WinnerID == nil {
+		return nil, errors.New("final has not been played")
+	}
+
+	ranks := map[string]int{*final.WinnerID: 1}
+	next := 2
+	for round := lastRound; round >= 1; round-- {
+		next = rankLosers(matches, "", round, ranks, next, tied)
+	}
+	return ranks, nil
+}
+
+// rankLosers ranks the losers of one round from next on, ordered by the rank of
+// whoever beat them or all sharing next if tied, and returns the next free rank
+func rankLosers(matches []TournamentMatch, bracket string, round int, ranks map[string]int, next int, tied bool) int {
+	type loss struct {
+		loserID  string
+		beatenBy int
+	}
+	losses := []loss{}
+	for i := range matches {
+		m := &matches[i]
+		if m.Bracket != bracket || m.Round != round {
+			continue
+		}
+		if loserID := loserOf(m); loserID != "" {
+			if _, ranked := ranks[loserID]; !ranked {
+				losses = append(losses, loss{loserID, ranks[*m.WinnerID]})
+			}
+		}
+	}
+	sort.SliceStable(losses, func(i, j int) bool { return losses[i].beatenBy < losses[j].beatenBy })
+	for i, l := range losses {
+		if tied {
+			ranks[l.loserID] = next
+		} else {
+			ranks[l.loserID] = next + i
+		}
+	}
+	return next + len(losses)
+}
+
+// DoubleElimination knocks players out on their second loss. Losers of the
+// winners bracket drop into the losers bracket; the winners of both meet in the
+// grand final, which is replayed if the losers bracket side wins it.
+type DoubleElimination struct{}
+
+// feed is where a match slot's player comes from
+type feed struct {
+	bracket string
+	round   int
+	index   int
+	loser   bool // The loser of that match rather than the winner
+}
+
+// doubleLayout sizes a double elimination bracket from its winners bracket
+type doubleLayout struct {
+	size     int // Winners bracket slots, a power of two
+	wbRounds int
+	lbRounds int
+}
+
+func layoutFor(matches []TournamentMatch) doubleLayout {
+	l := doubleLayout{}
+	for _, m := range matches {
+		if m.Bracket == BracketWinners && m.Round == 1 {
+			l.size += 2
+		}
+		if m.Bracket == BracketWinners && m.Round > l.wbRounds {
+			l.wbRounds = m.Round
+		}
+	}
+	l.lbRounds = 2 * (l.wbRounds - 1)
+	return l
+}
+
+// lbMatches is the number of matches in a losers bracket round: winners bracket
+// losers come in on even rounds, and odd rounds halve the field
+func (l doubleLayout) lbMatches(round int) int {
+	if round%2 == 0 {
+		return l.size >> uint(round/2+1)
+	}
+	return l.size >> uint((round+1)/2+1)
+}
+
+// feeds returns where the two players of a match come from; none for the first
+// winners bracket round and the grand final reset
+func (l doubleLayout) feeds(m *TournamentMatch) []feed {
+	switch {
+	case m.Bracket == BracketWinners && m.Round > 1:
+		return []feed{{BracketWinners, m.Round - 1, 2 * m.MatchIndex, false}, {BracketWinners, m.Round - 1, 2*m.MatchIndex + 1, false}}
+	case m.Bracket == BracketLosers && m.Round == 1:
+		return []feed{{BracketWinners, 1, 2 * m.MatchIndex, true}, {BracketWinners, 1, 2*m.MatchIndex + 1, true}}
+	case m.Bracket == BracketLosers && m.Round%2 == 0:
+		// Dropped players are fed in reverse order to put off rematches
+		wbRound := m.Round/2 + 1
+		return []feed{{BracketLosers, m.Round - 1, m.MatchIndex, false}, {BracketWinners, wbRound, l.lbMatches(m.Round) - 1 - m.MatchIndex, true}}
+	case m.Bracket == BracketLosers:
+		return []feed{{BracketLosers, m.Round - 1, 2 * m.MatchIndex, false}, {BracketLosers, m.Round - 1, 2*m.MatchIndex + 1, false}}
+	case m.Bracket == BracketGrandFinal && m.Round == 1:
+		if l.lbRounds == 0 {
+			return []feed{{BracketWinners, l.wbRounds, 0, false}, {BracketWinners, l.wbRounds, 0, true}}
+		}
+		return []feed{{BracketWinners, l.wbRounds, 0, false}, {BracketLosers, l.lbRounds, 0, false}}
+	}
+	return nil
+}
+
+func (DoubleElimination) Start(t *Tournament, seeded []string) ([]TournamentMatch, error) {
+	if len(seeded) < 2 {
+		return nil, errors.New("need at least 2 participants")
+	}
+
+	matches := singleEliminationBracket(t.ID, seeded)
+	for i := range matches {
+		matches[i].Bracket = BracketWinners
+	}
+	l := layoutFor(matches)
+	for round := 1; round <= l.lbRounds; round++ {
+		for i := 0; i < l.lbMatches(round); i++ {
+			matches = append(matches, newMatch(t.ID, BracketLosers, round, i))
+		}
+	}
+	matches = append(matches, newMatch(t.ID, BracketGrandFinal, 1, 0))
+
+	// Point every match at where its winner and loser go, for clients drawing the bracket
+	index := indexMatches(matches)
+	for i := range matches {
+		for _, f := range l.feeds(&matches[i]) {
+			from := index[f]
+			id := matches[i].ID
+			if f.loser {
+				from.LoserNextID = &id
+			} else {
+				from.NextMatchID = &id
+			}
+		}
+	}
+
+	settle(l, matches)
+	return matches, nil
+}
+
+func (DoubleElimination) Advance(t *Tournament, participants []Participant, matches []TournamentMatch, completed *TournamentMatch) (*Progress, error) {
+	loserID := loserOf(completed)
+	progress := &Progress{}
+
+	if completed.Bracket == BracketGrandFinal {
+		// The losers bracket side has lost once already; if it wins, both have one loss
+		if completed.Round == 1 && *completed.WinnerID != *completed.Player1ID {
+			reset := pairing(t.ID, 2, 0, *completed.Player1ID, *completed.Player2ID)
+			reset.Bracket = BracketGrandFinal
+			progress.Created = append(progress.Created, reset)
+			return progress, nil
+		}
+		progress.Eliminated = []string{loserID}
+		progress.Done = true
+		return progress, nil
+	}
+
+	if completed.Bracket == BracketLosers {
+		progress.Eliminated = []string{loserID}
+	}
+	l := layoutFor(matches)
+	for _, i := range settle(l, matches) {
+		progress.Updated = append(progress.Updated, matches[i])
+	}
+	return progress, nil
+}
+
+func (DoubleElimination) Rank(t *Tournament, participants []Participant, matches []TournamentMatch) (map[string]int, error) {
+	var final *TournamentMatch
+	for i := range matches {
+		m := &matches[i]
+		if m.Bracket == BracketGrandFinal && m.Status == MatchCompleted && (final == nil || m.Round > final.Round) {
+			final = m
+		}
+	}
+	if final == nil || final.WinnerID == nil {
+		return nil, errors.New("grand final has not been played")
+	}
+
+	ranks := map[string]int{*final.WinnerID: 1, loserOf(final): 2}
+	next := 3
+	l := layoutFor(matches)
+	for round := l.lbRounds; round >= 1; round-- {
+		next = rankLosers(matches, BracketLosers, round, ranks, next, t.Config.TiedRanks)
+	}
+	return ranks, nil
+}
+
+func indexMatches(matches []TournamentMatch) map[feed]*TournamentMatch {
+	index := make(map[feed]*TournamentMatch, len(matches))
+	for i := range matches {
+		m := &matches[i]
+		index[feed{m.Bracket, m.Round, m.MatchIndex, false}] = m
+		index[feed{m.Bracket, m.Round, m.MatchIndex, true}] = m
+	}
+	return index
+}
+
+// settle seats players whose feeding matches are decided and settles matches
+// left with one player (a bye) or none, until nothing changes. It returns the
+// indexes of the matches it changed.
+func settle(l doubleLayout, matches []TournamentMatch) []int {
+	index := indexMatches(matches)
+	changed := map[int]bool{}
+	for progress := true; progress; {
+		progress = false
+		for i := range matches {
+			m := &matches[i]
+			feeds := l.feeds(m)
+			if m.Status != MatchScheduled || len(feeds) == 0 {
+				continue
+			}
+
+			decided := 0
+			for slot, f := range feeds {
+				from := index[f]
+				if from.Status != MatchCompleted {
+					continue
+				}
+				decided++
+				player := &m.Player1ID
+				if slot == 1 {
+					player = &m.Player2ID
+				}
+				if *player != nil {
+					continue
+				}
+				if f.loser {
+					if loserID := loserOf(from); loserID != "" {
+						*player = &loserID
+						progress, changed[i] = true, true
+					}
+				} else if from.WinnerID != nil {
+					winnerID := *from.WinnerID
+					*player = &winnerID
+					progress, changed[i] = true, true
+				}
+			}
+
+			// With both feeds decided, a match short of players is settled without being played
+			if decided == len(feeds) && (m.Player1ID == nil || m.Player2ID == nil) {
+				m.Status = MatchCompleted
+				if m.Player1ID != nil {
+					m.WinnerID = m.Player1ID
+				} else if m.Player2ID != nil {
+					m.WinnerID = m.Player2ID
+				}
+				progress, changed[i] = true, true
+			}
+		}
+	}
+
+	indexes := make([]int, 0, len(changed))
+	for i := range changed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
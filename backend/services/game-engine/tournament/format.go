@@ -0,0 +1,116 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format lays out a tournament's matches and moves it on as results come in
+type Format interface {
+	// Start creates the opening matches for participants in seed order (top seed first)
+	Start(t *Tournament, seeded []string) ([]TournamentMatch, error)
+	// Advance is told about a completed match, already in matches, and says what follows
+	Advance(t *Tournament, participants []Participant, matches []TournamentMatch, completed *TournamentMatch) (*Progress, error)
+	// Rank gives every participant a final rank, 1 = champion, once Advance reports Done
+	Rank(t *Tournament, participants []Participant, matches []TournamentMatch) (map[string]int, error)
+}
+
+// Progress is what follows a match result
+type Progress struct {
+	Created    []TournamentMatch // New matches, e.g. the next Swiss round
+	Updated    []TournamentMatch // Existing matches that changed, e.g. a winner seated
+	Eliminated []string          // Players knocked out
+	Done       bool              // The tournament is decided
+}
+
+// NewFormat returns the format for config.BracketType
+func NewFormat(config TournamentConfig) (Format, error) {
+	for _, tb := range config.TieBreakers {
+		switch tb {
+		case TieBreakBuchholz, TieBreakHeadToHead, TieBreakScoreDiff:
+		default:
+			return nil, fmt.Errorf("unknown tie-breaker %s", tb)
+		}
+	}
+
+	switch config.BracketType {
+	case "", BracketSingleElimination:
+		return SingleElimination{}, nil
+	case BracketDoubleElimination:
+		return DoubleElimination{}, nil
+	case BracketRoundRobin:
+		return RoundRobin{}, nil
+	case BracketSwiss:
+		if config.Rounds < 0 {
+			return nil, fmt.Errorf("invalid number of rounds %d", config.Rounds)
+		}
+		return Swiss{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported bracket type %s", config.BracketType)
+	}
+}
+
+func newMatch(tournamentID, bracket string, round, index int) TournamentMatch {
+	now := time.Now()
+	return TournamentMatch{
+		ID:           uuid.New().String(),
+		TournamentID: tournamentID,
+		Bracket:      bracket,
+		Round:        round,
+		MatchIndex:   index,
+		Status:       MatchScheduled,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// pairing schedules a match between two players
+func pairing(tournamentID string, round, index int, player1, player2 string) TournamentMatch {
+	m := newMatch(tournamentID, "", round, index)
+	m.Player1ID, m.Player2ID = &player1, &player2
+	return m
+}
+
+// loserOf returns the player who lost a completed match, or "" for byes
+func loserOf(m *TournamentMatch) string {
+	if m.WinnerID == nil || m.Player1ID == nil || m.Player2ID == nil {
+		return ""
+	}
+	if *m.WinnerID == *m.Player1ID {
+		return *m.Player2ID
+	}
+	return *m.Player1ID
+}
+
+// seedOrder lists participants top seed first
+func seedOrder(participants []Participant) []string {
+	ordered := append([]Participant{}, participants...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Seed < ordered[j].Seed })
+	ids := make([]string, len(ordered))
+	for i, p := range ordered {
+		ids[i] = p.UserID
+	}
+	return ids
+}
+
+// roundComplete reports whether every match of the round has been decided
+func roundComplete(matches []TournamentMatch, round int) bool {
+	for _, m := range matches {
+		if m.Round == round && m.Status != MatchCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// rankByStandings ranks players by their place in the standings table
+func rankByStandings(standings []Standing) map[string]int {
+	ranks := make(map[string]int, len(standings))
+	for _, s := range standings {
+		ranks[s.UserID] = s.Rank
+	}
+	return ranks
+}
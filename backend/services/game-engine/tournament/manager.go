@@ -1,111 +1,648 @@
 package tournament
 
 import (
-	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/session"
+	"github.com/playkaro/game-engine/internal/settlement"
 )
 
+var (
+	ErrRegistrationClosed = errors.New("registration is not open")
+	ErrNotEnoughPlayers   = errors.New("not enough participants")
+	ErrInvalidStatus      = errors.New("tournament is not in the right state")
+	ErrInvalidWinner      = errors.New("winner is not a player in this match")
+	ErrMatchNotReady      = errors.New("match is not being played")
+	ErrEntryLimit         = errors.New("no re-entries left in this tournament")
+)
+
+// Wallet collects entry fees (implemented by wallet.WalletClient). Prizes and
+// refunds are paid through a settlement.Settler.
+type Wallet interface {
+	Debit(userID string, amount float64, refID, refType string) error
+}
+
+// SessionCreator opens the game session for a match (implemented by session.SessionManager)
+type SessionCreator interface {
+	CreateSession(gameID, userID string, opts ...session.SessionOption) (*engine.GameSession, error)
+}
+
+// Clock is the manager's time source, swapped for a fake one in tests
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
 // TournamentManager handles tournament lifecycle
 type TournamentManager struct {
-	DB               *sql.DB
+	Store            Store
 	BracketGen       *BracketGenerator
 	PrizeDistributor *PrizeDistributor
+	Clock            Clock
+	sessions         SessionCreator
+	wallet           Wallet
+	settler          *settlement.Settler // Pays prizes and refunds, retrying failed credits
+	mu               sync.Mutex          // Serialises lifecycle changes
 }
 
-// NewTournamentManager creates a new manager
-func NewTournamentManager(db *sql.DB) *TournamentManager {
+// NewTournamentManager creates a new manager that collects entry fees from
+// wallet and pays prizes and refunds through settler
+func NewTournamentManager(store Store, sessions SessionCreator, wallet Wallet, settler *settlement.Settler) *TournamentManager {
 	return &TournamentManager{
-		DB:               db,
+		Store:            store,
 		BracketGen:       NewBracketGenerator(),
 		PrizeDistributor: NewPrizeDistributor(),
+		Clock:            realClock{},
+		sessions:         sessions,
+		wallet:           wallet,
+		settler:          settler,
 	}
 }
 
-// CreateTournament creates a new tournament
-func (tm *TournamentManager) CreateTournament(name, gameType string, entryFee, prizePool float64, maxPlayers int, config TournamentConfig) (*Tournament, error) {
+// CreateTournament creates a new tournament. Registration opens at
+// registrationOpens (now if zero) and closes when the tournament starts.
+func (tm *TournamentManager) CreateTournament(name, gameType string, entryFee, prizePool float64, maxPlayers int, registrationOpens, startTime time.Time, config TournamentConfig) (*Tournament, error) {
 	t := &Tournament{
-		ID:         uuid.New().String(),
-		Name:       name,
-		GameType:   gameType,
-		EntryFee:   entryFee,
-		PrizePool:  prizePool,
-		MaxPlayers: maxPlayers,
-		Status:     StatusRegistration,
-		Config:     config,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-
-	// Save to DB (omitted for brevity, assume standard SQL insert)
-	// In real implementation:
-	// _, err := tm.DB.Exec(...)
+		Name:              name,
+		GameType:          gameType,
+		EntryFee:          entryFee,
+		PrizePool:         prizePool,
+		MaxPlayers:        maxPlayers,
+		Config:            config,
+		RegistrationOpens: registrationOpens,
+		StartTime:         startTime,
+	}
+	if err := tm.create(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
 
+// create validates a new tournament, fills in its defaults and saves it
+func (tm *TournamentManager) create(t *Tournament) error {
+	if err := tm.prepare(t); err != nil {
+		return err
+	}
+	return tm.Store.CreateTournament(t)
+}
+
+// prepare validates a new tournament and fills in its defaults
+func (tm *TournamentManager) prepare(t *Tournament) error {
+	game, err := registry.GetRegistry().GetGame(t.GameType)
+	if err != nil {
+		return err
+	}
+	// Every match is one player against another
+	if game.GetMinPlayers() > 2 || game.GetMaxPlayers() < 2 {
+		return fmt.Errorf("%s can't be played head to head", t.GameType)
+	}
+
+	config := &t.Config
+	if config.BracketType == "" {
+		config.BracketType = BracketSingleElimination
+	}
+	if _, err := NewFormat(*config); err != nil {
+		return err
+	}
+	if config.PrizeStrategy == "" {
+		config.PrizeStrategy = PrizeWinnerTakesAll
+	}
+	if config.MinPlayers < 2 {
+		config.MinPlayers = 2
+	}
+	if t.MaxPlayers < config.MinPlayers {
+		return errors.New("max players below min players")
+	}
+	if t.EntryFee < 0 || t.PrizePool < 0 {
+		return errors.New("entry fee and prize pool can't be negative")
+	}
+	if err := tm.PrizeDistributor.Validate(*config, t.PrizePool, t.MaxPlayers); err != nil {
+		return err
+	}
+
+	now := tm.Clock.Now()
+	if t.RegistrationOpens.IsZero() {
+		t.RegistrationOpens = now
+	}
+	if !t.StartTime.After(t.RegistrationOpens) || !t.StartTime.After(now) {
+		return errors.New("start time must be in the future and after registration opens")
+	}
+	if t.RegistrationCloses.IsZero() {
+		t.RegistrationCloses = t.StartTime
+	}
+	if t.RegistrationCloses.Before(t.StartTime) {
+		return errors.New("registration can't close before the start")
+	}
+	// Late entrants join the next Swiss round; other formats draw everyone at the start
+	if t.RegistrationCloses.After(t.StartTime) && config.BracketType != BracketSwiss {
+		return errors.New("late registration needs a Swiss tournament")
+	}
+	// Players re-enter the running tournament, so only while late registration is open
+	if t.MaxEntries > 1 && !t.RegistrationCloses.After(t.StartTime) {
+		return errors.New("re-entries need late registration")
+	}
+
+	t.ID = uuid.New().String()
+	t.Status = StatusRegistration
+	t.CurrentPlayers = 0
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	return nil
+}
+
+// GetTournament returns a tournament, failing with ErrTournamentNotFound
+func (tm *TournamentManager) GetTournament(tournamentID string) (*Tournament, error) {
+	t, err := tm.Store.GetTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTournamentNotFound
+	}
 	return t, nil
 }
 
-// RegisterParticipant adds a user to the tournament
+// RegisterParticipant adds a user to the tournament and collects their entry fee
 func (tm *TournamentManager) RegisterParticipant(tournamentID, userID string) error {
-	// Check if tournament is in REGISTRATION state
-	// Check if max players reached
-	// Deduct entry fee (via wallet service)
+	if engine.ReservedUserID(userID) {
+		return errors.New("bots can't enter tournaments")
+	}
+	full, err := tm.register(tournamentID, userID)
+	if err != nil || !full {
+		return err
+	}
+	// The entry stands either way; a failed start is retried at the start time
+	if err := tm.StartTournament(tournamentID); err != nil && !errors.Is(err, ErrInvalidStatus) {
+		log.Printf("tournament %s: failed to start when full: %v", tournamentID, err)
+	}
+	return nil
+}
+
+// register takes the user's seat and collects the fee, holding mu so the
+// tournament can't start or be cancelled meanwhile. It reports whether a
+// sit-and-go has just filled up.
+func (tm *TournamentManager) register(tournamentID, userID string) (bool, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
-	// Add to DB
+	t, err := tm.GetTournament(tournamentID)
+	if err != nil {
+		return false, err
+	}
+	now := tm.Clock.Now()
+	// Late registration: a running tournament takes entrants until registration
+	// closes. The store checks again as it takes the seat, for other instances.
+	open, late := t.registering(now)
+	if !open {
+		return false, ErrRegistrationClosed
+	}
+
+	p := &Participant{
+		ID:           uuid.New().String(),
+		TournamentID: tournamentID,
+		UserID:       userID,
+		Status:       ParticipantRegistered,
+		RegisteredAt: now,
+	}
+	// A player already in a running tournament may re-enter, starting over from
+	// the next round; the seat is put back as it was if the fee can't be collected
+	var seated *Participant
+	if late {
+		if seated, err = tm.seat(tournamentID, userID); err != nil {
+			return false, err
+		}
+		if seated != nil {
+			if p.EnteredRound, err = tm.nextRound(tournamentID); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// Take the seat first so a full tournament never charges anyone
+	if err := tm.Store.AddParticipant(p, t.MaxPlayers); err != nil {
+		return false, err
+	}
+
+	if t.EntryFee > 0 {
+		if err := tm.wallet.Debit(userID, t.EntryFee, tournamentID, "TOURNAMENT_ENTRY"); err != nil {
+			if seated != nil {
+				if undoErr := tm.Store.UpdateParticipant(seated); undoErr != nil {
+					log.Printf("tournament %s: failed to undo the re-entry of %s: %v", tournamentID, userID, undoErr)
+				}
+			} else if removeErr := tm.Store.RemoveParticipant(tournamentID, userID); removeErr != nil {
+				log.Printf("tournament %s: failed to free the seat of %s: %v", tournamentID, userID, removeErr)
+			}
+			return false, fmt.Errorf("failed to collect entry fee: %v", err)
+		}
+	}
+	return t.Status == StatusRegistration && t.Config.StartWhenFull && t.CurrentPlayers+1 >= t.MaxPlayers, nil
+}
+
+// seat returns the user's seat in the tournament, or nil if they have none
+func (tm *TournamentManager) seat(tournamentID, userID string) (*Participant, error) {
+	participants, err := tm.Store.Participants(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range participants {
+		if participants[i].UserID == userID {
+			return &participants[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// nextRound is the round after the latest one drawn
+func (tm *TournamentManager) nextRound(tournamentID string) (int, error) {
+	matches, err := tm.Store.Matches(tournamentID)
+	if err != nil {
+		return 0, err
+	}
+	round := 0
+	for _, m := range matches {
+		round = max(round, m.Round)
+	}
+	return round + 1, nil
+}
+
+// CancelTournament cancels a tournament that has not started and refunds every entry fee
+func (tm *TournamentManager) CancelTournament(tournamentID string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	t, err := tm.GetTournament(tournamentID)
+	if err != nil {
+		return err
+	}
+	if t.Status != StatusRegistration {
+		return ErrInvalidStatus
+	}
+	return tm.cancel(t)
+}
+
+func (tm *TournamentManager) cancel(t *Tournament) error {
+	t.Status = StatusCancelled
+	t.UpdatedAt = tm.Clock.Now()
+	if err := tm.Store.UpdateTournament(t); err != nil {
+		return err
+	}
+
+	participants, err := tm.Store.Participants(t.ID)
+	if err != nil {
+		return err
+	}
+	for i := range participants {
+		p := &participants[i]
+		tm.settler.Pay(settlement.Credit{UserID: p.UserID, Amount: t.EntryFee, RefID: t.ID, RefType: "TOURNAMENT_REFUND"})
+		p.Status = ParticipantRefunded
+		if err := tm.Store.UpdateParticipant(p); err != nil {
+			log.Printf("tournament %s: failed to mark %s refunded: %v", t.ID, p.UserID, err)
+		}
+	}
+	log.Printf("tournament %s cancelled, %d entries refunded", t.ID, len(participants))
 	return nil
 }
 
-// StartTournament generates brackets and starts the tournament
+// StartTournament generates brackets and starts the tournament. Below the
+// minimum number of participants it is cancelled instead.
 func (tm *TournamentManager) StartTournament(tournamentID string) error {
-	// Get participants
-	// participants := tm.getParticipants(tournamentID)
-	// Mock participants for now
-	participants := []string{"p1", "p2", "p3", "p4"} // Example
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	t, err := tm.GetTournament(tournamentID)
+	if err != nil {
+		return err
+	}
+	if t.Status != StatusRegistration {
+		return ErrInvalidStatus
+	}
+
+	participants, err := tm.Store.Participants(tournamentID)
+	if err != nil {
+		return err
+	}
+	if len(participants) < t.Config.MinPlayers {
+		if err := tm.cancel(t); err != nil {
+			return err
+		}
+		return ErrNotEnoughPlayers
+	}
 
-	// Generate Bracket
-	matches, err := tm.BracketGen.GenerateBracket(tournamentID, participants)
+	format, err := NewFormat(t.Config)
 	if err != nil {
 		return err
 	}
-	_ = matches // Mock save
 
-	// Save matches to DB
-	// Update tournament status to ACTIVE
+	userIDs := make([]string, len(participants))
+	for i, p := range participants {
+		userIDs[i] = p.UserID
+	}
+	// Seeded by rating when BracketGen has ratings
+	seeded, err := tm.BracketGen.SeedParticipants(t.GameType, userIDs)
+	if err != nil {
+		return err
+	}
+	seeds := make(map[string]int, len(seeded))
+	for i, userID := range seeded {
+		seeds[userID] = i + 1
+	}
+	for i := range participants {
+		participants[i].Seed = seeds[participants[i].UserID]
+		if err := tm.Store.UpdateParticipant(&participants[i]); err != nil {
+			return err
+		}
+	}
 
+	matches, err := format.Start(t, seeded)
+	if err != nil {
+		return err
+	}
+	if err := tm.Store.SaveMatches(matches); err != nil {
+		return err
+	}
+
+	t.Status = StatusActive
+	// Starting early brings the late registration window forward with it
+	now := tm.Clock.Now()
+	t.RegistrationCloses = now.Add(t.RegistrationCloses.Sub(t.StartTime))
+	t.StartTime = now
+	t.UpdatedAt = t.StartTime
+	if err := tm.Store.UpdateTournament(t); err != nil {
+		return err
+	}
+
+	tm.startReadyMatches(t, matches)
 	return nil
 }
 
+// startReadyMatches opens a game session for every scheduled match with both players
+func (tm *TournamentManager) startReadyMatches(t *Tournament, matches []TournamentMatch) {
+	for i := range matches {
+		m := &matches[i]
+		if m.Status == MatchScheduled && m.Player1ID != nil && m.Player2ID != nil {
+			if err := tm.startMatch(t, m); err != nil {
+				log.Printf("tournament %s: failed to start match %s: %v", t.ID, m.ID, err)
+			}
+		}
+	}
+}
+
+// startMatch opens the match's session. Entry fees were paid at registration,
+// so the session carries no stake.
+func (tm *TournamentManager) startMatch(t *Tournament, m *TournamentMatch) error {
+	sess, err := tm.sessions.CreateSession(t.GameType, *m.Player1ID,
+		session.WithPlayers(*m.Player2ID),
+		session.WithEntryFee(0),
+		session.WithPrepaidEntry(),
+	)
+	if err != nil {
+		return err
+	}
+	m.SessionID = sess.SessionID
+	m.Status = MatchInProgress
+	return tm.Store.UpdateMatch(m)
+}
+
+// HandleGameEnd advances the winner of a tournament match when its session ends
+// (see session.SessionManager.OnGameEnd)
+func (tm *TournamentManager) HandleGameEnd(sess *engine.GameSession, result *engine.GameResult) {
+	m, err := tm.Store.MatchBySession(sess.SessionID)
+	if err != nil {
+		log.Printf("tournament: failed to look up match of session %s: %v", sess.SessionID, err)
+		return
+	}
+	if m == nil || m.Status != MatchInProgress {
+		return // Not a tournament match
+	}
+
+	winnerID := ""
+	if result != nil {
+		winnerID = result.WinnerID
+	}
+	scores := make(map[string]int, len(sess.Players))
+	for _, p := range sess.Players {
+		scores[p.UserID] = p.Score
+	}
+	err = tm.advance(m.ID, winnerID, scores)
+	if errors.Is(err, ErrInvalidWinner) {
+		// No winner (e.g. abandoned): the match is replayed
+		tm.mu.Lock()
+		defer tm.mu.Unlock()
+		t, err := tm.GetTournament(m.TournamentID)
+		if err == nil {
+			err = tm.startMatch(t, m)
+		}
+		if err != nil {
+			log.Printf("tournament %s: failed to replay match %s: %v", m.TournamentID, m.ID, err)
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("tournament %s: failed to advance match %s: %v", m.TournamentID, m.ID, err)
+	}
+}
+
 // AdvanceMatch updates a match result and progresses the winner
 func (tm *TournamentManager) AdvanceMatch(matchID, winnerID string) error {
-	// Get match
-	// match := tm.getMatch(matchID)
+	return tm.advance(matchID, winnerID, nil)
+}
+
+// advance records the result, with the players' game scores when known, and
+// lets the tournament's format decide what follows
+func (tm *TournamentManager) advance(matchID, winnerID string, scores map[string]int) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	m, err := tm.Store.GetMatch(matchID)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return ErrMatchNotFound
+	}
+	if m.Status == MatchCompleted || m.Player1ID == nil || m.Player2ID == nil {
+		return ErrMatchNotReady
+	}
+	if winnerID != *m.Player1ID && winnerID != *m.Player2ID {
+		return ErrInvalidWinner
+	}
+
+	t, err := tm.GetTournament(m.TournamentID)
+	if err != nil {
+		return err
+	}
+	format, err := NewFormat(t.Config)
+	if err != nil {
+		return err
+	}
 
-	// Update match status to COMPLETED, set WinnerID
+	m.WinnerID = &winnerID
+	m.Status = MatchCompleted
+	if scores != nil {
+		m.Player1Score, m.Player2Score = scores[*m.Player1ID], scores[*m.Player2ID]
+	}
+	if err := tm.Store.UpdateMatch(m); err != nil {
+		return err
+	}
 
-	// If NextMatchID is set:
-	//   Get next match
-	//   If Player1ID is nil, set Player1ID = winnerID
-	//   Else if Player2ID is nil, set Player2ID = winnerID
-	//   Update next match in DB
+	participants, err := tm.Store.Participants(t.ID)
+	if err != nil {
+		return err
+	}
+	matches, err := tm.Store.Matches(t.ID)
+	if err != nil {
+		return err
+	}
+	progress, err := format.Advance(t, participants, matches, m)
+	if err != nil {
+		return err
+	}
 
-	// If NextMatchID is nil, this was the final.
-	//   CompleteTournament(tournamentID, winnerID)
+	for _, userID := range progress.Eliminated {
+		if err := tm.setParticipantStatus(participants, userID, ParticipantEliminated); err != nil {
+			log.Printf("tournament %s: failed to eliminate %s: %v", t.ID, userID, err)
+		}
+	}
+	if err := tm.Store.SaveMatches(progress.Created); err != nil {
+		return err
+	}
+	for i := range progress.Updated {
+		if err := tm.Store.UpdateMatch(&progress.Updated[i]); err != nil {
+			return err
+		}
+	}
 
+	if progress.Done {
+		return tm.complete(t)
+	}
+	tm.startReadyMatches(t, append(progress.Created, progress.Updated...))
 	return nil
 }
 
-// CompleteTournament finishes the tournament and distributes prizes
-func (tm *TournamentManager) CompleteTournament(tournamentID, winnerID string) error {
-	// Update tournament status to COMPLETED
+func (tm *TournamentManager) setParticipantStatus(participants []Participant, userID, status string) error {
+	for i := range participants {
+		if participants[i].UserID == userID {
+			participants[i].Status = status
+			return tm.Store.UpdateParticipant(&participants[i])
+		}
+	}
+	return ErrNotRegistered
+}
+
+// CompleteTournament finishes an active tournament whose final has been played
+// and distributes prizes
+func (tm *TournamentManager) CompleteTournament(tournamentID string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	t, err := tm.GetTournament(tournamentID)
+	if err != nil {
+		return err
+	}
+	return tm.complete(t)
+}
+
+func (tm *TournamentManager) complete(t *Tournament) error {
+	if t.Status != StatusActive {
+		return ErrInvalidStatus
+	}
+	format, err := NewFormat(t.Config)
+	if err != nil {
+		return err
+	}
+	matches, err := tm.Store.Matches(t.ID)
+	if err != nil {
+		return err
+	}
+	participants, err := tm.Store.Participants(t.ID)
+	if err != nil {
+		return err
+	}
+	ranks, err := format.Rank(t, participants, matches)
+	if err != nil {
+		return err
+	}
+
+	for i := range participants {
+		p := &participants[i]
+		p.Rank = ranks[p.UserID]
+		if p.Rank == 1 {
+			p.Status = ParticipantWinner
+		} else if p.Status == ParticipantRegistered {
+			p.Status = ParticipantEliminated // Formats without knockouts
+		}
+	}
 
-	// Calculate ranks
-	// Winner = Rank 1
-	// Runner-up = Rank 2 (Loser of final)
-	// Semi-final losers = Rank 3/4
+	payout, err := tm.PrizeDistributor.Distribute(t, participants)
+	if err != nil {
+		return err
+	}
+	prizes := payout.Prizes
 
-	// Distribute prizes
-	// prizes, err := tm.PrizeDistributor.CalculatePrizes(tournament, rankedParticipants)
-	// Credit wallets
+	now := tm.Clock.Now()
+	t.Rake, t.Overlay = payout.Rake, payout.Overlay
+	t.Status = StatusCompleted
+	t.EndTime = &now
+	t.UpdatedAt = now
+	if err := tm.Store.UpdateTournament(t); err != nil {
+		return err
+	}
 
+	for i := range participants {
+		p := &participants[i]
+		if prize := prizes[p.UserID]; prize > 0 {
+			tm.settler.Pay(settlement.Credit{UserID: p.UserID, Amount: prize, RefID: t.ID, RefType: "TOURNAMENT_PRIZE"})
+			p.PrizeAmount = prize
+		}
+		if err := tm.Store.UpdateParticipant(p); err != nil {
+			log.Printf("tournament %s: failed to save result of %s: %v", t.ID, p.UserID, err)
+		}
+	}
+	log.Printf("tournament %s completed: pool %.2f, rake %.2f, overlay %.2f", t.ID, payout.Pool, payout.Rake, payout.Overlay)
 	return nil
 }
+
+// Standings tables a tournament's results so far (see Standings)
+func (tm *TournamentManager) Standings(tournamentID string) ([]Standing, error) {
+	t, err := tm.GetTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := tm.Store.Participants(t.ID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := tm.Store.Matches(t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return Standings(participants, matches, tieBreakersFor(t.Config)), nil
+}
+
+// StartDue starts (or, short of players, cancels) every tournament whose start
+// time has passed
+func (tm *TournamentManager) StartDue(now time.Time) {
+	due, err := tm.Store.ListTournaments(StatusRegistration, 100, 0)
+	if err != nil {
+		log.Printf("tournament: failed to list open tournaments: %v", err)
+		return
+	}
+	for _, t := range due {
+		if t.StartTime.After(now) {
+			break // Listed by start time
+		}
+		if err := tm.StartTournament(t.ID); err != nil && !errors.Is(err, ErrNotEnoughPlayers) {
+			log.Printf("tournament %s: failed to start: %v", t.ID, err)
+		}
+	}
+}
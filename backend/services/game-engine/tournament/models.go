@@ -20,6 +20,7 @@ const (
 	ParticipantRegistered = "REGISTERED"
 	ParticipantEliminated = "ELIMINATED"
 	ParticipantWinner     = "WINNER"
+	ParticipantRefunded   = "REFUNDED" // Tournament cancelled, entry fee returned
 )
 
 // Match Status
@@ -29,19 +30,48 @@ const (
 	MatchCompleted  = "COMPLETED"
 )
 
+// Bracket Types
+const (
+	BracketSingleElimination = "SINGLE_ELIMINATION"
+	BracketDoubleElimination = "DOUBLE_ELIMINATION"
+	BracketRoundRobin        = "ROUND_ROBIN"
+	BracketSwiss             = "SWISS"
+)
+
+// Double elimination brackets a match belongs to
+const (
+	BracketWinners    = "WINNERS"
+	BracketLosers     = "LOSERS"
+	BracketGrandFinal = "GRAND_FINAL"
+)
+
+// Tie-breakers for round-robin and Swiss standings, applied in the configured order
+const (
+	TieBreakBuchholz   = "BUCHHOLZ"         // Sum of opponents' points
+	TieBreakHeadToHead = "HEAD_TO_HEAD"     // Points in games between the tied players
+	TieBreakScoreDiff  = "SCORE_DIFFERENCE" // Game score for minus against
+)
+
 // Prize Types
 const (
 	PrizeWinnerTakesAll = "WINNER_TAKES_ALL"
 	PrizeTop3           = "TOP_3"
-	PrizeTiered         = "TIERED"
+	PrizeTiered         = "TIERED" // Config.PayoutTable, or Config.PrizeDistribution
 )
 
 // TournamentConfig defines rules for the tournament
 type TournamentConfig struct {
-	BracketType     string                 `json:"bracket_type"` // "SINGLE_ELIMINATION"
-	PrizeStrategy   string                 `json:"prize_strategy"`
-	PrizeDistribution map[string]float64 `json:"prize_distribution,omitempty"` // Rank -> Percentage
-	MinPlayers      int                    `json:"min_players"`
+	BracketType       string             `json:"bracket_type"` // See the Bracket Types; defaults to single elimination
+	PrizeStrategy     string             `json:"prize_strategy"`
+	PrizeDistribution map[string]float64 `json:"prize_distribution,omitempty"` // Rank or range ("4-10") -> fraction of the pool
+	PayoutTable       string             `json:"payout_table,omitempty"`       // e.g. "1:25%, 2:15%, 3-5:5%, 6-20:1.5%"; see ParsePayoutTable
+	PoolFromEntries   bool               `json:"pool_from_entries,omitempty"`  // Pool is the entry fees less rake, with PrizePool as the guarantee
+	RakePercent       float64            `json:"rake_percent,omitempty"`       // Share of entry fees the house keeps when PoolFromEntries
+	TiedRanks         bool               `json:"tied_ranks,omitempty"`         // Knockout players out in the same round share their rank and prizes
+	StartWhenFull     bool               `json:"start_when_full,omitempty"`    // Sit-and-go: starts as soon as MaxPlayers have registered
+	MinPlayers        int                `json:"min_players"`                  // Cancelled with refunds below this at start time
+	Rounds            int                `json:"rounds,omitempty"`             // Swiss rounds; defaults to log2 of the field
+	TieBreakers       []string           `json:"tie_breakers,omitempty"`       // Round-robin and Swiss; defaults per format
 }
 
 // Value implements driver.Valuer for JSON storage
@@ -60,19 +90,45 @@ func (c *TournamentConfig) Scan(value interface{}) error {
 
 // Tournament represents a tournament instance
 type Tournament struct {
-	ID          string           `json:"id"`
-	Name        string           `json:"name"`
-	GameType    string           `json:"game_type"` // "LUDO", "CHESS"
-	EntryFee    float64          `json:"entry_fee"`
-	PrizePool   float64          `json:"prize_pool"`
-	MaxPlayers  int              `json:"max_players"`
-	CurrentPlayers int           `json:"current_players"`
-	Status      string           `json:"status"`
-	Config      TournamentConfig `json:"config"`
-	StartTime   time.Time        `json:"start_time"`
-	EndTime     *time.Time       `json:"end_time,omitempty"`
-	CreatedAt   time.Time        `json:"created_at"`
-	UpdatedAt   time.Time        `json:"updated_at"`
+	ID                 string           `json:"id"`
+	TemplateID         string           `json:"template_id,omitempty"` // Template it was created from, if any
+	Name               string           `json:"name"`
+	GameType           string           `json:"game_type"` // Registry game ID, e.g. "ludo_classic"
+	EntryFee           float64          `json:"entry_fee"`
+	PrizePool          float64          `json:"prize_pool"` // Fixed pool, or the guarantee when Config.PoolFromEntries
+	Rake               float64          `json:"rake"`       // What the house kept from entry fees; set on completion
+	Overlay            float64          `json:"overlay"`    // What the house added to meet the pool; set on completion
+	MaxPlayers         int              `json:"max_players"`
+	CurrentPlayers     int              `json:"current_players"`
+	MaxEntries         int              `json:"max_entries,omitempty"` // Entries per user, re-entries included; 0 or 1 = no re-entry
+	Status             string           `json:"status"`
+	Config             TournamentConfig `json:"config"`
+	RegistrationOpens  time.Time        `json:"registration_opens"`
+	RegistrationCloses time.Time        `json:"registration_closes"` // StartTime, or later for Swiss late registration
+	StartTime          time.Time        `json:"start_time"`
+	EndTime            *time.Time       `json:"end_time,omitempty"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+// registering reports whether the tournament takes entries at now, and whether
+// they are late ones into the running tournament
+func (t *Tournament) registering(now time.Time) (open, late bool) {
+	if now.Before(t.RegistrationOpens) || !now.Before(t.RegistrationCloses) {
+		return false, false
+	}
+	switch t.Status {
+	case StatusRegistration:
+		return true, false
+	case StatusActive:
+		return true, true
+	}
+	return false, false
+}
+
+// entryLimit is how many entries a user may buy, re-entries included
+func (t *Tournament) entryLimit() int {
+	return max(t.MaxEntries, 1)
 }
 
 // Participant represents a user in the tournament
@@ -82,22 +138,57 @@ type Participant struct {
 	UserID       string    `json:"user_id"`
 	Status       string    `json:"status"`
 	Rank         int       `json:"rank"`
+	Seed         int       `json:"seed,omitempty"`          // 1 = top seed; set when the tournament starts, or on a late entry
+	Entries      int       `json:"entries"`                 // Entry fees paid, re-entries included
+	EnteredRound int       `json:"entered_round,omitempty"` // Re-entry: only rounds from this one count towards the standings
 	PrizeAmount  float64   `json:"prize_amount"`
 	RegisteredAt time.Time `json:"registered_at"`
 }
 
 // TournamentMatch represents a match node in the bracket
 type TournamentMatch struct {
-	ID           string     `json:"id"`
-	TournamentID string     `json:"tournament_id"`
-	Round        int        `json:"round"`       // 1 = Final, 2 = Semis, etc. (or reverse)
-	MatchIndex   int        `json:"match_index"` // Position in the round
-	Player1ID    *string    `json:"player_1_id"` // UserID (nullable for Bye)
-	Player2ID    *string    `json:"player_2_id"` // UserID
-	WinnerID     *string    `json:"winner_id"`
-	Status       string     `json:"status"`
-	NextMatchID  *string    `json:"next_match_id"` // Pointer to parent node
-	Metadata     string     `json:"metadata"`      // Game session ID etc.
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID           string    `json:"id"`
+	TournamentID string    `json:"tournament_id"`
+	Bracket      string    `json:"bracket,omitempty"` // Double elimination: WINNERS, LOSERS or GRAND_FINAL
+	Round        int       `json:"round"`             // 1 = First round, highest = Final (per bracket)
+	MatchIndex   int       `json:"match_index"`       // Position in the round
+	Player1ID    *string   `json:"player_1_id"`       // UserID (nullable for Bye)
+	Player2ID    *string   `json:"player_2_id"`       // UserID
+	Player1Score int       `json:"player_1_score"`
+	Player2Score int       `json:"player_2_score"`
+	WinnerID     *string   `json:"winner_id"`
+	Status       string    `json:"status"`
+	NextMatchID  *string   `json:"next_match_id"`                 // Where the winner goes
+	LoserNextID  *string   `json:"loser_next_match_id,omitempty"` // Double elimination: where the loser drops to
+	SessionID    string    `json:"session_id,omitempty"`          // Game session the match is played in
+	Metadata     string    `json:"metadata"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Template Kinds
+const (
+	TemplateSitAndGo  = "SIT_AND_GO" // One tournament open at a time, started as soon as it fills
+	TemplateScheduled = "SCHEDULED"  // A tournament for every run of a cron schedule
+)
+
+// Template describes tournaments the scheduler creates over and over
+type Template struct {
+	ID                  string           `json:"id"`
+	Name                string           `json:"name"`
+	Kind                string           `json:"kind"`
+	GameType            string           `json:"game_type"`
+	EntryFee            float64          `json:"entry_fee"`
+	PrizePool           float64          `json:"prize_pool"`
+	MaxPlayers          int              `json:"max_players"` // A sit-and-go starts when this many have registered
+	Config              TournamentConfig `json:"config"`
+	Schedule            string           `json:"schedule,omitempty"`                  // Scheduled: cron expression, e.g. "0 21 * * *"
+	Timezone            string           `json:"timezone"`                            // Schedule time zone; defaults to UTC
+	RegistrationMinutes int              `json:"registration_minutes"`                // Scheduled: registration opens this long before the start; sit-and-go: cancelled if not full in this long
+	LateRegMinutes      int              `json:"late_registration_minutes,omitempty"` // Swiss: registration stays open this long after the start
+	MaxEntries          int              `json:"max_entries,omitempty"`               // Entries per user per tournament, re-entries included; more than 1 needs late registration
+	Active              bool             `json:"active"`
+	NextRun             *time.Time       `json:"next_run,omitempty"` // Scheduled: start time of the next tournament to create
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
 }
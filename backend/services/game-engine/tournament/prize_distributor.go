@@ -2,9 +2,122 @@ package tournament
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// PayoutEntry pays every rank from From to To either a percentage of the pool
+// each, or a fixed amount each
+type PayoutEntry struct {
+	From    int     `json:"from"`
+	To      int     `json:"to"`
+	Percent float64 `json:"percent,omitempty"`
+	Amount  float64 `json:"amount,omitempty"`
+}
+
+// PayoutTable is a validated list of payout entries in rank order
+type PayoutTable []PayoutEntry
+
+// ParsePayoutTable parses a table such as "1:25%, 2:15%, 3-5:5%, 6-20:1.5%".
+// Percentages are per rank and must sum to 100% over every rank they cover;
+// plain numbers ("1:500") are fixed amounts paid ahead of the percentages.
+func ParsePayoutTable(s string) (PayoutTable, error) {
+	table := PayoutTable{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		ranks, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("payout %q: want rank:value", part)
+		}
+		entry, err := parseRanks(ranks)
+		if err != nil {
+			return nil, err
+		}
+		value = strings.TrimSpace(value)
+		if percent, isPercent := strings.CutSuffix(value, "%"); isPercent {
+			entry.Percent, err = strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		} else {
+			entry.Amount, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("payout %q: bad value", part)
+		}
+		table = append(table, entry)
+	}
+	return table, table.validate()
+}
+
+// parseRanks parses "3" or "3-5"
+func parseRanks(s string) (PayoutEntry, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	if !isRange {
+		to = from
+	}
+	f, err1 := strconv.Atoi(strings.TrimSpace(from))
+	t, err2 := strconv.Atoi(strings.TrimSpace(to))
+	if err1 != nil || err2 != nil || f < 1 || t < f {
+		return PayoutEntry{}, fmt.Errorf("bad rank range %q", s)
+	}
+	return PayoutEntry{From: f, To: t}, nil
+}
+
+func (pt PayoutTable) validate() error {
+	if len(pt) == 0 {
+		return errors.New("payout table is empty")
+	}
+	sort.Slice(pt, func(i, j int) bool { return pt[i].From < pt[j].From })
+
+	percent := 0.0
+	for i, e := range pt {
+		if i > 0 && e.From <= pt[i-1].To {
+			return fmt.Errorf("payout ranks %d-%d overlap %d-%d", e.From, e.To, pt[i-1].From, pt[i-1].To)
+		}
+		if (e.Percent > 0) == (e.Amount > 0) || e.Percent < 0 || e.Amount < 0 {
+			return fmt.Errorf("payout for ranks %d-%d needs a positive percentage or amount", e.From, e.To)
+		}
+		percent += e.Percent * float64(e.To-e.From+1)
+	}
+	if percent > 0 && math.Abs(percent-100) > 1e-6 {
+		return fmt.Errorf("payout percentages sum to %g%%, not 100%%", percent)
+	}
+	return nil
+}
+
+// lookup returns the entry covering a rank, if any
+func (pt PayoutTable) lookup(rank int) (PayoutEntry, bool) {
+	for _, e := range pt {
+		if rank >= e.From && rank <= e.To {
+			return e, true
+		}
+	}
+	return PayoutEntry{}, false
+}
+
+// fixedTotal is what the fixed amounts come to for a field of n players
+func (pt PayoutTable) fixedTotal(n int) float64 {
+	total := 0.0
+	for _, e := range pt {
+		if e.Amount > 0 && e.From <= n {
+			total += e.Amount * float64(min(e.To, n)-e.From+1)
+		}
+	}
+	return total
+}
+
+// Payout is how a finished tournament's pool was worked out and shared
+type Payout struct {
+	Pool    float64            `json:"pool"`
+	Rake    float64            `json:"rake"`
+	Overlay float64            `json:"overlay"`
+	Prizes  map[string]float64 `json:"prizes"` // UserID -> amount
+}
+
 // PrizeDistributor calculates prize amounts
 type PrizeDistributor struct{}
 
@@ -13,100 +126,202 @@ func NewPrizeDistributor() *PrizeDistributor {
 	return &PrizeDistributor{}
 }
 
+// PayoutTableFor resolves a config's prize strategy to a payout table
+func (pd *PrizeDistributor) PayoutTableFor(config TournamentConfig) (PayoutTable, error) {
+	switch config.PrizeStrategy {
+	case PrizeWinnerTakesAll:
+		return ParsePayoutTable("1:100%")
+	case PrizeTop3:
+		return ParsePayoutTable("1:50%, 2:30%, 3:20%")
+	case PrizeTiered:
+		if config.PayoutTable != "" {
+			return ParsePayoutTable(config.PayoutTable)
+		}
+		if len(config.PrizeDistribution) == 0 {
+			return ParsePayoutTable("1:50%, 2:30%, 3:20%")
+		}
+		// Fractions of the pool keyed by rank or range
+		table := PayoutTable{}
+		for ranks, fraction := range config.PrizeDistribution {
+			entry, err := parseRanks(ranks)
+			if err != nil {
+				return nil, err
+			}
+			entry.Percent = fraction * 100
+			table = append(table, entry)
+		}
+		return table, table.validate()
+	default:
+		return nil, errors.New("unknown prize strategy")
+	}
+}
+
+// Validate checks a config's prize rules before a tournament is created. A
+// fixed pool has to cover the fixed amounts on its own.
+func (pd *PrizeDistributor) Validate(config TournamentConfig, prizePool float64, maxPlayers int) error {
+	table, err := pd.PayoutTableFor(config)
+	if err != nil {
+		return err
+	}
+	if config.RakePercent < 0 || config.RakePercent >= 100 {
+		return errors.New("rake percent must be between 0 and 100")
+	}
+	if config.RakePercent > 0 && !config.PoolFromEntries {
+		return errors.New("rake only applies to pools built from entry fees")
+	}
+	if !config.PoolFromEntries && table.fixedTotal(maxPlayers) > prizePool {
+		return errors.New("fixed prizes exceed the prize pool")
+	}
+	return nil
+}
+
 // CalculatePrizes determines prize amounts for winners
 func (pd *PrizeDistributor) CalculatePrizes(t *Tournament, participants []Participant) (map[string]float64, error) {
-	payouts := make(map[string]float64)
+	payout, err := pd.Distribute(t, participants)
+	if err != nil {
+		return nil, err
+	}
+	return payout.Prizes, nil
+}
 
-	// Filter for ranked players (Rank > 0)
-	rankedPlayers := make([]Participant, 0)
-	for _, p := range participants {
+// Placing is a finisher's rank, for PayoutTable.Split
+type Placing struct {
+	ID   string
+	Rank int
+}
+
+// Split shares a pool among placings by the table, returning the prizes by ID
+// and the pool actually paid out.
+//
+// The pool grows to whatever the fixed amounts need. Fixed amounts are paid
+// first and the rest goes by percentage; ranks nobody reached are left out and
+// the percentages of the ranks that were reached scaled up to the whole. With
+// no percentage to take the rest, only the fixed amounts are paid.
+//
+// Placings sharing a rank split the prizes of the places they cover evenly.
+// Everything is worked in paise: the odd paise of the percentages go one each
+// to the top places, and those of a split to the tied placings by ID.
+func (pt PayoutTable) Split(pool float64, placings []Placing) (map[string]float64, float64) {
+	ranked := make([]Placing, 0, len(placings))
+	for _, p := range placings {
 		if p.Rank > 0 {
-			rankedPlayers = append(rankedPlayers, p)
+			ranked = append(ranked, p)
 		}
 	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Rank != ranked[j].Rank {
+			return ranked[i].Rank < ranked[j].Rank
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
 
-	if len(rankedPlayers) == 0 {
-		return nil, errors.New("no ranked players found")
+	// Places 1..n, each paid by the table entry for its number
+	n := len(ranked)
+	places := make([]int64, n)
+	fixed, percent := int64(0), 0.0
+	for place := 1; place <= n; place++ {
+		if e, ok := pt.lookup(place); ok {
+			if e.Amount > 0 {
+				places[place-1] = toPaise(e.Amount)
+				fixed += places[place-1]
+			}
+			percent += e.Percent
+		}
 	}
+	paise := max(toPaise(pool), fixed)
 
-	strategy := t.Config.PrizeStrategy
-	pool := t.PrizePool
-
-	switch strategy {
-	case PrizeWinnerTakesAll:
-		for _, p := range rankedPlayers {
-			if p.Rank == 1 {
-				payouts[p.UserID] = pool
-				break
+	remainder := paise - fixed
+	if percent > 0 {
+		paid := int64(0)
+		for place := 1; place <= n; place++ {
+			if e, ok := pt.lookup(place); ok && e.Percent > 0 {
+				share := int64(math.Floor(float64(remainder) * e.Percent / percent))
+				places[place-1] = share
+				paid += share
 			}
 		}
-
-	case PrizeTop3:
-		// 1st: 50%, 2nd: 30%, 3rd: 20%
-		for _, p := range rankedPlayers {
-			switch p.Rank {
-			case 1:
-				payouts[p.UserID] = pool * 0.50
-			case 2:
-				payouts[p.UserID] = pool * 0.30
-			case 3:
-				payouts[p.UserID] = pool * 0.20
+		for place := 0; paid < remainder; place = (place + 1) % n {
+			if e, ok := pt.lookup(place + 1); ok && e.Percent > 0 {
+				places[place]++
+				paid++
 			}
 		}
+	} else {
+		paise = fixed
+	}
 
-	case PrizeTiered:
-		// Use custom distribution from config
-		// Map of "Rank" -> Percentage (e.g., "1": 0.5, "2": 0.3)
-		// Or "1-10": 0.1
-
-		// For simplicity, let's assume Config.PrizeDistribution is map[string]float64
-		// keys are rank strings "1", "2", "3"
-
-		dist := t.Config.PrizeDistribution
-		if dist == nil {
-			// Fallback to Top 3 if not configured
-			return pd.CalculatePrizes(t, participants) // Recursive call with Top 3? No, infinite loop risk.
-			// Just use Top 3 logic
-			for _, p := range rankedPlayers {
-				if p.Rank == 1 { payouts[p.UserID] = pool * 0.5 }
-				if p.Rank == 2 { payouts[p.UserID] = pool * 0.3 }
-				if p.Rank == 3 { payouts[p.UserID] = pool * 0.2 }
-			}
-			break
-		}
-
-		for _, p := range rankedPlayers {
-			// Check exact rank
-			// rankStr := string(rune('0' + p.Rank)) // Unused
-			// Better: use fmt.Sprintf or just iterate map
-
-			// Let's iterate the map to handle ranges if we supported them
-			// But for now, exact match
-			// We need a way to convert int rank to string key or just use int keys in struct?
-			// Config uses map[string]float64 because JSON keys are strings.
-
-			// Hacky int to string
-			rankKey := ""
-			if p.Rank < 10 {
-				rankKey = string(rune('0' + p.Rank))
-			} else {
-				// We won't support > 9 ranks for this simple implementation without strconv
-				continue
+	prizes := make(map[string]float64)
+	for start := 0; start < n; {
+		end := start
+		for end < n && ranked[end].Rank == ranked[start].Rank {
+			end++
+		}
+		total := int64(0)
+		for i := start; i < end; i++ {
+			total += places[i]
+		}
+		tied := int64(end - start)
+		for i := start; i < end; i++ {
+			share := total / tied
+			if int64(i-start) < total%tied {
+				share++
 			}
-
-			if percentage, ok := dist[rankKey]; ok {
-				payouts[p.UserID] = pool * percentage
+			if share > 0 {
+				prizes[ranked[i].ID] = fromPaise(share)
 			}
 		}
+		start = end
+	}
+	return prizes, fromPaise(paise)
+}
 
-	default:
-		return nil, errors.New("unknown prize strategy")
+// Distribute works out the pool and shares it among ranked players by the
+// payout table (see PayoutTable.Split). The pool is the tournament's prize
+// pool, or with PoolFromEntries the entry fees less rake, topped up by the
+// house to the prize pool as a guarantee.
+func (pd *PrizeDistributor) Distribute(t *Tournament, participants []Participant) (*Payout, error) {
+	table, err := pd.PayoutTableFor(t.Config)
+	if err != nil {
+		return nil, err
 	}
 
-	// Round to 2 decimal places
-	for userID, amount := range payouts {
-		payouts[userID] = math.Floor(amount*100) / 100
+	placings := make([]Placing, 0, len(participants))
+	for _, p := range participants {
+		if p.Rank > 0 {
+			placings = append(placings, Placing{ID: p.UserID, Rank: p.Rank})
+		}
 	}
+	if len(placings) == 0 {
+		return nil, errors.New("no ranked players found")
+	}
+
+	entries := 0
+	for _, p := range participants {
+		entries += max(p.Entries, 1)
+	}
+	collected := toPaise(t.EntryFee * float64(entries))
+	pool := toPaise(t.PrizePool)
+	if t.Config.PoolFromEntries {
+		rake := toPaise(fromPaise(collected) * t.Config.RakePercent / 100)
+		pool = max(collected-rake, pool)
+	}
+	prizes, paidPool := table.Split(fromPaise(pool), placings)
+	pool = toPaise(paidPool)
+
+	// Whatever the pool doesn't use of the entry fees is the house's, and
+	// whatever it needs beyond them the house puts up
+	return &Payout{
+		Pool:    fromPaise(pool),
+		Rake:    fromPaise(max(collected-pool, 0)),
+		Overlay: fromPaise(max(pool-collected, 0)),
+		Prizes:  prizes,
+	}, nil
+}
+
+func toPaise(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
 
-	return payouts, nil
+func fromPaise(paise int64) float64 {
+	return float64(paise) / 100
 }
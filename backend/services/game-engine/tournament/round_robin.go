@@ -0,0 +1,68 @@
+package tournament
+
+import "errors"
+
+// RoundRobin has everyone play everyone once, one round at a time, and ranks
+// players by the standings table
+type RoundRobin struct{}
+
+func (RoundRobin) Start(t *Tournament, seeded []string) ([]TournamentMatch, error) {
+	if len(seeded) < 2 {
+		return nil, errors.New("need at least 2 participants")
+	}
+	return roundRobinRound(t.ID, seeded, 1), nil
+}
+
+func (RoundRobin) Advance(t *Tournament, participants []Participant, matches []TournamentMatch, completed *TournamentMatch) (*Progress, error) {
+	progress := &Progress{}
+	if !roundComplete(matches, completed.Round) {
+		return progress, nil
+	}
+
+	seeded := seedOrder(participants)
+	if completed.Round >= roundRobinRounds(len(seeded)) {
+		progress.Done = true
+		return progress, nil
+	}
+	progress.Created = roundRobinRound(t.ID, seeded, completed.Round+1)
+	return progress, nil
+}
+
+func (RoundRobin) Rank(t *Tournament, participants []Participant, matches []TournamentMatch) (map[string]int, error) {
+	return rankByStandings(Standings(participants, matches, tieBreakersFor(t.Config))), nil
+}
+
+// roundRobinRounds is the number of rounds for n players; with an odd number
+// everyone sits one round out
+func roundRobinRounds(n int) int {
+	if n%2 == 1 {
+		return n
+	}
+	return n - 1
+}
+
+// roundRobinRound pairs one round by the circle method: the top seed stays put
+// while everyone else rotates one place a round
+func roundRobinRound(tournamentID string, seeded []string, round int) []TournamentMatch {
+	circle := append([]string{}, seeded...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, "") // Playing "" is sitting the round out
+	}
+	n := len(circle)
+
+	rotated := make([]string, n)
+	rotated[0] = circle[0]
+	for i := 1; i < n; i++ {
+		rotated[1+(i-1+round-1)%(n-1)] = circle[i]
+	}
+
+	matches := []TournamentMatch{}
+	for i := 0; i < n/2; i++ {
+		p1, p2 := rotated[i], rotated[n-1-i]
+		if p1 == "" || p2 == "" {
+			continue
+		}
+		matches = append(matches, pairing(tournamentID, round, len(matches), p1, p2))
+	}
+	return matches
+}
@@ -0,0 +1,229 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scheduler creates tournaments from templates and starts (or, short of
+// players, cancels) tournaments when they are due. It reads the time from the
+// manager's Clock.
+type Scheduler struct {
+	Manager *TournamentManager
+	mu      sync.Mutex // One tick at a time
+}
+
+func NewScheduler(manager *TournamentManager) *Scheduler {
+	return &Scheduler{Manager: manager}
+}
+
+// CreateTemplate validates a template and saves it active. A scheduled
+// template's first tournament is its next run from now.
+func (s *Scheduler) CreateTemplate(tmpl *Template) error {
+	now := s.Manager.Clock.Now()
+	if tmpl.Timezone == "" {
+		tmpl.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(tmpl.Timezone)
+	if err != nil {
+		return err
+	}
+	if tmpl.RegistrationMinutes <= 0 {
+		tmpl.RegistrationMinutes = 60
+	}
+	if tmpl.LateRegMinutes < 0 || tmpl.MaxEntries < 0 {
+		return errors.New("late registration and max entries can't be negative")
+	}
+
+	// Check the rest against a tournament as the template would create it
+	var sample *Tournament
+	switch tmpl.Kind {
+	case TemplateSitAndGo:
+		if tmpl.Schedule != "" || tmpl.LateRegMinutes > 0 || tmpl.MaxEntries > 1 {
+			return errors.New("a sit-and-go has no schedule, late registration or re-entries")
+		}
+		sample = s.sitAndGoTournament(tmpl, now)
+	case TemplateScheduled:
+		schedule, err := ParseSchedule(tmpl.Schedule)
+		if err != nil {
+			return err
+		}
+		next := schedule.Next(now.In(loc)).UTC()
+		if next.IsZero() {
+			return errors.New("schedule never runs")
+		}
+		tmpl.NextRun = &next
+		sample = s.scheduledTournament(tmpl, loc, next)
+	default:
+		return errors.New("template kind must be SIT_AND_GO or SCHEDULED")
+	}
+	if err := s.Manager.prepare(sample); err != nil {
+		return err
+	}
+
+	tmpl.ID = uuid.New().String()
+	tmpl.Active = true
+	tmpl.CreatedAt = now
+	tmpl.UpdatedAt = now
+	return s.Manager.Store.CreateTemplate(tmpl)
+}
+
+// SetActive pauses or resumes a template. Its open tournaments are left to run;
+// a resumed scheduled template picks up from its next run after now.
+func (s *Scheduler) SetActive(templateID string, active bool) (*Template, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmpl, err := s.Manager.Store.GetTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl == nil {
+		return nil, ErrTemplateNotFound
+	}
+	now := s.Manager.Clock.Now()
+	if active && !tmpl.Active && tmpl.Kind == TemplateScheduled {
+		tmpl.NextRun = nil
+	}
+	tmpl.Active = active
+	tmpl.UpdatedAt = now
+	if err := s.Manager.Store.UpdateTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Tick starts or cancels due tournaments, then creates whatever active
+// templates call for
+func (s *Scheduler) Tick() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Manager.Clock.Now()
+	s.Manager.StartDue(now)
+
+	templates, err := s.Manager.Store.ListTemplates(true)
+	if err != nil {
+		log.Printf("tournament scheduler: failed to list templates: %v", err)
+		return
+	}
+	for _, tmpl := range templates {
+		switch tmpl.Kind {
+		case TemplateSitAndGo:
+			err = s.tickSitAndGo(tmpl, now)
+		case TemplateScheduled:
+			err = s.tickScheduled(tmpl, now)
+		}
+		if err != nil {
+			log.Printf("tournament template %s: %v", tmpl.ID, err)
+		}
+	}
+}
+
+// Run ticks until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick()
+		}
+	}
+}
+
+// tickSitAndGo keeps one tournament open for registration. Once it fills and
+// starts, or lapses unfilled and is cancelled, the next tick opens another.
+func (s *Scheduler) tickSitAndGo(tmpl *Template, now time.Time) error {
+	open, err := s.Manager.Store.TemplateTournaments(tmpl.ID, StatusRegistration)
+	if err != nil || len(open) > 0 {
+		return err
+	}
+	return s.Manager.create(s.sitAndGoTournament(tmpl, now))
+}
+
+// tickScheduled creates the tournament for the next run once its registration
+// opens. Runs whose start was missed, e.g. while the server was down, are
+// skipped.
+func (s *Scheduler) tickScheduled(tmpl *Template, now time.Time) error {
+	schedule, err := ParseSchedule(tmpl.Schedule)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(tmpl.Timezone)
+	if err != nil {
+		return err
+	}
+	if tmpl.NextRun == nil {
+		next := schedule.Next(now.In(loc)).UTC()
+		tmpl.NextRun = &next
+	}
+
+	changed := false
+	for !tmpl.NextRun.IsZero() {
+		start := *tmpl.NextRun
+		t := s.scheduledTournament(tmpl, loc, start)
+		if now.Before(t.RegistrationOpens) {
+			break
+		}
+		if now.Before(start) {
+			if err := s.Manager.create(t); err != nil {
+				return err
+			}
+		} else {
+			log.Printf("tournament template %s: skipped the run at %s", tmpl.ID, start.Format(time.RFC3339))
+		}
+		next := schedule.Next(start.In(loc)).UTC()
+		tmpl.NextRun = &next
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	tmpl.UpdatedAt = now
+	return s.Manager.Store.UpdateTemplate(tmpl)
+}
+
+func (s *Scheduler) sitAndGoTournament(tmpl *Template, now time.Time) *Tournament {
+	config := tmpl.Config
+	config.StartWhenFull = true
+	config.MinPlayers = tmpl.MaxPlayers // Cancelled unless it fills in time
+	return &Tournament{
+		TemplateID:        tmpl.ID,
+		Name:              tmpl.Name,
+		GameType:          tmpl.GameType,
+		EntryFee:          tmpl.EntryFee,
+		PrizePool:         tmpl.PrizePool,
+		MaxPlayers:        tmpl.MaxPlayers,
+		MaxEntries:        tmpl.MaxEntries,
+		Config:            config,
+		RegistrationOpens: now,
+		StartTime:         now.Add(time.Duration(tmpl.RegistrationMinutes) * time.Minute),
+	}
+}
+
+// scheduledTournament is the tournament for a run. Times are kept in UTC and
+// only the name is in the template's time zone.
+func (s *Scheduler) scheduledTournament(tmpl *Template, loc *time.Location, start time.Time) *Tournament {
+	start = start.UTC()
+	return &Tournament{
+		TemplateID:         tmpl.ID,
+		Name:               tmpl.Name + " " + start.In(loc).Format("02 Jan 15:04"),
+		GameType:           tmpl.GameType,
+		EntryFee:           tmpl.EntryFee,
+		PrizePool:          tmpl.PrizePool,
+		MaxPlayers:         tmpl.MaxPlayers,
+		MaxEntries:         tmpl.MaxEntries,
+		Config:             tmpl.Config,
+		RegistrationOpens:  start.Add(-time.Duration(tmpl.RegistrationMinutes) * time.Minute),
+		RegistrationCloses: start.Add(time.Duration(tmpl.LateRegMinutes) * time.Minute),
+		StartTime:          start,
+	}
+}
@@ -0,0 +1,317 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/playkaro/game-engine/games/ludo"
+	"github.com/playkaro/game-engine/internal/engine"
+	"github.com/playkaro/game-engine/internal/registry"
+	"github.com/playkaro/game-engine/internal/session"
+	"github.com/playkaro/game-engine/internal/settlement"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+type fakeSessions struct{ opened int }
+
+func (s *fakeSessions) CreateSession(gameID, userID string, opts ...session.SessionOption) (*engine.GameSession, error) {
+	s.opened++
+	return &engine.GameSession{SessionID: fmt.Sprintf("match_%d", s.opened), GameID: gameID}, nil
+}
+
+// testWallet collects entry fees, failing for one user if asked to
+type testWallet struct {
+	debited map[string]float64
+	failing string
+}
+
+func (w *testWallet) Debit(userID string, amount float64, refID, refType string) error {
+	if userID == w.failing {
+		return errors.New("insufficient balance")
+	}
+	w.debited[userID] += amount
+	return nil
+}
+
+func (w *testWallet) Credit(userID string, amount float64, refID, refType string) error {
+	return nil
+}
+
+func newTestScheduler(now time.Time) (*Scheduler, *fakeClock, *testWallet, *settlement.Settler) {
+	registry.GetRegistry().RegisterGame(ludo.NewLudoGame())
+	wallet := &testWallet{debited: make(map[string]float64)}
+	settler := settlement.NewSettler(settlement.DefaultConfig(), wallet, nil)
+	manager := NewTournamentManager(NewMemoryStore(), &fakeSessions{}, wallet, settler)
+	clock := &fakeClock{now: now}
+	manager.Clock = clock
+	return NewScheduler(manager), clock, wallet, settler
+}
+
+func TestScheduleNext(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []time.Time // Successive runs
+	}{
+		{"day of month or day of week", "0 12 15 * 1", utc(3, 1, 0, 0),
+			[]time.Time{utc(3, 2, 12, 0), utc(3, 9, 12, 0), utc(3, 15, 12, 0), utc(3, 16, 12, 0)}},
+		{"day of month only", "0 12 15 * *", utc(3, 1, 0, 0),
+			[]time.Time{utc(3, 15, 12, 0), utc(4, 15, 12, 0)}},
+		{"day of week only", "0 12 * * 1", utc(3, 2, 12, 0),
+			[]time.Time{utc(3, 9, 12, 0), utc(3, 16, 12, 0)}},
+		{"months without the day", "30 23 31 * *", utc(4, 1, 0, 0),
+			[]time.Time{utc(5, 31, 23, 30), utc(7, 31, 23, 30), utc(8, 31, 23, 30)}},
+		{"year rollover", "0 0 1 1 *", utc(6, 1, 0, 0),
+			[]time.Time{time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{"leap day", "0 0 29 2 *", utc(1, 1, 0, 0),
+			[]time.Time{time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)}},
+		{"never", "0 0 31 2 *", utc(1, 1, 0, 0), []time.Time{{}}},
+		{"step", "*/20 9 * * *", utc(3, 1, 9, 20),
+			[]time.Time{utc(3, 1, 9, 40), utc(3, 2, 9, 0)}},
+		{"time zone", "0 21 * * *", utc(5, 1, 16, 0).In(kolkata), // 21:30 IST, past today's run
+			[]time.Time{utc(5, 2, 15, 30), utc(5, 3, 15, 30)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := tt.from
+			for i, want := range tt.want {
+				got := schedule.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("run %d after %s = %s, want %s", i+1, from, got, want)
+				}
+				if !got.IsZero() && got.Location() != from.Location() {
+					t.Fatalf("run %d in %s, want %s", i+1, got.Location(), from.Location())
+				}
+				from = got
+			}
+		})
+	}
+}
+
+func TestSitAndGoRefills(t *testing.T) {
+	s, clock, wallet, settler := newTestScheduler(time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC))
+	tmpl := &Template{Name: "Heads-up", Kind: TemplateSitAndGo, GameType: "ludo_classic", EntryFee: 10, PrizePool: 18, MaxPlayers: 2, RegistrationMinutes: 10}
+	if err := s.CreateTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	open := func() []*Tournament {
+		t.Helper()
+		open, err := s.Manager.Store.TemplateTournaments(tmpl.ID, StatusRegistration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return open
+	}
+
+	s.Tick()
+	s.Tick()
+	first := open()
+	if len(first) != 1 {
+		t.Fatalf("%d open after two ticks, want 1", len(first))
+	}
+
+	// Filling up starts it at once; the next tick opens another
+	for _, userID := range []string{"p1", "p2"} {
+		if err := s.Manager.RegisterParticipant(first[0].ID, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if started, _ := s.Manager.GetTournament(first[0].ID); started.Status != StatusActive {
+		t.Fatalf("full sit-and-go %s, want ACTIVE", started.Status)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	s.Tick()
+	second := open()
+	if len(second) != 1 || second[0].ID == first[0].ID {
+		t.Fatalf("open after the first filled: %+v", second)
+	}
+
+	// One that lapses unfilled is cancelled and refunded, then replaced
+	if err := s.Manager.RegisterParticipant(second[0].ID, "p3"); err != nil {
+		t.Fatal(err)
+	}
+	clock.now = clock.now.Add(11 * time.Minute)
+	s.Tick()
+	if lapsed, _ := s.Manager.GetTournament(second[0].ID); lapsed.Status != StatusCancelled {
+		t.Fatalf("lapsed sit-and-go %s, want CANCELLED", lapsed.Status)
+	}
+	if wallet.debited["p3"] != 10 || settler.Pending() != 1 {
+		t.Fatalf("p3 debited %.2f with %d refunds queued, want 10 and 1", wallet.debited["p3"], settler.Pending())
+	}
+	third := open()
+	if len(third) != 1 || !third[0].RegistrationOpens.Equal(clock.now) {
+		t.Fatalf("open after the lapse: %+v", third)
+	}
+}
+
+func TestScheduledRunsSkippedAfterDowntime(t *testing.T) {
+	s, clock, _, _ := newTestScheduler(time.Date(2026, 5, 1, 10, 10, 0, 0, time.UTC))
+	tmpl := &Template{Name: "Hourly", Kind: TemplateScheduled, GameType: "ludo_classic", PrizePool: 100, MaxPlayers: 8, Schedule: "0 * * * *", RegistrationMinutes: 30}
+	if err := s.CreateTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	at := func(hour, minute int) time.Time { return time.Date(2026, 5, 1, hour, minute, 0, 0, time.UTC) }
+	created := func() []*Tournament {
+		t.Helper()
+		tournaments, err := s.Manager.Store.ListTournaments("", 100, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tournaments
+	}
+
+	s.Tick() // The 11:00 run opens at 10:30
+	if got := created(); len(got) != 0 {
+		t.Fatalf("%d created before registration opens", len(got))
+	}
+	clock.now = at(10, 30)
+	s.Tick()
+	if got := created(); len(got) != 1 || !got[0].StartTime.Equal(at(11, 0)) {
+		t.Fatalf("created at 10:30: %+v", got)
+	}
+
+	// Down from 10:30 to 13:40: the 11:00 tournament is cancelled for want of
+	// players, 12:00 and 13:00 are skipped and 14:00 is open for registration
+	clock.now = at(13, 40)
+	s.Tick()
+	got := created()
+	if len(got) != 2 {
+		t.Fatalf("%d tournaments after the downtime, want 2", len(got))
+	}
+	for _, tournament := range got {
+		switch {
+		case tournament.StartTime.Equal(at(11, 0)):
+			if tournament.Status != StatusCancelled {
+				t.Errorf("11:00 run %s, want CANCELLED", tournament.Status)
+			}
+		case tournament.StartTime.Equal(at(14, 0)):
+			if tournament.Status != StatusRegistration {
+				t.Errorf("14:00 run %s, want REGISTRATION", tournament.Status)
+			}
+		default:
+			t.Errorf("unexpected run at %s", tournament.StartTime)
+		}
+	}
+	saved, _ := s.Manager.Store.GetTemplate(tmpl.ID)
+	if !saved.NextRun.Equal(at(15, 0)) {
+		t.Fatalf("next run %s, want 15:00", saved.NextRun)
+	}
+}
+
+func TestReentryRestartsFromTheNextRound(t *testing.T) {
+	s, clock, wallet, _ := newTestScheduler(time.Date(2026, 5, 1, 19, 0, 0, 0, time.UTC))
+	tmpl := &Template{
+		Name: "Nightly", Kind: TemplateScheduled, GameType: "ludo_classic", EntryFee: 10, MaxPlayers: 8,
+		Config:   TournamentConfig{BracketType: BracketSwiss, Rounds: 3, PoolFromEntries: true, RakePercent: 10},
+		Schedule: "0 20 * * *", RegistrationMinutes: 60, LateRegMinutes: 30, MaxEntries: 2,
+	}
+	if err := s.CreateTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	s.Tick()
+	tournaments, _ := s.Manager.Store.TemplateTournaments(tmpl.ID, StatusRegistration)
+	if len(tournaments) != 1 {
+		t.Fatalf("%d open, want 1", len(tournaments))
+	}
+	id := tournaments[0].ID
+	tm := s.Manager
+
+	for _, userID := range []string{"p1", "p2", "p3", "p4"} {
+		if err := tm.RegisterParticipant(id, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tm.RegisterParticipant(id, "p1"); err != ErrAlreadyRegistered {
+		t.Fatalf("second entry before the start: err = %v", err)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	s.Tick()
+	matches, _ := tm.Store.Matches(id)
+	var lost *TournamentMatch
+	for i := range matches {
+		m := &matches[i]
+		if m.Player1ID != nil && m.Player2ID != nil && (*m.Player1ID == "p1" || *m.Player2ID == "p1") {
+			lost = m
+		}
+	}
+	if lost == nil {
+		t.Fatalf("p1 has no round 1 match: %+v", matches)
+	}
+	winner := *lost.Player1ID
+	if winner == "p1" {
+		winner = *lost.Player2ID
+	}
+	if err := tm.AdvanceMatch(lost.ID, winner); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.now = clock.now.Add(5 * time.Minute)
+	if err := tm.RegisterParticipant(id, "p1"); err != nil {
+		t.Fatal(err)
+	}
+	seat, _ := tm.seat(id, "p1")
+	if seat.Entries != 2 || seat.EnteredRound != 2 || wallet.debited["p1"] != 20 {
+		t.Fatalf("re-entry %+v debited %.2f, want 2 entries from round 2 and 20 debited", seat, wallet.debited["p1"])
+	}
+	if err := tm.RegisterParticipant(id, "p1"); err != ErrEntryLimit {
+		t.Fatalf("third entry: err = %v", err)
+	}
+
+	// A re-entry that can't be paid for leaves the seat as it was
+	wallet.failing = winner
+	if err := tm.RegisterParticipant(id, winner); err == nil {
+		t.Fatal("re-entry without funds succeeded")
+	}
+	if seat, _ := tm.seat(id, winner); seat.Entries != 1 || seat.EnteredRound != 0 {
+		t.Fatalf("unpaid re-entry left %+v", seat)
+	}
+
+	standings, err := tm.Standings(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range standings {
+		switch st.UserID {
+		case "p1":
+			if st.Played != 0 || st.Losses != 0 {
+				t.Errorf("re-entered p1 %+v, want round 1 not counted", st)
+			}
+		case winner:
+			if st.Wins != 1 {
+				t.Errorf("%s %+v, want the win over p1 kept", winner, st)
+			}
+		}
+	}
+
+	// Every entry goes into the pool: 5 x 10 less 10% rake
+	participants, _ := tm.Store.Participants(id)
+	for i := range participants {
+		participants[i].Rank = i + 1
+	}
+	tournament, _ := tm.GetTournament(id)
+	payout, err := tm.PrizeDistributor.Distribute(tournament, participants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payout.Pool != 45 {
+		t.Fatalf("pool %.2f, want 45", payout.Pool)
+	}
+}
@@ -0,0 +1,150 @@
+package tournament
+
+import "sort"
+
+// Standing is one player's line in a standings table. A win or a bye is worth
+// one point.
+type Standing struct {
+	Rank      int    `json:"rank"`
+	UserID    string `json:"user_id"`
+	Seed      int    `json:"seed"`
+	Played    int    `json:"played"`
+	Wins      int    `json:"wins"`
+	Losses    int    `json:"losses"`
+	Byes      int    `json:"byes"`
+	Points    int    `json:"points"`
+	Buchholz  int    `json:"buchholz"`
+	ScoreDiff int    `json:"score_difference"`
+}
+
+// Standings tables the decided matches: by points, then each tie-breaker in
+// order, then seed. A player who re-entered only has the rounds since counted;
+// their opponents keep what they won against the earlier entry.
+func Standings(participants []Participant, matches []TournamentMatch, tieBreakers []string) []Standing {
+	table := make(map[string]*Standing, len(participants))
+	standings := make([]*Standing, 0, len(participants))
+	enteredRound := make(map[string]int, len(participants))
+	for _, p := range participants {
+		s := &Standing{UserID: p.UserID, Seed: p.Seed}
+		table[p.UserID] = s
+		standings = append(standings, s)
+		enteredRound[p.UserID] = p.EnteredRound
+	}
+	// counted is the player's line if the match counts towards it
+	counted := func(userID string, round int) *Standing {
+		if round < enteredRound[userID] {
+			return nil
+		}
+		return table[userID]
+	}
+
+	opponents := make(map[string][]string)
+	beat := make(map[string][]string) // userID -> players they beat
+	for i := range matches {
+		m := &matches[i]
+		if m.Status != MatchCompleted || m.WinnerID == nil {
+			continue
+		}
+		if m.Player1ID == nil || m.Player2ID == nil {
+			// A bye is a free win
+			if s := counted(*m.WinnerID, m.Round); s != nil {
+				s.Byes++
+				s.Points++
+			}
+			continue
+		}
+
+		if table[*m.Player1ID] == nil || table[*m.Player2ID] == nil {
+			continue
+		}
+		p1, p2 := counted(*m.Player1ID, m.Round), counted(*m.Player2ID, m.Round)
+		if p1 != nil {
+			p1.Played++
+			p1.ScoreDiff += m.Player1Score - m.Player2Score
+			opponents[p1.UserID] = append(opponents[p1.UserID], *m.Player2ID)
+		}
+		if p2 != nil {
+			p2.Played++
+			p2.ScoreDiff += m.Player2Score - m.Player1Score
+			opponents[p2.UserID] = append(opponents[p2.UserID], *m.Player1ID)
+		}
+
+		winner, loser := p1, p2
+		winnerID, loserID := *m.Player1ID, *m.Player2ID
+		if *m.WinnerID == *m.Player2ID {
+			winner, loser = p2, p1
+			winnerID, loserID = loserID, winnerID
+		}
+		if winner != nil {
+			winner.Wins++
+			winner.Points++
+			beat[winnerID] = append(beat[winnerID], loserID)
+		}
+		if loser != nil {
+			loser.Losses++
+		}
+	}
+
+	for _, s := range standings {
+		for _, opp := range opponents[s.UserID] {
+			s.Buchholz += table[opp].Points
+		}
+	}
+
+	// Head to head only counts games between players level on points
+	headToHead := make(map[string]int, len(standings))
+	for _, s := range standings {
+		for _, beaten := range beat[s.UserID] {
+			if table[beaten].Points == s.Points {
+				headToHead[s.UserID]++
+			}
+		}
+	}
+
+	value := func(s *Standing, tieBreaker string) int {
+		switch tieBreaker {
+		case TieBreakBuchholz:
+			return s.Buchholz
+		case TieBreakHeadToHead:
+			return headToHead[s.UserID]
+		case TieBreakScoreDiff:
+			return s.ScoreDiff
+		}
+		return 0
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		for _, tb := range tieBreakers {
+			if va, vb := value(a, tb), value(b, tb); va != vb {
+				return va > vb
+			}
+		}
+		if a.Seed != b.Seed {
+			return a.Seed < b.Seed
+		}
+		return a.UserID < b.UserID
+	})
+
+	result := make([]Standing, len(standings))
+	for i, s := range standings {
+		s.Rank = i + 1
+		result[i] = *s
+	}
+	return result
+}
+
+// tieBreakersFor is the configured tie-breakers, or the format's defaults
+func tieBreakersFor(config TournamentConfig) []string {
+	if len(config.TieBreakers) > 0 {
+		return config.TieBreakers
+	}
+	switch config.BracketType {
+	case BracketRoundRobin:
+		return []string{TieBreakHeadToHead, TieBreakScoreDiff}
+	default:
+		return []string{TieBreakBuchholz, TieBreakHeadToHead, TieBreakScoreDiff}
+	}
+}
@@ -0,0 +1,800 @@
+package tournament
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTournamentNotFound = errors.New("tournament not found")
+	ErrMatchNotFound      = errors.New("match not found")
+	ErrTournamentFull     = errors.New("tournament is full")
+	ErrAlreadyRegistered  = errors.New("already registered")
+	ErrNotRegistered      = errors.New("not registered")
+	ErrTemplateNotFound   = errors.New("template not found")
+)
+
+// Store persists tournaments, their participants and bracket matches. Get
+// methods return nil, nil when nothing is found.
+type Store interface {
+	CreateTournament(t *Tournament) error
+	GetTournament(id string) (*Tournament, error)
+	// ListTournaments lists tournaments by start time; all statuses if status is empty
+	ListTournaments(status string, limit, offset int) ([]*Tournament, error)
+	// UpdateTournament saves the status, prize pool and times
+	UpdateTournament(t *Tournament) error
+
+	// AddParticipant takes a seat, failing with ErrRegistrationClosed unless the
+	// tournament takes entries at p.RegisteredAt, ErrTournamentFull or
+	// ErrAlreadyRegistered, and bumps CurrentPlayers. A late entrant into a
+	// running tournament is seeded below everyone already drawn. A user already
+	// seated there re-enters instead, keeping their seat, until they have
+	// MaxEntries (ErrEntryLimit): p is set to their seat, with one more entry
+	// counted from p.EnteredRound.
+	AddParticipant(p *Participant, maxPlayers int) error
+	// RemoveParticipant gives the seat back (e.g. the entry fee could not be collected)
+	RemoveParticipant(tournamentID, userID string) error
+	// Participants lists participants in registration order
+	Participants(tournamentID string) ([]Participant, error)
+	UpdateParticipant(p *Participant) error
+
+	SaveMatches(matches []TournamentMatch) error
+	// Matches lists the bracket by bracket (see bracketOrder), round, then position
+	Matches(tournamentID string) ([]TournamentMatch, error)
+	GetMatch(id string) (*TournamentMatch, error)
+	MatchBySession(sessionID string) (*TournamentMatch, error)
+	UpdateMatch(m *TournamentMatch) error
+
+	CreateTemplate(t *Template) error
+	GetTemplate(id string) (*Template, error)
+	// ListTemplates lists templates by creation; only active ones if activeOnly
+	ListTemplates(activeOnly bool) ([]*Template, error)
+	// UpdateTemplate saves whether the template is active and its next run
+	UpdateTemplate(t *Template) error
+	// TemplateTournaments lists a template's tournaments in a status by start time
+	TemplateTournaments(templateID, status string) ([]*Tournament, error)
+}
+
+// MemoryStore keeps tournaments in process, for running without a database
+type MemoryStore struct {
+	tournaments  map[string]*Tournament
+	participants map[string][]Participant // tournamentID -> participants
+	matches      map[string]*TournamentMatch
+	templates    map[string]*Template
+	mu           sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tournaments:  make(map[string]*Tournament),
+		participants: make(map[string][]Participant),
+		matches:      make(map[string]*TournamentMatch),
+		templates:    make(map[string]*Template),
+	}
+}
+
+func (s *MemoryStore) CreateTournament(t *Tournament) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *t
+	s.tournaments[t.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetTournament(id string) (*Tournament, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.tournaments[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (s *MemoryStore) ListTournaments(status string, limit, offset int) ([]*Tournament, error) {
+	s.mu.RLock()
+	all := []*Tournament{}
+	for _, t := range s.tournaments {
+		if status == "" || t.Status == status {
+			copied := *t
+			all = append(all, &copied)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].StartTime.Equal(all[j].StartTime) {
+			return all[i].StartTime.Before(all[j].StartTime)
+		}
+		return all[i].ID < all[j].ID
+	})
+	if offset >= len(all) {
+		return []*Tournament{}, nil
+	}
+	all = all[offset:]
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	return all, nil
+}
+
+func (s *MemoryStore) UpdateTournament(t *Tournament) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.tournaments[t.ID]
+	if !ok {
+		return ErrTournamentNotFound
+	}
+	// The player count is only moved by AddParticipant/RemoveParticipant
+	copied := *t
+	copied.CurrentPlayers = existing.CurrentPlayers
+	s.tournaments[t.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) AddParticipant(p *Participant, maxPlayers int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tournaments[p.TournamentID]
+	if !ok {
+		return ErrTournamentNotFound
+	}
+	open, late := t.registering(p.RegisteredAt)
+	if !open {
+		return ErrRegistrationClosed
+	}
+	participants := s.participants[p.TournamentID]
+	for i := range participants {
+		if participants[i].UserID != p.UserID {
+			continue
+		}
+		seat, err := reentry(t, participants[i], late, p.EnteredRound)
+		if err != nil {
+			return err
+		}
+		participants[i], *p = seat, seat
+		return nil
+	}
+	if t.CurrentPlayers >= maxPlayers {
+		return ErrTournamentFull
+	}
+	if late {
+		p.Seed = t.CurrentPlayers + 1
+	}
+	p.Entries, p.EnteredRound = 1, 0
+	s.participants[p.TournamentID] = append(s.participants[p.TournamentID], *p)
+	t.CurrentPlayers++
+	return nil
+}
+
+func (s *MemoryStore) RemoveParticipant(tournamentID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	participants := s.participants[tournamentID]
+	for i, p := range participants {
+		if p.UserID == userID {
+			s.participants[tournamentID] = append(participants[:i:i], participants[i+1:]...)
+			s.tournaments[tournamentID].CurrentPlayers--
+			return nil
+		}
+	}
+	return ErrNotRegistered
+}
+
+func (s *MemoryStore) Participants(tournamentID string) ([]Participant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Participant{}, s.participants[tournamentID]...), nil
+}
+
+func (s *MemoryStore) UpdateParticipant(p *Participant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	participants := s.participants[p.TournamentID]
+	for i := range participants {
+		if participants[i].UserID == p.UserID {
+			participants[i] = *p
+			return nil
+		}
+	}
+	return ErrNotRegistered
+}
+
+func (s *MemoryStore) SaveMatches(matches []TournamentMatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range matches {
+		copied := matches[i]
+		s.matches[copied.ID] = &copied
+	}
+	return nil
+}
+
+func (s *MemoryStore) Matches(tournamentID string) ([]TournamentMatch, error) {
+	s.mu.RLock()
+	matches := []TournamentMatch{}
+	for _, m := range s.matches {
+		if m.TournamentID == tournamentID {
+			matches = append(matches, *m)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Bracket != matches[j].Bracket {
+			return bracketOrder(matches[i].Bracket) < bracketOrder(matches[j].Bracket)
+		}
+		if matches[i].Round != matches[j].Round {
+			return matches[i].Round < matches[j].Round
+		}
+		return matches[i].MatchIndex < matches[j].MatchIndex
+	})
+	return matches, nil
+}
+
+func (s *MemoryStore) GetMatch(id string) (*TournamentMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.matches[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *m
+	return &copied, nil
+}
+
+func (s *MemoryStore) MatchBySession(sessionID string) (*TournamentMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, m := range s.matches {
+		if m.SessionID == sessionID {
+			copied := *m
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) UpdateMatch(m *TournamentMatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.matches[m.ID]; !ok {
+		return ErrMatchNotFound
+	}
+	copied := *m
+	s.matches[m.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) CreateTemplate(t *Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *t
+	s.templates[t.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetTemplate(id string) (*Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (s *MemoryStore) ListTemplates(activeOnly bool) ([]*Template, error) {
+	s.mu.RLock()
+	templates := []*Template{}
+	for _, t := range s.templates {
+		if t.Active || !activeOnly {
+			copied := *t
+			templates = append(templates, &copied)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(templates, func(i, j int) bool {
+		if !templates[i].CreatedAt.Equal(templates[j].CreatedAt) {
+			return templates[i].CreatedAt.Before(templates[j].CreatedAt)
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates, nil
+}
+
+func (s *MemoryStore) UpdateTemplate(t *Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.templates[t.ID]; !ok {
+		return ErrTemplateNotFound
+	}
+	copied := *t
+	s.templates[t.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) TemplateTournaments(templateID, status string) ([]*Tournament, error) {
+	s.mu.RLock()
+	tournaments := []*Tournament{}
+	for _, t := range s.tournaments {
+		if t.TemplateID == templateID && t.Status == status {
+			copied := *t
+			tournaments = append(tournaments, &copied)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(tournaments, func(i, j int) bool {
+		if !tournaments[i].StartTime.Equal(tournaments[j].StartTime) {
+			return tournaments[i].StartTime.Before(tournaments[j].StartTime)
+		}
+		return tournaments[i].ID < tournaments[j].ID
+	})
+	return tournaments, nil
+}
+
+// PostgresStore keeps tournaments in the tournaments, tournament_participants
+// and tournament_matches tables
+type PostgresStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+const tournamentColumns = `id, COALESCE(template_id::text, ''), name, game_type, entry_fee, prize_pool, rake_amount,
+	overlay_amount, max_players, current_players, max_entries, status, config, registration_opens, registration_closes,
+	start_time, end_time, created_at, updated_at`
+
+func scanTournament(row interface{ Scan(...interface{}) error }) (*Tournament, error) {
+	t := &Tournament{}
+	var endTime sql.NullTime
+	err := row.Scan(&t.ID, &t.TemplateID, &t.Name, &t.GameType, &t.EntryFee, &t.PrizePool, &t.Rake, &t.Overlay,
+		&t.MaxPlayers, &t.CurrentPlayers, &t.MaxEntries, &t.Status, &t.Config, &t.RegistrationOpens, &t.RegistrationCloses,
+		&t.StartTime, &endTime, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if endTime.Valid {
+		t.EndTime = &endTime.Time
+	}
+	return t, nil
+}
+
+func (s *PostgresStore) CreateTournament(t *Tournament) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO tournaments (id, template_id, name, game_type, entry_fee, prize_pool, max_players, current_players,
+			max_entries, status, config, registration_opens, registration_closes, start_time, created_at, updated_at)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`, t.ID, t.TemplateID, t.Name, t.GameType, t.EntryFee, t.PrizePool, t.MaxPlayers, t.CurrentPlayers,
+		t.MaxEntries, t.Status, t.Config, t.RegistrationOpens, t.RegistrationCloses, t.StartTime, t.CreatedAt, t.UpdatedAt)
+	return err
+}
+
+func (s *PostgresStore) GetTournament(id string) (*Tournament, error) {
+	t, err := scanTournament(s.DB.QueryRow(`SELECT `+tournamentColumns+` FROM tournaments WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (s *PostgresStore) ListTournaments(status string, limit, offset int) ([]*Tournament, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+tournamentColumns+`
+		FROM tournaments
+		WHERE $1 = '' OR status = $1
+		ORDER BY start_time, id LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tournaments := []*Tournament{}
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, t)
+	}
+	return tournaments, rows.Err()
+}
+
+func (s *PostgresStore) UpdateTournament(t *Tournament) error {
+	_, err := s.DB.Exec(`
+		UPDATE tournaments
+		SET status = $2, prize_pool = $3, rake_amount = $4, overlay_amount = $5, registration_closes = $6,
+			start_time = $7, end_time = $8, updated_at = $9
+		WHERE id = $1
+	`, t.ID, t.Status, t.PrizePool, t.Rake, t.Overlay, t.RegistrationCloses, t.StartTime, t.EndTime, t.UpdatedAt)
+	return err
+}
+
+// AddParticipant locks the tournament row so concurrent registrations can't
+// overfill it, or enter it after it has started or closed
+func (s *PostgresStore) AddParticipant(p *Participant, maxPlayers int) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var t Tournament
+	err = tx.QueryRow(`
+		SELECT status, registration_opens, registration_closes, current_players, max_entries
+		FROM tournaments WHERE id = $1 FOR UPDATE
+	`, p.TournamentID).Scan(&t.Status, &t.RegistrationOpens, &t.RegistrationCloses, &t.CurrentPlayers, &t.MaxEntries)
+	if err == sql.ErrNoRows {
+		return ErrTournamentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	open, late := t.registering(p.RegisteredAt)
+	if !open {
+		return ErrRegistrationClosed
+	}
+
+	seat, err := scanParticipant(tx.QueryRow(`
+		SELECT `+participantColumns+` FROM tournament_participants WHERE tournament_id = $1 AND user_id = $2
+	`, p.TournamentID, p.UserID))
+	if err == nil {
+		if *p, err = reentry(&t, *seat, late, p.EnteredRound); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			UPDATE tournament_participants SET status = $3, entries = $4, entered_round = NULLIF($5, 0)
+			WHERE tournament_id = $1 AND user_id = $2
+		`, p.TournamentID, p.UserID, p.Status, p.Entries, p.EnteredRound); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	if t.CurrentPlayers >= maxPlayers {
+		return ErrTournamentFull
+	}
+	if late {
+		p.Seed = t.CurrentPlayers + 1
+	}
+	p.Entries, p.EnteredRound = 1, 0
+
+	res, err := tx.Exec(`
+		INSERT INTO tournament_participants (id, tournament_id, user_id, status, seed, entries, registered_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, $7)
+		ON CONFLICT (tournament_id, user_id) DO NOTHING
+	`, p.ID, p.TournamentID, p.UserID, p.Status, p.Seed, p.Entries, p.RegisteredAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAlreadyRegistered
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE tournaments SET current_players = current_players + 1, updated_at = NOW() WHERE id = $1
+	`, p.TournamentID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) RemoveParticipant(tournamentID, userID string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM tournament_participants WHERE tournament_id = $1 AND user_id = $2`, tournamentID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotRegistered
+	}
+	if _, err := tx.Exec(`
+		UPDATE tournaments SET current_players = current_players - 1, updated_at = NOW() WHERE id = $1
+	`, tournamentID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) Participants(tournamentID string) ([]Participant, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+participantColumns+`
+		FROM tournament_participants
+		WHERE tournament_id = $1
+		ORDER BY registered_at, id
+	`, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []Participant{}
+	for rows.Next() {
+		p, err := scanParticipant(rows)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, *p)
+	}
+	return participants, rows.Err()
+}
+
+const participantColumns = `id, tournament_id, user_id, status, COALESCE(rank, 0), COALESCE(seed, 0), entries,
+	COALESCE(entered_round, 0), COALESCE(prize_amount, 0), registered_at`
+
+func scanParticipant(row interface{ Scan(...interface{}) error }) (*Participant, error) {
+	p := &Participant{}
+	err := row.Scan(&p.ID, &p.TournamentID, &p.UserID, &p.Status, &p.Rank, &p.Seed, &p.Entries, &p.EnteredRound,
+		&p.PrizeAmount, &p.RegisteredAt)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *PostgresStore) UpdateParticipant(p *Participant) error {
+	_, err := s.DB.Exec(`
+		UPDATE tournament_participants
+		SET status = $3, rank = NULLIF($4, 0), seed = NULLIF($5, 0), prize_amount = $6, entries = $7,
+			entered_round = NULLIF($8, 0)
+		WHERE tournament_id = $1 AND user_id = $2
+	`, p.TournamentID, p.UserID, p.Status, p.Rank, p.Seed, p.PrizeAmount, p.Entries, p.EnteredRound)
+	return err
+}
+
+// SaveMatches inserts the matches, then links them, so every next match
+// already exists when it is referenced
+func (s *PostgresStore) SaveMatches(matches []TournamentMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, m := range matches {
+		_, err := tx.Exec(`
+			INSERT INTO tournament_matches (id, tournament_id, bracket, round, match_index, player_1_id, player_2_id,
+				player_1_score, player_2_score, winner_id, status, session_id, metadata, created_at, updated_at)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, '')::jsonb, $14, $15)
+		`, m.ID, m.TournamentID, m.Bracket, m.Round, m.MatchIndex, m.Player1ID, m.Player2ID,
+			m.Player1Score, m.Player2Score, m.WinnerID, m.Status, m.SessionID, m.Metadata, m.CreatedAt, m.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	for _, m := range matches {
+		if m.NextMatchID == nil && m.LoserNextID == nil {
+			continue
+		}
+		if _, err := tx.Exec(`
+			UPDATE tournament_matches SET next_match_id = $2, loser_next_match_id = $3 WHERE id = $1
+		`, m.ID, m.NextMatchID, m.LoserNextID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+const matchColumns = `id, tournament_id, COALESCE(bracket, ''), round, match_index, player_1_id, player_2_id,
+	COALESCE(player_1_score, 0), COALESCE(player_2_score, 0), winner_id, status, next_match_id, loser_next_match_id,
+	COALESCE(session_id, ''), COALESCE(metadata::text, ''), created_at, updated_at`
+
+func scanMatch(row interface{ Scan(...interface{}) error }) (*TournamentMatch, error) {
+	m := &TournamentMatch{}
+	var player1, player2, winner, next, loserNext sql.NullString
+	err := row.Scan(&m.ID, &m.TournamentID, &m.Bracket, &m.Round, &m.MatchIndex, &player1, &player2,
+		&m.Player1Score, &m.Player2Score, &winner, &m.Status, &next, &loserNext,
+		&m.SessionID, &m.Metadata, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.Player1ID, m.Player2ID, m.WinnerID = nullable(player1), nullable(player2), nullable(winner)
+	m.NextMatchID, m.LoserNextID = nullable(next), nullable(loserNext)
+	return m, nil
+}
+
+func nullable(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func (s *PostgresStore) Matches(tournamentID string) ([]TournamentMatch, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+matchColumns+`
+		FROM tournament_matches
+		WHERE tournament_id = $1
+		ORDER BY CASE bracket WHEN 'WINNERS' THEN 1 WHEN 'LOSERS' THEN 2 WHEN 'GRAND_FINAL' THEN 3 ELSE 0 END,
+			round, match_index
+	`, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []TournamentMatch{}
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, *m)
+	}
+	return matches, rows.Err()
+}
+
+func (s *PostgresStore) GetMatch(id string) (*TournamentMatch, error) {
+	m, err := scanMatch(s.DB.QueryRow(`SELECT `+matchColumns+` FROM tournament_matches WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+func (s *PostgresStore) MatchBySession(sessionID string) (*TournamentMatch, error) {
+	m, err := scanMatch(s.DB.QueryRow(`SELECT `+matchColumns+` FROM tournament_matches WHERE session_id = $1`, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+func (s *PostgresStore) UpdateMatch(m *TournamentMatch) error {
+	_, err := s.DB.Exec(`
+		UPDATE tournament_matches
+		SET player_1_id = $2, player_2_id = $3, player_1_score = $4, player_2_score = $5, winner_id = $6, status = $7,
+			session_id = NULLIF($8, ''), metadata = NULLIF($9, '')::jsonb, updated_at = $10
+		WHERE id = $1
+	`, m.ID, m.Player1ID, m.Player2ID, m.Player1Score, m.Player2Score, m.WinnerID, m.Status, m.SessionID, m.Metadata, time.Now())
+	return err
+}
+
+const templateColumns = `id, name, kind, game_type, entry_fee, prize_pool, max_players, config,
+	COALESCE(schedule, ''), timezone, registration_minutes, late_registration_minutes, max_entries, active, next_run,
+	created_at, updated_at`
+
+func scanTemplate(row interface{ Scan(...interface{}) error }) (*Template, error) {
+	t := &Template{}
+	var nextRun sql.NullTime
+	err := row.Scan(&t.ID, &t.Name, &t.Kind, &t.GameType, &t.EntryFee, &t.PrizePool, &t.MaxPlayers, &t.Config,
+		&t.Schedule, &t.Timezone, &t.RegistrationMinutes, &t.LateRegMinutes, &t.MaxEntries, &t.Active, &nextRun,
+		&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if nextRun.Valid {
+		t.NextRun = &nextRun.Time
+	}
+	return t, nil
+}
+
+func (s *PostgresStore) CreateTemplate(t *Template) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO tournament_templates (id, name, kind, game_type, entry_fee, prize_pool, max_players, config,
+			schedule, timezone, registration_minutes, late_registration_minutes, max_entries, active, next_run,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13, $14, $15, $16, $17)
+	`, t.ID, t.Name, t.Kind, t.GameType, t.EntryFee, t.PrizePool, t.MaxPlayers, t.Config,
+		t.Schedule, t.Timezone, t.RegistrationMinutes, t.LateRegMinutes, t.MaxEntries, t.Active, t.NextRun,
+		t.CreatedAt, t.UpdatedAt)
+	return err
+}
+
+func (s *PostgresStore) GetTemplate(id string) (*Template, error) {
+	t, err := scanTemplate(s.DB.QueryRow(`SELECT `+templateColumns+` FROM tournament_templates WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (s *PostgresStore) ListTemplates(activeOnly bool) ([]*Template, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+templateColumns+`
+		FROM tournament_templates
+		WHERE active OR NOT $1
+		ORDER BY created_at, id
+	`, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []*Template{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (s *PostgresStore) UpdateTemplate(t *Template) error {
+	res, err := s.DB.Exec(`
+		UPDATE tournament_templates SET active = $2, next_run = $3, updated_at = $4 WHERE id = $1
+	`, t.ID, t.Active, t.NextRun, t.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) TemplateTournaments(templateID, status string) ([]*Tournament, error) {
+	rows, err := s.DB.Query(`
+		SELECT `+tournamentColumns+`
+		FROM tournaments
+		WHERE template_id = $1 AND status = $2
+		ORDER BY start_time, id
+	`, templateID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tournaments := []*Tournament{}
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, t)
+	}
+	return tournaments, rows.Err()
+}
+
+// reentry is seat with one more entry, if the tournament lets its user re-enter
+func reentry(t *Tournament, seat Participant, late bool, enteredRound int) (Participant, error) {
+	// Knockout brackets are drawn once, so only a running Swiss tournament can
+	// take anyone back
+	if !late {
+		return seat, ErrAlreadyRegistered
+	}
+	if seat.Entries >= t.entryLimit() {
+		return seat, ErrEntryLimit
+	}
+	seat.Entries++
+	seat.EnteredRound = enteredRound
+	seat.Status = ParticipantRegistered
+	return seat, nil
+}
+
+// bracketOrder lists single-bracket matches, then the winners, losers and grand
+// final brackets of double elimination
+func bracketOrder(bracket string) int {
+	switch bracket {
+	case BracketWinners:
+		return 1
+	case BracketLosers:
+		return 2
+	case BracketGrandFinal:
+		return 3
+	}
+	return 0
+}
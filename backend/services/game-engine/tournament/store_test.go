@@ -0,0 +1,110 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreChecksRegistrationAsSeatIsTaken(t *testing.T) {
+	start := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	tournament := &Tournament{
+		ID:                 "t1",
+		Status:             StatusRegistration,
+		MaxPlayers:         8,
+		RegistrationOpens:  start.Add(-time.Hour),
+		RegistrationCloses: start.Add(30 * time.Minute), // Swiss late registration
+		StartTime:          start,
+	}
+	if err := store.CreateTournament(tournament); err != nil {
+		t.Fatal(err)
+	}
+	entry := func(userID string, at time.Time) *Participant {
+		return &Participant{ID: userID, TournamentID: "t1", UserID: userID, Status: ParticipantRegistered, RegisteredAt: at}
+	}
+
+	if err := store.AddParticipant(entry("early", start.Add(-2*time.Hour)), 8); err != ErrRegistrationClosed {
+		t.Errorf("before registration opens: err = %v", err)
+	}
+	first := entry("p1", start.Add(-time.Minute))
+	if err := store.AddParticipant(first, 8); err != nil || first.Seed != 0 {
+		t.Fatalf("open registration: err = %v, seed %d", err, first.Seed)
+	}
+
+	// Started since the manager last looked: the entry is a late one
+	tournament.Status = StatusActive
+	if err := store.UpdateTournament(tournament); err != nil {
+		t.Fatal(err)
+	}
+	late := entry("p2", start.Add(10*time.Minute))
+	if err := store.AddParticipant(late, 8); err != nil || late.Seed != 2 {
+		t.Fatalf("late registration: err = %v, seed %d, want 2", err, late.Seed)
+	}
+	if err := store.AddParticipant(entry("p3", start.Add(30*time.Minute)), 8); err != ErrRegistrationClosed {
+		t.Errorf("after late registration closes: err = %v", err)
+	}
+
+	for _, status := range []string{StatusCompleted, StatusCancelled} {
+		tournament.Status = status
+		if err := store.UpdateTournament(tournament); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AddParticipant(entry("p4", start.Add(time.Minute)), 8); err != ErrRegistrationClosed {
+			t.Errorf("%s tournament: err = %v", status, err)
+		}
+	}
+}
+
+func TestMemoryStoreCountsReentries(t *testing.T) {
+	start := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	tournament := &Tournament{
+		ID:                 "t1",
+		Status:             StatusRegistration,
+		MaxPlayers:         2,
+		MaxEntries:         2,
+		RegistrationOpens:  start.Add(-time.Hour),
+		RegistrationCloses: start.Add(30 * time.Minute),
+		StartTime:          start,
+	}
+	if err := store.CreateTournament(tournament); err != nil {
+		t.Fatal(err)
+	}
+	entry := func(at time.Time, round int) *Participant {
+		return &Participant{ID: at.Format(time.Kitchen), TournamentID: "t1", UserID: "p1", Status: ParticipantRegistered, EnteredRound: round, RegisteredAt: at}
+	}
+
+	if err := store.AddParticipant(entry(start.Add(-time.Minute), 0), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddParticipant(entry(start.Add(-time.Minute), 0), 2); err != ErrAlreadyRegistered {
+		t.Fatalf("second entry before the start: err = %v", err)
+	}
+
+	tournament.Status = StatusActive
+	if err := store.UpdateTournament(tournament); err != nil {
+		t.Fatal(err)
+	}
+	// A full tournament still takes a re-entry: it keeps its seat
+	if err := store.AddParticipant(&Participant{ID: "p2", TournamentID: "t1", UserID: "p2", RegisteredAt: start}, 2); err != nil {
+		t.Fatal(err)
+	}
+	again := entry(start.Add(time.Minute), 3)
+	if err := store.AddParticipant(again, 2); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != "7:59PM" || again.Entries != 2 || again.EnteredRound != 3 {
+		t.Fatalf("re-entry %+v, want the first seat with 2 entries from round 3", again)
+	}
+	if err := store.AddParticipant(entry(start.Add(2*time.Minute), 4), 2); err != ErrEntryLimit {
+		t.Fatalf("third entry: err = %v", err)
+	}
+
+	participants, _ := store.Participants("t1")
+	if len(participants) != 2 || participants[0].Entries != 2 || participants[1].Entries != 1 {
+		t.Fatalf("participants %+v", participants)
+	}
+	if got, _ := store.GetTournament("t1"); got.CurrentPlayers != 2 {
+		t.Fatalf("%d players seated, want 2", got.CurrentPlayers)
+	}
+}
@@ -0,0 +1,163 @@
+package tournament
+
+import (
+	"errors"
+	"math"
+)
+
+// Swiss plays a fixed number of rounds, each pairing players on equal points
+// who haven't met yet, and ranks players by the standings table
+type Swiss struct{}
+
+// swissSearchBudget caps the pairing search before rematches are allowed
+const swissSearchBudget = 100000
+
+func (Swiss) Start(t *Tournament, seeded []string) ([]TournamentMatch, error) {
+	if len(seeded) < 2 {
+		return nil, errors.New("need at least 2 participants")
+	}
+	participants := make([]Participant, len(seeded))
+	for i, userID := range seeded {
+		participants[i] = Participant{UserID: userID, Seed: i + 1}
+	}
+	return swissRound(t, participants, nil, 1), nil
+}
+
+func (Swiss) Advance(t *Tournament, participants []Participant, matches []TournamentMatch, completed *TournamentMatch) (*Progress, error) {
+	progress := &Progress{}
+	if !roundComplete(matches, completed.Round) {
+		return progress, nil
+	}
+	if completed.Round >= swissRounds(t.Config, len(participants)) {
+		progress.Done = true
+		return progress, nil
+	}
+	progress.Created = swissRound(t, participants, matches, completed.Round+1)
+	return progress, nil
+}
+
+func (Swiss) Rank(t *Tournament, participants []Participant, matches []TournamentMatch) (map[string]int, error) {
+	return rankByStandings(Standings(participants, matches, tieBreakersFor(t.Config))), nil
+}
+
+// swissRounds is the configured number of rounds, by default enough to leave a
+// single unbeaten player, and never more than it takes to meet everyone
+func swissRounds(config TournamentConfig, players int) int {
+	rounds := config.Rounds
+	if rounds == 0 {
+		rounds = int(math.Ceil(math.Log2(float64(players))))
+	}
+	if rounds > players-1 {
+		rounds = players - 1
+	}
+	if rounds < 1 {
+		rounds = 1
+	}
+	return rounds
+}
+
+// swissRound pairs the next round from the current standings. With an odd
+// number of players the lowest-placed player who hasn't had a bye gets one.
+func swissRound(t *Tournament, participants []Participant, matches []TournamentMatch, round int) []TournamentMatch {
+	standings := Standings(participants, matches, tieBreakersFor(t.Config))
+
+	played := make(map[[2]string]bool)
+	hadBye := make(map[string]bool)
+	for i := range matches {
+		m := &matches[i]
+		if m.Player1ID != nil && m.Player2ID != nil {
+			played[pairKey(*m.Player1ID, *m.Player2ID)] = true
+		} else if m.WinnerID != nil {
+			hadBye[*m.WinnerID] = true
+		}
+	}
+
+	ids := make([]string, 0, len(standings))
+	points := make(map[string]int, len(standings))
+	for _, s := range standings {
+		ids = append(ids, s.UserID)
+		points[s.UserID] = s.Points
+	}
+
+	bye := ""
+	if len(ids)%2 == 1 {
+		for i := len(ids) - 1; i >= 0; i-- {
+			if !hadBye[ids[i]] || i == 0 {
+				bye = ids[i]
+				ids = append(ids[:i:i], ids[i+1:]...)
+				break
+			}
+		}
+	}
+
+	budget := swissSearchBudget
+	pairs, ok := pairSwiss(ids, points, played, false, &budget)
+	if !ok {
+		// Everyone left has met: pair again, rematches and all
+		budget = swissSearchBudget
+		pairs, _ = pairSwiss(ids, points, played, true, &budget)
+	}
+
+	created := make([]TournamentMatch, 0, len(pairs)+1)
+	for _, pair := range pairs {
+		created = append(created, pairing(t.ID, round, len(created), pair[0], pair[1]))
+	}
+	if bye != "" {
+		m := newMatch(t.ID, "", round, len(created))
+		m.Player1ID, m.WinnerID = &bye, &bye
+		m.Status = MatchCompleted
+		created = append(created, m)
+	}
+	return created
+}
+
+// pairSwiss pairs players in standings order by backtracking. Following the
+// Dutch system, the top player of a score group is first offered the player half
+// the group below, which pairs 1v5, 2v6... in an eight-player opening round.
+func pairSwiss(ids []string, points map[string]int, played map[[2]string]bool, rematches bool, budget *int) ([][2]string, bool) {
+	if len(ids) == 0 {
+		return nil, true
+	}
+	if *budget--; *budget < 0 {
+		return nil, false
+	}
+
+	first, rest := ids[0], ids[1:]
+	group := 0
+	for _, id := range rest {
+		if points[id] == points[first] {
+			group++
+		}
+	}
+	ideal := (group+1)/2 - 1
+	if ideal < 0 {
+		ideal = 0
+	}
+
+	order := make([]int, 0, len(rest))
+	for i := ideal; i < len(rest); i++ {
+		order = append(order, i)
+	}
+	for i := 0; i < ideal; i++ {
+		order = append(order, i)
+	}
+
+	for _, i := range order {
+		opponent := rest[i]
+		if !rematches && played[pairKey(first, opponent)] {
+			continue
+		}
+		remaining := append(append([]string{}, rest[:i]...), rest[i+1:]...)
+		if pairs, ok := pairSwiss(remaining, points, played, rematches, budget); ok {
+			return append([][2]string{{first, opponent}}, pairs...), true
+		}
+	}
+	return nil, false
+}
+
+func pairKey(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
@@ -0,0 +1,204 @@
+package tournament
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func swissPlayers(n int) []Participant {
+	participants := make([]Participant, n)
+	for i := range participants {
+		participants[i] = Participant{UserID: fmt.Sprintf("p%02d", i+1), Seed: i + 1}
+	}
+	return participants
+}
+
+// playRound decides every undecided match of a round at random
+func playRound(rng *rand.Rand, matches []TournamentMatch) {
+	for i := range matches {
+		m := &matches[i]
+		if m.Status == MatchCompleted {
+			continue // Bye
+		}
+		m.Player1Score, m.Player2Score = rng.Intn(10), rng.Intn(10)
+		winner := m.Player1ID
+		if m.Player2Score > m.Player1Score || (m.Player2Score == m.Player1Score && rng.Intn(2) == 0) {
+			winner = m.Player2ID
+		}
+		m.WinnerID = winner
+		m.Status = MatchCompleted
+	}
+}
+
+// checkRound fails unless every participant plays or sits out exactly once
+func checkRound(t *testing.T, participants []Participant, round []TournamentMatch) {
+	t.Helper()
+	seen := make(map[string]int)
+	for _, m := range round {
+		if m.Player1ID != nil {
+			seen[*m.Player1ID]++
+		}
+		if m.Player2ID != nil {
+			seen[*m.Player2ID]++
+		}
+	}
+	for _, p := range participants {
+		if seen[p.UserID] != 1 {
+			t.Fatalf("round %d: %s seated %d times", round[0].Round, p.UserID, seen[p.UserID])
+		}
+	}
+}
+
+func TestSwissPairsWithoutRematches(t *testing.T) {
+	tournament := &Tournament{ID: "swiss"}
+	for _, n := range []int{4, 6, 7, 8, 9, 12, 16, 21} {
+		for seed := int64(1); seed <= 25; seed++ {
+			rng := rand.New(rand.NewSource(seed))
+			participants := swissPlayers(n)
+			seeded := make([]string, n)
+			for i, p := range participants {
+				seeded[i] = p.UserID
+			}
+
+			matches, err := Swiss{}.Start(tournament, seeded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			played := make(map[[2]string]int)
+			rounds := swissRounds(tournament.Config, n)
+			for round := 1; round <= rounds; round++ {
+				if round > 1 {
+					matches = append(matches, swissRound(tournament, participants, matches, round)...)
+				}
+				current := matches[len(matches)-(n+1)/2:]
+				checkRound(t, participants, current)
+				for _, m := range current {
+					if m.Player2ID == nil {
+						continue
+					}
+					key := pairKey(*m.Player1ID, *m.Player2ID)
+					if played[key]++; played[key] > 1 {
+						t.Fatalf("%d players, seed %d, round %d: %s and %s meet again", n, seed, round, key[0], key[1])
+					}
+				}
+				playRound(rng, current)
+			}
+		}
+	}
+}
+
+func TestSwissByeRotates(t *testing.T) {
+	tournament := &Tournament{ID: "swiss"}
+	for _, n := range []int{3, 5, 7, 9} {
+		for seed := int64(1); seed <= 10; seed++ {
+			rng := rand.New(rand.NewSource(seed))
+			participants := swissPlayers(n)
+			byes := make(map[string]int)
+			matches := []TournamentMatch{}
+
+			// As many rounds as players, so everyone sits out exactly once
+			for round := 1; round <= n; round++ {
+				created := swissRound(tournament, participants, matches, round)
+				checkRound(t, participants, created)
+				for _, m := range created {
+					if m.Player2ID == nil {
+						byes[*m.Player1ID]++
+					}
+				}
+				playRound(rng, created)
+				matches = append(matches, created...)
+			}
+			for _, p := range participants {
+				if byes[p.UserID] != 1 {
+					t.Fatalf("%d players, seed %d: %s had %d byes in %d rounds", n, seed, p.UserID, byes[p.UserID], n)
+				}
+			}
+		}
+	}
+}
+
+func TestSwissByeGoesToLowestPlaced(t *testing.T) {
+	tournament := &Tournament{ID: "swiss"}
+	participants := swissPlayers(5)
+	matches := swissRound(tournament, participants, nil, 1)
+
+	// In round one the lowest seed sits out
+	last := matches[len(matches)-1]
+	if last.Player2ID != nil || *last.Player1ID != "p05" || *last.WinnerID != "p05" {
+		t.Fatalf("round 1 bye %+v, want p05", last)
+	}
+}
+
+// TestSwissFallsBackWhenSearchRunsOut gives the pairing search a round it can't
+// finish: two score groups of 15, where everyone has already met everyone in the
+// other group. No rematch-free pairing exists, and proving that takes longer than
+// the search budget allows, so the round is paired again allowing rematches.
+func TestSwissFallsBackWhenSearchRunsOut(t *testing.T) {
+	tournament := &Tournament{ID: "swiss"}
+	participants := make([]Participant, 0, 30)
+	for i := 1; i <= 15; i++ {
+		participants = append(participants, Participant{UserID: fmt.Sprintf("x%02d", i), Seed: i})
+	}
+	for i := 1; i <= 15; i++ {
+		participants = append(participants, Participant{UserID: fmt.Sprintf("y%02d", i), Seed: 15 + i})
+	}
+
+	matches := []TournamentMatch{}
+	for _, x := range participants[:15] {
+		for _, y := range participants[15:] {
+			m := pairing(tournament.ID, 1, len(matches), x.UserID, y.UserID)
+			m.WinnerID, m.Status = m.Player1ID, MatchCompleted
+			matches = append(matches, m)
+		}
+	}
+
+	ids := make([]string, 0, len(participants))
+	points := make(map[string]int)
+	played := make(map[[2]string]bool)
+	for _, s := range Standings(participants, matches, tieBreakersFor(tournament.Config)) {
+		ids = append(ids, s.UserID)
+		points[s.UserID] = s.Points
+	}
+	for _, m := range matches {
+		played[pairKey(*m.Player1ID, *m.Player2ID)] = true
+	}
+	budget := swissSearchBudget
+	if _, ok := pairSwiss(ids, points, played, false, &budget); ok || budget >= 0 {
+		t.Fatalf("search found a pairing or gave up with %d of its budget left", budget)
+	}
+
+	created := swissRound(tournament, participants, matches, 16)
+	if len(created) != 15 {
+		t.Fatalf("%d matches, want 15", len(created))
+	}
+	checkRound(t, participants, created)
+
+	// Score groups stay together: only one pair has to cross between them
+	crossed := 0
+	for _, m := range created {
+		if (*m.Player1ID)[0] != (*m.Player2ID)[0] {
+			crossed++
+		}
+	}
+	if crossed != 1 {
+		t.Fatalf("%d pairs cross score groups, want 1", crossed)
+	}
+}
+
+func TestSwissRematchesWhenEveryoneHasMet(t *testing.T) {
+	tournament := &Tournament{ID: "swiss"}
+	participants := swissPlayers(4)
+	rng := rand.New(rand.NewSource(1))
+
+	matches := []TournamentMatch{}
+	for round := 1; round <= 4; round++ {
+		created := swissRound(tournament, participants, matches, round)
+		if len(created) != 2 {
+			t.Fatalf("round %d: %d matches, want 2", round, len(created))
+		}
+		checkRound(t, participants, created)
+		playRound(rng, created)
+		matches = append(matches, created...)
+	}
+}
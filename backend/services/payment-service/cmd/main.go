@@ -2,15 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 
 	pb "github.com/playkaro/backend/proto/wallet"
+	"github.com/playkaro/payment-service/internal/aml"
+	"github.com/playkaro/payment-service/internal/compliance"
 	"github.com/playkaro/payment-service/internal/db"
 	"github.com/playkaro/payment-service/internal/gateways/razorpay"
 	grpc_impl "github.com/playkaro/payment-service/internal/grpc"
@@ -43,8 +47,36 @@ func main() {
 	// Initialize Wallet Service (Business Logic)
 	walletService := wallet.NewService(db.DB)
 
+	// Initialize KYC document storage
+	kycBlobs, err := newKYCBlobStore()
+	if err != nil {
+		log.Fatal("Failed to initialize KYC document storage:", err)
+	}
+	// No name-match provider is integrated yet; the mock accepts any valid number
+	log.Println("KYC name match: using the mock verifier")
+	kycService, err := compliance.NewKYCService(db.DB, kycBlobs, compliance.NewMockVerifier(), compliance.NewUserProfiles(db.DB))
+	if err != nil {
+		log.Fatal("Failed to initialize KYC service:", err)
+	}
+	kycService.BaseURL = os.Getenv("KYC_DOCUMENT_BASE_URL")
+	go kycService.RunRetention(context.Background(), time.Hour)
+	go func() {
+		n, err := kycService.BackfillDocumentHashes(context.Background())
+		if err != nil {
+			log.Printf("KYC document hash backfill failed: %v", err)
+		} else if n > 0 {
+			log.Printf("KYC document hash backfill: %d requests hashed", n)
+		}
+	}()
+
+	// Initialize AML transaction monitoring
+	amlMonitor := aml.NewMonitor(db.DB)
+	go amlMonitor.Run(context.Background(), 15*time.Minute)
+
 	// Initialize handlers
 	paymentHandler := handlers.NewPaymentHandler(db.DB, razorpayClient, walletService)
+	kycHandler := handlers.NewKYCHandler(kycService)
+	amlHandler := handlers.NewAMLHandler(amlMonitor)
 
 	// Initialize OpenTelemetry
 	shutdown, err := telemetry.InitTracer("payment-service", "otel-collector:4317")
@@ -68,12 +100,23 @@ func main() {
 	{
 		// Public routes
 		v1.POST("/webhook/razorpay", paymentHandler.HandleRazorpayWebhook)
+		v1.GET("/kyc/documents/:document_id/content", kycHandler.GetDocumentContent) // Signed URL
 
 		// Internal routes (Protected by API Key in production)
 		internal := v1.Group("/internal")
 		{
 			internal.POST("/transaction", paymentHandler.ProcessInternalTransaction)
 			internal.GET("/balance", paymentHandler.GetBalance)
+			internal.POST("/wallets/freeze", paymentHandler.FreezeWallet)
+			internal.POST("/wallets/unfreeze", paymentHandler.UnfreezeWallet)
+			internal.GET("/kyc/requests/:request_id/documents", kycHandler.ListDocuments)
+			internal.POST("/kyc/requests/:request_id/verify", kycHandler.VerifyKYC)
+			internal.GET("/aml/cases", amlHandler.ListCases)
+			internal.GET("/aml/cases/:case_id", amlHandler.GetCase)
+			internal.POST("/aml/cases/:case_id/review", AuthMiddleware(), amlHandler.ReviewCase)
+			internal.GET("/aml/rules", amlHandler.GetRules)
+			internal.POST("/aml/rules", AuthMiddleware(), amlHandler.PublishRules)
+			internal.POST("/aml/replay", amlHandler.Replay)
 		}
 
 		// Protected routes (require JWT)
@@ -83,6 +126,8 @@ func main() {
 			authorized.POST("/deposit", paymentHandler.InitiateDeposit)
 			authorized.GET("/order/:order_id", paymentHandler.GetOrderStatus)
 			authorized.GET("/balance", paymentHandler.GetBalance)
+			authorized.POST("/kyc", kycHandler.SubmitKYC)
+			authorized.GET("/kyc", kycHandler.GetKYCStatus)
 		}
 	}
 
@@ -122,6 +167,24 @@ func main() {
 	}
 }
 
+// newKYCBlobStore picks where KYC documents are kept: an S3-compatible bucket
+// when KYC_BLOB_STORE=s3, otherwise the local filesystem
+func newKYCBlobStore() (compliance.BlobStore, error) {
+	if os.Getenv("KYC_BLOB_STORE") == "s3" {
+		endpoint, bucket := os.Getenv("KYC_S3_ENDPOINT"), os.Getenv("KYC_S3_BUCKET")
+		if endpoint == "" || bucket == "" {
+			return nil, errors.New("KYC_S3_ENDPOINT and KYC_S3_BUCKET are required")
+		}
+		return compliance.NewS3BlobStore(endpoint, os.Getenv("KYC_S3_REGION"), bucket,
+			os.Getenv("KYC_S3_ACCESS_KEY"), os.Getenv("KYC_S3_SECRET_KEY")), nil
+	}
+	dir := os.Getenv("KYC_BLOB_DIR")
+	if dir == "" {
+		dir = "./data/kyc"
+	}
+	return compliance.NewLocalBlobStore(dir)
+}
+
 // AuthMiddleware validates JWT token (simplified version)
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
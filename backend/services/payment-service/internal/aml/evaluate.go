@@ -0,0 +1,405 @@
+package aml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/playkaro/payment-service/internal/models"
+)
+
+// Case Statuses, as a compliance officer works through the queue
+const (
+	CaseOpen      = "OPEN"
+	CaseEscalated = "ESCALATED"
+	CaseDismissed = "DISMISSED"
+	CaseReported  = "REPORTED" // Suspicious transaction report filed
+)
+
+// Evidence is what a rule saw when it fired
+type Evidence struct {
+	Summary      string             `json:"summary"`
+	Transactions []string           `json:"transactions"` // Ledger transaction IDs
+	Figures      map[string]float64 `json:"figures"`
+}
+
+// Case is a rule match awaiting compliance review
+type Case struct {
+	ID             string     `json:"id"`
+	RuleID         string     `json:"rule_id"`
+	RuleVersion    int        `json:"rule_version"`
+	UserID         string     `json:"user_id"`
+	CounterpartyID string     `json:"counterparty_id,omitempty"` // The other user, for round trips
+	Status         string     `json:"status"`
+	Evidence       Evidence   `json:"evidence"`
+	TriggeredAt    time.Time  `json:"triggered_at"` // When the last transaction it needed happened
+	DedupeKey      string     `json:"-"`            // The same match in a later scan or replay has the same key
+	ReviewedBy     string     `json:"reviewed_by,omitempty"`
+	ReviewNotes    string     `json:"review_notes,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func isDeposit(e models.LedgerEntry) bool {
+	return e.Type == models.TxTypeDeposit
+}
+
+func isWithdrawal(e models.LedgerEntry) bool {
+	return e.Type == models.TxTypeWithdrawal || e.ReferenceType == models.TxTypeWithdrawal
+}
+
+// isStake is money put into play: any debit that isn't a withdrawal
+func isStake(e models.LedgerEntry) bool {
+	return e.Amount < 0 && !isWithdrawal(e)
+}
+
+// Evaluate runs the enabled rules over ledger entries, returning a case for
+// every match triggered within [from, to). entries must reach back Lookback()
+// before from; lastActive has each user's latest activity before them.
+func (r *RuleSet) Evaluate(entries []models.LedgerEntry, lastActive map[string]time.Time, from, to time.Time) []*Case {
+	sorted := make([]models.LedgerEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].TransactionID < sorted[j].TransactionID
+	})
+	byUser := make(map[string][]models.LedgerEntry)
+	users := []string{}
+	for _, e := range sorted {
+		if _, ok := byUser[e.UserID]; !ok {
+			users = append(users, e.UserID)
+		}
+		byUser[e.UserID] = append(byUser[e.UserID], e)
+	}
+	sort.Strings(users)
+
+	inRange := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	cases := []*Case{}
+	for _, user := range users {
+		es := byUser[user]
+		if r.DepositWithdrawal.Enabled {
+			cases = append(cases, r.depositWithdrawal(user, es, inRange)...)
+		}
+		if r.Structuring.Enabled {
+			cases = append(cases, r.structuring(user, es, inRange)...)
+		}
+		if r.DormancySpike.Enabled {
+			cases = append(cases, r.dormancySpike(user, es, lastActive[user], inRange)...)
+		}
+	}
+	if r.RoundTrip.Enabled {
+		cases = append(cases, r.roundTrips(sorted, inRange)...)
+	}
+
+	for _, c := range cases {
+		c.RuleVersion = r.Version
+		c.Status = CaseOpen
+	}
+	return cases
+}
+
+func (r *RuleSet) depositWithdrawal(user string, es []models.LedgerEntry, inRange func(time.Time) bool) []*Case {
+	rule := r.DepositWithdrawal
+	window := time.Duration(rule.WindowHours) * time.Hour
+	cases := []*Case{}
+	for _, w := range es {
+		if !isWithdrawal(w) || !inRange(w.CreatedAt) {
+			continue
+		}
+		start := w.CreatedAt.Add(-window)
+		deposited, staked := 0.0, 0.0
+		var firstDeposit time.Time
+		txIDs := []string{}
+		for _, e := range es {
+			if e.CreatedAt.Before(start) || e.CreatedAt.After(w.CreatedAt) || !isDeposit(e) {
+				continue
+			}
+			if firstDeposit.IsZero() {
+				firstDeposit = e.CreatedAt
+			}
+			deposited += e.Amount
+			txIDs = append(txIDs, e.TransactionID)
+		}
+		if deposited < rule.MinDeposits {
+			continue
+		}
+		for _, e := range es {
+			if !e.CreatedAt.Before(firstDeposit) && !e.CreatedAt.After(w.CreatedAt) && isStake(e) {
+				staked -= e.Amount
+			}
+		}
+		withdrawn := math.Abs(w.Amount)
+		if staked >= rule.MaxPlayRatio*deposited || withdrawn < rule.MinWithdrawalRatio*deposited {
+			continue
+		}
+		cases = append(cases, &Case{
+			RuleID:      RuleDepositWithdrawal,
+			UserID:      user,
+			TriggeredAt: w.CreatedAt,
+			DedupeKey:   w.TransactionID,
+			Evidence: Evidence{
+				Summary: fmt.Sprintf("Withdrew %.2f of %.2f deposited in the previous %dh after staking only %.2f",
+					withdrawn, deposited, rule.WindowHours, staked),
+				Transactions: append(txIDs, w.TransactionID),
+				Figures:      map[string]float64{"deposited": deposited, "staked": staked, "withdrawn": withdrawn, "play_ratio": staked / deposited},
+			},
+		})
+	}
+	return cases
+}
+
+// firstInWindow reports whether match i is the first of a run: no earlier
+// match happened within window before it. A run raises one case however long
+// it goes on for within the window.
+func firstInWindow(times []time.Time, matched []bool, i int, window time.Duration) bool {
+	for j := i - 1; j >= 0 && times[i].Sub(times[j]) < window; j-- {
+		if matched[j] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RuleSet) structuring(user string, es []models.LedgerEntry, inRange func(time.Time) bool) []*Case {
+	rule := r.Structuring
+	window := time.Duration(rule.WindowHours) * time.Hour
+	cases := []*Case{}
+	for _, threshold := range rule.Thresholds {
+		near := []models.LedgerEntry{}
+		for _, e := range es {
+			if isDeposit(e) && e.Amount >= threshold*(1-rule.Margin) && e.Amount < threshold {
+				near = append(near, e)
+			}
+		}
+
+		times := make([]time.Time, len(near))
+		matched := make([]bool, len(near))
+		starts := make([]int, len(near)) // First deposit in each one's window
+		for i, d := range near {
+			times[i] = d.CreatedAt
+			start := i
+			for start > 0 && d.CreatedAt.Sub(near[start-1].CreatedAt) < window {
+				start--
+			}
+			starts[i] = start
+			matched[i] = i-start+1 >= rule.MinCount
+		}
+
+		for i, d := range near {
+			if !matched[i] || !inRange(d.CreatedAt) || !firstInWindow(times, matched, i, window) {
+				continue
+			}
+			total := 0.0
+			txIDs := []string{}
+			for _, e := range near[starts[i] : i+1] {
+				total += e.Amount
+				txIDs = append(txIDs, e.TransactionID)
+			}
+			count := i - starts[i] + 1
+			cases = append(cases, &Case{
+				RuleID:      RuleStructuring,
+				UserID:      user,
+				TriggeredAt: d.CreatedAt,
+				DedupeKey:   fmt.Sprintf("%s:%g", d.TransactionID, threshold),
+				Evidence: Evidence{
+					Summary:      fmt.Sprintf("%d deposits totalling %.2f just under %.2f within %dh", count, total, threshold, rule.WindowHours),
+					Transactions: txIDs,
+					Figures:      map[string]float64{"threshold": threshold, "count": float64(count), "total": total},
+				},
+			})
+		}
+	}
+	return cases
+}
+
+// pairSession is what one P2P session moved between two users, a < b
+type pairSession struct {
+	session string
+	end     time.Time
+	aToB    float64
+	bToA    float64
+	txIDs   []string
+}
+
+func (r *RuleSet) roundTrips(entries []models.LedgerEntry, inRange func(time.Time) bool) []*Case {
+	rule := r.RoundTrip
+	window := time.Duration(rule.WindowHours) * time.Hour
+	games := make(map[string]bool, len(rule.Games))
+	for _, g := range rule.Games {
+		games[g] = true
+	}
+
+	// Each session's net result per player, refunds included
+	type session struct {
+		id    string
+		end   time.Time
+		nets  map[string]float64
+		txIDs map[string][]string
+	}
+	sessions := make(map[string]*session)
+	order := []string{}
+	for _, e := range entries {
+		game := strings.TrimSuffix(e.ReferenceType, "_REFUND")
+		if !games[game] || e.ReferenceID == "" {
+			continue
+		}
+		key := game + ":" + e.ReferenceID
+		s, ok := sessions[key]
+		if !ok {
+			s = &session{id: key, nets: make(map[string]float64), txIDs: make(map[string][]string)}
+			sessions[key] = s
+			order = append(order, key)
+		}
+		s.nets[e.UserID] += e.Amount
+		s.txIDs[e.UserID] = append(s.txIDs[e.UserID], e.TransactionID)
+		if e.CreatedAt.After(s.end) {
+			s.end = e.CreatedAt
+		}
+	}
+
+	// Split each loser's loss across the winners in proportion to their winnings
+	pairs := make(map[[2]string][]pairSession)
+	for _, key := range order {
+		s := sessions[key]
+		won := 0.0
+		for _, net := range s.nets {
+			if net > 0 {
+				won += net
+			}
+		}
+		if won == 0 {
+			continue
+		}
+		for loser, lost := range s.nets {
+			if lost >= 0 {
+				continue
+			}
+			for winner, net := range s.nets {
+				if net <= 0 {
+					continue
+				}
+				flow := -lost * net / won
+				pair := [2]string{loser, winner}
+				ps := pairSession{session: s.id, end: s.end, aToB: flow}
+				if winner < loser {
+					pair = [2]string{winner, loser}
+					ps = pairSession{session: s.id, end: s.end, bToA: flow}
+				}
+				ps.txIDs = append(append([]string{}, s.txIDs[loser]...), s.txIDs[winner]...)
+				pairs[pair] = append(pairs[pair], ps)
+			}
+		}
+	}
+
+	keys := make([][2]string, 0, len(pairs))
+	for pair := range pairs {
+		keys = append(keys, pair)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	cases := []*Case{}
+	for _, pair := range keys {
+		played := pairs[pair]
+		sort.SliceStable(played, func(i, j int) bool { return played[i].end.Before(played[j].end) })
+
+		times := make([]time.Time, len(played))
+		matched := make([]bool, len(played))
+		starts := make([]int, len(played))
+		sums := make([][2]float64, len(played))
+		for i, ps := range played {
+			times[i] = ps.end
+			start := i
+			for start > 0 && ps.end.Sub(played[start-1].end) < window {
+				start--
+			}
+			starts[i] = start
+			for _, p := range played[start : i+1] {
+				sums[i][0] += p.aToB
+				sums[i][1] += p.bToA
+			}
+			matched[i] = i-start+1 >= rule.MinSessions && sums[i][0] >= rule.MinAmount && sums[i][1] >= rule.MinAmount
+		}
+
+		for i, ps := range played {
+			if !matched[i] || !inRange(ps.end) || !firstInWindow(times, matched, i, window) {
+				continue
+			}
+			txIDs := []string{}
+			for _, p := range played[starts[i] : i+1] {
+				txIDs = append(txIDs, p.txIDs...)
+			}
+			count := i - starts[i] + 1
+			cases = append(cases, &Case{
+				RuleID:         RuleRoundTrip,
+				UserID:         pair[0],
+				CounterpartyID: pair[1],
+				TriggeredAt:    ps.end,
+				DedupeKey:      pair[0] + "|" + pair[1] + ":" + ps.session,
+				Evidence: Evidence{
+					Summary: fmt.Sprintf("%.2f won by the counterparty and %.2f won back over %d sessions within %dh",
+						sums[i][0], sums[i][1], count, rule.WindowHours),
+					Transactions: txIDs,
+					Figures:      map[string]float64{"to_counterparty": sums[i][0], "from_counterparty": sums[i][1], "sessions": float64(count)},
+				},
+			})
+		}
+	}
+	return cases
+}
+
+func (r *RuleSet) dormancySpike(user string, es []models.LedgerEntry, lastActive time.Time, inRange func(time.Time) bool) []*Case {
+	rule := r.DormancySpike
+	dormant := time.Duration(rule.DormantDays) * 24 * time.Hour
+	spike := time.Duration(rule.SpikeWindowHours) * time.Hour
+	cases := []*Case{}
+	for i, e := range es {
+		previous := lastActive
+		if i > 0 {
+			previous = es[i-1].CreatedAt
+		}
+		if previous.IsZero() || e.CreatedAt.Sub(previous) < dormant {
+			continue // A new account, or not dormant
+		}
+
+		volume := 0.0
+		txIDs := []string{}
+		for _, next := range es[i:] {
+			if next.CreatedAt.Sub(e.CreatedAt) > spike {
+				break
+			}
+			if !isDeposit(next) && !isWithdrawal(next) && !isStake(next) {
+				continue
+			}
+			volume += math.Abs(next.Amount)
+			txIDs = append(txIDs, next.TransactionID)
+			if volume < rule.MinVolume {
+				continue
+			}
+			if inRange(next.CreatedAt) {
+				idle := e.CreatedAt.Sub(previous).Hours() / 24
+				cases = append(cases, &Case{
+					RuleID:      RuleDormancySpike,
+					UserID:      user,
+					TriggeredAt: next.CreatedAt,
+					DedupeKey:   e.TransactionID,
+					Evidence: Evidence{
+						Summary:      fmt.Sprintf("Moved %.2f within %dh of returning after %.0f days without activity", volume, rule.SpikeWindowHours, idle),
+						Transactions: txIDs,
+						Figures:      map[string]float64{"dormant_days": idle, "volume": volume},
+					},
+				})
+			}
+			break
+		}
+	}
+	return cases
+}
@@ -0,0 +1,257 @@
+package aml
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/playkaro/payment-service/internal/models"
+)
+
+var t0 = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+func at(hours int) time.Time {
+	return t0.Add(time.Duration(hours) * time.Hour)
+}
+
+func entry(id, user, typ string, amount float64, hours int) []models.LedgerEntry {
+	return []models.LedgerEntry{{ID: id, TransactionID: id, UserID: user, Type: typ, Amount: amount, CreatedAt: at(hours)}}
+}
+
+func deposit(id, user string, amount float64, hours int) []models.LedgerEntry {
+	return entry(id, user, models.TxTypeDeposit, amount, hours)
+}
+
+func withdrawal(id, user string, amount float64, hours int) []models.LedgerEntry {
+	return entry(id, user, models.TxTypeWithdrawal, -amount, hours)
+}
+
+func bet(id, user string, amount float64, hours int) []models.LedgerEntry {
+	return entry(id, user, models.TxTypeBet, -amount, hours)
+}
+
+// duel is a P2P session in which winner took amount off loser
+func duel(game, session, winner, loser string, amount float64, hours int) []models.LedgerEntry {
+	lost := entry(session+"-"+loser, loser, models.TxTypeBet, -amount, hours)[0]
+	won := entry(session+"-"+winner, winner, models.TxTypeWin, amount, hours)[0]
+	lost.ReferenceType, lost.ReferenceID = game, session
+	won.ReferenceType, won.ReferenceID = game, session
+	return []models.LedgerEntry{lost, won}
+}
+
+func join(parts ...[]models.LedgerEntry) []models.LedgerEntry {
+	entries := []models.LedgerEntry{}
+	for _, part := range parts {
+		entries = append(entries, part...)
+	}
+	return entries
+}
+
+// only is the default rule set with every other rule disabled
+func only(rule string) *RuleSet {
+	r := DefaultRuleSet()
+	r.DepositWithdrawal.Enabled = rule == RuleDepositWithdrawal
+	r.Structuring.Enabled = rule == RuleStructuring
+	r.RoundTrip.Enabled = rule == RuleRoundTrip
+	r.DormancySpike.Enabled = rule == RuleDormancySpike
+	return r
+}
+
+type ruleTest struct {
+	name       string
+	entries    []models.LedgerEntry
+	lastActive map[string]time.Time
+	from       int      // Hours after t0; the range runs for 180 days from t0
+	want       []string // Dedupe keys, in order
+}
+
+func runRuleTests(t *testing.T, rule string, tests []ruleTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := only(rule).Evaluate(tt.entries, tt.lastActive, at(tt.from), t0.AddDate(0, 0, 180))
+			keys := []string{}
+			for _, c := range cases {
+				keys = append(keys, c.DedupeKey)
+				if c.RuleID != rule || c.RuleVersion != 1 || c.Status != CaseOpen {
+					t.Errorf("case %s: rule %s v%d %s", c.DedupeKey, c.RuleID, c.RuleVersion, c.Status)
+				}
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Fatalf("cases %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepositWithdrawalRule(t *testing.T) {
+	runRuleTests(t, RuleDepositWithdrawal, []ruleTest{
+		{name: "withdrawn after minimal play",
+			entries: join(deposit("d1", "u1", 6000, 0), bet("b1", "u1", 1000, 1), withdrawal("w1", "u1", 5000, 10)),
+			want:    []string{"w1"}},
+		{name: "enough play",
+			entries: join(deposit("d1", "u1", 6000, 0), bet("b1", "u1", 2000, 1), withdrawal("w1", "u1", 4000, 10)),
+			want:    []string{}},
+		{name: "deposits too small",
+			entries: join(deposit("d1", "u1", 4000, 0), withdrawal("w1", "u1", 3500, 10)),
+			want:    []string{}},
+		{name: "most of it left in the wallet",
+			entries: join(deposit("d1", "u1", 6000, 0), withdrawal("w1", "u1", 4000, 10)),
+			want:    []string{}},
+		{name: "deposits summed within the window",
+			entries: join(deposit("d1", "u1", 3000, 0), deposit("d2", "u1", 3000, 48), withdrawal("w1", "u1", 5000, 60)),
+			want:    []string{"w1"}},
+		{name: "deposit before the window",
+			entries: join(deposit("d1", "u1", 6000, 0), withdrawal("w1", "u1", 5000, 80)),
+			want:    []string{}},
+		{name: "stakes before the deposits don't count",
+			entries: join(bet("b1", "u1", 5000, 0), deposit("d1", "u1", 6000, 1), withdrawal("w1", "u1", 5000, 10)),
+			want:    []string{"w1"}},
+		{name: "withdrawal before the range",
+			entries: join(deposit("d1", "u1", 6000, 0), withdrawal("w1", "u1", 5000, 10)),
+			from:    11,
+			want:    []string{}},
+	})
+}
+
+func TestStructuringRule(t *testing.T) {
+	runRuleTests(t, RuleStructuring, []ruleTest{
+		{name: "three just under a threshold",
+			entries: join(deposit("d1", "u1", 9500, 0), deposit("d2", "u1", 9200, 24), deposit("d3", "u1", 9900, 48)),
+			want:    []string{"d3:10000"}},
+		{name: "a run raises one case",
+			entries: join(deposit("d1", "u1", 9500, 0), deposit("d2", "u1", 9500, 24), deposit("d3", "u1", 9500, 48),
+				deposit("d4", "u1", 9500, 72), deposit("d5", "u1", 9500, 96)),
+			want: []string{"d3:10000"}},
+		{name: "a new run after a quiet window",
+			entries: join(deposit("d1", "u1", 9500, 0), deposit("d2", "u1", 9500, 24), deposit("d3", "u1", 9500, 48),
+				deposit("d4", "u1", 9500, 300), deposit("d5", "u1", 9500, 324), deposit("d6", "u1", 9500, 348)),
+			want: []string{"d3:10000", "d6:10000"}},
+		{name: "the run started before the range",
+			entries: join(deposit("d1", "u1", 9500, 0), deposit("d2", "u1", 9500, 24), deposit("d3", "u1", 9500, 48),
+				deposit("d4", "u1", 9500, 72)),
+			from: 60,
+			want: []string{}},
+		{name: "at the threshold is not under it",
+			entries: join(deposit("d1", "u1", 10000, 0), deposit("d2", "u1", 10000, 24), deposit("d3", "u1", 10000, 48)),
+			want:    []string{}},
+		{name: "below the margin",
+			entries: join(deposit("d1", "u1", 8900, 0), deposit("d2", "u1", 8900, 24), deposit("d3", "u1", 8900, 48)),
+			want:    []string{}},
+		{name: "spread beyond the window",
+			entries: join(deposit("d1", "u1", 9500, 0), deposit("d2", "u1", 9500, 100), deposit("d3", "u1", 9500, 200)),
+			want:    []string{}},
+		{name: "each threshold",
+			entries: join(deposit("d1", "u1", 47000, 0), deposit("d2", "u1", 46000, 24), deposit("d3", "u1", 49999, 48)),
+			want:    []string{"d3:50000"}},
+		{name: "withdrawals are not deposits",
+			entries: join(withdrawal("w1", "u1", 9500, 0), withdrawal("w2", "u1", 9500, 24), withdrawal("w3", "u1", 9500, 48)),
+			want:    []string{}},
+	})
+}
+
+func TestRoundTripRule(t *testing.T) {
+	runRuleTests(t, RuleRoundTrip, []ruleTest{
+		{name: "money won back and forth",
+			entries: join(duel("GAME_LUDO", "s1", "a", "b", 1500, 0), duel("GAME_LUDO", "s2", "b", "a", 2500, 10),
+				duel("GAME_LUDO", "s3", "a", "b", 1000, 20)),
+			want: []string{"a|b:GAME_LUDO:s3"}},
+		{name: "a run raises one case",
+			entries: join(duel("GAME_LUDO", "s1", "a", "b", 1500, 0), duel("GAME_LUDO", "s2", "b", "a", 2500, 10),
+				duel("GAME_LUDO", "s3", "a", "b", 1000, 20), duel("GAME_RUMMY", "s4", "b", "a", 3000, 30)),
+			want: []string{"a|b:GAME_LUDO:s3"}},
+		{name: "pairs are keyed in order",
+			entries: join(duel("GAME_LUDO", "s1", "z", "c", 1500, 0), duel("GAME_LUDO", "s2", "c", "z", 2500, 10),
+				duel("GAME_LUDO", "s3", "z", "c", 1000, 20)),
+			want: []string{"c|z:GAME_LUDO:s3"}},
+		{name: "one way only",
+			entries: join(duel("GAME_LUDO", "s1", "a", "b", 3000, 0), duel("GAME_LUDO", "s2", "a", "b", 3000, 10),
+				duel("GAME_LUDO", "s3", "a", "b", 3000, 20)),
+			want: []string{}},
+		{name: "too few sessions",
+			entries: join(duel("GAME_LUDO", "s1", "a", "b", 3000, 0), duel("GAME_LUDO", "s2", "b", "a", 3000, 10)),
+			want:    []string{}},
+		{name: "sessions beyond the window",
+			entries: join(duel("GAME_LUDO", "s1", "a", "b", 1500, 0), duel("GAME_LUDO", "s2", "b", "a", 2500, 30),
+				duel("GAME_LUDO", "s3", "a", "b", 1000, 60)),
+			want: []string{}},
+		{name: "games not monitored",
+			entries: join(duel("GAME_CRASH", "s1", "a", "b", 1500, 0), duel("GAME_CRASH", "s2", "b", "a", 2500, 10),
+				duel("GAME_CRASH", "s3", "a", "b", 1000, 20)),
+			want: []string{}},
+	})
+}
+
+func TestDormancySpikeRule(t *testing.T) {
+	longAgo := map[string]time.Time{"u1": t0.AddDate(0, 0, -100)}
+	runRuleTests(t, RuleDormancySpike, []ruleTest{
+		{name: "back after months and moving money",
+			entries:    join(deposit("d1", "u1", 20000, 0), withdrawal("w1", "u1", 10000, 5)),
+			lastActive: longAgo,
+			want:       []string{"d1"}},
+		{name: "not dormant long enough",
+			entries:    join(deposit("d1", "u1", 20000, 0), withdrawal("w1", "u1", 10000, 5)),
+			lastActive: map[string]time.Time{"u1": t0.AddDate(0, 0, -30)},
+			want:       []string{}},
+		{name: "a new account",
+			entries: join(deposit("d1", "u1", 20000, 0), withdrawal("w1", "u1", 10000, 5)),
+			want:    []string{}},
+		{name: "volume spread beyond the window",
+			entries:    join(deposit("d1", "u1", 20000, 0), withdrawal("w1", "u1", 10000, 80)),
+			lastActive: longAgo,
+			want:       []string{}},
+		{name: "winnings are not volume",
+			entries:    join(deposit("d1", "u1", 20000, 0), entry("g1", "u1", models.TxTypeWin, 10000, 5)),
+			lastActive: longAgo,
+			want:       []string{}},
+		{name: "a gap within the entries",
+			entries: join(bet("b1", "u1", 10, 0), deposit("d1", "u1", 30000, 91*24)),
+			want:    []string{"d1"}},
+		{name: "spike completed before the range",
+			entries:    join(deposit("d1", "u1", 20000, 0), withdrawal("w1", "u1", 10000, 5), bet("b1", "u1", 5000, 6)),
+			lastActive: longAgo,
+			from:       6,
+			want:       []string{}},
+	})
+}
+
+func TestFirstInWindow(t *testing.T) {
+	hours := func(hs ...int) []time.Time {
+		times := []time.Time{}
+		for _, h := range hs {
+			times = append(times, at(h))
+		}
+		return times
+	}
+	tests := []struct {
+		name    string
+		times   []time.Time
+		matched []bool
+		i       int
+		want    bool
+	}{
+		{"only match", hours(0), []bool{true}, 0, true},
+		{"earlier match within the window", hours(0, 10), []bool{true, true}, 1, false},
+		{"earlier match a window before", hours(0, 24), []bool{true, true}, 1, true},
+		{"earlier unmatched within the window", hours(0, 10), []bool{false, true}, 1, true},
+		{"match behind an unmatched one", hours(0, 5, 10), []bool{true, false, true}, 2, false},
+		{"match beyond the window behind an unmatched one", hours(0, 20, 30), []bool{true, false, true}, 2, true},
+	}
+	for _, tt := range tests {
+		if got := firstInWindow(tt.times, tt.matched, tt.i, 24*time.Hour); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLookbackCoversEveryRule(t *testing.T) {
+	r := DefaultRuleSet()
+	if got := r.Lookback(); got != 2*7*24*time.Hour {
+		t.Fatalf("default lookback %s, want two structuring windows", got)
+	}
+	r.Structuring.WindowHours = 1
+	r.DormancySpike.SpikeWindowHours = 500
+	if got := r.Lookback(); got != 500*time.Hour {
+		t.Fatalf("lookback %s, want the dormancy spike window", got)
+	}
+}
@@ -0,0 +1,379 @@
+package aml
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/playkaro/payment-service/internal/models"
+)
+
+var ErrCaseNotFound = errors.New("aml case not found")
+
+// LedgerSource reads the ledger for scans
+type LedgerSource interface {
+	// Entries returns the entries made in [from, to), oldest first
+	Entries(ctx context.Context, from, to time.Time) ([]models.LedgerEntry, error)
+	// LastActivity returns each user's latest entry before a time
+	LastActivity(ctx context.Context, userIDs []string, before time.Time) (map[string]time.Time, error)
+}
+
+// SQLLedger reads the wallet ledger table
+type SQLLedger struct {
+	DB *sql.DB
+}
+
+func (l *SQLLedger) Entries(ctx context.Context, from, to time.Time) ([]models.LedgerEntry, error) {
+	rows, err := l.DB.QueryContext(ctx, `
+		SELECT id, transaction_id, user_id, type, amount, COALESCE(reference_id, ''), COALESCE(reference_type, ''), balance_after, created_at
+		FROM ledger
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY created_at, transaction_id
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LedgerEntry{}
+	for rows.Next() {
+		var e models.LedgerEntry
+		if err := rows.Scan(&e.ID, &e.TransactionID, &e.UserID, &e.Type, &e.Amount, &e.ReferenceID, &e.ReferenceType, &e.BalanceAfter, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (l *SQLLedger) LastActivity(ctx context.Context, userIDs []string, before time.Time) (map[string]time.Time, error) {
+	last := make(map[string]time.Time, len(userIDs))
+	if len(userIDs) == 0 {
+		return last, nil
+	}
+	rows, err := l.DB.QueryContext(ctx, `
+		SELECT user_id, MAX(created_at) FROM ledger
+		WHERE user_id::text = ANY($1) AND created_at < $2
+		GROUP BY user_id
+	`, pq.Array(userIDs), before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID string
+		var at time.Time
+		if err := rows.Scan(&userID, &at); err != nil {
+			return nil, err
+		}
+		last[userID] = at
+	}
+	return last, rows.Err()
+}
+
+// Monitor scans the ledger after the fact for money laundering patterns,
+// opening a case for compliance on every match
+type Monitor struct {
+	DB     *sql.DB
+	Ledger LedgerSource
+}
+
+func NewMonitor(db *sql.DB) *Monitor {
+	return &Monitor{DB: db, Ledger: &SQLLedger{DB: db}}
+}
+
+// ScanResult is one scan's matches; Created counts the cases that were new
+type ScanResult struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	RuleVersion int       `json:"rule_version"`
+	DryRun      bool      `json:"dry_run"`
+	Matches     []*Case   `json:"matches"`
+	Created     int       `json:"created"`
+}
+
+// Scan evaluates rules over the transactions made in [from, to). Unless
+// dryRun, matches become cases; a match already raised as a case, by an
+// earlier scan or under another rule version, is not raised again.
+func (m *Monitor) Scan(ctx context.Context, rules *RuleSet, from, to time.Time, dryRun bool) (*ScanResult, error) {
+	entries, err := m.Ledger.Entries(ctx, from.Add(-rules.Lookback()), to)
+	if err != nil {
+		return nil, err
+	}
+	users := []string{}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if !seen[e.UserID] {
+			seen[e.UserID] = true
+			users = append(users, e.UserID)
+		}
+	}
+	lastActive, err := m.Ledger.LastActivity(ctx, users, from.Add(-rules.Lookback()))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{From: from, To: to, RuleVersion: rules.Version, DryRun: dryRun}
+	result.Matches = rules.Evaluate(entries, lastActive, from, to)
+	if dryRun {
+		return result, nil
+	}
+
+	for _, c := range result.Matches {
+		created, err := m.createCase(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			result.Created++
+		}
+	}
+	_, err = m.DB.ExecContext(ctx, `
+		INSERT INTO aml_scans (scan_from, scan_to, rule_version, matches, created)
+		VALUES ($1, $2, $3, $4, $5)
+	`, from, to, rules.Version, len(result.Matches), result.Created)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Replay scans historical transactions a day at a time with a given rule
+// version, e.g. to see what a new version would have caught before
+// publishing it
+func (m *Monitor) Replay(ctx context.Context, version int, from, to time.Time, dryRun bool) (*ScanResult, error) {
+	rules, err := m.RuleSet(version)
+	if err != nil {
+		return nil, err
+	}
+	return m.replay(ctx, rules, from, to, dryRun)
+}
+
+func (m *Monitor) replay(ctx context.Context, rules *RuleSet, from, to time.Time, dryRun bool) (*ScanResult, error) {
+	total := &ScanResult{From: from, To: to, RuleVersion: rules.Version, DryRun: dryRun, Matches: []*Case{}}
+	for start := from; start.Before(to); start = start.Add(24 * time.Hour) {
+		end := start.Add(24 * time.Hour)
+		if end.After(to) {
+			end = to
+		}
+		day, err := m.Scan(ctx, rules, start, end, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		total.Matches = append(total.Matches, day.Matches...)
+		total.Created += day.Created
+	}
+	return total, nil
+}
+
+// Run scans new transactions with the latest rules every interval until ctx
+// is cancelled, picking up where the last scan left off
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.scanNew(ctx, interval); err != nil {
+				log.Printf("aml: scan failed: %v", err)
+			}
+		}
+	}
+}
+
+func (m *Monitor) scanNew(ctx context.Context, interval time.Duration) error {
+	to := time.Now()
+	var last sql.NullTime
+	if err := m.DB.QueryRowContext(ctx, "SELECT MAX(scan_to) FROM aml_scans").Scan(&last); err != nil {
+		return err
+	}
+	from := to.Add(-interval)
+	if last.Valid {
+		from = last.Time
+	}
+	// A long outage is caught up a day at a time
+	if to.Sub(from) > 24*time.Hour {
+		to = from.Add(24 * time.Hour)
+	}
+
+	rules, err := m.LatestRuleSet()
+	if err != nil {
+		return err
+	}
+	result, err := m.Scan(ctx, rules, from, to, false)
+	if err != nil {
+		return err
+	}
+	if result.Created > 0 {
+		log.Printf("aml: %d new cases from %s to %s", result.Created, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (m *Monitor) createCase(ctx context.Context, c *Case) (bool, error) {
+	evidence, err := json.Marshal(c.Evidence)
+	if err != nil {
+		return false, err
+	}
+	c.ID = uuid.New().String()
+	c.CreatedAt = time.Now()
+	result, err := m.DB.ExecContext(ctx, `
+		INSERT INTO aml_cases (id, rule_id, rule_version, user_id, counterparty_id, dedupe_key, status, evidence, triggered_at, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10)
+		ON CONFLICT (rule_id, dedupe_key) DO NOTHING
+	`, c.ID, c.RuleID, c.RuleVersion, c.UserID, c.CounterpartyID, c.DedupeKey, c.Status, evidence, c.TriggeredAt, c.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		c.ID = ""
+	}
+	return rows > 0, nil
+}
+
+const caseColumns = `id, rule_id, rule_version, user_id, COALESCE(counterparty_id, ''), dedupe_key, status, evidence, triggered_at,
+	COALESCE(reviewed_by, ''), COALESCE(review_notes, ''), reviewed_at, created_at`
+
+func scanCase(row interface{ Scan(...any) error }) (*Case, error) {
+	var c Case
+	var evidence []byte
+	err := row.Scan(&c.ID, &c.RuleID, &c.RuleVersion, &c.UserID, &c.CounterpartyID, &c.DedupeKey, &c.Status, &evidence,
+		&c.TriggeredAt, &c.ReviewedBy, &c.ReviewNotes, &c.ReviewedAt, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(evidence, &c.Evidence); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListCases returns the compliance queue, oldest first; an empty status lists
+// every open or escalated case
+func (m *Monitor) ListCases(status string, limit int) ([]*Case, error) {
+	statuses := []string{CaseOpen, CaseEscalated}
+	if status != "" {
+		statuses = []string{status}
+	}
+	rows, err := m.DB.Query(`
+		SELECT `+caseColumns+` FROM aml_cases
+		WHERE status = ANY($1)
+		ORDER BY triggered_at, id
+		LIMIT $2
+	`, pq.Array(statuses), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cases := []*Case{}
+	for rows.Next() {
+		c, err := scanCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, rows.Err()
+}
+
+func (m *Monitor) GetCase(caseID string) (*Case, error) {
+	c, err := scanCase(m.DB.QueryRow(`SELECT `+caseColumns+` FROM aml_cases WHERE id = $1`, caseID))
+	if err == sql.ErrNoRows {
+		return nil, ErrCaseNotFound
+	}
+	return c, err
+}
+
+// ReviewCase records a compliance officer's decision on a case
+func (m *Monitor) ReviewCase(caseID, status, reviewer, notes string) (*Case, error) {
+	switch status {
+	case CaseEscalated, CaseDismissed, CaseReported:
+	default:
+		return nil, errors.New("status must be ESCALATED, DISMISSED or REPORTED")
+	}
+	if reviewer == "" {
+		return nil, errors.New("reviewer is required")
+	}
+	result, err := m.DB.Exec(`
+		UPDATE aml_cases SET status = $1, reviewed_by = $2, review_notes = $3, reviewed_at = $4
+		WHERE id = $5 AND status IN ($6, $7)
+	`, status, reviewer, notes, time.Now(), caseID, CaseOpen, CaseEscalated)
+	if err != nil {
+		return nil, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		if _, err := m.GetCase(caseID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("case is already closed")
+	}
+	return m.GetCase(caseID)
+}
+
+// RuleSet returns a published rule version; version 1 is the built-in default
+// unless it has been stored
+func (m *Monitor) RuleSet(version int) (*RuleSet, error) {
+	var data []byte
+	err := m.DB.QueryRow("SELECT rules FROM aml_rule_sets WHERE version = $1", version).Scan(&data)
+	if err == sql.ErrNoRows {
+		if version == 1 {
+			return DefaultRuleSet(), nil
+		}
+		return nil, ErrRulesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rules RuleSet
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LatestRuleSet returns the newest published rules
+func (m *Monitor) LatestRuleSet() (*RuleSet, error) {
+	var version sql.NullInt64
+	if err := m.DB.QueryRow("SELECT MAX(version) FROM aml_rule_sets").Scan(&version); err != nil {
+		return nil, err
+	}
+	if !version.Valid {
+		return DefaultRuleSet(), nil
+	}
+	return m.RuleSet(int(version.Int64))
+}
+
+// PublishRuleSet stores rules as the next version, used by scans from now on
+func (m *Monitor) PublishRuleSet(rules *RuleSet, createdBy string) (*RuleSet, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	latest, err := m.LatestRuleSet()
+	if err != nil {
+		return nil, err
+	}
+	rules.Version = latest.Version + 1
+	rules.CreatedBy = createdBy
+	rules.CreatedAt = time.Now()
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+	_, err = m.DB.Exec(`
+		INSERT INTO aml_rule_sets (version, rules, created_by, created_at) VALUES ($1, $2, $3, $4)
+	`, rules.Version, data, createdBy, rules.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
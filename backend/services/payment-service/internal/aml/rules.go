@@ -0,0 +1,151 @@
+package aml
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rule IDs, recorded on every case
+const (
+	RuleDepositWithdrawal = "DEPOSIT_WITHDRAWAL_MINIMAL_PLAY"
+	RuleStructuring       = "STRUCTURING"
+	RuleRoundTrip         = "P2P_ROUND_TRIP"
+	RuleDormancySpike     = "DORMANCY_SPIKE"
+)
+
+var ErrRulesNotFound = errors.New("aml rule set not found")
+
+// DepositWithdrawalRule flags a withdrawal of most of what was recently
+// deposited when little of it was played
+type DepositWithdrawalRule struct {
+	Enabled            bool    `json:"enabled"`
+	WindowHours        int     `json:"window_hours"`         // How far back from the withdrawal deposits count
+	MinDeposits        float64 `json:"min_deposits"`         // Smallest deposit total worth flagging
+	MaxPlayRatio       float64 `json:"max_play_ratio"`       // Staked / deposited below this is minimal play
+	MinWithdrawalRatio float64 `json:"min_withdrawal_ratio"` // Withdrawn / deposited at or above this
+}
+
+// StructuringRule flags repeated deposits just under a limit, made to stay
+// below it
+type StructuringRule struct {
+	Enabled     bool      `json:"enabled"`
+	Thresholds  []float64 `json:"thresholds"`   // Limits people structure around
+	Margin      float64   `json:"margin"`       // "Just under" is within this fraction of a threshold
+	MinCount    int       `json:"min_count"`    // Deposits just under one threshold within the window
+	WindowHours int       `json:"window_hours"` // Sliding window
+}
+
+// RoundTripRule flags pairs of users passing money back and forth through
+// P2P games: each has won a meaningful amount off the other within the window
+type RoundTripRule struct {
+	Enabled     bool     `json:"enabled"`
+	Games       []string `json:"games"`        // Ledger reference types of P2P games
+	MinAmount   float64  `json:"min_amount"`   // Flow each way
+	MinSessions int      `json:"min_sessions"` // Sessions the pair played together
+	WindowHours int      `json:"window_hours"`
+}
+
+// DormancySpikeRule flags an account that comes back after a long silence and
+// immediately moves a lot of money
+type DormancySpikeRule struct {
+	Enabled          bool    `json:"enabled"`
+	DormantDays      int     `json:"dormant_days"`       // No ledger activity for at least this long
+	SpikeWindowHours int     `json:"spike_window_hours"` // From the first transaction back
+	MinVolume        float64 `json:"min_volume"`         // Deposits, stakes and withdrawals, in total
+}
+
+// RuleSet is one version of the AML rules. Versions are never changed once
+// published, so a case can always be traced to the exact thresholds that
+// raised it.
+type RuleSet struct {
+	Version           int                   `json:"version"`
+	DepositWithdrawal DepositWithdrawalRule `json:"deposit_withdrawal"`
+	Structuring       StructuringRule       `json:"structuring"`
+	RoundTrip         RoundTripRule         `json:"round_trip"`
+	DormancySpike     DormancySpikeRule     `json:"dormancy_spike"`
+	CreatedBy         string                `json:"created_by"`
+	CreatedAt         time.Time             `json:"created_at"`
+}
+
+// DefaultRuleSet is version 1, used until a newer version is published
+func DefaultRuleSet() *RuleSet {
+	return &RuleSet{
+		Version: 1,
+		DepositWithdrawal: DepositWithdrawalRule{
+			Enabled:            true,
+			WindowHours:        72,
+			MinDeposits:        5000,
+			MaxPlayRatio:       0.3,
+			MinWithdrawalRatio: 0.7,
+		},
+		Structuring: StructuringRule{
+			Enabled:     true,
+			Thresholds:  []float64{10000, 50000}, // The fraud detector's new-user flag and daily limit
+			Margin:      0.1,
+			MinCount:    3,
+			WindowHours: 7 * 24,
+		},
+		RoundTrip: RoundTripRule{
+			Enabled:     true,
+			Games:       []string{"GAME_LUDO", "GAME_TEENPATTI", "GAME_RUMMY"},
+			MinAmount:   2000,
+			MinSessions: 3,
+			WindowHours: 48,
+		},
+		DormancySpike: DormancySpikeRule{
+			Enabled:          true,
+			DormantDays:      90,
+			SpikeWindowHours: 72,
+			MinVolume:        25000,
+		},
+	}
+}
+
+// Validate checks every enabled rule can be evaluated
+func (r *RuleSet) Validate() error {
+	if dw := r.DepositWithdrawal; dw.Enabled {
+		if dw.WindowHours < 1 || dw.MinDeposits <= 0 {
+			return errors.New("deposit_withdrawal: window and minimum deposits must be positive")
+		}
+		if dw.MaxPlayRatio < 0 || dw.MinWithdrawalRatio <= 0 || dw.MinWithdrawalRatio > 1 {
+			return errors.New("deposit_withdrawal: bad ratios")
+		}
+	}
+	if st := r.Structuring; st.Enabled {
+		if len(st.Thresholds) == 0 || st.MinCount < 2 || st.WindowHours < 1 {
+			return errors.New("structuring: needs thresholds, a count of at least 2 and a window")
+		}
+		if st.Margin <= 0 || st.Margin >= 1 {
+			return errors.New("structuring: margin must be between 0 and 1")
+		}
+		for _, t := range st.Thresholds {
+			if t <= 0 {
+				return fmt.Errorf("structuring: bad threshold %g", t)
+			}
+		}
+	}
+	if rt := r.RoundTrip; rt.Enabled {
+		if len(rt.Games) == 0 || rt.MinAmount <= 0 || rt.MinSessions < 2 || rt.WindowHours < 1 {
+			return errors.New("round_trip: needs games, an amount, at least 2 sessions and a window")
+		}
+	}
+	if ds := r.DormancySpike; ds.Enabled {
+		if ds.DormantDays < 1 || ds.SpikeWindowHours < 1 || ds.MinVolume <= 0 {
+			return errors.New("dormancy_spike: days, window and volume must be positive")
+		}
+	}
+	return nil
+}
+
+// Lookback is how much ledger history before a scan's start the rules need
+// to judge the transactions in it
+func (r *RuleSet) Lookback() time.Duration {
+	hours := max(
+		r.DepositWithdrawal.WindowHours,
+		2*r.Structuring.WindowHours, // To know whether an earlier deposit already raised a case
+		2*r.RoundTrip.WindowHours,
+		r.DormancySpike.SpikeWindowHours,
+	)
+	return time.Duration(hours) * time.Hour
+}
@@ -0,0 +1,59 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// testBlobStore checks the BlobStore contract every store must keep
+func testBlobStore(t *testing.T, store BlobStore) {
+	t.Helper()
+	ctx := context.Background()
+	key := "kyc/req-1/doc-1"
+
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("get before put: err = %v, want ErrBlobNotFound", err)
+	}
+
+	data := []byte{0x00, 0xff, 'p', 'n', 'g', '\n'}
+	if err := store.Put(ctx, key, data); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	got, err := store.Get(ctx, key)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("get = %q, %v, want %q", got, err, data)
+	}
+
+	if err := store.Put(ctx, key, []byte("replaced")); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	if got, _ := store.Get(ctx, key); string(got) != "replaced" {
+		t.Fatalf("get after overwrite = %q", got)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("get after delete: err = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("deleting a missing blob: %v", err)
+	}
+
+	for _, bad := range []string{"", "/kyc/doc", "kyc/", "kyc//doc", "kyc/../secrets", "./doc", "KYC/doc", "kyc/doc?x=1", "kyc/doc name"} {
+		if err := store.Put(ctx, bad, data); err == nil {
+			t.Errorf("put accepted key %q", bad)
+		}
+	}
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testBlobStore(t, store)
+}
@@ -0,0 +1,323 @@
+package compliance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Document upload limits
+const (
+	MaxDocumentSize        = 5 << 20 // 5 MB per file
+	MaxDocumentsPerRequest = 4       // e.g. front and back of a card
+)
+
+// Accepted document content types, as sniffed from the file itself
+var documentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+// Default document retention once a request is reviewed. Verified documents
+// are kept for the five years PMLA requires; rejected ones only long enough
+// to handle an appeal.
+const (
+	DefaultVerifiedRetention = 5 * 365 * 24 * time.Hour
+	DefaultRejectedRetention = 90 * 24 * time.Hour
+)
+
+// DocumentURLTTL is how long a reviewer's signed document URL stays valid
+const DocumentURLTTL = 5 * time.Minute
+
+var (
+	ErrDocumentNotFound = errors.New("document not found")
+	ErrBadSignature     = errors.New("invalid or expired document url")
+)
+
+// DocumentFile is an uploaded ID image or PDF, before storage
+type DocumentFile struct {
+	Name        string
+	ContentType string // As declared by the client; checked against the content
+	Data        []byte
+}
+
+// KYCDocument is a stored file belonging to a KYC request. The file itself is
+// encrypted in the blob store under BlobKey.
+type KYCDocument struct {
+	ID          string     `json:"id" db:"id"`
+	RequestID   string     `json:"request_id" db:"request_id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	FileName    string     `json:"file_name" db:"file_name"`
+	ContentType string     `json:"content_type" db:"content_type"`
+	Size        int        `json:"size" db:"size"`
+	SHA256      string     `json:"sha256" db:"sha256"` // Of the plaintext, for audits
+	BlobKey     string     `json:"-" db:"blob_key"`
+	DeleteAfter *time.Time `json:"delete_after,omitempty" db:"delete_after"` // Set on review
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	URL         string     `json:"url,omitempty" db:"-"` // Signed, for reviewers
+}
+
+// validateDocument checks a file's size and type, returning the sniffed
+// content type
+func validateDocument(f DocumentFile) (string, error) {
+	if len(f.Data) == 0 {
+		return "", fmt.Errorf("%s is empty", f.Name)
+	}
+	if len(f.Data) > MaxDocumentSize {
+		return "", fmt.Errorf("%s is larger than %d MB", f.Name, MaxDocumentSize>>20)
+	}
+	sniffed := http.DetectContentType(f.Data)
+	if !documentContentTypes[sniffed] {
+		return "", fmt.Errorf("%s: only JPEG, PNG and PDF files are accepted", f.Name)
+	}
+	if f.ContentType != "" && f.ContentType != "application/octet-stream" && f.ContentType != sniffed {
+		return "", fmt.Errorf("%s is %s, not %s", f.Name, sniffed, f.ContentType)
+	}
+	return sniffed, nil
+}
+
+// putDocuments encrypts and stores a request's files. On error, any already
+// stored are removed again.
+func (s *KYCService) putDocuments(ctx context.Context, req *KYCRequest, files []DocumentFile) ([]*KYCDocument, error) {
+	docs := make([]*KYCDocument, 0, len(files))
+	for _, f := range files {
+		contentType, err := validateDocument(f)
+		if err != nil {
+			s.deleteBlobs(ctx, docs)
+			return nil, err
+		}
+		doc := &KYCDocument{
+			ID:          uuid.New().String(),
+			RequestID:   req.ID,
+			UserID:      req.UserID,
+			FileName:    f.Name,
+			ContentType: contentType,
+			Size:        len(f.Data),
+			SHA256:      sha256Hex(f.Data),
+			CreatedAt:   req.CreatedAt,
+		}
+		doc.BlobKey = "kyc/" + req.ID + "/" + doc.ID
+
+		encrypted, err := EncryptPII(string(f.Data))
+		if err != nil {
+			s.deleteBlobs(ctx, docs)
+			return nil, err
+		}
+		if err := s.Blobs.Put(ctx, doc.BlobKey, []byte(encrypted)); err != nil {
+			s.deleteBlobs(ctx, docs)
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func (s *KYCService) deleteBlobs(ctx context.Context, docs []*KYCDocument) {
+	for _, doc := range docs {
+		if err := s.Blobs.Delete(ctx, doc.BlobKey); err != nil {
+			log.Printf("kyc: failed to delete blob %s: %v", doc.BlobKey, err)
+		}
+	}
+}
+
+// ListDocuments returns a request's documents, each with a signed URL valid
+// for DocumentURLTTL
+func (s *KYCService) ListDocuments(requestID string) ([]*KYCDocument, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, request_id, user_id, file_name, content_type, size, sha256, blob_key, delete_after, deleted_at, created_at
+		FROM kyc_documents
+		WHERE request_id = $1
+		ORDER BY created_at, id
+	`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	expires := time.Now().Add(DocumentURLTTL)
+	docs := []*KYCDocument{}
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		if doc.DeletedAt == nil {
+			doc.URL = s.DocumentURL(doc.ID, expires)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// DocumentURL returns a URL that serves a document until expires, without
+// any other authentication
+func (s *KYCService) DocumentURL(documentID string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	q := url.Values{"expires": {exp}, "signature": {s.documentSignature(documentID, exp)}}
+	return s.BaseURL + "/v1/payments/kyc/documents/" + url.PathEscape(documentID) + "/content?" + q.Encode()
+}
+
+func (s *KYCService) documentSignature(documentID, expires string) string {
+	mac := hmac.New(sha256.New, s.URLKey)
+	mac.Write([]byte(documentID + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkDocumentURL accepts a signed URL's expiry and signature if the URL is
+// still valid at now
+func (s *KYCService) checkDocumentURL(documentID, expires, signature string, now time.Time) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > exp {
+		return ErrBadSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.documentSignature(documentID, expires))) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// OpenDocument checks a signed URL's expiry and signature and returns the
+// decrypted document
+func (s *KYCService) OpenDocument(ctx context.Context, documentID, expires, signature string) (*KYCDocument, []byte, error) {
+	if err := s.checkDocumentURL(documentID, expires, signature, time.Now()); err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := scanDocument(s.DB.QueryRow(`
+		SELECT id, request_id, user_id, file_name, content_type, size, sha256, blob_key, delete_after, deleted_at, created_at
+		FROM kyc_documents WHERE id = $1
+	`, documentID))
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if doc.DeletedAt != nil {
+		return nil, nil, ErrDocumentNotFound
+	}
+
+	encrypted, err := s.Blobs.Get(ctx, doc.BlobKey)
+	if err == ErrBlobNotFound {
+		return nil, nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := DecryptPII(string(encrypted))
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, []byte(plaintext), nil
+}
+
+// scheduleDeletion starts a reviewed request's retention period
+func (s *KYCService) scheduleDeletion(requestID, status string, reviewedAt time.Time) error {
+	retention := s.VerifiedRetention
+	if status == KYCStatusRejected {
+		retention = s.RejectedRetention
+	}
+	_, err := s.DB.Exec(`
+		UPDATE kyc_documents SET delete_after = $1
+		WHERE request_id = $2 AND deleted_at IS NULL
+	`, reviewedAt.Add(retention), requestID)
+	return err
+}
+
+// PurgeExpiredDocuments deletes the files of documents past their retention,
+// keeping the metadata row as a record. It returns how many were deleted.
+func (s *KYCService) PurgeExpiredDocuments(ctx context.Context, now time.Time) (int, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, blob_key FROM kyc_documents
+		WHERE delete_after <= $1 AND deleted_at IS NULL
+	`, now)
+	if err != nil {
+		return 0, err
+	}
+	type expired struct{ id, key string }
+	due := []expired{}
+	for rows.Next() {
+		var e expired
+		if err := rows.Scan(&e.id, &e.key); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		due = append(due, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, e := range due {
+		if err := s.Blobs.Delete(ctx, e.key); err != nil {
+			log.Printf("kyc: failed to delete document %s: %v", e.id, err)
+			continue
+		}
+		if _, err := s.DB.Exec("UPDATE kyc_documents SET deleted_at = $1 WHERE id = $2", now, e.id); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// RunRetention purges expired documents every interval until ctx is cancelled
+func (s *KYCService) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.PurgeExpiredDocuments(ctx, time.Now())
+			if err != nil {
+				log.Printf("kyc: document retention failed: %v", err)
+			} else if n > 0 {
+				log.Printf("kyc: deleted %d documents past retention", n)
+			}
+		}
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDocument(row rowScanner) (*KYCDocument, error) {
+	var doc KYCDocument
+	err := row.Scan(&doc.ID, &doc.RequestID, &doc.UserID, &doc.FileName, &doc.ContentType, &doc.Size,
+		&doc.SHA256, &doc.BlobKey, &doc.DeleteAfter, &doc.DeletedAt, &doc.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// urlSigningKey is the key for signed document URLs
+func urlSigningKey() ([]byte, error) {
+	key := []byte(os.Getenv("KYC_URL_SIGNING_KEY"))
+	if len(key) < 32 {
+		if os.Getenv("APP_ENV") != "production" {
+			return []byte("dev-kyc-url-signing-key-32-bytes"), nil
+		}
+		return nil, errors.New("KYC_URL_SIGNING_KEY must be at least 32 bytes")
+	}
+	return key, nil
+}
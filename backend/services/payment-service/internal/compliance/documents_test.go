@@ -0,0 +1,94 @@
+package compliance
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKYCService() *KYCService {
+	return &KYCService{
+		URLKey:  []byte("test-kyc-url-signing-key-32-bytes"),
+		BaseURL: "https://api.example.com",
+	}
+}
+
+// parseDocumentURL splits a signed URL into the parts OpenDocument receives
+func parseDocumentURL(t *testing.T, raw string) (documentID, expires, signature string) {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/v1/payments/kyc/documents/"), "/content")
+	return path, u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestDocumentURLVerifiesUntilExpiry(t *testing.T) {
+	s := testKYCService()
+	now := time.Unix(1_800_000_000, 0)
+	expires := now.Add(DocumentURLTTL)
+
+	raw := s.DocumentURL("doc-1", expires)
+	if !strings.HasPrefix(raw, "https://api.example.com/v1/payments/kyc/documents/doc-1/content?") {
+		t.Fatalf("url %s", raw)
+	}
+	id, exp, sig := parseDocumentURL(t, raw)
+
+	for _, at := range []time.Time{now, expires} {
+		if err := s.checkDocumentURL(id, exp, sig, at); err != nil {
+			t.Fatalf("at %s: %v", at, err)
+		}
+	}
+	if err := s.checkDocumentURL(id, exp, sig, expires.Add(time.Second)); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("a second after expiry: err = %v, want ErrBadSignature", err)
+	}
+}
+
+func TestDocumentURLRejectsTampering(t *testing.T) {
+	s := testKYCService()
+	now := time.Unix(1_800_000_000, 0)
+	id, exp, sig := parseDocumentURL(t, s.DocumentURL("doc-1", now.Add(DocumentURLTTL)))
+	later := strconv.FormatInt(now.Add(24*time.Hour).Unix(), 10)
+	flipped := []byte(sig)
+	flipped[0] ^= 1
+
+	otherKey := testKYCService()
+	otherKey.URLKey = []byte("another-kyc-url-signing-key-32-b")
+
+	tests := []struct {
+		name             string
+		service          *KYCService
+		id, expires, sig string
+	}{
+		{"other document", s, "doc-2", exp, sig},
+		{"extended expiry", s, id, later, sig},
+		{"flipped signature bit", s, id, exp, string(flipped)},
+		{"truncated signature", s, id, exp, sig[:len(sig)-2]},
+		{"no signature", s, id, exp, ""},
+		{"expiry not a number", s, id, exp + "x", sig},
+		{"no expiry", s, id, "", sig},
+		{"different signing key", otherKey, id, exp, sig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.service.checkDocumentURL(tt.id, tt.expires, tt.sig, now); !errors.Is(err, ErrBadSignature) {
+				t.Fatalf("err = %v, want ErrBadSignature", err)
+			}
+		})
+	}
+}
+
+func TestDocumentSignatureBindsIDAndExpiry(t *testing.T) {
+	s := testKYCService()
+	// The separator keeps "doc-1"+"23" and "doc-12"+"3" apart
+	if s.documentSignature("doc-1", "23") == s.documentSignature("doc-12", "3") {
+		t.Fatal("signatures collide across the ID/expiry boundary")
+	}
+	if s.documentSignature("doc-1", "23") != s.documentSignature("doc-1", "23") {
+		t.Fatal("signature is not deterministic")
+	}
+}
@@ -0,0 +1,215 @@
+package compliance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidDocumentNumber = errors.New("invalid document number")
+
+var panPattern = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+
+// PAN holder types, from the fourth character of a PAN
+var panEntityTypes = map[byte]string{
+	'P': "individual",
+	'C': "company",
+	'H': "Hindu undivided family",
+	'F': "firm",
+	'A': "association of persons",
+	'T': "trust",
+	'B': "body of individuals",
+	'L': "local authority",
+	'J': "artificial juridical person",
+	'G': "government",
+}
+
+// NormalizeDocumentNumber uppercases a document number and drops the spaces
+// and hyphens people type into them
+func NormalizeDocumentNumber(number string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(number)))
+}
+
+// ValidateDocumentNumber checks a normalized number's format. Only an
+// individual's PAN can verify an account.
+func ValidateDocumentNumber(docType, number string) error {
+	switch docType {
+	case DocTypePAN:
+		if !panPattern.MatchString(number) {
+			return fmt.Errorf("%w: a PAN is 5 letters, 4 digits and a letter", ErrInvalidDocumentNumber)
+		}
+		entity, ok := panEntityTypes[number[3]]
+		if !ok {
+			return fmt.Errorf("%w: unknown PAN holder type %q", ErrInvalidDocumentNumber, number[3])
+		}
+		if number[3] != 'P' {
+			return fmt.Errorf("%w: this PAN belongs to a %s; only an individual's PAN can be used", ErrInvalidDocumentNumber, entity)
+		}
+	case DocTypeAadhaar:
+		if len(number) != 12 || strings.Trim(number, "0123456789") != "" {
+			return fmt.Errorf("%w: an Aadhaar number is 12 digits", ErrInvalidDocumentNumber)
+		}
+		if number[0] == '0' || number[0] == '1' {
+			return fmt.Errorf("%w: an Aadhaar number doesn't start with 0 or 1", ErrInvalidDocumentNumber)
+		}
+		if !verhoeffValid(number) {
+			return fmt.Errorf("%w: Aadhaar checksum doesn't match", ErrInvalidDocumentNumber)
+		}
+	default:
+		return errors.New("invalid document type")
+	}
+	return nil
+}
+
+// Verhoeff checksum tables: the dihedral group D5's multiplication table and
+// the position permutations
+var (
+	verhoeffD = [10][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+		{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+		{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+		{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+		{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+		{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+		{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+		{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+		{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	}
+	verhoeffP = [8][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+		{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+		{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+		{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+		{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+		{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+		{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+	}
+)
+
+// verhoeffValid checks a digit string whose last digit is its Verhoeff check
+// digit
+func verhoeffValid(digits string) bool {
+	c := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		c = verhoeffD[c][verhoeffP[i%8][digit]]
+	}
+	return c == 0
+}
+
+// MaskDocumentNumber hides all but the last four characters
+func MaskDocumentNumber(number string) string {
+	if len(number) <= 4 {
+		return strings.Repeat("X", len(number))
+	}
+	return strings.Repeat("X", len(number)-4) + number[len(number)-4:]
+}
+
+// hashDocumentNumber is a keyed hash of a normalized number, so duplicates
+// can be found without storing the number in a form that can be looked up
+func hashDocumentNumber(key []byte, docType, number string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(docType + ":" + number))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// documentHashKey is the key for document number hashes. Changing it breaks
+// duplicate detection against existing requests.
+func documentHashKey() ([]byte, error) {
+	key := []byte(os.Getenv("KYC_HASH_KEY"))
+	if len(key) < 32 {
+		if os.Getenv("APP_ENV") != "production" {
+			return []byte("dev-kyc-document-hash-key-32byte"), nil
+		}
+		return nil, errors.New("KYC_HASH_KEY must be at least 32 bytes")
+	}
+	return key, nil
+}
+
+// Titles dropped before comparing names
+var nameTitles = map[string]bool{"MR": true, "MRS": true, "MS": true, "MISS": true, "DR": true, "SHRI": true, "SMT": true, "KUMARI": true}
+
+func nameTokens(name string) []string {
+	clean := strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' || r == ' ' {
+			return r
+		}
+		if r == '.' || r == ',' || r == '-' {
+			return ' '
+		}
+		return -1
+	}, strings.ToUpper(name))
+	tokens := []string{}
+	for _, t := range strings.Fields(clean) {
+		if !nameTitles[t] {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// NameSimilarity scores how alike two names are, from 0 to 1. Word order,
+// titles and punctuation are ignored, an initial matches the word it stands
+// for, and a missing middle name costs less than a wrong surname.
+func NameSimilarity(a, b string) float64 {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	return (coverage(ta, tb) + coverage(tb, ta)) / 2
+}
+
+// coverage is how well each word of from is matched somewhere in to
+func coverage(from, to []string) float64 {
+	total := 0.0
+	for _, f := range from {
+		best := 0.0
+		for _, t := range to {
+			best = max(best, wordSimilarity(f, t))
+		}
+		total += best
+	}
+	return total / float64(len(from))
+}
+
+func wordSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if (len(a) == 1 || len(b) == 1) && a[0] == b[0] {
+		return 0.9
+	}
+	return 1 - float64(levenshtein(a, b))/float64(max(len(a), len(b)))
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,113 @@
+package compliance
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestVerhoeffValid(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{"2363", true}, // The worked example: 236 has check digit 3
+		{"123451", true},
+		{"1428570", true},
+		{"234123412346", true},
+		{"0", true},
+		{"2364", false},
+		{"3263", false}, // Adjacent digits swapped
+		{"123415", false},
+		{"234123412345", false},
+		{"234123412364", false},
+	}
+	for _, tt := range tests {
+		if got := verhoeffValid(tt.digits); got != tt.want {
+			t.Errorf("verhoeffValid(%s) = %v, want %v", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestValidateDocumentNumber(t *testing.T) {
+	tests := []struct {
+		docType string
+		number  string
+		wantErr string // Empty for a valid number
+	}{
+		{DocTypePAN, "ABCPE1234F", ""},
+		{DocTypePAN, "ABCCE1234F", "belongs to a company"},
+		{DocTypePAN, "ABCHE1234F", "belongs to a Hindu undivided family"},
+		{DocTypePAN, "ABCFE1234F", "belongs to a firm"},
+		{DocTypePAN, "ABCTE1234F", "belongs to a trust"},
+		{DocTypePAN, "ABCGE1234F", "belongs to a government"},
+		{DocTypePAN, "ABCXE1234F", "unknown PAN holder type 'X'"},
+		{DocTypePAN, "ABCPE12345", "5 letters, 4 digits and a letter"},
+		{DocTypePAN, "ABCPE1234", "5 letters, 4 digits and a letter"},
+		{DocTypePAN, "abcpe1234f", "5 letters, 4 digits and a letter"}, // Not normalized
+		{DocTypeAadhaar, "234123412346", ""},
+		{DocTypeAadhaar, "234123412345", "checksum"},
+		{DocTypeAadhaar, "123412341234", "doesn't start with 0 or 1"},
+		{DocTypeAadhaar, "23412341234", "12 digits"},
+		{DocTypeAadhaar, "23412341234A", "12 digits"},
+		{"PASSPORT", "K1234567", "invalid document type"},
+	}
+	for _, tt := range tests {
+		err := ValidateDocumentNumber(tt.docType, tt.number)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s %s: %v", tt.docType, tt.number, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s %s: err = %v, want %q", tt.docType, tt.number, err, tt.wantErr)
+		}
+		if tt.docType != "PASSPORT" && !errors.Is(err, ErrInvalidDocumentNumber) {
+			t.Errorf("%s %s: err = %v, want ErrInvalidDocumentNumber", tt.docType, tt.number, err)
+		}
+	}
+}
+
+func TestNormalizedNumbersValidate(t *testing.T) {
+	if err := ValidateDocumentNumber(DocTypeAadhaar, NormalizeDocumentNumber(" 2341 2341-2346 ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateDocumentNumber(DocTypePAN, NormalizeDocumentNumber("abcpe 1234f")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Rahul Kumar Sharma", "rahul kumar sharma", 1},
+		{"Mr. Sharma, Rahul", "Rahul Sharma", 1}, // Order, title and punctuation
+		{"R. Sharma", "Rahul Sharma", 0.95},      // An initial
+		{"R K Sharma", "Rahul Kumar Sharma", 0.9333},
+		{"Rahul Kumar Sharma", "Rahul Sharma", 0.8611}, // Missing middle name
+		{"Rahul Sharma", "Rahul Sharmaa", 0.9286},      // A typo
+		{"Rahul Sharma", "Rahul Verma", 0.75},          // Different surname
+		{"Priya Nair", "Rahul Sharma", 0.1833},
+		{"Dr.", "Rahul", 0},
+		{"", "Rahul", 0},
+	}
+	for _, tt := range tests {
+		got := NameSimilarity(tt.a, tt.b)
+		if math.Abs(got-tt.want) > 0.0001 {
+			t.Errorf("NameSimilarity(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+		if back := NameSimilarity(tt.b, tt.a); back != got {
+			t.Errorf("NameSimilarity(%q, %q) = %.4f, but %.4f the other way", tt.a, tt.b, got, back)
+		}
+	}
+
+	// The default threshold takes a missing middle name but not another surname
+	if NameSimilarity("Rahul Kumar Sharma", "Rahul Sharma") < DefaultNameMatchThreshold ||
+		NameSimilarity("Rahul Sharma", "Rahul Verma") >= DefaultNameMatchThreshold {
+		t.Fatal("default threshold misjudges middle names or surnames")
+	}
+}
@@ -1,8 +1,11 @@
 package compliance
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,39 +26,115 @@ const (
 
 // KYCRequest represents a user's KYC submission
 type KYCRequest struct {
-	ID             string    `json:"id" db:"id"`
-	UserID         string    `json:"user_id" db:"user_id"`
-	DocumentType   string    `json:"document_type" db:"document_type"`
-	DocumentNumber string    `json:"document_number" db:"document_number"` // Encrypted
-	ImageURL       string    `json:"image_url" db:"image_url"`             // Encrypted
-	Status         string    `json:"status" db:"status"`
-	AdminNotes     string    `json:"admin_notes" db:"admin_notes"`
-	VerifiedAt     *time.Time `json:"verified_at" db:"verified_at"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	ID             string         `json:"id" db:"id"`
+	UserID         string         `json:"user_id" db:"user_id"`
+	DocumentType   string         `json:"document_type" db:"document_type"`
+	DocumentNumber string         `json:"-" db:"document_number"`               // Encrypted
+	MaskedNumber   string         `json:"document_number" db:"document_masked"` // Last four characters only
+	NameMatchScore float64        `json:"name_match_score" db:"name_match_score"`
+	Status         string         `json:"status" db:"status"`
+	AdminNotes     string         `json:"admin_notes" db:"admin_notes"`
+	VerifiedAt     *time.Time     `json:"verified_at" db:"verified_at"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	Documents      []*KYCDocument `json:"documents,omitempty" db:"-"`
 }
 
 type KYCService struct {
-	DB *sql.DB
+	DB       *sql.DB
+	Blobs    BlobStore
+	Verifier NameVerifier
+	Profiles ProfileSource
+
+	HashKey            []byte  // Keys document number hashes
+	NameMatchThreshold float64 // Lowest name match score accepted
+	URLKey             []byte  // Signs reviewers' document URLs
+	BaseURL            string  // Prefixed to document URLs, e.g. https://api.playkaro.com
+	VerifiedRetention  time.Duration
+	RejectedRetention  time.Duration
 }
 
-func NewKYCService(db *sql.DB) *KYCService {
-	return &KYCService{DB: db}
+// DefaultNameMatchThreshold accepts a missing middle name or an initial, but
+// not a different surname
+const DefaultNameMatchThreshold = 0.8
+
+var (
+	ErrDocumentInUse = errors.New("this document is already used by another account")
+	ErrNameMismatch  = errors.New("the name on this document doesn't match your profile")
+)
+
+func NewKYCService(db *sql.DB, blobs BlobStore, verifier NameVerifier, profiles ProfileSource) (*KYCService, error) {
+	key, err := urlSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	hashKey, err := documentHashKey()
+	if err != nil {
+		return nil, err
+	}
+	return &KYCService{
+		DB:                 db,
+		Blobs:              blobs,
+		Verifier:           verifier,
+		Profiles:           profiles,
+		HashKey:            hashKey,
+		NameMatchThreshold: DefaultNameMatchThreshold,
+		URLKey:             key,
+		VerifiedRetention:  DefaultVerifiedRetention,
+		RejectedRetention:  DefaultRejectedRetention,
+	}, nil
 }
 
-// SubmitKYC handles document submission
-func (s *KYCService) SubmitKYC(userID, docType, docNumber, imageURL string) (*KYCRequest, error) {
+// SubmitKYC handles document submission. The number is checked for format, for
+// use by another account and against the profile name with the issuer; the
+// files are encrypted into the blob store and recorded against the request.
+func (s *KYCService) SubmitKYC(ctx context.Context, userID, docType, docNumber string, files []DocumentFile) (*KYCRequest, error) {
 	// Validate inputs
 	if docType != DocTypeAadhaar && docType != DocTypePAN {
 		return nil, errors.New("invalid document type")
 	}
+	if len(files) == 0 {
+		return nil, errors.New("at least one document file is required")
+	}
+	if len(files) > MaxDocumentsPerRequest {
+		return nil, fmt.Errorf("at most %d document files are allowed", MaxDocumentsPerRequest)
+	}
+	docNumber = NormalizeDocumentNumber(docNumber)
+	if err := ValidateDocumentNumber(docType, docNumber); err != nil {
+		return nil, err
+	}
 
-	// Encrypt sensitive data
-	encDocNumber, err := EncryptPII(docNumber)
+	// One document verifies one account
+	docHash := hashDocumentNumber(s.HashKey, docType, docNumber)
+	var otherUsers int
+	err := s.DB.QueryRow(`
+		SELECT COUNT(*) FROM kyc_requests
+		WHERE document_hash = $1 AND user_id <> $2 AND status IN ($3, $4)
+	`, docHash, userID, KYCStatusPending, KYCStatusVerified).Scan(&otherUsers)
 	if err != nil {
 		return nil, err
 	}
-	encImageURL, err := EncryptPII(imageURL)
+	if otherUsers > 0 {
+		return nil, ErrDocumentInUse
+	}
+
+	profileName, err := s.Profiles.FullName(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profileName == "" {
+		return nil, ErrNoProfileName
+	}
+	match, err := s.Verifier.VerifyName(ctx, docType, docNumber, profileName)
+	if err != nil {
+		return nil, err
+	}
+	if match.Score < s.NameMatchThreshold {
+		return nil, ErrNameMismatch
+	}
+
+	// Encrypt sensitive data
+	encDocNumber, err := EncryptPII(docNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -75,41 +154,85 @@ func (s *KYCService) SubmitKYC(userID, docType, docNumber, imageURL string) (*KY
 		UserID:         userID,
 		DocumentType:   docType,
 		DocumentNumber: encDocNumber,
-		ImageURL:       encImageURL,
+		MaskedNumber:   MaskDocumentNumber(docNumber),
+		NameMatchScore: match.Score,
 		Status:         KYCStatusPending,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
-	_, err = s.DB.Exec(`
-		INSERT INTO kyc_requests (id, user_id, document_type, document_number, image_url, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, req.ID, req.UserID, req.DocumentType, req.DocumentNumber, req.ImageURL, req.Status, req.CreatedAt, req.UpdatedAt)
-
+	docs, err := s.putDocuments(ctx, req, files)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.insertRequest(req, docHash, docs); err != nil {
+		s.deleteBlobs(ctx, docs)
+		return nil, err
+	}
+	req.Documents = docs
 
 	return req, nil
 }
 
+func (s *KYCService) insertRequest(req *KYCRequest, docHash string, docs []*KYCDocument) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO kyc_requests (id, user_id, document_type, document_number, document_hash, document_masked, name_match_score, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, req.ID, req.UserID, req.DocumentType, req.DocumentNumber, docHash, req.MaskedNumber, req.NameMatchScore, req.Status, req.CreatedAt, req.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		_, err = tx.Exec(`
+			INSERT INTO kyc_documents (id, request_id, user_id, file_name, content_type, size, sha256, blob_key, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, doc.ID, doc.RequestID, doc.UserID, doc.FileName, doc.ContentType, doc.Size, doc.SHA256, doc.BlobKey, doc.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // VerifyKYC allows admin to approve/reject
 func (s *KYCService) VerifyKYC(requestID, status, notes string) error {
 	if status != KYCStatusVerified && status != KYCStatusRejected {
 		return errors.New("invalid status")
 	}
 
+	now := time.Now()
 	var verifiedAt *time.Time
 	if status == KYCStatusVerified {
-		now := time.Now()
 		verifiedAt = &now
+
+		// Another account may have been verified with it since submission
+		var otherUsers int
+		err := s.DB.QueryRow(`
+			SELECT COUNT(*) FROM kyc_requests r
+			JOIN kyc_requests o ON o.document_hash = r.document_hash AND o.user_id <> r.user_id
+			WHERE r.id = $1 AND o.status = $2
+		`, requestID, KYCStatusVerified).Scan(&otherUsers)
+		if err != nil {
+			return err
+		}
+		if otherUsers > 0 {
+			return ErrDocumentInUse
+		}
 	}
 
 	result, err := s.DB.Exec(`
 		UPDATE kyc_requests
 		SET status = $1, admin_notes = $2, verified_at = $3, updated_at = $4
 		WHERE id = $5
-	`, status, notes, verifiedAt, time.Now(), requestID)
+	`, status, notes, verifiedAt, now, requestID)
 
 	if err != nil {
 		return err
@@ -120,19 +243,76 @@ func (s *KYCService) VerifyKYC(requestID, status, notes string) error {
 		return errors.New("request not found")
 	}
 
-	return nil
+	// Reviewed documents are kept only as long as retention requires
+	return s.scheduleDeletion(requestID, status, now)
+}
+
+// BackfillDocumentHashes hashes and masks the document numbers of pending and
+// verified requests made before hashes were stored (migration 033), so they
+// count towards duplicate detection. Numbers are decrypted here as only the
+// service holds the keys. A number already verified on another request is
+// logged and left unhashed for compliance to resolve.
+func (s *KYCService) BackfillDocumentHashes(ctx context.Context) (int, error) {
+	type unhashed struct{ id, docType, number string }
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT id, document_type, document_number FROM kyc_requests
+		WHERE document_hash IS NULL AND status IN ($1, $2)
+		ORDER BY created_at
+	`, KYCStatusPending, KYCStatusVerified)
+	if err != nil {
+		return 0, err
+	}
+	pending := []unhashed{}
+	for rows.Next() {
+		var r unhashed
+		if err := rows.Scan(&r.id, &r.docType, &r.number); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	filled := 0
+	for _, r := range pending {
+		number, err := DecryptPII(r.number)
+		if err != nil {
+			log.Printf("kyc: request %s: failed to decrypt the document number: %v", r.id, err)
+			continue
+		}
+		number = NormalizeDocumentNumber(number)
+		docHash := hashDocumentNumber(s.HashKey, r.docType, number)
+		result, err := s.DB.ExecContext(ctx, `
+			UPDATE kyc_requests r SET document_hash = $1, document_masked = COALESCE(r.document_masked, $2)
+			WHERE r.id = $3 AND r.document_hash IS NULL AND NOT (r.status = $4 AND EXISTS (
+				SELECT 1 FROM kyc_requests o WHERE o.document_hash = $1 AND o.status = $4
+			))
+		`, docHash, MaskDocumentNumber(number), r.id, KYCStatusVerified)
+		if err != nil {
+			return filled, err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			log.Printf("kyc: request %s: document already verified on another request; left for review", r.id)
+			continue
+		}
+		filled++
+	}
+	return filled, nil
 }
 
 // GetKYCStatus returns the user's current status
 func (s *KYCService) GetKYCStatus(userID string) (*KYCRequest, error) {
 	var req KYCRequest
 	err := s.DB.QueryRow(`
-		SELECT id, user_id, document_type, status, admin_notes, created_at
+		SELECT id, user_id, document_type, COALESCE(document_masked, ''), COALESCE(name_match_score, 0), status, COALESCE(admin_notes, ''), created_at
 		FROM kyc_requests
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT 1
-	`, userID).Scan(&req.ID, &req.UserID, &req.DocumentType, &req.Status, &req.AdminNotes, &req.CreatedAt)
+	`, userID).Scan(&req.ID, &req.UserID, &req.DocumentType, &req.MaskedNumber, &req.NameMatchScore, &req.Status, &req.AdminNotes, &req.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil // No request found
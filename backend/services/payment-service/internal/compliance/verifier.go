@@ -0,0 +1,83 @@
+package compliance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrDocumentNotRegistered = errors.New("document number is not registered with the issuer")
+	ErrNoProfileName         = errors.New("add your full name to your profile before submitting KYC")
+)
+
+// NameMatch is an issuer's record of a document, compared with a name
+type NameMatch struct {
+	RegisteredName string  `json:"-"` // As the issuer has it; PII, never returned
+	Score          float64 `json:"score"`
+}
+
+// NameVerifier checks a document number with its issuer (NSDL for PAN, UIDAI
+// for Aadhaar) through a name-match API, scoring the registered name against
+// the one given
+type NameVerifier interface {
+	VerifyName(ctx context.Context, docType, number, name string) (*NameMatch, error)
+}
+
+// MockVerifier stands in for a name-match provider in development and tests.
+// Numbers in Registered are scored with NameSimilarity; any other valid
+// number is treated as registered to whatever name it is asked about.
+type MockVerifier struct {
+	mu         sync.RWMutex
+	Registered map[string]string // "<doc type>:<number>" -> name
+	Missing    map[string]bool   // Numbers the issuer doesn't know
+}
+
+func NewMockVerifier() *MockVerifier {
+	return &MockVerifier{Registered: make(map[string]string), Missing: make(map[string]bool)}
+}
+
+// Register records the name a document number belongs to
+func (v *MockVerifier) Register(docType, number, name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.Registered[docType+":"+NormalizeDocumentNumber(number)] = name
+}
+
+func (v *MockVerifier) VerifyName(ctx context.Context, docType, number, name string) (*NameMatch, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key := docType + ":" + number
+	if v.Missing[key] {
+		return nil, ErrDocumentNotRegistered
+	}
+	registered, ok := v.Registered[key]
+	if !ok {
+		return &NameMatch{RegisteredName: name, Score: 1}, nil
+	}
+	return &NameMatch{RegisteredName: registered, Score: NameSimilarity(registered, name)}, nil
+}
+
+// ProfileSource looks up the legal name on a user's profile
+type ProfileSource interface {
+	FullName(userID string) (string, error)
+}
+
+// UserProfiles reads profile names from the users table
+type UserProfiles struct {
+	DB *sql.DB
+}
+
+func NewUserProfiles(db *sql.DB) *UserProfiles {
+	return &UserProfiles{DB: db}
+}
+
+func (p *UserProfiles) FullName(userID string) (string, error) {
+	var name string
+	err := p.DB.QueryRow("SELECT COALESCE(full_name, '') FROM users WHERE id = $1", userID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return name, err
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/payment-service/internal/aml"
+)
+
+type AMLHandler struct {
+	Monitor *aml.Monitor
+}
+
+func NewAMLHandler(monitor *aml.Monitor) *AMLHandler {
+	return &AMLHandler{Monitor: monitor}
+}
+
+// ReviewCaseRequest is a compliance officer's decision on a case. The
+// reviewer is the authenticated caller.
+type ReviewCaseRequest struct {
+	Status string `json:"status" binding:"required"` // ESCALATED, DISMISSED, REPORTED
+	Notes  string `json:"notes"`
+}
+
+// PublishRulesRequest publishes a new AML rule version, credited to the
+// authenticated caller
+type PublishRulesRequest struct {
+	Rules aml.RuleSet `json:"rules"`
+}
+
+// ReplayRequest rescans historical transactions with a rule version
+type ReplayRequest struct {
+	Version int       `json:"version" binding:"required"`
+	From    time.Time `json:"from" binding:"required"`
+	To      time.Time `json:"to" binding:"required"`
+	DryRun  bool      `json:"dry_run"`
+}
+
+// ListCases returns the compliance queue (?status=, ?limit=)
+func (h *AMLHandler) ListCases(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 50
+	}
+	cases, err := h.Monitor.ListCases(c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cases": cases})
+}
+
+func (h *AMLHandler) GetCase(c *gin.Context) {
+	amlCase, err := h.Monitor.GetCase(c.Param("case_id"))
+	if err == aml.ErrCaseNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, amlCase)
+}
+
+func (h *AMLHandler) ReviewCase(c *gin.Context) {
+	var req ReviewCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	amlCase, err := h.Monitor.ReviewCase(c.Param("case_id"), req.Status, c.GetString("userID"), req.Notes)
+	if err == aml.ErrCaseNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, amlCase)
+}
+
+// GetRules returns the latest rules, or ?version=
+func (h *AMLHandler) GetRules(c *gin.Context) {
+	var rules *aml.RuleSet
+	var err error
+	if v := c.Query("version"); v != "" {
+		version, convErr := strconv.Atoi(v)
+		if convErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+			return
+		}
+		rules, err = h.Monitor.RuleSet(version)
+	} else {
+		rules, err = h.Monitor.LatestRuleSet()
+	}
+	if err == aml.ErrRulesNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *AMLHandler) PublishRules(c *gin.Context) {
+	var req PublishRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules, err := h.Monitor.PublishRuleSet(&req.Rules, c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rules)
+}
+
+// Replay runs a rule version over a historical range; with dry_run nothing is
+// raised, only reported
+func (h *AMLHandler) Replay(c *gin.Context) {
+	var req ReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.To.After(req.From) || req.To.Sub(req.From) > 366*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Range must be positive and at most a year"})
+		return
+	}
+
+	result, err := h.Monitor.Replay(c.Request.Context(), req.Version, req.From, req.To, req.DryRun)
+	if err == aml.ErrRulesNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
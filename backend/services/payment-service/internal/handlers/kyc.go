@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/playkaro/payment-service/internal/compliance"
+)
+
+type KYCHandler struct {
+	KYC *compliance.KYCService
+}
+
+func NewKYCHandler(kyc *compliance.KYCService) *KYCHandler {
+	return &KYCHandler{KYC: kyc}
+}
+
+// VerifyKYCRequest is a reviewer's decision on a KYC request
+type VerifyKYCRequest struct {
+	Status string `json:"status" binding:"required"` // VERIFIED, REJECTED
+	Notes  string `json:"notes"`
+}
+
+// SubmitKYC accepts a multipart form with document_type, document_number and
+// one or more "documents" files (JPEG, PNG or PDF)
+func (h *KYCHandler) SubmitKYC(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	// Room for every file plus the form fields
+	limit := int64(compliance.MaxDocumentsPerRequest*compliance.MaxDocumentSize + 1<<20)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload: " + err.Error()})
+		return
+	}
+
+	headers := form.File["documents"]
+	files := make([]compliance.DocumentFile, 0, len(headers))
+	for _, fh := range headers {
+		if fh.Size > compliance.MaxDocumentSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("%s is larger than %d MB", fh.Filename, compliance.MaxDocumentSize>>20)})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read " + fh.Filename})
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(f, compliance.MaxDocumentSize+1))
+		f.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read " + fh.Filename})
+			return
+		}
+		files = append(files, compliance.DocumentFile{
+			Name:        fh.Filename,
+			ContentType: fh.Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+
+	req, err := h.KYC.SubmitKYC(c.Request.Context(), userID, c.PostForm("document_type"), c.PostForm("document_number"), files)
+	if err != nil {
+		c.JSON(kycErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, req)
+}
+
+// GetKYCStatus returns the user's latest KYC request
+func (h *KYCHandler) GetKYCStatus(c *gin.Context) {
+	req, err := h.KYC.GetKYCStatus(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if req == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "NOT_SUBMITTED"})
+		return
+	}
+	c.JSON(http.StatusOK, req)
+}
+
+// ListDocuments returns a request's documents with short-lived signed URLs for
+// reviewers
+func (h *KYCHandler) ListDocuments(c *gin.Context) {
+	docs, err := h.KYC.ListDocuments(c.Param("request_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"documents": docs})
+}
+
+// VerifyKYC approves or rejects a request, starting its documents' retention
+func (h *KYCHandler) VerifyKYC(c *gin.Context) {
+	var req VerifyKYCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.KYC.VerifyKYC(c.Param("request_id"), req.Status, req.Notes); err != nil {
+		c.JSON(kycErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": req.Status, "request_id": c.Param("request_id")})
+}
+
+// GetDocumentContent serves a decrypted document to the holder of a signed URL
+func (h *KYCHandler) GetDocumentContent(c *gin.Context) {
+	doc, data, err := h.KYC.OpenDocument(c.Request.Context(), c.Param("document_id"), c.Query("expires"), c.Query("signature"))
+	switch err {
+	case nil:
+	case compliance.ErrBadSignature:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case compliance.ErrDocumentNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load document"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", doc.FileName))
+	c.Data(http.StatusOK, doc.ContentType, data)
+}
+
+// kycErrorStatus maps KYC errors to HTTP statuses
+func kycErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, compliance.ErrDocumentInUse):
+		return http.StatusConflict
+	case errors.Is(err, compliance.ErrNameMismatch), errors.Is(err, compliance.ErrNoProfileName),
+		errors.Is(err, compliance.ErrDocumentNotRegistered):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadRequest
+	}
+}
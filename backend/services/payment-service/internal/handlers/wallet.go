@@ -65,6 +65,10 @@ func (h *PaymentHandler) ProcessInternalTransaction(c *gin.Context) {
 			c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient funds"})
 			return
 		}
+		if err == wallet.ErrWalletFrozen {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Wallet frozen"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -75,3 +79,39 @@ func (h *PaymentHandler) ProcessInternalTransaction(c *gin.Context) {
 		"transaction_id": result.ID,
 	})
 }
+
+// FreezeRequest for internal wallet freeze/unfreeze calls
+type FreezeRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// FreezeWallet blocks debits from a wallet (e.g. account suspended pending review)
+func (h *PaymentHandler) FreezeWallet(c *gin.Context) {
+	var req FreezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.WalletService.Freeze(req.UserID, req.Reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "frozen", "user_id": req.UserID})
+}
+
+// UnfreezeWallet lifts a freeze
+func (h *PaymentHandler) UnfreezeWallet(c *gin.Context) {
+	var req FreezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.WalletService.Unfreeze(req.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "active", "user_id": req.UserID})
+}
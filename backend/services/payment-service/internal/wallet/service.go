@@ -11,6 +11,7 @@ import (
 var (
 	ErrInsufficientFunds = errors.New("insufficient funds")
 	ErrWalletNotFound    = errors.New("wallet not found")
+	ErrWalletFrozen      = errors.New("wallet frozen")
 )
 
 type Service struct {
@@ -85,9 +86,10 @@ func (s *Service) processTransaction(userID string, amount float64, txType, refI
 
 	// Lock Wallet Row & Update Balance
 	var currentBalance float64
+	var frozen bool
 	err = tx.QueryRow(`
-		SELECT balance FROM wallets WHERE user_id = $1 FOR UPDATE
-	`, userID).Scan(&currentBalance)
+		SELECT balance, frozen FROM wallets WHERE user_id = $1 FOR UPDATE
+	`, userID).Scan(&currentBalance, &frozen)
 
 	if err == sql.ErrNoRows {
 		// Create wallet if missing
@@ -100,6 +102,11 @@ func (s *Service) processTransaction(userID string, amount float64, txType, refI
 		return nil, err
 	}
 
+	// A frozen wallet still takes credits (e.g. refunds) but pays nothing out
+	if amount < 0 && frozen {
+		return nil, ErrWalletFrozen
+	}
+
 	// Check Sufficient Funds (for Debits)
 	if amount < 0 && currentBalance+amount < 0 {
 		return nil, ErrInsufficientFunds
@@ -134,3 +141,21 @@ func (s *Service) processTransaction(userID string, amount float64, txType, refI
 		BalanceAfter: newBalance,
 	}, nil
 }
+
+// Freeze blocks debits from the wallet until Unfreeze (e.g. account suspended by anticheat)
+func (s *Service) Freeze(userID, reason string) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO wallets (user_id, balance, frozen, frozen_reason, frozen_at)
+		VALUES ($1, 0, TRUE, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET frozen = TRUE, frozen_reason = EXCLUDED.frozen_reason, frozen_at = EXCLUDED.frozen_at
+	`, userID, reason, time.Now())
+	return err
+}
+
+func (s *Service) Unfreeze(userID string) error {
+	_, err := s.DB.Exec(`
+		UPDATE wallets SET frozen = FALSE, frozen_reason = NULL, frozen_at = NULL WHERE user_id = $1
+	`, userID)
+	return err
+}
@@ -0,0 +1,8 @@
+-- Wallet freeze (accounts suspended pending anticheat review)
+-- Database: payments_db
+
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen_reason TEXT;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_wallets_frozen ON wallets(user_id) WHERE frozen;